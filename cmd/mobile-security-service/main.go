@@ -16,17 +16,32 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/aerogear/mobile-security-service/pkg/config"
 	"github.com/aerogear/mobile-security-service/pkg/db"
+	"github.com/aerogear/mobile-security-service/pkg/events"
+	"github.com/aerogear/mobile-security-service/pkg/health"
+	"github.com/aerogear/mobile-security-service/pkg/lifecycle"
+	"github.com/aerogear/mobile-security-service/pkg/ratelimit"
+	"github.com/aerogear/mobile-security-service/pkg/web/alerts"
 	"github.com/aerogear/mobile-security-service/pkg/web/apps"
 	"github.com/aerogear/mobile-security-service/pkg/web/checks"
 	"github.com/aerogear/mobile-security-service/pkg/web/initclient"
 	"github.com/aerogear/mobile-security-service/pkg/web/router"
+	"github.com/aerogear/mobile-security-service/pkg/web/stream"
+	"github.com/aerogear/mobile-security-service/pkg/web/tenants"
 	"github.com/aerogear/mobile-security-service/pkg/web/user"
+	"github.com/aerogear/mobile-security-service/pkg/web/webhooks"
 	dotenv "github.com/joho/godotenv"
 	"github.com/labstack/echo"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -48,12 +63,59 @@ func main() {
 	e := router.NewRouter(config)
 
 	db := connectDatabase(config)
-	setupServer(e, config, db)
+	lc := lifecycle.NewManager()
+	setupServer(e, config, db, lc)
 
 	// start webserver
-	if err := e.Start(config.ListenAddress); err != nil {
-		panic("failed to start" + err.Error())
+	go func() {
+		if err := e.Start(config.ListenAddress); err != nil && err != http.ErrServerClosed {
+			panic("failed to start" + err.Error())
+		}
+	}()
+
+	waitForShutdownSignal()
+	shutdown(e, config.Shutdown, db, lc)
+}
+
+// Block until SIGINT or SIGTERM is received. A second signal stops the process immediately.
+func waitForShutdownSignal() {
+	quit := make(chan os.Signal, 2)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	sig := <-quit
+	log.Infof("Received %v, shutting down", sig)
+
+	go func() {
+		sig := <-quit
+		log.Warnf("Received %v again, exiting without draining", sig)
+		os.Exit(1)
+	}()
+}
+
+// Stop the server in order: fail readiness, drain in-flight requests, stop the
+// background workers and finally close the database pool
+func shutdown(e *echo.Echo, c config.ShutdownConfig, dbConn *sql.DB, lc *lifecycle.Manager) {
+	lc.StartDraining()
+
+	// give the load balancer time to observe the failing readiness probe
+	time.Sleep(c.DrainDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	if err := e.Shutdown(ctx); err != nil {
+		log.Errorf("Failed to drain in-flight requests: %v", err)
+	}
+
+	if err := lc.Stop(ctx); err != nil {
+		log.Errorf("Failed to stop background workers: %v", err)
+	}
+
+	if err := dbConn.Close(); err != nil {
+		log.Errorf("Failed to close the database connection: %v", err)
 	}
+
+	log.Info("Server stopped")
 }
 
 func initLogger(level, format string) {
@@ -92,35 +154,154 @@ func connectDatabase(c config.Config) *sql.DB {
 }
 
 // Invoke handlers, services and repositories here
-func setupServer(e *echo.Echo, c config.Config, dbConn *sql.DB) {
-	// Prefix api routes
+func setupServer(e *echo.Echo, c config.Config, dbConn *sql.DB, lc *lifecycle.Manager) {
+	// Prefix api routes. The probes and metrics are not versioned.
 	APIRoutePrefix := c.APIRoutePrefix
 	apiGroup := e.Group(APIRoutePrefix)
 
+	// Versioned api routes, whose requests are validated against the OpenAPI document
+	// The users are identified by the headers of the trusted proxies or by a bearer token before the requests are handled
+	authenticate, err := user.Authenticate(c.Auth)
+	if err != nil {
+		log.Fatal(err)
+	}
+	openAPI := router.NewOpenAPI(c)
+	middlewares := []echo.MiddlewareFunc{authenticate, router.NewOpenAPIValidator(openAPI, c, false)}
+
+	// Tenants handler setup. When they are enabled, the users only see the apps of their tenants.
+	tenantsService := tenants.NewService(tenants.NewPostgreSQLRepository(dbConn), c.Tenants.SuperAdmins)
+	tenantsHandler := tenants.NewHTTPHandler(e, tenantsService)
+	requireSuperAdmin := user.RequireAdmin(c.Tenants.SuperAdmins)
+	scopeApp := tenants.ScopeApp(tenantsService)
+	if c.Tenants.Enabled {
+		middlewares = append(middlewares, tenants.Scope(tenantsService))
+	}
+
+	apiV1Groups := router.NewAPIV1Groups(e, c, middlewares...)
+	apiV2Group := router.NewAPIV2Group(e, c, middlewares...)
+	requireAdmin := user.RequireAdmin(c.AdminUsers)
+
 	// User handler setup
 	userHandler := user.NewHTTPHandler(e)
 
-	// Setup user routes
-	router.SetUserRoutes(apiGroup, userHandler)
+	// App handler setup. The changes of the apps are published to the subscribers of the events bus,
+	// and the archive of a hard deleted app includes its webhooks and alerts.
+	eventsBus := events.NewBus()
+	appsPostgreSQLRepository := apps.NewPostgreSQLRepository(dbConn, webhooks.ExportAppWebhooks, alerts.ExportAppAlerts)
+	appsService := apps.NewService(appsPostgreSQLRepository, eventsBus)
+
+	// Business metrics of the apps, with the number of their disabled versions loaded from the database
+	prometheus.MustRegister(apps.AppLaunchesTotal, apps.AppNewDevicesTotal, apps.AppBlockedLaunchesTotal, apps.AppDisabledVersions)
+	apps.SetMaxMetricVersions(c.Metrics.MaxVersionsPerApp)
+	if err := apps.LoadMetrics(appsPostgreSQLRepository); err != nil {
+		log.Errorf("Could not load the metrics of the apps: %v", err)
+	}
+
+	// Reconcile the apps with the policy directory and reject the API changes of the apps it manages
+	if c.Policy.Dir != "" {
+		prometheus.MustRegister(apps.PolicyReconciliationsTotal, apps.PolicyLastSuccessfulReconciliation, apps.PolicyDrift, apps.PolicyManagedApps)
+		policyController := apps.NewPolicyController(appsService, c.Policy.Dir, c.Policy.Interval)
+		lc.Go("policy-controller", policyController)
+		appsService = apps.NewManagedService(appsService, appsPostgreSQLRepository, policyController)
+	}
 
-	// App handler setup
-	appsPostgreSQLRepository := apps.NewPostgreSQLRepository(dbConn)
-	appsService := apps.NewService(appsPostgreSQLRepository)
 	appsHandler := apps.NewHTTPHandler(e, appsService)
 
-	// Setup app routes
-	router.SetAppRoutes(apiGroup, appsHandler)
+	// Purge the soft deleted apps past the retention period
+	if c.Purge.Retention > 0 {
+		lc.Go("purge-deleted-apps", apps.NewPurgeWorker(appsService, c.Purge.Retention, c.Purge.Interval))
+	}
+
+	// Webhooks handler setup and the delivery of the events of their outbox
+	webhooksService := webhooks.NewService(webhooks.NewPostgreSQLRepository(dbConn), appsPostgreSQLRepository, c.Webhooks)
+	webhooksHandler := webhooks.NewHTTPHandler(e, webhooksService)
+	lc.Go("webhook-deliveries", webhooks.NewDeliveryWorker(webhooksService, c.Webhooks.DeliveryInterval))
+
+	// Subscribers of the events of the apps. The webhooks add the deliveries to their outbox with the change,
+	// while the audit log does not slow the requests down.
+	eventsBus.Subscribe("webhooks", webhooks.NewEventHandler(webhooksService))
+	eventsBus.SubscribeAsync("audit-log", events.NewLogHandler())
 
-	// Initclient handler setup
-	initclientHandler := initclient.NewHTTPHandler(e, appsService)
+	// Stream the changes of the apps to the dashboard. The streams end when the server shuts down.
+	streamBroker := stream.NewBroker(c.Stream.LaunchesInterval)
+	eventsBus.SubscribeAsync("stream", streamBroker)
+	lc.Go("stream-launches", streamBroker)
+	e.Server.RegisterOnShutdown(streamBroker.Close)
+	streamHandler := stream.NewHTTPHandler(e, appsService, streamBroker, c.Stream)
+	requireUser := user.RequireUser()
+
+	lc.Go("events", eventsBus)
+
+	// Alerts handler setup and the analysis of the init requests
+	alertsService := alerts.NewService(alerts.NewPostgreSQLRepository(dbConn), appsPostgreSQLRepository, c.Alerts)
+	alertsHandler := alerts.NewHTTPHandler(e, alertsService)
+	if c.Alerts.Interval > 0 {
+		lc.Go("alerts-analyzer", alerts.NewAnalyzerWorker(alertsService, c.Alerts.Interval))
+	}
+
+	// Initclient handler setup. The init requests are not authenticated, so they are rate limited.
+	prometheus.MustRegister(ratelimit.RejectedRequestsTotal)
+	rateLimitStore := newRateLimitStore(c.InitRateLimit, dbConn)
+	lc.Go("rate-limit-prune", ratelimit.NewPruneWorker(rateLimitStore, time.Minute))
+	initLimiter := ratelimit.NewLimiter(rateLimitStore, map[string]ratelimit.Limit{
+		ratelimit.ClientIP: ratelimit.PerMinute(c.InitRateLimit.PerIP),
+		ratelimit.App:      ratelimit.PerMinute(c.InitRateLimit.PerApp),
+		ratelimit.Device:   ratelimit.PerMinute(c.InitRateLimit.PerDevice),
+	})
+	trustedProxies, err := user.ParseTrustedProxies(c.Auth.TrustedProxies)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(trustedProxies) == 0 && c.InitRateLimit.PerIP > 0 {
+		log.Warn("TRUSTED_PROXY_CIDRS is not set: the init requests are limited per IP by the address of their peer, so all the clients behind a proxy share the INIT_RATE_LIMIT_PER_IP limit")
+	}
+	initclientHandler := initclient.NewHTTPHandler(e, appsService, initLimiter, trustedProxies)
+
+	// Readiness checks setup
+	healthRegistry := health.NewRegistry(c.Health.CheckTimeout)
+	healthRegistry.Register("server", lc)
+	healthRegistry.Register("database", health.DatabaseChecker(dbConn))
+	healthRegistry.Register("schema", health.SchemaChecker(dbConn))
 
 	// InitChecks handler setup
-	checksHandler := checks.NewHTTPHandler(e, appsService)
+	checksHandler := checks.NewHTTPHandler(e, healthRegistry)
+
+	// Setup user, app, webhook, tenant, stream, alert and initclient routes in every version of the api
+	for _, g := range apiV1Groups {
+		router.SetUserRoutes(g, userHandler)
+		router.SetAppRoutes(g, appsHandler, requireAdmin, scopeApp)
+		router.SetWebhookRoutes(g, webhooksHandler, requireAdmin, scopeApp)
+		router.SetTenantRoutes(g, tenantsHandler, requireSuperAdmin)
+		router.SetStreamRoutes(g, streamHandler, requireUser, scopeApp)
+		router.SetAlertRoutes(g, alertsHandler, scopeApp)
+		router.SetInitRoutes(g, initclientHandler)
+	}
+	router.SetUserRoutesV2(apiV2Group, userHandler)
+	router.SetAppRoutesV2(apiV2Group, appsHandler, requireAdmin, scopeApp)
+	router.SetWebhookRoutes(apiV2Group, webhooksHandler, requireAdmin, scopeApp)
+	router.SetTenantRoutes(apiV2Group, tenantsHandler, requireSuperAdmin)
+	router.SetStreamRoutes(apiV2Group, streamHandler, requireUser, scopeApp)
+	router.SetAlertRoutes(apiV2Group, alertsHandler, scopeApp)
+	router.SetInitRoutesV2(apiV2Group, initclientHandler)
 
-	// Setup initclient routes
-	router.SetInitRoutes(apiGroup, initclientHandler)
+	// Setup checks routes
 	router.SetChecksRouter(apiGroup, checksHandler)
 
 	// Setup metrics route
 	router.SetMetricsRouter(apiGroup)
+
+	// Setup the route of the OpenAPI document
+	router.SetOpenAPIRouter(apiGroup, openAPI)
+}
+
+// Returns the store of the rate limit buckets, shared by the instances of the service when it is the database
+func newRateLimitStore(c config.RateLimitConfig, dbConn *sql.DB) ratelimit.Store {
+	switch c.Store {
+	case "memory":
+		return ratelimit.NewMemoryStore()
+	case "postgres":
+		return ratelimit.NewPostgreSQLStore(dbConn)
+	default:
+		panic("rate limit store " + c.Store + " is not allowed. Must be one of [memory, postgres]")
+	}
 }
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/client"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// command is a subcommand of the tool, e.g. apps list.
+// Each command calls the endpoint of a single method of apps.HTTPHandler.
+type command struct {
+	name string
+	args string
+	// description is shown in the usage with the handler called by the command
+	description string
+	handler     string
+	// flags registers the flags of the command and returns the function running it
+	flags func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error)
+}
+
+var commands = []command{
+	{
+		name:        "apps list",
+		description: "List the apps",
+		handler:     "GetAppsPage",
+		flags: func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+			deleted := fs.Bool("deleted", false, "list the soft deleted apps instead of the active ones")
+			search := fs.String("search", "", "list only the apps whose name or appId contains the text")
+			sort := fs.String("sort", "", `order by name, launches or installs, prefixed with "-" for descending order`)
+
+			return func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+				apps, err := c.GetAllApps(ctx, models.AppsQuery{Deleted: *deleted, Search: *search, Sort: *sort})
+				if err != nil {
+					return nil, err
+				}
+				return appsOutput(apps), nil
+			}
+		},
+	},
+	{
+		name:        "apps get",
+		args:        "<id>",
+		description: "Show an app",
+		handler:     "GetActiveAppByID",
+		flags: func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+			return func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+				if len(args) != 1 {
+					return nil, errUsage
+				}
+
+				app, err := c.GetApp(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+
+				o := appsOutput([]models.App{*app})
+				o.value = app
+				return o, nil
+			}
+		},
+	},
+	{
+		name:        "apps create",
+		args:        "<appId>",
+		description: "Create an app, or restore it when an app with the same appId was deleted",
+		handler:     "CreateApp",
+		flags: func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+			name := fs.String("name", "", "the name of the app")
+
+			return func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+				if len(args) != 1 {
+					return nil, errUsage
+				}
+				return nil, c.CreateApp(ctx, args[0], *name)
+			}
+		},
+	},
+	{
+		name:        "apps delete",
+		args:        "<id>",
+		description: "Soft delete an app",
+		handler:     "DeleteAppById",
+		flags: func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+			return func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+				if len(args) != 1 {
+					return nil, errUsage
+				}
+				return nil, c.DeleteApp(ctx, args[0])
+			}
+		},
+	},
+	{
+		name:        "apps export",
+		description: "Export the apps and the state of their versions as a policy document, e.g. with -o yaml",
+		handler:     "ExportApps",
+		flags: func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+			return func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+				if len(args) != 0 {
+					return nil, errUsage
+				}
+
+				policy, err := c.ExportApps(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return policyOutput(policy), nil
+			}
+		},
+	},
+	{
+		name:        "apps import",
+		args:        "<file>",
+		description: "Apply a policy document in YAML or JSON and print the changes made",
+		handler:     "ImportApps",
+		flags: func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+			dryRun := fs.Bool("dry-run", false, "print the changes which would be made without making them")
+
+			return func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+				if len(args) != 1 {
+					return nil, errUsage
+				}
+
+				policy, err := readPolicy(args[0])
+				if err != nil {
+					return nil, err
+				}
+
+				result, err := c.ImportApps(ctx, *policy, *dryRun)
+				if err != nil {
+					return nil, err
+				}
+				return changesOutput(result), nil
+			}
+		},
+	},
+	{
+		name:        "versions list",
+		args:        "<app id>",
+		description: "List the versions of an app",
+		handler:     "GetActiveAppByID",
+		flags: func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+			return func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+				if len(args) != 1 {
+					return nil, errUsage
+				}
+
+				app, err := c.GetApp(ctx, args[0])
+				if err != nil {
+					return nil, err
+				}
+				return versionsOutput(versionsOf(app)), nil
+			}
+		},
+	},
+	{
+		name:        "versions disable",
+		args:        "<app id> <version>...",
+		description: "Disable versions of an app, by id or version",
+		handler:     "UpdateAppVersions",
+		flags: func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+			message := fs.String("message", "", "the message shown to the users of the disabled versions")
+
+			return func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+				return setVersionsDisabled(ctx, c, args, true, *message)
+			}
+		},
+	},
+	{
+		name:        "versions enable",
+		args:        "<app id> <version>...",
+		description: "Enable versions of an app, by id or version",
+		handler:     "UpdateAppVersions",
+		flags: func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+			return func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+				return setVersionsDisabled(ctx, c, args, false, "")
+			}
+		},
+	},
+	{
+		name:        "versions disable-all",
+		args:        "<app id>",
+		description: "Disable all the versions of an app",
+		handler:     "DisableAllAppVersionsByAppID",
+		flags: func(fs *flag.FlagSet) func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+			message := fs.String("message", "", "the message shown to the users of the app")
+
+			return func(ctx context.Context, c *client.Client, args []string) (*output, error) {
+				if len(args) != 1 {
+					return nil, errUsage
+				}
+				return nil, c.DisableAppVersions(ctx, args[0], *message)
+			}
+		},
+	},
+}
+
+// errUsage is returned by the commands called with the wrong arguments
+var errUsage = errors.New("wrong arguments")
+
+// run parses the global flags and runs the command of the arguments, writing its output to w
+func run(args []string, w io.Writer, getenv func(string) string) error {
+	fs := flag.NewFlagSet("mssctl", flag.ContinueOnError)
+	fs.SetOutput(w)
+	configPath := fs.String("config", "", "the path of the config file (default $"+configEnv+" or ~/"+defaultFile+")")
+	url := fs.String("url", "", "the base URL of the API, including the API route prefix (default $"+urlEnv+" or "+defaultURL+")")
+	format := fs.String("o", "", "the output format: table, json or yaml (default $"+outputEnv+" or table)")
+	fs.Usage = func() { usage(fs, w) }
+
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(*configPath, getenv)
+	if err != nil {
+		return err
+	}
+	if *url != "" {
+		cfg.URL = *url
+	}
+	if *format != "" {
+		cfg.Output = *format
+	}
+
+	args = fs.Args()
+	if len(args) < 2 {
+		fs.Usage()
+		return errUsage
+	}
+
+	name := args[0] + " " + args[1]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		cmdFlags := flag.NewFlagSet("mssctl "+name, flag.ContinueOnError)
+		cmdFlags.SetOutput(ioutil.Discard)
+		runCommand := cmd.flags(cmdFlags)
+		if err := cmdFlags.Parse(args[2:]); err != nil {
+			return fmt.Errorf("%v: %v", name, err)
+		}
+
+		c := client.NewClient(cfg.URL, client.Options{
+			Token:      cfg.Token,
+			Username:   cfg.Username,
+			Email:      cfg.Email,
+			MaxRetries: cfg.Retries,
+			RetryWait:  time.Second,
+		})
+
+		o, err := runCommand(context.Background(), c, cmdFlags.Args())
+		if err == errUsage {
+			return fmt.Errorf("usage: mssctl %v %v", name, cmd.args)
+		}
+		if err != nil {
+			return describe(err)
+		}
+
+		if o == nil {
+			return nil
+		}
+		return o.print(w, cfg.Output)
+	}
+
+	fs.Usage()
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func usage(fs *flag.FlagSet, w io.Writer) {
+	fmt.Fprintln(w, "Usage: mssctl [flags] <command> [command flags] [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-22v %-24v %v (apps.HTTPHandler.%v)\n", cmd.name, cmd.args, cmd.description, cmd.handler)
+	}
+	fmt.Fprintln(w, "\nFlags:")
+	fs.PrintDefaults()
+}
+
+// describe returns an error with the details of the invalid fields of a models error
+func describe(err error) error {
+	var modelErr *models.Error
+	if !errors.As(err, &modelErr) || len(modelErr.Fields) == 0 {
+		return err
+	}
+
+	details := make([]string, 0, len(modelErr.Fields))
+	for _, field := range modelErr.Fields {
+		details = append(details, field.Message)
+	}
+	return fmt.Errorf("%v: %v", modelErr.Message, strings.Join(details, ", "))
+}
+
+// setVersionsDisabled updates the versions of an app selected by id or version
+func setVersionsDisabled(ctx context.Context, c *client.Client, args []string, disabled bool, message string) (*output, error) {
+	if len(args) < 2 {
+		return nil, errUsage
+	}
+
+	app, err := c.GetApp(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []models.Version
+	for _, selector := range args[1:] {
+		version, ok := findVersion(versionsOf(app), selector)
+		if !ok {
+			return nil, fmt.Errorf("the app %v has no version %v", args[0], selector)
+		}
+
+		version.Disabled = disabled
+		if disabled {
+			version.DisabledMessage = message
+		}
+		updated = append(updated, version)
+	}
+
+	if err := c.UpdateAppVersions(ctx, app.ID, updated); err != nil {
+		return nil, err
+	}
+	return versionsOutput(updated), nil
+}
+
+// readPolicy reads a policy document from a file. The JSON documents are read as YAML, of which JSON is a subset.
+// The unknown fields are rejected so a typo is not ignored.
+func readPolicy(path string) (*models.AppsPolicy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := models.AppsPolicy{}
+	if err := yaml.UnmarshalStrict(b, &policy); err != nil {
+		return nil, fmt.Errorf("%v is not a valid policy document: %v", path, err)
+	}
+	return &policy, nil
+}
+
+func findVersion(versions []models.Version, selector string) (models.Version, bool) {
+	for _, v := range versions {
+		if v.ID == selector || v.Version == selector {
+			return v, true
+		}
+	}
+	return models.Version{}, false
+}
+
+func versionsOf(app *models.App) []models.Version {
+	if app.DeployedVersions == nil {
+		return []models.Version{}
+	}
+	return *app.DeployedVersions
+}
+
+func appsOutput(apps []models.App) *output {
+	o := &output{
+		value:  apps,
+		header: []string{"ID", "APP ID", "NAME", "VERSIONS", "INSTALLS", "LAUNCHES", "DELETED AT"},
+	}
+	for _, app := range apps {
+		o.rows = append(o.rows, []string{
+			app.ID,
+			app.AppID,
+			app.AppName,
+			count(app.NumOfDeployedVersions),
+			count(app.NumOfCurrentInstalls),
+			count(app.NumOfAppLaunches),
+			app.DeletedAt,
+		})
+	}
+	return o
+}
+
+func versionsOutput(versions []models.Version) *output {
+	o := &output{
+		value:  versions,
+		header: []string{"ID", "VERSION", "DISABLED", "MESSAGE", "INSTALLS", "LAUNCHES", "LAST LAUNCHED AT"},
+	}
+	for _, v := range versions {
+		o.rows = append(o.rows, []string{
+			v.ID,
+			v.Version,
+			strconv.FormatBool(v.Disabled),
+			v.DisabledMessage,
+			strconv.FormatInt(v.NumOfCurrentInstalls, 10),
+			strconv.FormatInt(v.NumOfAppLaunches, 10),
+			v.LastLaunchedAt,
+		})
+	}
+	return o
+}
+
+func policyOutput(policy *models.AppsPolicy) *output {
+	o := &output{
+		value:  policy,
+		header: []string{"APP ID", "NAME", "VERSION", "DISABLED", "MESSAGE"},
+	}
+	for _, app := range policy.Apps {
+		o.rows = append(o.rows, []string{app.AppID, app.AppName, "", "", ""})
+		for _, v := range app.Versions {
+			o.rows = append(o.rows, []string{app.AppID, "", v.Version, strconv.FormatBool(v.Disabled), v.DisabledMessage})
+		}
+	}
+	return o
+}
+
+func changesOutput(result *models.PolicyImport) *output {
+	o := &output{
+		value:  result,
+		header: []string{"ACTION", "APP ID", "VERSION", "FIELD", "FROM", "TO", "REASON"},
+	}
+	for _, change := range result.Changes {
+		o.rows = append(o.rows, []string{change.Action, change.AppID, change.Version, change.Field, change.From, change.To, change.Reason})
+	}
+	return o
+}
+
+func count(n *int) string {
+	if n == nil {
+		return "-"
+	}
+	return strconv.Itoa(*n)
+}
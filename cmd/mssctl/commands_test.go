@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// request is a request received by the fake API
+type request struct {
+	method string
+	path   string
+	body   string
+}
+
+// newFakeAPI returns a server answering the requests of the commands with the fixtures, recording them
+func newFakeAPI(requests *[]request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*requests = append(*requests, request{method: r.Method, path: r.URL.RequestURI(), body: string(body)})
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/export":
+			json.NewEncoder(w).Encode(models.AppsPolicy{Apps: []models.AppPolicy{{
+				AppID:    "com.aerogear.app",
+				AppName:  "App",
+				Versions: []models.VersionPolicy{{Version: "1.0", Disabled: true, DisabledMessage: "Please update"}},
+			}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/apps":
+			json.NewEncoder(w).Encode(map[string]interface{}{"apps": helpers.GetMockAppList()})
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/v2/apps/"+helpers.GetMockApp().ID):
+			json.NewEncoder(w).Encode(helpers.GetMockApp())
+		case r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"not_found","detail":"App not found"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/apps":
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/import":
+			json.NewEncoder(w).Encode(models.PolicyImport{DryRun: true, Changes: []models.PolicyChange{
+				{Action: models.PolicyActionCreate, AppID: "com.aerogear.app", Field: "appName", To: "App"},
+			}})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestRun(t *testing.T) {
+	app := helpers.GetMockApp()
+	versions := helpers.GetMockAppVersionList()
+
+	dir, err := ioutil.TempDir("", "mssctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	policy := filepath.Join(dir, "policy.yaml")
+	if err := ioutil.WriteFile(policy, []byte("apps:\n- appId: com.aerogear.app\n  appName: App\n  versions:\n  - version: \"1.0\"\n    disabled: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	invalidPolicy := filepath.Join(dir, "invalid.yaml")
+	if err := ioutil.WriteFile(invalidPolicy, []byte("apps:\n- appId: com.aerogear.app\n  disable: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantRequests []request
+		wantOutput   string
+		wantErr      string
+	}{
+		{
+			name:         "apps list should print the apps in a table",
+			args:         []string{"apps", "list", "-search", "one"},
+			wantRequests: []request{{method: http.MethodGet, path: "/api/v2/apps?q=one"}},
+			wantOutput:   "com.aerogear.mobile_app_one",
+		},
+		{
+			name:         "apps get should print the app in JSON",
+			args:         []string{"-o", "json", "apps", "get", app.ID},
+			wantRequests: []request{{method: http.MethodGet, path: "/api/v2/apps/" + app.ID}},
+			wantOutput:   `"deployedVersions": [`,
+		},
+		{
+			name:         "apps create should send the app",
+			args:         []string{"apps", "create", "-name", "App", "com.aerogear.app"},
+			wantRequests: []request{{method: http.MethodPost, path: "/api/v2/apps", body: `{"appId":"com.aerogear.app","appName":"App"}`}},
+		},
+		{
+			name:         "apps delete should delete the app",
+			args:         []string{"apps", "delete", app.ID},
+			wantRequests: []request{{method: http.MethodDelete, path: "/api/v2/apps/" + app.ID}},
+		},
+		{
+			name:         "apps export should print the policy document in YAML",
+			args:         []string{"-o", "yaml", "apps", "export"},
+			wantRequests: []request{{method: http.MethodGet, path: "/api/v2/export"}},
+			wantOutput:   "apps:\n- appId: com.aerogear.app\n  appName: App\n  versions:\n  - disabled: true\n    disabledMessage: Please update\n    version: \"1.0\"\n",
+		},
+		{
+			name: "apps import should send the policy document and print the changes",
+			args: []string{"apps", "import", "-dry-run", policy},
+			wantRequests: []request{{
+				method: http.MethodPost,
+				path:   "/api/v2/import?dryRun=true",
+				body:   `{"apps":[{"appId":"com.aerogear.app","appName":"App","versions":[{"version":"1.0","disabled":true}]}]}`,
+			}},
+			wantOutput: "create   com.aerogear.app",
+		},
+		{
+			name:    "apps import should reject the unknown fields of the document",
+			args:    []string{"apps", "import", invalidPolicy},
+			wantErr: "field disable not found",
+		},
+		{
+			name:         "versions list should print the versions in YAML",
+			args:         []string{"-o", "yaml", "versions", "list", app.ID},
+			wantRequests: []request{{method: http.MethodGet, path: "/api/v2/apps/" + app.ID}},
+			wantOutput:   "- appId: com.aerogear.mobile_app_one\n",
+		},
+		{
+			name: "versions disable should update the selected versions",
+			args: []string{"versions", "disable", "-message", "Please update", app.ID, versions[1].Version},
+			wantRequests: []request{
+				{method: http.MethodGet, path: "/api/v2/apps/" + app.ID},
+				{
+					method: http.MethodPut,
+					path:   "/api/v2/apps/" + app.ID + "/versions",
+					body:   `[{"id":"` + versions[1].ID + `","appId":"com.aerogear.mobile_app_one","version":"` + versions[1].Version + `","disabled":true,"disabledMessage":"Please update"}]`,
+				},
+			},
+			wantOutput: "Please update",
+		},
+		{
+			name:         "versions enable should fail on an unknown version",
+			args:         []string{"versions", "enable", app.ID, "9.9"},
+			wantRequests: []request{{method: http.MethodGet, path: "/api/v2/apps/" + app.ID}},
+			wantErr:      "has no version 9.9",
+		},
+		{
+			name:         "versions disable-all should send the message",
+			args:         []string{"versions", "disable-all", "-message", "Please update", app.ID},
+			wantRequests: []request{{method: http.MethodPost, path: "/api/v2/apps/" + app.ID + "/versions/disable", body: `{"disabledMessage":"Please update"}`}},
+		},
+		{
+			name:         "A missing app should be reported",
+			args:         []string{"apps", "get", "a4f6e9c2-0c5b-4c54-9d4c-8b1f4e5b2a10"},
+			wantRequests: []request{{method: http.MethodGet, path: "/api/v2/apps/a4f6e9c2-0c5b-4c54-9d4c-8b1f4e5b2a10"}},
+			wantErr:      "App not found",
+		},
+		{
+			name:    "A command called with the wrong arguments should print its usage",
+			args:    []string{"apps", "get"},
+			wantErr: "usage: mssctl apps get <id>",
+		},
+		{
+			name:    "An unknown command should be reported",
+			args:    []string{"apps", "purge"},
+			wantErr: `unknown command "apps purge"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []request
+			server := newFakeAPI(&requests)
+			defer server.Close()
+
+			env := map[string]string{urlEnv: server.URL + "/api", configEnv: os.DevNull}
+			w := &bytes.Buffer{}
+			err := run(tt.args, w, func(key string) string { return env[key] })
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("run() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+
+			if len(requests) != len(tt.wantRequests) {
+				t.Fatalf("run() sent %v, want %v", requests, tt.wantRequests)
+			}
+			for i, want := range tt.wantRequests {
+				got := requests[i]
+				got.body = strings.TrimSpace(got.body)
+				if got != want {
+					t.Errorf("run() sent %v, want %v", got, want)
+				}
+			}
+
+			if !strings.Contains(w.String(), tt.wantOutput) {
+				t.Errorf("run() output = %v, want %v", w.String(), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mssctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte("MSSCTL_URL=http://file/api\nMSSCTL_TOKEN=file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		env     map[string]string
+		want    config
+		wantErr bool
+	}{
+		{
+			name: "loadConfig() should use the defaults without a config file",
+			env:  map[string]string{configEnv: os.DevNull},
+			want: config{URL: defaultURL, Output: outputTable, Retries: 2},
+		},
+		{
+			name: "loadConfig() should read the config file",
+			path: path,
+			want: config{URL: "http://file/api", Token: "file-token", Output: outputTable, Retries: 2},
+		},
+		{
+			name: "loadConfig() should prefer the environment variables to the config file",
+			env:  map[string]string{configEnv: path, tokenEnv: "env-token", outputEnv: outputJSON},
+			want: config{URL: "http://file/api", Token: "env-token", Output: outputJSON, Retries: 2},
+		},
+		{
+			name:    "loadConfig() should fail on a missing config file set explicitly",
+			path:    filepath.Join(dir, "missing"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadConfig(tt.path, func(key string) string { return tt.env[key] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("loadConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/joho/godotenv"
+)
+
+// The settings are read, in order of precedence, from the flags, the environment variables and the config file.
+// The config file has the format of a .env file with the same keys as the environment variables.
+const (
+	configEnv   = "MSSCTL_CONFIG"
+	urlEnv      = "MSSCTL_URL"
+	tokenEnv    = "MSSCTL_TOKEN"
+	userEnv     = "MSSCTL_USER"
+	emailEnv    = "MSSCTL_EMAIL"
+	outputEnv   = "MSSCTL_OUTPUT"
+	retriesEnv  = "MSSCTL_RETRIES"
+	defaultURL  = "http://localhost:3000/api"
+	defaultFile = ".mssctl"
+)
+
+// config holds the settings of the tool
+type config struct {
+	// URL is the base URL of the API, including the API route prefix
+	URL string
+	// Token is sent as a bearer token, e.g. to go through the OAuth proxy
+	Token string
+	// Username and Email are sent in the headers of the OAuth proxy when the service is called without it
+	Username string
+	Email    string
+	// Output is the format of the output: table, json or yaml
+	Output  string
+	Retries int
+}
+
+// defaultConfigPath returns the path of the config file in the home directory of the user
+func defaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return defaultFile
+	}
+	return filepath.Join(home, defaultFile)
+}
+
+// loadConfig reads the settings from the config file and the environment variables.
+// A missing config file is only an error when its path was set explicitly.
+func loadConfig(path string, getenv func(string) string) (config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = getenv(configEnv)
+		explicit = path != ""
+	}
+	if !explicit {
+		path = defaultConfigPath()
+	}
+
+	file, err := godotenv.Read(path)
+	if err != nil && (explicit || !os.IsNotExist(err)) {
+		return config{}, err
+	}
+
+	get := func(key, defaultValue string) string {
+		if v := getenv(key); v != "" {
+			return v
+		}
+		if v, ok := file[key]; ok && v != "" {
+			return v
+		}
+		return defaultValue
+	}
+
+	retries, err := strconv.Atoi(get(retriesEnv, "2"))
+	if err != nil {
+		return config{}, err
+	}
+
+	return config{
+		URL:      get(urlEnv, defaultURL),
+		Token:    get(tokenEnv, ""),
+		Username: get(userEnv, ""),
+		Email:    get(emailEnv, ""),
+		Output:   get(outputEnv, outputTable),
+		Retries:  retries,
+	}, nil
+}
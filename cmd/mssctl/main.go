@@ -0,0 +1,16 @@
+// mssctl is the command-line admin tool of the Mobile Security Service.
+// It calls the REST API of the service with the Go client, so on-call engineers can manage the apps and
+// their versions without the UI.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Getenv); err != nil {
+		fmt.Fprintln(os.Stderr, "mssctl:", err)
+		os.Exit(1)
+	}
+}
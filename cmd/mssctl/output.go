@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// The formats of the output
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// output is the result of a command. The value is printed in JSON and YAML, and the rows in a table.
+type output struct {
+	value  interface{}
+	header []string
+	rows   [][]string
+}
+
+// print writes the output in the given format
+func (o output) print(w io.Writer, format string) error {
+	switch format {
+	case outputTable:
+		return o.printTable(w)
+	case outputJSON:
+		b, err := json.MarshalIndent(o.value, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case outputYAML:
+		b, err := toYAML(o.value)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	default:
+		return fmt.Errorf("unknown output format %q, must be one of %v, %v or %v", format, outputTable, outputJSON, outputYAML)
+	}
+}
+
+func (o output) printTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(o.header, "\t"))
+	for _, row := range o.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// toYAML returns the YAML of a value with the same field names as its JSON, the maps being sorted by key
+func toYAML(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var value interface{}
+	if err := d.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if isScalar(value) {
+		fmt.Fprintln(buf, yamlScalar(value))
+		return buf.Bytes(), nil
+	}
+	writeYAML(buf, value, 0)
+	return buf.Bytes(), nil
+}
+
+// writeYAML writes a map or a list with the given indentation. The items of a list have the indentation of their key.
+func writeYAML(buf *bytes.Buffer, v interface{}, indent int) {
+	pad := strings.Repeat(" ", indent)
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			child := v[key]
+			fmt.Fprintf(buf, "%v%v:", pad, yamlScalar(key))
+			switch {
+			case isScalar(child):
+				fmt.Fprintf(buf, " %v\n", yamlScalar(child))
+			case isMap(child):
+				buf.WriteString("\n")
+				writeYAML(buf, child, indent+2)
+			default:
+				buf.WriteString("\n")
+				writeYAML(buf, child, indent)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			switch {
+			case isScalar(item):
+				fmt.Fprintf(buf, "%v- %v\n", pad, yamlScalar(item))
+			case isMap(item):
+				// the first key of a map is on the line of the dash
+				child := &bytes.Buffer{}
+				writeYAML(child, item, indent+2)
+				buf.WriteString(pad + "- " + strings.TrimPrefix(child.String(), pad+"  "))
+			default:
+				fmt.Fprintf(buf, "%v-\n", pad)
+				writeYAML(buf, item, indent+2)
+			}
+		}
+	}
+}
+
+// isScalar is true for the values written on the line of their key: the scalars, the empty maps and the empty lists
+func isScalar(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return true
+}
+
+func isMap(v interface{}) bool {
+	_, ok := v.(map[string]interface{})
+	return ok
+}
+
+// plainString matches the strings which can be written without quotes
+var plainString = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9_ ./@-]*[A-Za-z0-9_./@-]$|^[A-Za-z_/]$`)
+
+// keywords are the plain strings read as another type than a string
+var keywords = map[string]bool{"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true, "null": true, "y": true, "n": true}
+
+func yamlScalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	case string:
+		if plainString.MatchString(v) && !keywords[strings.ToLower(v)] {
+			return v
+		}
+		// the JSON strings are valid double-quoted YAML strings
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
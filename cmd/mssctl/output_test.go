@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+func TestToYAML(t *testing.T) {
+	installs := 2
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{
+			name: "toYAML() should write the maps sorted by key with their JSON names",
+			value: models.App{
+				ID:                   "7f89ce49-a736-459e-9110-e52d049fc027",
+				AppID:                "com.aerogear.app",
+				NumOfCurrentInstalls: &installs,
+				DeployedVersions:     &[]models.Version{{ID: "1", Version: "1.0", DisabledMessage: "Please: update"}},
+			},
+			want: `appId: com.aerogear.app
+deployedVersions:
+- appId: ""
+  disabled: false
+  disabledMessage: "Please: update"
+  id: "1"
+  version: "1.0"
+id: "7f89ce49-a736-459e-9110-e52d049fc027"
+numOfCurrentInstalls: 2
+`,
+		},
+		{
+			name:  "toYAML() should write the empty lists inline",
+			value: []models.App{},
+			want:  "[]\n",
+		},
+		{
+			name:  "toYAML() should quote the strings read as another type",
+			value: []string{"true", "no", "10", "name"},
+			want:  "- \"true\"\n- \"no\"\n- \"10\"\n- name\n",
+		},
+		{
+			name:  "toYAML() should write the nested lists",
+			value: [][]int{{1, 2}, {}},
+			want:  "-\n  - 1\n  - 2\n- []\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toYAML(tt.value)
+			if err != nil {
+				t.Fatalf("toYAML() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("toYAML() = \n%v, want \n%v", string(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestOutput_print(t *testing.T) {
+	o := output{
+		value:  map[string]string{"id": "1"},
+		header: []string{"ID", "NAME"},
+		rows:   [][]string{{"1", "App"}, {"10", "Another app"}},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "print() should align the columns of a table",
+			format: outputTable,
+			want:   "ID   NAME\n1    App\n10   Another app\n",
+		},
+		{
+			name:   "print() should indent the JSON",
+			format: outputJSON,
+			want:   "{\n  \"id\": \"1\"\n}\n",
+		},
+		{
+			name:   "print() should write the YAML",
+			format: outputYAML,
+			want:   "id: \"1\"\n",
+		},
+		{
+			name:    "print() should fail on an unknown format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := o.print(w, tt.format); (err != nil) != tt.wantErr {
+				t.Fatalf("output.print() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := w.String(); got != tt.want {
+				t.Errorf("output.print() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// appsPage is the body of a page of apps
+type appsPage struct {
+	Apps       []models.App `json:"apps"`
+	NextCursor string       `json:"nextCursor"`
+}
+
+// version is a version in the body of UpdateAppVersions
+type version struct {
+	ID              string `json:"id"`
+	AppID           string `json:"appId"`
+	Version         string `json:"version"`
+	Disabled        bool   `json:"disabled"`
+	DisabledMessage string `json:"disabledMessage"`
+}
+
+// GetApps returns a page of apps. The following page is fetched with the NextCursor of the page as the Cursor of the query.
+func (c *Client) GetApps(ctx context.Context, query models.AppsQuery) (*models.AppsPage, error) {
+	params := url.Values{}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.Cursor != "" {
+		params.Set("cursor", query.Cursor)
+	}
+	if query.Sort != "" {
+		params.Set("sort", query.Sort)
+	}
+	if query.Search != "" {
+		params.Set("q", query.Search)
+	}
+	if query.Deleted {
+		params.Set("deleted", "true")
+	}
+
+	page := appsPage{}
+	if err := c.do(ctx, http.MethodGet, "/apps", params, nil, &page); err != nil {
+		return nil, err
+	}
+
+	return &models.AppsPage{Apps: page.Apps, NextCursor: page.NextCursor}, nil
+}
+
+// GetAllApps returns all the apps matching the query, fetching every page
+func (c *Client) GetAllApps(ctx context.Context, query models.AppsQuery) ([]models.App, error) {
+	apps := []models.App{}
+	for {
+		page, err := c.GetApps(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, page.Apps...)
+
+		if page.NextCursor == "" {
+			return apps, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// GetApp returns an active app with its versions
+func (c *Client) GetApp(ctx context.Context, id string) (*models.App, error) {
+	app := models.App{}
+	if err := c.do(ctx, http.MethodGet, "/apps/"+url.PathEscape(id), nil, nil, &app); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// CreateApp creates an app, or restores it when an app with the same appId was deleted
+func (c *Client) CreateApp(ctx context.Context, appID, appName string) error {
+	body := map[string]string{"appId": appID, "appName": appName}
+	return c.do(ctx, http.MethodPost, "/apps", nil, body, nil)
+}
+
+// UpdateAppName updates the name of an app
+func (c *Client) UpdateAppName(ctx context.Context, id, appName string) error {
+	body := map[string]string{"appName": appName}
+	return c.do(ctx, http.MethodPatch, "/apps/"+url.PathEscape(id), nil, body, nil)
+}
+
+// DeleteApp soft deletes an app
+func (c *Client) DeleteApp(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/apps/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// RestoreApp restores a soft deleted app
+func (c *Client) RestoreApp(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/apps/"+url.PathEscape(id)+"/restore", nil, nil, nil)
+}
+
+// HardDeleteApp permanently deletes an app with its versions and devices, released versions, signing certificates,
+// webhooks and alerts and returns them in an archive. On a dry run, only the numbers of what would be deleted are returned.
+// It requires an admin user.
+func (c *Client) HardDeleteApp(ctx context.Context, id string, dryRun bool) (*models.AppHardDelete, error) {
+	params := url.Values{}
+	if dryRun {
+		params.Set("dryRun", "true")
+	}
+
+	result := models.AppHardDelete{}
+	if err := c.do(ctx, http.MethodPost, "/apps/"+url.PathEscape(id)+"/purge", params, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateAppVersions updates the disabled state and message of versions of an app
+func (c *Client) UpdateAppVersions(ctx context.Context, id string, versions []models.Version) error {
+	body := make([]version, 0, len(versions))
+	for _, v := range versions {
+		body = append(body, version{
+			ID:              v.ID,
+			AppID:           v.AppID,
+			Version:         v.Version,
+			Disabled:        v.Disabled,
+			DisabledMessage: v.DisabledMessage,
+		})
+	}
+
+	return c.do(ctx, http.MethodPut, "/apps/"+url.PathEscape(id)+"/versions", nil, body, nil)
+}
+
+// DisableAppVersions disables all the versions of an app with the given message
+func (c *Client) DisableAppVersions(ctx context.Context, id, message string) error {
+	body := map[string]string{"disabledMessage": message}
+	return c.do(ctx, http.MethodPost, "/apps/"+url.PathEscape(id)+"/versions/disable", nil, body, nil)
+}
+
+// ExportApps returns the policy document of the active apps and of the state of their versions
+func (c *Client) ExportApps(ctx context.Context) (*models.AppsPolicy, error) {
+	policy := models.AppsPolicy{}
+	if err := c.do(ctx, http.MethodGet, "/export", nil, nil, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// ImportApps applies a policy document and returns the changes made.
+// When dryRun is true nothing is changed and the changes which would be made are returned.
+func (c *Client) ImportApps(ctx context.Context, policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error) {
+	params := url.Values{}
+	if dryRun {
+		params.Set("dryRun", "true")
+	}
+
+	result := models.PolicyImport{}
+	if err := c.do(ctx, http.MethodPost, "/import", params, policy, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
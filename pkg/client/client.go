@@ -0,0 +1,225 @@
+// Package client is a typed Go client of the REST API of the Mobile Security Service.
+// It calls the second version of the API and decodes its errors into the models errors,
+// so they can be matched with errors.Is, e.g. errors.Is(err, models.ErrNotFound).
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+const (
+	// apiVersion is the path of the version of the API called by the client under the API route prefix
+	apiVersion = "/v2"
+
+	// userNameHeader and userEmailHeader identify the user as the OAuth proxy in front of the service does
+	userNameHeader  = "X-Forwarded-User"
+	userEmailHeader = "X-Forwarded-Email"
+)
+
+// Options are the settings of a client
+type Options struct {
+	// HTTPClient sends the requests. http.DefaultClient is used when it is not set.
+	HTTPClient *http.Client
+	// Token is sent as a bearer token in the Authorization header, e.g. to go through the OAuth proxy
+	Token string
+	// Username and Email are sent in the headers set by the OAuth proxy, when the service is called without it
+	Username string
+	Email    string
+	// MaxRetries is the number of times a request which failed temporarily is sent again
+	MaxRetries int
+	// RetryWait is the time waited before the first retry. It doubles on each following retry.
+	RetryWait time.Duration
+}
+
+// Client calls the REST API of the service
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	options    Options
+}
+
+// NewClient returns a new client of the API served at the base URL, including the API route prefix,
+// e.g. http://localhost:3000/api
+func NewClient(baseURL string, options Options) *Client {
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: httpClient,
+		options:    options,
+	}
+}
+
+// do sends a request with the JSON of the body, when it is not nil, and decodes the JSON of the response into out,
+// when it is not nil. The requests which can be sent again safely are retried when they fail temporarily.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	target := c.baseURL + apiVersion + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		res, err := c.send(ctx, method, target, b)
+
+		if attempt < c.options.MaxRetries && isIdempotent(method) && isTemporary(res, err) {
+			wait := c.retryWait(attempt, res)
+			if res != nil {
+				drain(res.Body)
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+				continue
+			}
+		}
+
+		if err != nil {
+			return err
+		}
+		defer drain(res.Body)
+
+		if res.StatusCode >= http.StatusBadRequest {
+			return decodeError(method, path, res)
+		}
+
+		if out == nil || res.StatusCode == http.StatusNoContent {
+			return nil
+		}
+		return json.NewDecoder(res.Body).Decode(out)
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, target string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.options.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.options.Token)
+	}
+	if c.options.Username != "" {
+		req.Header.Set(userNameHeader, c.options.Username)
+	}
+	if c.options.Email != "" {
+		req.Header.Set(userEmailHeader, c.options.Email)
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// retryWait returns the time to wait before a retry: the Retry-After of the response, in seconds, or the doubled RetryWait
+func (c *Client) retryWait(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return c.options.RetryWait << uint(attempt)
+}
+
+// isIdempotent is true for the methods whose requests have the same effect when they are sent again
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isTemporary is true when a request failed to be sent or the service could not handle it for now
+func isTemporary(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// drain reads the rest of a body before closing it so the connection can be reused
+func drain(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, body)
+	body.Close()
+}
+
+// problem is the error body of the responses of the service
+type problem struct {
+	Code    models.ErrorCode    `json:"code"`
+	Detail  string              `json:"detail"`
+	Message string              `json:"message"`
+	Errors  []models.FieldError `json:"errors"`
+}
+
+// statusCodes maps the status codes of the responses without an error code to the models error codes
+var statusCodes = map[int]models.ErrorCode{
+	http.StatusBadRequest:          models.CodeBadParamInput,
+	http.StatusUnauthorized:        models.CodeUnauthorized,
+	http.StatusForbidden:           models.CodeForbidden,
+	http.StatusNotFound:            models.CodeNotFound,
+	http.StatusConflict:            models.CodeConflict,
+	http.StatusUnprocessableEntity: models.CodeValidationFailed,
+}
+
+// decodeError returns the models error of a response with an error status.
+// The status of the response is kept in the cause of the error.
+func decodeError(method, path string, res *http.Response) error {
+	p := problem{}
+	// a body which is not a problem, e.g. from a proxy, is described by the status only
+	json.NewDecoder(res.Body).Decode(&p)
+
+	code := p.Code
+	if code == "" {
+		var ok bool
+		if code, ok = statusCodes[res.StatusCode]; !ok {
+			code = models.CodeInternalServerError
+		}
+	}
+
+	message := p.Detail
+	if message == "" {
+		message = p.Message
+	}
+	if message == "" {
+		message = http.StatusText(res.StatusCode)
+	}
+
+	return &models.Error{
+		Code:    code,
+		Message: message,
+		Fields:  p.Errors,
+		Cause:   fmt.Errorf("%v %v returned %v", method, path, res.StatusCode),
+	}
+}
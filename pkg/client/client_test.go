@@ -0,0 +1,445 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+	"github.com/aerogear/mobile-security-service/pkg/web/initclient"
+	"github.com/aerogear/mobile-security-service/pkg/web/router"
+	"github.com/aerogear/mobile-security-service/pkg/web/tenants"
+	"github.com/aerogear/mobile-security-service/pkg/web/user"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/labstack/echo"
+)
+
+const appID = "7f89ce49-a736-459e-9110-e52d049fc027"
+
+// newTestServer returns a server with the routes of the API, whose requests and responses are validated against
+// the OpenAPI document. The requests go through the wrapper, when it is not nil, before reaching the router.
+// The router is built without NewRouter, which registers the Prometheus metrics.
+func newTestServer(appsService apps.Service, wrapper func(http.Handler) http.Handler) *httptest.Server {
+	config := config.Get()
+	config.AdminUsers = []string{"admin"}
+
+	e := echo.New()
+	e.Validator = validation.NewValidator()
+
+	appsHandler := apps.NewHTTPHandler(e, appsService)
+	userHandler := user.NewHTTPHandler(e)
+	initHandler := initclient.NewHTTPHandler(e, appsService, nil, nil)
+	requireAdmin := user.RequireAdmin(config.AdminUsers)
+	// the tenants are disabled, so the apps are not scoped
+	scopeApp := tenants.ScopeApp(&tenants.ServiceMock{})
+
+	validate := router.NewOpenAPIValidator(router.NewOpenAPI(config), config, true)
+	for _, g := range router.NewAPIV1Groups(e, config, validate) {
+		router.SetUserRoutes(g, userHandler)
+		router.SetAppRoutes(g, appsHandler, requireAdmin, scopeApp)
+		router.SetInitRoutes(g, initHandler)
+	}
+	v2 := router.NewAPIV2Group(e, config, validate)
+	router.SetUserRoutesV2(v2, userHandler)
+	router.SetAppRoutesV2(v2, appsHandler, requireAdmin, scopeApp)
+	router.SetInitRoutesV2(v2, initHandler)
+
+	var handler http.Handler = e
+	if wrapper != nil {
+		handler = wrapper(handler)
+	}
+	return httptest.NewServer(handler)
+}
+
+func newTestClient(server *httptest.Server) *Client {
+	return NewClient(server.URL+"/api", Options{Username: "admin", Email: "admin@example.com"})
+}
+
+func TestClient_Apps(t *testing.T) {
+	var got []interface{}
+	policy := models.AppsPolicy{Apps: []models.AppPolicy{{
+		AppID:    "com.aerogear.mobile_app_one",
+		AppName:  "App One",
+		Versions: []models.VersionPolicy{{Version: "1.0", Disabled: true, DisabledMessage: "Please update"}},
+	}}}
+	change := models.PolicyChange{Action: models.PolicyActionUpdate, AppID: "com.aerogear.mobile_app_one", Version: "1.0", Field: "disabled", From: "false", To: "true"}
+	appsService := &apps.ServiceMock{
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+			got = []interface{}{query}
+			if query.Cursor == "" {
+				return &models.AppsPage{Apps: helpers.GetMockAppList()[:2], NextCursor: "bmV4dA"}, nil
+			}
+			return &models.AppsPage{Apps: helpers.GetMockAppList()[2:]}, nil
+		},
+		GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
+			got = []interface{}{ID}
+			return helpers.GetMockApp(), nil
+		},
+		CreateAppFunc: func(app models.App) error {
+			got = []interface{}{app.AppID, app.AppName}
+			return nil
+		},
+		UpdateAppNameByIDFunc: func(id string, name string) error {
+			got = []interface{}{id, name}
+			return nil
+		},
+		DeleteAppByIdFunc: func(id string) error {
+			got = []interface{}{id}
+			return nil
+		},
+		RestoreAppByIDFunc: func(id string) error {
+			got = []interface{}{id}
+			return nil
+		},
+		HardDeleteAppByIDFunc: func(id string, dryRun bool) (*models.AppHardDelete, error) {
+			got = []interface{}{id, dryRun}
+			return &models.AppHardDelete{DryRun: dryRun, Versions: 2, Devices: 3}, nil
+		},
+		UpdateAppVersionsFunc: func(id string, versions []models.Version) error {
+			got = []interface{}{id, versions[0].ID, versions[0].Disabled}
+			return nil
+		},
+		DisableAllAppVersionsByAppIDFunc: func(id string, message string) error {
+			got = []interface{}{id, message}
+			return nil
+		},
+		ExportAppsFunc: func() (*models.AppsPolicy, error) {
+			got = []interface{}{}
+			return &policy, nil
+		},
+		ImportAppsFunc: func(policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error) {
+			got = []interface{}{policy, dryRun}
+			return &models.PolicyImport{DryRun: dryRun, Changes: []models.PolicyChange{change}}, nil
+		},
+	}
+	server := newTestServer(appsService, nil)
+	defer server.Close()
+	c := newTestClient(server)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		call     func() (interface{}, error)
+		want     interface{}
+		wantArgs []interface{}
+	}{
+		{
+			name: "GetApps() should return a page of apps",
+			call: func() (interface{}, error) {
+				page, err := c.GetApps(ctx, models.AppsQuery{Limit: 2, Sort: "-name", Search: "app"})
+				if err != nil {
+					return nil, err
+				}
+				return len(page.Apps), nil
+			},
+			want:     2,
+			wantArgs: []interface{}{models.AppsQuery{Limit: 2, Sort: "-name", Search: "app"}},
+		},
+		{
+			name: "GetAllApps() should fetch every page",
+			call: func() (interface{}, error) {
+				apps, err := c.GetAllApps(ctx, models.AppsQuery{Limit: 2})
+				return len(apps), err
+			},
+			want:     3,
+			wantArgs: []interface{}{models.AppsQuery{Limit: 2, Cursor: "bmV4dA"}},
+		},
+		{
+			name: "GetApp() should return the app with its versions",
+			call: func() (interface{}, error) {
+				app, err := c.GetApp(ctx, appID)
+				if err != nil {
+					return nil, err
+				}
+				return len(*app.DeployedVersions), nil
+			},
+			want:     len(helpers.GetMockAppVersionList()),
+			wantArgs: []interface{}{appID},
+		},
+		{
+			name: "CreateApp() should send the app",
+			call: func() (interface{}, error) {
+				return nil, c.CreateApp(ctx, "com.aerogear.app", "App")
+			},
+			wantArgs: []interface{}{"com.aerogear.app", "App"},
+		},
+		{
+			name: "UpdateAppName() should send the name",
+			call: func() (interface{}, error) {
+				return nil, c.UpdateAppName(ctx, appID, "New name")
+			},
+			wantArgs: []interface{}{appID, "New name"},
+		},
+		{
+			name: "DeleteApp() should delete the app",
+			call: func() (interface{}, error) {
+				return nil, c.DeleteApp(ctx, appID)
+			},
+			wantArgs: []interface{}{appID},
+		},
+		{
+			name: "RestoreApp() should restore the app",
+			call: func() (interface{}, error) {
+				return nil, c.RestoreApp(ctx, appID)
+			},
+			wantArgs: []interface{}{appID},
+		},
+		{
+			name: "HardDeleteApp() should return the counts of a dry run",
+			call: func() (interface{}, error) {
+				result, err := c.HardDeleteApp(ctx, appID, true)
+				if err != nil {
+					return nil, err
+				}
+				return *result, nil
+			},
+			want:     models.AppHardDelete{DryRun: true, Versions: 2, Devices: 3},
+			wantArgs: []interface{}{appID, true},
+		},
+		{
+			name: "UpdateAppVersions() should send the versions",
+			call: func() (interface{}, error) {
+				versions := helpers.GetMockAppVersionList()
+				versions[0].Disabled = true
+				return nil, c.UpdateAppVersions(ctx, appID, versions)
+			},
+			wantArgs: []interface{}{appID, helpers.GetMockAppVersionList()[0].ID, true},
+		},
+		{
+			name: "DisableAppVersions() should send the message",
+			call: func() (interface{}, error) {
+				return nil, c.DisableAppVersions(ctx, appID, "Please update")
+			},
+			wantArgs: []interface{}{appID, "Please update"},
+		},
+		{
+			name: "ExportApps() should return the policy document",
+			call: func() (interface{}, error) {
+				result, err := c.ExportApps(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return *result, nil
+			},
+			want:     policy,
+			wantArgs: []interface{}{},
+		},
+		{
+			name: "ImportApps() should send the policy document and return the changes",
+			call: func() (interface{}, error) {
+				result, err := c.ImportApps(ctx, policy, true)
+				if err != nil {
+					return nil, err
+				}
+				return *result, nil
+			},
+			want:     models.PolicyImport{DryRun: true, Changes: []models.PolicyChange{change}},
+			wantArgs: []interface{}{policy, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+
+			result, err := tt.call()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Errorf("result = %v, want %v", result, tt.want)
+			}
+
+			if !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("service called with %v, want %v", got, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestClient_InitClientApp(t *testing.T) {
+	var got *models.Device
+	want := helpers.GetMockVersion()
+	server := newTestServer(&apps.ServiceMock{
+		InitClientAppFunc: func(deviceInfo *models.Device) (*models.Version, error) {
+			got = deviceInfo
+			return want, nil
+		},
+	}, nil)
+	defer server.Close()
+
+	device := *helpers.GetMockDevice()
+	device.Version = "1.0"
+	version, err := newTestClient(server).InitClientApp(context.Background(), device)
+	if err != nil {
+		t.Fatalf("Client.InitClientApp() error = %v", err)
+	}
+
+	if got.DeviceID != device.DeviceID || got.AppID != device.AppID {
+		t.Errorf("Client.InitClientApp() sent %v, want %v", got, device)
+	}
+
+	if version.ID != want.ID || version.Disabled != want.Disabled {
+		t.Errorf("Client.InitClientApp() = %v, want %v", version, want)
+	}
+}
+
+func TestClient_GetUser(t *testing.T) {
+	var authorization string
+	server := newTestServer(&apps.ServiceMock{}, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			next.ServeHTTP(w, r)
+		})
+	})
+	defer server.Close()
+
+	c := NewClient(server.URL+"/api", Options{Token: "token", Username: "user", Email: "user@example.com"})
+	got, err := c.GetUser(context.Background())
+	if err != nil {
+		t.Fatalf("Client.GetUser() error = %v", err)
+	}
+
+	if want := (models.User{Username: "user", Email: "user@example.com"}); *got != want {
+		t.Errorf("Client.GetUser() = %v, want %v", got, want)
+	}
+	if authorization != "Bearer token" {
+		t.Errorf("Authorization = %v, want the bearer token", authorization)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	server := newTestServer(&apps.ServiceMock{
+		GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
+			return nil, models.ErrNotFound
+		},
+		CreateAppFunc: func(app models.App) error {
+			return models.ErrConflict
+		},
+	}, nil)
+	defer server.Close()
+	c := newTestClient(server)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		call       func() error
+		wantErr    error
+		wantFields []models.FieldError
+	}{
+		{
+			name:    "A missing app should be a not found error",
+			call:    func() error { _, err := c.GetApp(ctx, appID); return err },
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name:       "An invalid id should be a bad param input error with the field",
+			call:       func() error { _, err := c.GetApp(ctx, "1"); return err },
+			wantErr:    models.ErrBadParamInput,
+			wantFields: []models.FieldError{{Field: "id", Message: "id must be a valid UUID"}},
+		},
+		{
+			name:       "An invalid app should be a validation error with the field",
+			call:       func() error { return c.CreateApp(ctx, "app", "App") },
+			wantErr:    models.ErrValidationFailed,
+			wantFields: []models.FieldError{{Field: "appId", Message: "appId must match the pattern " + validation.AppIDPattern}},
+		},
+		{
+			name:    "An existing app should be a conflict error",
+			call:    func() error { return c.CreateApp(ctx, "com.aerogear.app", "App") },
+			wantErr: models.ErrConflict,
+		},
+		{
+			name: "A user who is not an admin should get a forbidden error",
+			call: func() error {
+				_, err := NewClient(server.URL+"/api", Options{Username: "user"}).HardDeleteApp(ctx, appID, false)
+				return err
+			},
+			wantErr: models.ErrForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			var modelErr *models.Error
+			errors.As(err, &modelErr)
+			if tt.wantFields != nil && !reflect.DeepEqual(modelErr.Fields, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", modelErr.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		call         func(c *Client) error
+		wantErr      error
+		wantRequests int32
+	}{
+		{
+			name:         "A GET should be retried until the service is available",
+			failures:     2,
+			call:         func(c *Client) error { _, err := c.GetApp(context.Background(), appID); return err },
+			wantRequests: 3,
+		},
+		{
+			name:         "A GET should fail when the service is still unavailable after the retries",
+			failures:     5,
+			call:         func(c *Client) error { _, err := c.GetApp(context.Background(), appID); return err },
+			wantErr:      models.ErrInternalServerError,
+			wantRequests: 4,
+		},
+		{
+			name:         "A POST which is not idempotent should not be retried",
+			failures:     1,
+			call:         func(c *Client) error { return c.CreateApp(context.Background(), "com.aerogear.app", "App") },
+			wantErr:      models.ErrInternalServerError,
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := newTestServer(&apps.ServiceMock{
+				GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
+					return helpers.GetMockApp(), nil
+				},
+				CreateAppFunc: func(app models.App) error {
+					return nil
+				},
+			}, func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if atomic.AddInt32(&requests, 1) <= tt.failures {
+						w.WriteHeader(http.StatusServiceUnavailable)
+						return
+					}
+					next.ServeHTTP(w, r)
+				})
+			})
+			defer server.Close()
+
+			c := NewClient(server.URL+"/api", Options{MaxRetries: 3, RetryWait: time.Millisecond})
+			if err := tt.call(c); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %v, want %v", requests, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// initRequest is the body of InitClientApp
+type initRequest struct {
+	AppID              string `json:"appId"`
+	DeviceID           string `json:"deviceId"`
+	Version            string `json:"version"`
+	DeviceVersion      string `json:"deviceVersion"`
+	DeviceType         string `json:"deviceType"`
+	SigningCertificate string `json:"signingCertificate,omitempty"`
+}
+
+// InitClientApp records the launch of an app by a device, as the SDKs do, and returns the state of its version
+func (c *Client) InitClientApp(ctx context.Context, device models.Device) (*models.Version, error) {
+	body := initRequest{
+		AppID:              device.AppID,
+		DeviceID:           device.DeviceID,
+		Version:            device.Version,
+		DeviceVersion:      device.DeviceVersion,
+		DeviceType:         device.DeviceType,
+		SigningCertificate: device.SigningCertificate,
+	}
+
+	version := models.Version{}
+	if err := c.do(ctx, http.MethodPost, "/init", nil, body, &version); err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
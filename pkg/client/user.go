@@ -0,0 +1,17 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// GetUser returns the user the requests are sent as
+func (c *Client) GetUser(ctx context.Context) (*models.User, error) {
+	user := models.User{}
+	if err := c.do(ctx, http.MethodGet, "/user", nil, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
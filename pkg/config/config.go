@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Config defines the top level server configuration properties
@@ -17,6 +18,21 @@ type Config struct {
 	StaticFilesDir string
 	APIRoutePrefix string
 	DB             DBConfig
+	Shutdown       ShutdownConfig
+	Health         HealthConfig
+	Purge          PurgeConfig
+	Policy         PolicyConfig
+	Webhooks       WebhooksConfig
+	Stream         StreamConfig
+	InitRateLimit  RateLimitConfig
+	Alerts         AlertsConfig
+	Tenants        TenantsConfig
+	Auth           AuthConfig
+	Metrics        MetricsConfig
+	// APIV1Sunset is when version 1 of the API will stop being served, sent in the Sunset header of its responses
+	APIV1Sunset time.Time
+	// AdminUsers are the usernames, as set by the oauth-proxy, allowed to run admin operations such as a hard delete
+	AdminUsers []string
 }
 
 // CORSConfig defines the CORS (Cross Origin Resouce Sharing) configuration properties
@@ -31,6 +47,132 @@ type DBConfig struct {
 	MaxConnections   int
 }
 
+// ShutdownConfig defines how the server stops when it receives a termination signal
+type ShutdownConfig struct {
+	// DrainDelay is how long the readiness probe reports the server as unavailable
+	// before it stops accepting connections, so the load balancer can stop routing to it
+	DrainDelay time.Duration
+	// Timeout is how long in-flight requests and background workers are given to finish
+	Timeout time.Duration
+}
+
+// HealthConfig defines the readiness probe configuration properties
+type HealthConfig struct {
+	// CheckTimeout is how long each dependency check may take before it is reported as down
+	CheckTimeout time.Duration
+}
+
+// PurgeConfig defines how soft deleted apps are permanently removed
+type PurgeConfig struct {
+	// Retention is how long an app stays soft deleted before it is purged. 0 disables the purge.
+	Retention time.Duration
+	// Interval is how often the soft deleted apps past the retention period are looked for
+	Interval time.Duration
+}
+
+// PolicyConfig defines the controller which reconciles the apps with the policy documents of a directory
+type PolicyConfig struct {
+	// Dir is the directory of the YAML policy documents. Empty disables the controller.
+	Dir string
+	// Interval is how often the directory is read and the apps reconciled with it
+	Interval time.Duration
+}
+
+// WebhooksConfig defines how the events are delivered to the webhooks
+type WebhooksConfig struct {
+	// DeliveryInterval is how often the pending deliveries are looked for
+	DeliveryInterval time.Duration
+	// Timeout is how long a webhook is given to respond to a delivery
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts after which a delivery is marked as failed
+	MaxAttempts int
+	// RetryBackoff is the delay before the first retry, doubled after each attempt
+	RetryBackoff time.Duration
+}
+
+// StreamConfig defines how the live updates of the apps are streamed to the dashboard
+type StreamConfig struct {
+	// LaunchesInterval is how often the launch counters of the apps are sent, aggregated since the last ones
+	LaunchesInterval time.Duration
+	// HeartbeatInterval is how often a comment is sent to keep idle streams open through the proxies
+	HeartbeatInterval time.Duration
+}
+
+// RateLimitConfig defines the rate limits of the unauthenticated /init endpoint, in requests per minute.
+// 0 disables a limit.
+type RateLimitConfig struct {
+	// Store is where the buckets of the limits are kept: memory, for each instance on its own,
+	// or postgres, shared by all the instances
+	Store string
+	// PerIP is the limit of the requests of a client IP
+	PerIP int
+	// PerApp is the limit of the requests of an app, by all of its devices
+	PerApp int
+	// PerDevice is the limit of the requests of a device
+	PerDevice int
+}
+
+// AlertsConfig defines the analysis of the init requests which raises alerts on suspicious patterns.
+// A threshold of 0 disables its check.
+type AlertsConfig struct {
+	// Interval is how often the recent init requests are analysed. 0 disables the analysis.
+	Interval time.Duration
+	// Window is the period of the recent init requests analysed
+	Window time.Duration
+	// NewDevices is the number of new devices of an app in a window from which a spike is raised
+	NewDevices int
+	// NewDevicesSpikeFactor is how many times more new devices than its average of the 24 previous windows
+	// an app needs in a window for a spike to be raised
+	NewDevicesSpikeFactor int
+	// UnreleasedVersionMaxDevices is the number of devices under which a version launched for the first time
+	// in the previous window is considered unreleased
+	UnreleasedVersionMaxDevices int
+	// DeviceOSVersions is the number of new OS versions of a device in a window from which it is raised
+	DeviceOSVersions int
+	// Retention is how long the alerts which are no longer detected are kept
+	Retention time.Duration
+}
+
+// TenantsConfig defines how the apps are shared between the tenants of the service
+type TenantsConfig struct {
+	// Enabled restricts the users to the apps of their tenants. Otherwise every user sees every app.
+	Enabled bool
+	// SuperAdmins are the usernames, as set by the oauth-proxy, who see the apps of every tenant and manage the tenants
+	SuperAdmins []string
+}
+
+// AuthConfig defines how the users of the API are identified: by the headers set by a trusted oauth-proxy
+// or by a bearer JWT signed by an OpenID Connect issuer
+type AuthConfig struct {
+	// TrustedProxies are the CIDRs of the proxies whose X-Forwarded-User and X-Forwarded-Email headers are trusted.
+	// When empty, the headers of every client are trusted unless the bearer tokens are validated.
+	TrustedProxies []string
+	OIDC           OIDCConfig
+}
+
+// OIDCConfig defines the validation of the bearer JWTs of the users
+type OIDCConfig struct {
+	// Issuer is the iss claim of the tokens. Empty disables the validation of the bearer tokens.
+	Issuer string
+	// Audience is the aud claim the tokens must have. Empty accepts any audience.
+	Audience string
+	// JWKSURL is where the signing keys are read from. Defaults to the jwks_uri of the discovery document of the issuer.
+	JWKSURL string
+	// JWKSFile is a file the signing keys are read from instead, e.g. for offline tests
+	JWKSFile string
+	// UsernameClaim is the claim of the tokens mapped to the username
+	UsernameClaim string
+	// EmailClaim is the claim of the tokens mapped to the email
+	EmailClaim string
+}
+
+// MetricsConfig defines the business metrics of the apps
+type MetricsConfig struct {
+	// MaxVersionsPerApp is the number of versions of an app which have their own label in the metrics.
+	// The launches of the other versions are counted under the "other" version.
+	MaxVersionsPerApp int
+}
+
 // Get the Config struct
 func Get() Config {
 	return Config{
@@ -43,10 +185,70 @@ func Get() Config {
 		},
 		StaticFilesDir: getEnv("STATIC_FILES_DIR", ""),
 		APIRoutePrefix: "/api", //should start with a "/",
+		APIV1Sunset:    getEnvTime("API_V1_SUNSET", time.Time{}),
 		DB: DBConfig{
 			ConnectionString: getDBConnectionString(),
 			MaxConnections:   getEnvInt("DB_MAX_CONNECTIONS", 100),
 		},
+		Shutdown: ShutdownConfig{
+			DrainDelay: getEnvDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+			Timeout:    getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		},
+		Health: HealthConfig{
+			CheckTimeout: getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		},
+		Purge: PurgeConfig{
+			Retention: getEnvDuration("DELETED_APPS_RETENTION", 0),
+			Interval:  getEnvInterval("DELETED_APPS_PURGE_INTERVAL", time.Hour),
+		},
+		Policy: PolicyConfig{
+			Dir:      getEnv("POLICY_DIR", ""),
+			Interval: getEnvInterval("POLICY_SYNC_INTERVAL", 30*time.Second),
+		},
+		Webhooks: WebhooksConfig{
+			DeliveryInterval: getEnvInterval("WEBHOOK_DELIVERY_INTERVAL", 5*time.Second),
+			Timeout:          getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			MaxAttempts:      getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			RetryBackoff:     getEnvDuration("WEBHOOK_RETRY_BACKOFF", 30*time.Second),
+		},
+		Stream: StreamConfig{
+			LaunchesInterval:  getEnvInterval("STREAM_LAUNCHES_INTERVAL", 2*time.Second),
+			HeartbeatInterval: getEnvInterval("STREAM_HEARTBEAT_INTERVAL", 15*time.Second),
+		},
+		InitRateLimit: RateLimitConfig{
+			Store:     strings.ToLower(getEnv("INIT_RATE_LIMIT_STORE", "memory")),
+			PerIP:     getEnvInt("INIT_RATE_LIMIT_PER_IP", 300),
+			PerApp:    getEnvInt("INIT_RATE_LIMIT_PER_APP", 6000),
+			PerDevice: getEnvInt("INIT_RATE_LIMIT_PER_DEVICE", 10),
+		},
+		Alerts: AlertsConfig{
+			Interval:                    getEnvDuration("ALERT_ANALYSIS_INTERVAL", 5*time.Minute),
+			Window:                      getEnvDuration("ALERT_WINDOW", time.Hour),
+			NewDevices:                  getEnvInt("ALERT_NEW_DEVICES_THRESHOLD", 100),
+			NewDevicesSpikeFactor:       getEnvInt("ALERT_NEW_DEVICES_SPIKE_FACTOR", 5),
+			UnreleasedVersionMaxDevices: getEnvInt("ALERT_UNRELEASED_VERSION_MAX_DEVICES", 3),
+			DeviceOSVersions:            getEnvInt("ALERT_DEVICE_OS_VERSIONS_THRESHOLD", 5),
+			Retention:                   getEnvDuration("ALERT_RETENTION", 30*24*time.Hour),
+		},
+		Tenants: TenantsConfig{
+			Enabled:     getEnvBool("TENANTS_ENABLED", false),
+			SuperAdmins: getEnvSlice("SUPER_ADMIN_USERS", []string{}, ","),
+		},
+		Auth: AuthConfig{
+			TrustedProxies: getEnvSlice("TRUSTED_PROXY_CIDRS", []string{}, ","),
+			OIDC: OIDCConfig{
+				Issuer:        getEnv("OIDC_ISSUER_URL", ""),
+				Audience:      getEnv("OIDC_AUDIENCE", ""),
+				JWKSURL:       getEnv("OIDC_JWKS_URL", ""),
+				JWKSFile:      getEnv("OIDC_JWKS_FILE", ""),
+				UsernameClaim: getEnv("OIDC_USERNAME_CLAIM", "preferred_username"),
+				EmailClaim:    getEnv("OIDC_EMAIL_CLAIM", "email"),
+			},
+		},
+		Metrics: MetricsConfig{
+			MaxVersionsPerApp: getEnvInt("METRICS_MAX_VERSIONS_PER_APP", 20),
+		},
+		AdminUsers: getEnvSlice("ADMIN_USERS", []string{}, ","),
 	}
 }
 
@@ -109,6 +311,32 @@ func getEnvBool(name string, defaultVal bool) bool {
 	return defaultVal
 }
 
+// Helper to read an environment variable into a time.Duration (e.g. "30s") or return default value
+func getEnvDuration(name string, defaultVal time.Duration) time.Duration {
+	valStr := getEnv(name, "")
+	if val, err := time.ParseDuration(valStr); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
+// Helper to read an environment variable into the interval of a ticker, which must be positive, or return default value
+func getEnvInterval(name string, defaultVal time.Duration) time.Duration {
+	if val := getEnvDuration(name, defaultVal); val > 0 {
+		return val
+	}
+	return defaultVal
+}
+
+// Helper to read an environment variable into a time.Time (RFC 3339, e.g. "2020-06-30T00:00:00Z") or return default value
+func getEnvTime(name string, defaultVal time.Time) time.Time {
+	valStr := getEnv(name, "")
+	if val, err := time.Parse(time.RFC3339, valStr); err == nil {
+		return val
+	}
+	return defaultVal
+}
+
 // Helper to read an environment variable into a string slice or return default value
 func getEnvSlice(name string, defaultVal []string, sep string) []string {
 	valStr := getEnv(name, "")
@@ -4,6 +4,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestGet(t *testing.T) {
@@ -21,6 +22,61 @@ func TestGet(t *testing.T) {
 			ConnectionString: "connect_timeout=5 dbname=mobile_security_service host=localhost password=postgres port=5432 sslmode=disable user=postgresql",
 			MaxConnections:   100,
 		},
+		Shutdown: ShutdownConfig{
+			DrainDelay: 5 * time.Second,
+			Timeout:    30 * time.Second,
+		},
+		Health: HealthConfig{
+			CheckTimeout: 2 * time.Second,
+		},
+		Purge: PurgeConfig{
+			Retention: 0,
+			Interval:  time.Hour,
+		},
+		Policy: PolicyConfig{
+			Dir:      "",
+			Interval: 30 * time.Second,
+		},
+		Webhooks: WebhooksConfig{
+			DeliveryInterval: 5 * time.Second,
+			Timeout:          10 * time.Second,
+			MaxAttempts:      8,
+			RetryBackoff:     30 * time.Second,
+		},
+		Stream: StreamConfig{
+			LaunchesInterval:  2 * time.Second,
+			HeartbeatInterval: 15 * time.Second,
+		},
+		InitRateLimit: RateLimitConfig{
+			Store:     "memory",
+			PerIP:     300,
+			PerApp:    6000,
+			PerDevice: 10,
+		},
+		Alerts: AlertsConfig{
+			Interval:                    5 * time.Minute,
+			Window:                      time.Hour,
+			NewDevices:                  100,
+			NewDevicesSpikeFactor:       5,
+			UnreleasedVersionMaxDevices: 3,
+			DeviceOSVersions:            5,
+			Retention:                   30 * 24 * time.Hour,
+		},
+		Tenants: TenantsConfig{
+			Enabled:     false,
+			SuperAdmins: []string{},
+		},
+		Auth: AuthConfig{
+			TrustedProxies: []string{},
+			OIDC: OIDCConfig{
+				UsernameClaim: "preferred_username",
+				EmailClaim:    "email",
+			},
+		},
+		Metrics: MetricsConfig{
+			MaxVersionsPerApp: 20,
+		},
+		AdminUsers: []string{},
 	}
 
 	tests := []struct {
@@ -48,50 +104,193 @@ func TestGet(t *testing.T) {
 					ConnectionString: "connect_timeout=5 dbname=mobile_security_service host=localhost password=postgres port=5432 sslmode=disable user=postgresql",
 					MaxConnections:   100,
 				},
+				Shutdown: ShutdownConfig{
+					DrainDelay: 0,
+					Timeout:    time.Minute,
+				},
+				Health: HealthConfig{
+					CheckTimeout: 500 * time.Millisecond,
+				},
+				Purge: PurgeConfig{
+					Retention: 720 * time.Hour,
+					Interval:  15 * time.Minute,
+				},
+				Policy: PolicyConfig{
+					Dir:      "/etc/mobile-security-service/policy",
+					Interval: time.Minute,
+				},
+				Webhooks: WebhooksConfig{
+					DeliveryInterval: time.Second,
+					Timeout:          5 * time.Second,
+					MaxAttempts:      3,
+					RetryBackoff:     time.Minute,
+				},
+				Stream: StreamConfig{
+					LaunchesInterval:  5 * time.Second,
+					HeartbeatInterval: 30 * time.Second,
+				},
+				InitRateLimit: RateLimitConfig{
+					Store:     "postgres",
+					PerIP:     60,
+					PerApp:    0,
+					PerDevice: 5,
+				},
+				Alerts: AlertsConfig{
+					Interval:                    time.Minute,
+					Window:                      30 * time.Minute,
+					NewDevices:                  50,
+					NewDevicesSpikeFactor:       10,
+					UnreleasedVersionMaxDevices: 0,
+					DeviceOSVersions:            3,
+					Retention:                   24 * time.Hour,
+				},
+				Tenants: TenantsConfig{
+					Enabled:     true,
+					SuperAdmins: []string{"root"},
+				},
+				Auth: AuthConfig{
+					TrustedProxies: []string{"10.0.0.0/8", "127.0.0.1/32"},
+					OIDC: OIDCConfig{
+						Issuer:        "https://sso.example.com/auth/realms/mobile",
+						Audience:      "mobile-security-service",
+						JWKSURL:       "https://sso.example.com/auth/realms/mobile/protocol/openid-connect/certs",
+						JWKSFile:      "/etc/mobile-security-service/jwks.json",
+						UsernameClaim: "sub",
+						EmailClaim:    "mail",
+					},
+				},
+				Metrics: MetricsConfig{
+					MaxVersionsPerApp: 50,
+				},
+				APIV1Sunset: time.Date(2020, time.June, 30, 0, 0, 0, 0, time.UTC),
+				AdminUsers:  []string{"admin", "other-admin"},
 			},
 			envVars: map[string]string{
-				"PORT":                             "4000",
-				"LOG_LEVEL":                        "error",
-				"LOG_FORMAT":                       "json",
-				"ACCESS_CONTROL_ALLOW_ORIGIN":      "http://localhost:1234,http://localhost:2345",
-				"ACCESS_CONTROL_ALLOW_CREDENTIALS": "false",
-				"STATIC_FILES_DIR":                 "static",
-				"PGDATABASE":                       "mobile_security_service",
-				"PGUSER":                           "postgresql",
-				"PGPASSWORD":                       "postgres",
-				"PGHOST":                           "localhost",
-				"PGPORT":                           "5432",
-				"PGSSLMODE":                        "disable",
-				"PGCONNECT_TIMEOUT":                "5",
-				"PGAPPNAME":                        "",
-				"PGSSLCERT":                        "",
-				"PGSSLKEY":                         "",
-				"PGSSLROOTCERT":                    "",
-				"DB_MAX_CONNECTIONS":               "100",
+				"PORT":                                 "4000",
+				"LOG_LEVEL":                            "error",
+				"LOG_FORMAT":                           "json",
+				"ACCESS_CONTROL_ALLOW_ORIGIN":          "http://localhost:1234,http://localhost:2345",
+				"ACCESS_CONTROL_ALLOW_CREDENTIALS":     "false",
+				"STATIC_FILES_DIR":                     "static",
+				"PGDATABASE":                           "mobile_security_service",
+				"PGUSER":                               "postgresql",
+				"PGPASSWORD":                           "postgres",
+				"PGHOST":                               "localhost",
+				"PGPORT":                               "5432",
+				"PGSSLMODE":                            "disable",
+				"PGCONNECT_TIMEOUT":                    "5",
+				"PGAPPNAME":                            "",
+				"PGSSLCERT":                            "",
+				"PGSSLKEY":                             "",
+				"PGSSLROOTCERT":                        "",
+				"DB_MAX_CONNECTIONS":                   "100",
+				"SHUTDOWN_DRAIN_DELAY":                 "0s",
+				"SHUTDOWN_TIMEOUT":                     "1m",
+				"HEALTH_CHECK_TIMEOUT":                 "500ms",
+				"DELETED_APPS_RETENTION":               "720h",
+				"DELETED_APPS_PURGE_INTERVAL":          "15m",
+				"POLICY_DIR":                           "/etc/mobile-security-service/policy",
+				"POLICY_SYNC_INTERVAL":                 "1m",
+				"WEBHOOK_DELIVERY_INTERVAL":            "1s",
+				"WEBHOOK_TIMEOUT":                      "5s",
+				"WEBHOOK_MAX_ATTEMPTS":                 "3",
+				"WEBHOOK_RETRY_BACKOFF":                "1m",
+				"STREAM_LAUNCHES_INTERVAL":             "5s",
+				"STREAM_HEARTBEAT_INTERVAL":            "30s",
+				"INIT_RATE_LIMIT_STORE":                "Postgres",
+				"INIT_RATE_LIMIT_PER_IP":               "60",
+				"INIT_RATE_LIMIT_PER_APP":              "0",
+				"INIT_RATE_LIMIT_PER_DEVICE":           "5",
+				"ALERT_ANALYSIS_INTERVAL":              "1m",
+				"ALERT_WINDOW":                         "30m",
+				"ALERT_NEW_DEVICES_THRESHOLD":          "50",
+				"ALERT_NEW_DEVICES_SPIKE_FACTOR":       "10",
+				"ALERT_UNRELEASED_VERSION_MAX_DEVICES": "0",
+				"ALERT_DEVICE_OS_VERSIONS_THRESHOLD":   "3",
+				"ALERT_RETENTION":                      "24h",
+				"ADMIN_USERS":                          "admin,other-admin",
+				"TENANTS_ENABLED":                      "true",
+				"SUPER_ADMIN_USERS":                    "root",
+				"TRUSTED_PROXY_CIDRS":                  "10.0.0.0/8,127.0.0.1/32",
+				"OIDC_ISSUER_URL":                      "https://sso.example.com/auth/realms/mobile",
+				"OIDC_AUDIENCE":                        "mobile-security-service",
+				"OIDC_JWKS_URL":                        "https://sso.example.com/auth/realms/mobile/protocol/openid-connect/certs",
+				"OIDC_JWKS_FILE":                       "/etc/mobile-security-service/jwks.json",
+				"OIDC_USERNAME_CLAIM":                  "sub",
+				"OIDC_EMAIL_CLAIM":                     "mail",
+				"METRICS_MAX_VERSIONS_PER_APP":         "50",
+				"API_V1_SUNSET":                        "2020-06-30T00:00:00Z",
 			},
 		},
 		{
 			name: "Get() should return sensible defaults when empty environment variables are set",
 			want: defaultConfig,
 			envVars: map[string]string{
-				"PORT":                             "",
-				"LOG_LEVEL":                        "",
-				"LOG_FORMAT":                       "",
-				"ACCESS_CONTROL_ALLOW_ORIGIN":      "",
-				"ACCESS_CONTROL_ALLOW_CREDENTIALS": "",
-				"STATIC_FILES_DIR":                 "",
-				"PGDATABASE":                       "",
-				"PGUSER":                           "",
-				"PGPASSWORD":                       "",
-				"PGHOST":                           "",
-				"PGPORT":                           "",
-				"PGSSLMODE":                        "",
-				"PGCONNECT_TIMEOUT":                "",
-				"PGAPPNAME":                        "",
-				"PGSSLCERT":                        "",
-				"PGSSLKEY":                         "",
-				"PGSSLROOTCERT":                    "",
-				"DB_MAX_CONNECTIONS":               "",
+				"PORT":                                 "",
+				"LOG_LEVEL":                            "",
+				"LOG_FORMAT":                           "",
+				"ACCESS_CONTROL_ALLOW_ORIGIN":          "",
+				"ACCESS_CONTROL_ALLOW_CREDENTIALS":     "",
+				"STATIC_FILES_DIR":                     "",
+				"PGDATABASE":                           "",
+				"PGUSER":                               "",
+				"PGPASSWORD":                           "",
+				"PGHOST":                               "",
+				"PGPORT":                               "",
+				"PGSSLMODE":                            "",
+				"PGCONNECT_TIMEOUT":                    "",
+				"PGAPPNAME":                            "",
+				"PGSSLCERT":                            "",
+				"PGSSLKEY":                             "",
+				"PGSSLROOTCERT":                        "",
+				"DB_MAX_CONNECTIONS":                   "",
+				"SHUTDOWN_DRAIN_DELAY":                 "",
+				"SHUTDOWN_TIMEOUT":                     "",
+				"HEALTH_CHECK_TIMEOUT":                 "",
+				"DELETED_APPS_RETENTION":               "",
+				"DELETED_APPS_PURGE_INTERVAL":          "",
+				"POLICY_DIR":                           "",
+				"POLICY_SYNC_INTERVAL":                 "",
+				"WEBHOOK_DELIVERY_INTERVAL":            "",
+				"WEBHOOK_TIMEOUT":                      "",
+				"WEBHOOK_MAX_ATTEMPTS":                 "",
+				"WEBHOOK_RETRY_BACKOFF":                "",
+				"STREAM_LAUNCHES_INTERVAL":             "",
+				"STREAM_HEARTBEAT_INTERVAL":            "",
+				"INIT_RATE_LIMIT_STORE":                "",
+				"INIT_RATE_LIMIT_PER_IP":               "",
+				"INIT_RATE_LIMIT_PER_APP":              "",
+				"INIT_RATE_LIMIT_PER_DEVICE":           "",
+				"ALERT_ANALYSIS_INTERVAL":              "",
+				"ALERT_WINDOW":                         "",
+				"ALERT_NEW_DEVICES_THRESHOLD":          "",
+				"ALERT_NEW_DEVICES_SPIKE_FACTOR":       "",
+				"ALERT_UNRELEASED_VERSION_MAX_DEVICES": "",
+				"ALERT_DEVICE_OS_VERSIONS_THRESHOLD":   "",
+				"ALERT_RETENTION":                      "",
+				"ADMIN_USERS":                          "",
+				"TENANTS_ENABLED":                      "",
+				"SUPER_ADMIN_USERS":                    "",
+				"TRUSTED_PROXY_CIDRS":                  "",
+				"OIDC_ISSUER_URL":                      "",
+				"OIDC_AUDIENCE":                        "",
+				"OIDC_JWKS_URL":                        "",
+				"OIDC_JWKS_FILE":                       "",
+				"OIDC_USERNAME_CLAIM":                  "",
+				"OIDC_EMAIL_CLAIM":                     "",
+				"METRICS_MAX_VERSIONS_PER_APP":         "",
+				"API_V1_SUNSET":                        "",
+			},
+		},
+		{
+			name: "Get() should return the default intervals when they are not positive",
+			want: defaultConfig,
+			envVars: map[string]string{
+				"STREAM_LAUNCHES_INTERVAL":    "0s",
+				"STREAM_HEARTBEAT_INTERVAL":   "-1m",
+				"WEBHOOK_DELIVERY_INTERVAL":   "0s",
+				"POLICY_SYNC_INTERVAL":        "0s",
+				"DELETED_APPS_PURGE_INTERVAL": "0s",
 			},
 		},
 	}
@@ -191,6 +390,83 @@ func Test_getEnvInt(t *testing.T) {
 	}
 }
 
+func Test_getEnvDuration(t *testing.T) {
+	type args struct {
+		name       string
+		defaultVal time.Duration
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   time.Duration
+		envVar string
+	}{
+		{
+			name: "getEnvDuration() should return default value when no environment variable is set",
+			args: args{"SHUTDOWN_TIMEOUT", 30 * time.Second},
+			want: 30 * time.Second,
+		},
+		{
+			name:   "getEnvDuration() should return environment variable value when set instead of default value",
+			args:   args{"SHUTDOWN_TIMEOUT", 30 * time.Second},
+			want:   2 * time.Minute,
+			envVar: "2m",
+		},
+		{
+			name:   "getEnvDuration() should return default value when an invalid duration is set",
+			args:   args{"SHUTDOWN_TIMEOUT", 30 * time.Second},
+			want:   30 * time.Second,
+			envVar: "thirty seconds",
+		},
+	}
+	for _, tt := range tests {
+		if len(tt.envVar) > 0 {
+			os.Setenv(tt.args.name, tt.envVar)
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getEnvDuration(tt.args.name, tt.args.defaultVal); got != tt.want {
+				t.Errorf("getEnvDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	os.Setenv("SHUTDOWN_TIMEOUT", "")
+}
+
+func Test_getEnvInterval(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   time.Duration
+		envVar string
+	}{
+		{
+			name:   "getEnvInterval() should return environment variable value when it is positive",
+			want:   2 * time.Minute,
+			envVar: "2m",
+		},
+		{
+			name:   "getEnvInterval() should return default value when a zero interval is set",
+			want:   30 * time.Second,
+			envVar: "0s",
+		},
+		{
+			name:   "getEnvInterval() should return default value when a negative interval is set",
+			want:   30 * time.Second,
+			envVar: "-5s",
+		},
+	}
+	for _, tt := range tests {
+		os.Setenv("STREAM_LAUNCHES_INTERVAL", tt.envVar)
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getEnvInterval("STREAM_LAUNCHES_INTERVAL", 30*time.Second); got != tt.want {
+				t.Errorf("getEnvInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	os.Setenv("STREAM_LAUNCHES_INTERVAL", "")
+}
+
 func Test_getEnvSlice(t *testing.T) {
 	type args struct {
 		name       string
@@ -285,3 +561,46 @@ func Test_getDBConnectionString(t *testing.T) {
 		})
 	}
 }
+
+func Test_getEnvTime(t *testing.T) {
+	type args struct {
+		name       string
+		defaultVal time.Time
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   time.Time
+		envVar string
+	}{
+		{
+			name: "getEnvTime() should return default value when no environment variable is set",
+			args: args{"API_V1_SUNSET", time.Time{}},
+			want: time.Time{},
+		},
+		{
+			name:   "getEnvTime() should return environment variable value when set instead of default value",
+			args:   args{"API_V1_SUNSET", time.Time{}},
+			want:   time.Date(2020, time.June, 30, 0, 0, 0, 0, time.UTC),
+			envVar: "2020-06-30T00:00:00Z",
+		},
+		{
+			name:   "getEnvTime() should return default value when an invalid time is set",
+			args:   args{"API_V1_SUNSET", time.Time{}},
+			want:   time.Time{},
+			envVar: "30/06/2020",
+		},
+	}
+	for _, tt := range tests {
+		if len(tt.envVar) > 0 {
+			os.Setenv(tt.args.name, tt.envVar)
+		}
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getEnvTime(tt.args.name, tt.args.defaultVal); !got.Equal(tt.want) {
+				t.Errorf("getEnvTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	os.Setenv("API_V1_SUNSET", "")
+}
@@ -1,8 +1,10 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	// Import the PostgreSQL driver which is used in the background
@@ -32,8 +34,156 @@ func Connect(connString string, maxConnections int) (*sql.DB, error) {
 	return nil, err
 }
 
+// migrations holds the schema changes applied by Setup, in order. The position
+// of a migration in the list (starting at 1) is its schema version.
+// Never edit or reorder an existing migration, append a new one instead.
+var migrations = []string{
+	// 1: initial schema
+	`
+	CREATE TABLE IF NOT EXISTS app (
+		id uuid NOT NULL PRIMARY KEY,
+		app_id character varying NOT NULL UNIQUE,
+		app_name character varying,
+		deleted_at timestamptz
+	);
+	CREATE TABLE IF NOT EXISTS version (
+		id uuid NOT NULL PRIMARY KEY,
+		version character varying NOT NULL,
+		app_id character varying NOT NULL REFERENCES app(app_id),
+		disabled boolean DEFAULT false NOT NULL,
+		disabled_message character varying,
+		num_of_app_launches integer DEFAULT 1 NOT NULL,
+		last_launched_at timestamptz NOT NULL default now(),
+		unique (app_id, version)
+	);
+	CREATE TABLE IF NOT EXISTS device (
+		id uuid NOT NULL PRIMARY KEY,
+		version_id uuid NOT NULL REFERENCES version(id),
+		app_id character varying NOT NULL,
+		device_id character varying NOT NULL,
+		device_type character varying NOT NULL,
+		device_version character varying NOT NULL
+	);`,
+	// 2: apps managed by the policy controller
+	`
+	ALTER TABLE app ADD COLUMN IF NOT EXISTS managed boolean DEFAULT false NOT NULL;`,
+	// 3: webhooks and the outbox of their deliveries
+	`
+	CREATE TABLE IF NOT EXISTS webhook (
+		id uuid NOT NULL PRIMARY KEY,
+		app_id character varying NOT NULL REFERENCES app(app_id) ON DELETE CASCADE,
+		url character varying NOT NULL,
+		secret character varying NOT NULL,
+		events character varying[] NOT NULL,
+		created_at timestamptz NOT NULL default now()
+	);
+	CREATE TABLE IF NOT EXISTS webhook_delivery (
+		id uuid NOT NULL PRIMARY KEY,
+		webhook_id uuid NOT NULL REFERENCES webhook(id) ON DELETE CASCADE,
+		event_id uuid NOT NULL,
+		event_type character varying NOT NULL,
+		payload jsonb NOT NULL,
+		status character varying NOT NULL,
+		attempts integer DEFAULT 0 NOT NULL,
+		next_attempt_at timestamptz NOT NULL,
+		last_attempt_at timestamptz,
+		response_status integer DEFAULT 0 NOT NULL,
+		last_error character varying DEFAULT '' NOT NULL,
+		replay_of uuid,
+		created_at timestamptz NOT NULL default now(),
+		delivered_at timestamptz
+	);
+	CREATE INDEX IF NOT EXISTS webhook_delivery_due_idx ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+	CREATE INDEX IF NOT EXISTS webhook_delivery_webhook_idx ON webhook_delivery (webhook_id, created_at);`,
+	// 4: buckets of the rate limits shared by the instances of the service
+	`
+	CREATE TABLE IF NOT EXISTS rate_limit_bucket (
+		key character varying NOT NULL PRIMARY KEY,
+		tokens double precision NOT NULL,
+		updated_at timestamptz NOT NULL,
+		full_at timestamptz NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS rate_limit_bucket_full_idx ON rate_limit_bucket (full_at);`,
+	// 5: history of the init requests and the alerts raised by their analysis. The devices and versions
+	// created before have no creation time, so they are left out of the analysis.
+	`
+	ALTER TABLE device ADD COLUMN IF NOT EXISTS created_at timestamptz;
+	ALTER TABLE device ALTER COLUMN created_at SET DEFAULT now();
+	CREATE INDEX IF NOT EXISTS device_created_at_idx ON device (created_at);
+	ALTER TABLE version ADD COLUMN IF NOT EXISTS created_at timestamptz;
+	ALTER TABLE version ALTER COLUMN created_at SET DEFAULT now();
+	CREATE TABLE IF NOT EXISTS device_os_version (
+		device_id uuid NOT NULL REFERENCES device(id) ON DELETE CASCADE,
+		device_version character varying NOT NULL,
+		first_seen_at timestamptz NOT NULL default now(),
+		PRIMARY KEY (device_id, device_version)
+	);
+	CREATE INDEX IF NOT EXISTS device_os_version_first_seen_idx ON device_os_version (first_seen_at);
+	CREATE TABLE IF NOT EXISTS alert (
+		id uuid NOT NULL PRIMARY KEY,
+		app_id character varying NOT NULL REFERENCES app(app_id) ON DELETE CASCADE,
+		kind character varying NOT NULL,
+		subject character varying NOT NULL,
+		value bigint NOT NULL,
+		threshold bigint NOT NULL,
+		first_detected_at timestamptz NOT NULL,
+		last_detected_at timestamptz NOT NULL,
+		unique (app_id, kind, subject)
+	);`,
+	// 6: released versions declared for the apps and what the init endpoint does with the other versions
+	`
+	ALTER TABLE app ADD COLUMN IF NOT EXISTS unknown_versions character varying DEFAULT 'flag' NOT NULL;
+	CREATE TABLE IF NOT EXISTS released_version (
+		app_id character varying NOT NULL REFERENCES app(app_id) ON DELETE CASCADE,
+		version character varying NOT NULL,
+		declared_at timestamptz NOT NULL default now(),
+		PRIMARY KEY (app_id, version)
+	);`,
+	// 7: signing certificates of the builds of the apps and what the init endpoint does with the tampered builds
+	`
+	ALTER TABLE app ADD COLUMN IF NOT EXISTS tampered_builds character varying DEFAULT 'flag' NOT NULL;
+	CREATE TABLE IF NOT EXISTS signing_certificate (
+		id uuid NOT NULL PRIMARY KEY,
+		app_id character varying NOT NULL REFERENCES app(app_id) ON DELETE CASCADE,
+		platform character varying NOT NULL,
+		fingerprint character varying NOT NULL,
+		description character varying NOT NULL DEFAULT '',
+		created_at timestamptz NOT NULL DEFAULT now(),
+		unique (app_id, platform, fingerprint)
+	);`,
+	// 8: the tenants owning the apps, with their members. The existing apps belong to the default tenant.
+	`
+	CREATE TABLE IF NOT EXISTS tenant (
+		id uuid NOT NULL PRIMARY KEY,
+		name character varying NOT NULL UNIQUE,
+		created_at timestamptz NOT NULL DEFAULT now()
+	);
+	INSERT INTO tenant (id, name) VALUES ('00000000-0000-4000-8000-000000000001', 'default') ON CONFLICT DO NOTHING;
+	CREATE TABLE IF NOT EXISTS tenant_member (
+		tenant_id uuid NOT NULL REFERENCES tenant(id) ON DELETE CASCADE,
+		username character varying NOT NULL,
+		PRIMARY KEY (tenant_id, username)
+	);
+	CREATE INDEX IF NOT EXISTS tenant_member_username_idx ON tenant_member (username);
+	ALTER TABLE app ADD COLUMN IF NOT EXISTS tenant_id uuid NOT NULL DEFAULT '00000000-0000-4000-8000-000000000001' REFERENCES tenant(id);
+	CREATE INDEX IF NOT EXISTS app_tenant_id_idx ON app (tenant_id);`,
+}
+
+// SchemaVersion returns the schema version this build of the server expects
+func SchemaVersion() int {
+	return len(migrations)
+}
+
+// CurrentSchemaVersion returns the schema version applied to the database
+func CurrentSchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations;`).Scan(&version)
+	return version, err
+}
+
 // Setup uses the existing database connection to create
-// the necessary tables for the API (if they don't exist).
+// the necessary tables for the API (if they don't exist)
+// and applies any pending migration.
 func Setup(db *sql.DB) error {
 	if db == nil {
 		return errors.New("cannot setup database, must call Connect() first")
@@ -41,33 +191,50 @@ func Setup(db *sql.DB) error {
 
 	if _, err := db.Exec(`
 		SET TIME ZONE 'UTC';
-		CREATE TABLE IF NOT EXISTS app (
-			id uuid NOT NULL PRIMARY KEY,
-			app_id character varying NOT NULL UNIQUE,
-			app_name character varying,
-			deleted_at timestamptz
-		);
-		CREATE TABLE IF NOT EXISTS version (
-			id uuid NOT NULL PRIMARY KEY,
-			version character varying NOT NULL,
-			app_id character varying NOT NULL REFERENCES app(app_id),
-			disabled boolean DEFAULT false NOT NULL,
-			disabled_message character varying,
-			num_of_app_launches integer DEFAULT 1 NOT NULL,
-			last_launched_at timestamptz NOT NULL default now(),
-			unique (app_id, version)
-		);
-		CREATE TABLE IF NOT EXISTS device (
-			id uuid NOT NULL PRIMARY KEY,
-			version_id uuid NOT NULL REFERENCES version(id),
-			app_id character varying NOT NULL,
-			device_id character varying NOT NULL,
-			device_type character varying NOT NULL,
-			device_version character varying NOT NULL
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version integer NOT NULL PRIMARY KEY,
+			applied_at timestamptz NOT NULL default now()
 		);
 	`); err != nil {
 		return err
 	}
 
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := migrate(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%v (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrate applies the pending migrations inside the given transaction. The lock
+// prevents several replicas starting at the same time from migrating concurrently.
+func migrate(tx *sql.Tx) error {
+	if _, err := tx.Exec(`LOCK TABLE schema_migrations IN EXCLUSIVE MODE;`); err != nil {
+		return err
+	}
+
+	var current int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations;`).Scan(&current); err != nil {
+		return err
+	}
+
+	for i := current; i < len(migrations); i++ {
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %v", i+1, err)
+		}
+
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES ($1);`, i+1); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
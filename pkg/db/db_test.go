@@ -3,6 +3,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
@@ -129,6 +130,16 @@ func TestSetup(t *testing.T) {
 				if !exists {
 					t.Error("Expected table version does not exist")
 				}
+
+				version, err := CurrentSchemaVersion(context.Background(), db)
+
+				if err != nil {
+					t.Errorf("Database returned an error while reading the schema version: %v", err.Error())
+				}
+
+				if version != SchemaVersion() {
+					t.Errorf("CurrentSchemaVersion() = %v, want %v", version, SchemaVersion())
+				}
 			}
 		})
 	}
@@ -0,0 +1,146 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// queueSize is the number of events queued for each asynchronous subscriber before new ones are dropped
+const queueSize = 1024
+
+// Handler reacts to the events given to a subscriber
+type Handler interface {
+	Handle(event Event) error
+}
+
+// HandlerFunc allows the use of ordinary functions as a Handler
+type HandlerFunc func(event Event) error
+
+// Handle calls f(event)
+func (f HandlerFunc) Handle(event Event) error {
+	return f(event)
+}
+
+// Bus is an in-process publisher of the events. Its subscribers are registered at startup, before the
+// events are published. The synchronous subscribers handle each event in Publish, in the order they were
+// registered, and the asynchronous ones in their own goroutine started by Run.
+type Bus struct {
+	mu    sync.RWMutex
+	sync  []subscriber
+	async []asyncSubscriber
+}
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+type asyncSubscriber struct {
+	subscriber
+	queue chan Event
+}
+
+// NewBus returns a new Bus without subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a synchronous subscriber, which handles the events before Publish returns
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sync = append(b.sync, subscriber{name: name, handler: h})
+}
+
+// SubscribeAsync registers an asynchronous subscriber, which handles the events in order in the background
+// so it does not slow down the changes. The events are lost when its queue is full or the process exits.
+func (b *Bus) SubscribeAsync(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.async = append(b.async, asyncSubscriber{
+		subscriber: subscriber{name: name, handler: h},
+		queue:      make(chan Event, queueSize),
+	})
+}
+
+// Publish gives an event to all the subscribers. The change which emitted the event is already made,
+// so the errors of the subscribers are logged and not returned.
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, s := range b.sync {
+		s.handle(event)
+	}
+
+	for _, s := range b.async {
+		select {
+		case s.queue <- event:
+		default:
+			log.WithFields(log.Fields{"subscriber": s.name, "event": event.Name()}).Error("The queue of the subscriber is full, the event is dropped")
+		}
+	}
+}
+
+// Run implements the lifecycle.Worker contract. It handles the events of the asynchronous subscribers until
+// the context is cancelled, then the events already queued before it returns.
+func (b *Bus) Run(ctx context.Context) {
+	b.mu.RLock()
+	subscribers := b.async
+	b.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, s := range subscribers {
+		wg.Add(1)
+		go func(s asyncSubscriber) {
+			defer wg.Done()
+			s.run(ctx)
+		}(s)
+	}
+	wg.Wait()
+}
+
+func (s asyncSubscriber) run(ctx context.Context) {
+	for {
+		select {
+		case event := <-s.queue:
+			s.handle(event)
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-s.queue:
+					s.handle(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle gives an event to the handler of the subscriber. A failing handler does not stop the others.
+func (s subscriber) handle(event Event) {
+	logger := log.WithFields(log.Fields{"subscriber": s.name, "event": event.Name()})
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("The subscriber panicked handling the event: %v", r)
+		}
+	}()
+
+	if err := s.handler.Handle(event); err != nil {
+		logger.Errorf("The subscriber failed to handle the event: %v", err)
+	}
+}
+
+// NewLogHandler returns a handler which logs the events, as an audit trail of the changes of the apps
+func NewLogHandler() Handler {
+	return HandlerFunc(func(event Event) error {
+		log.WithField("event", event.Name()).Infof("%+v", event)
+		return nil
+	})
+}
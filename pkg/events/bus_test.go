@@ -0,0 +1,130 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// recorder is a handler which records the events it handles
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) Handle(event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recorder) got() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events...)
+}
+
+func TestBus_Publish(t *testing.T) {
+	app := models.App{ID: "7f89ce49-a736-459e-9110-e52d049fc027", AppID: "com.aerogear.mobile_app_one"}
+	event := AppDeleted{App: app}
+
+	var order []string
+	b := NewBus()
+	b.Subscribe("failing", HandlerFunc(func(event Event) error {
+		order = append(order, "failing")
+		return errors.New("failed")
+	}))
+	b.Subscribe("panicking", HandlerFunc(func(event Event) error {
+		order = append(order, "panicking")
+		panic("panicked")
+	}))
+	r := &recorder{}
+	b.Subscribe("recorder", r)
+
+	b.Publish(event)
+
+	// the synchronous subscribers handled the event in order, whatever the outcome of the previous ones
+	if want := []string{"failing", "panicking"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Bus.Publish() called %v, want %v", order, want)
+	}
+	if got := r.got(); !reflect.DeepEqual(got, []Event{event}) {
+		t.Errorf("Bus.Publish() handled %v, want %v", got, []Event{event})
+	}
+}
+
+func TestBus_Run(t *testing.T) {
+	first := AppCreated{App: models.App{AppID: "com.aerogear.mobile_app_one"}}
+	second := AppDeleted{App: models.App{AppID: "com.aerogear.mobile_app_one"}}
+
+	b := NewBus()
+	r := &recorder{}
+	b.SubscribeAsync("recorder", r)
+
+	// the events published before Run are queued
+	b.Publish(first)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		b.Run(ctx)
+		close(done)
+	}()
+
+	b.Publish(second)
+
+	deadline := time.Now().Add(time.Second)
+	for len(r.got()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+
+	if got := r.got(); !reflect.DeepEqual(got, []Event{first, second}) {
+		t.Errorf("Bus.Run() handled %v, want %v", got, []Event{first, second})
+	}
+}
+
+func TestBus_Run_drainsQueue(t *testing.T) {
+	b := NewBus()
+	r := &recorder{}
+	b.SubscribeAsync("recorder", r)
+
+	for i := 0; i < 10; i++ {
+		b.Publish(VersionFirstSeen{Version: models.Version{Version: "1.0"}})
+	}
+
+	// the events queued when the context is cancelled are handled before Run returns
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.Run(ctx)
+
+	if got := len(r.got()); got != 10 {
+		t.Errorf("Bus.Run() handled %v events, want 10", got)
+	}
+}
+
+func TestBus_Publish_fullQueue(t *testing.T) {
+	b := NewBus()
+	r := &recorder{}
+	b.SubscribeAsync("recorder", r)
+
+	// the events which do not fit in the queue are dropped without blocking
+	for i := 0; i < queueSize+5; i++ {
+		b.Publish(VersionFirstSeen{Version: models.Version{Version: "1.0"}})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.Run(ctx)
+
+	if got := len(r.got()); got != queueSize {
+		t.Errorf("Bus.Run() handled %v events, want %v", got, queueSize)
+	}
+}
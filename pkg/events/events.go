@@ -0,0 +1,145 @@
+package events
+
+import "github.com/aerogear/mobile-security-service/pkg/models"
+
+// The names of the events
+const (
+	AppCreatedName          = "AppCreated"
+	AppDeletedName          = "AppDeleted"
+	AppRestoredName         = "AppRestored"
+	VersionsUpdatedName     = "VersionsUpdated"
+	AllVersionsDisabledName = "AllVersionsDisabled"
+	DeviceRegisteredName    = "DeviceRegistered"
+	DeviceBlockedName       = "DeviceBlocked"
+	AppLaunchedName         = "AppLaunched"
+	VersionFirstSeenName    = "VersionFirstSeen"
+	TamperedBuildName       = "TamperedBuild"
+)
+
+// Event is a change of the apps emitted by the apps service once it is made.
+// The subscribers find the concrete type with a type switch.
+type Event interface {
+	// Name is the name of the type of the event
+	Name() string
+}
+
+// AppCreated is emitted when a new app is created
+type AppCreated struct {
+	App models.App
+}
+
+// AppDeleted is emitted when an app is soft deleted
+type AppDeleted struct {
+	App models.App
+}
+
+// AppRestored is emitted when a soft deleted app is restored
+type AppRestored struct {
+	App models.App
+}
+
+// VersionsUpdated is emitted when versions of an app are updated. Versions are the updated versions, which
+// may only have their id and state, and Previous the versions of the app before the update.
+type VersionsUpdated struct {
+	AppID    string
+	Previous []models.Version
+	Versions []models.Version
+}
+
+// AllVersionsDisabled is emitted when all the versions of an app are disabled. Previous are the versions
+// of the app before they were disabled. The ones which were already disabled keep their message.
+type AllVersionsDisabled struct {
+	AppID           string
+	DisabledMessage string
+	Previous        []models.Version
+}
+
+// Updated returns the updated versions with the number of the previous version of the same id,
+// as the updated versions may only have their id and state
+func (e VersionsUpdated) Updated() []models.Version {
+	numbers := map[string]string{}
+	for _, v := range e.Previous {
+		numbers[v.ID] = v.Version
+	}
+
+	updated := make([]models.Version, len(e.Versions))
+	for i, v := range e.Versions {
+		updated[i] = v
+		if number, ok := numbers[v.ID]; ok && v.Version == "" {
+			updated[i].Version = number
+		}
+	}
+	return updated
+}
+
+// Updated returns the previous versions once disabled
+func (e AllVersionsDisabled) Updated() []models.Version {
+	updated := make([]models.Version, len(e.Previous))
+	for i, v := range e.Previous {
+		updated[i] = v
+		if !v.Disabled {
+			updated[i].Disabled = true
+			updated[i].DisabledMessage = e.DisabledMessage
+		}
+	}
+	return updated
+}
+
+// DeviceRegistered is emitted when a device launches an app for the first time
+type DeviceRegistered struct {
+	Device models.Device
+}
+
+// DeviceBlocked is emitted when a device launches a disabled version of an app
+type DeviceBlocked struct {
+	Device  models.Device
+	Version models.Version
+}
+
+// AppLaunched is emitted for each launch of an app by a device, after the launch counters are updated
+type AppLaunched struct {
+	Device  models.Device
+	Version models.Version
+}
+
+// VersionFirstSeen is emitted when a version of an app is launched for the first time
+type VersionFirstSeen struct {
+	Version models.Version
+}
+
+// TamperedBuild is emitted when a device launches a build of an app which is not signed by one of the
+// certificates registered for its platform. Blocked is true when the launch was rejected.
+type TamperedBuild struct {
+	Device  models.Device
+	Blocked bool
+}
+
+// Name returns AppCreatedName
+func (AppCreated) Name() string { return AppCreatedName }
+
+// Name returns AppDeletedName
+func (AppDeleted) Name() string { return AppDeletedName }
+
+// Name returns AppRestoredName
+func (AppRestored) Name() string { return AppRestoredName }
+
+// Name returns VersionsUpdatedName
+func (VersionsUpdated) Name() string { return VersionsUpdatedName }
+
+// Name returns AllVersionsDisabledName
+func (AllVersionsDisabled) Name() string { return AllVersionsDisabledName }
+
+// Name returns DeviceRegisteredName
+func (DeviceRegistered) Name() string { return DeviceRegisteredName }
+
+// Name returns DeviceBlockedName
+func (DeviceBlocked) Name() string { return DeviceBlockedName }
+
+// Name returns AppLaunchedName
+func (AppLaunched) Name() string { return AppLaunchedName }
+
+// Name returns VersionFirstSeenName
+func (VersionFirstSeen) Name() string { return VersionFirstSeenName }
+
+// Name returns TamperedBuildName
+func (TamperedBuild) Name() string { return TamperedBuildName }
@@ -0,0 +1,33 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aerogear/mobile-security-service/pkg/db"
+)
+
+// DatabaseChecker returns a Checker which pings the database
+func DatabaseChecker(dbConn *sql.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		return dbConn.PingContext(ctx)
+	})
+}
+
+// SchemaChecker returns a Checker which fails when the database schema is
+// behind the version expected by this build of the server
+func SchemaChecker(dbConn *sql.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		current, err := db.CurrentSchemaVersion(ctx, dbConn)
+		if err != nil {
+			return err
+		}
+
+		if expected := db.SchemaVersion(); current < expected {
+			return fmt.Errorf("database schema version is %d, expected %d", current, expected)
+		}
+
+		return nil
+	})
+}
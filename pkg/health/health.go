@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Status of a single check or of the whole report
+type Status string
+
+const (
+	// StatusUp means the dependency is available
+	StatusUp Status = "up"
+	// StatusDown means the dependency is not available
+	StatusDown Status = "down"
+)
+
+// Checker defines the contract of a dependency health check
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc allows the use of ordinary functions as a Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx)
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckResult is the outcome of a single check
+// swagger:model CheckResult
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    Status  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of all the registered checks
+// swagger:model HealthReport
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+type namedChecker struct {
+	name    string
+	checker Checker
+}
+
+// Registry holds the checks which are run by the readiness probe
+type Registry struct {
+	mu      sync.RWMutex
+	checks  []namedChecker
+	timeout time.Duration
+}
+
+// NewRegistry returns a new Registry where each check is cancelled after the given timeout
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{
+		timeout: timeout,
+	}
+}
+
+// Register adds a named check to the registry
+func (r *Registry) Register(name string, c Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = append(r.checks, namedChecker{name: name, checker: c})
+}
+
+// Run executes all the registered checks concurrently and returns the report.
+// The report is down if any of the checks failed.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make([]namedChecker, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	report := Report{
+		Status: StatusUp,
+		Checks: make([]CheckResult, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c namedChecker) {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+// run executes a single check, bounded by the registry timeout
+func (r *Registry) run(ctx context.Context, c namedChecker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	result := CheckResult{
+		Name:   c.name,
+		Status: StatusUp,
+	}
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result.LatencyMs = float64(time.Since(start)) / float64(time.Millisecond)
+
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Run(t *testing.T) {
+	up := CheckerFunc(func(ctx context.Context) error {
+		return nil
+	})
+	down := CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	slow := CheckerFunc(func(ctx context.Context) error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	})
+
+	tests := []struct {
+		name       string
+		checks     map[string]Checker
+		wantStatus Status
+		wantDown   []string
+	}{
+		{
+			name:       "Run() should report up when no checks are registered",
+			checks:     map[string]Checker{},
+			wantStatus: StatusUp,
+		},
+		{
+			name:       "Run() should report up when all checks pass",
+			checks:     map[string]Checker{"database": up, "schema": up},
+			wantStatus: StatusUp,
+		},
+		{
+			name:       "Run() should report down when a check fails",
+			checks:     map[string]Checker{"database": down, "schema": up},
+			wantStatus: StatusDown,
+			wantDown:   []string{"database"},
+		},
+		{
+			name:       "Run() should report down when a check times out",
+			checks:     map[string]Checker{"database": slow},
+			wantStatus: StatusDown,
+			wantDown:   []string{"database"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry(50 * time.Millisecond)
+			for name, c := range tt.checks {
+				r.Register(name, c)
+			}
+
+			report := r.Run(context.Background())
+
+			if report.Status != tt.wantStatus {
+				t.Errorf("Registry.Run() status = %v, want %v", report.Status, tt.wantStatus)
+			}
+
+			if len(report.Checks) != len(tt.checks) {
+				t.Errorf("Registry.Run() returned %v checks, want %v", len(report.Checks), len(tt.checks))
+			}
+
+			for _, name := range tt.wantDown {
+				for _, result := range report.Checks {
+					if result.Name == name && (result.Status != StatusDown || result.Error == "") {
+						t.Errorf("Registry.Run() check %v = %+v, want it to be down with an error", name, result)
+					}
+				}
+			}
+		})
+	}
+}
@@ -2,11 +2,15 @@ package httperrors
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"strconv"
+	"time"
 
 	"github.com/aerogear/mobile-security-service/pkg/web/middleware"
 
 	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/openapi"
 	"github.com/labstack/echo"
 )
 
@@ -52,9 +56,54 @@ var codes = map[int]string{
 	511: "Network Authentication Required",
 }
 
+// ProblemContentType is the media type of the error responses, as defined by RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// errResponse is an RFC 7807 problem details body. Message and StatusCode
+// are kept alongside Detail and Status for the clients relying on them.
+// swagger:model Problem
 type errResponse struct {
-	Message    string `json:"message"`
-	StatusCode int    `json:"statusCode"`
+	Type       string              `json:"type"`
+	Title      string              `json:"title"`
+	Status     int                 `json:"status"`
+	Detail     string              `json:"detail,omitempty"`
+	Instance   string              `json:"instance,omitempty"`
+	Code       models.ErrorCode    `json:"code,omitempty"`
+	Errors     []models.FieldError `json:"errors,omitempty"`
+	Message    string              `json:"message"`
+	StatusCode int                 `json:"statusCode"`
+}
+
+// statusCodes maps the error codes to the HTTP status code of their response
+var statusCodes = map[models.ErrorCode]int{
+	models.CodeInternalServerError: 500,
+	models.CodeNotFound:            404,
+	models.CodeConflict:            409,
+	models.CodeBadParamInput:       400,
+	models.CodeUnauthorized:        401,
+	models.CodeForbidden:           403,
+	models.CodeDatabaseError:       500,
+	models.CodeValidationFailed:    422,
+}
+
+// defaultCodes are the error codes of the responses written for a status code only
+var defaultCodes = map[int]models.ErrorCode{
+	400: models.CodeBadParamInput,
+	401: models.CodeUnauthorized,
+	403: models.CodeForbidden,
+	404: models.CodeNotFound,
+	409: models.CodeConflict,
+	422: models.CodeValidationFailed,
+	429: models.CodeTooManyRequests,
+	500: models.CodeInternalServerError,
+}
+
+// Schemas returns the error responses by the name of their schema in the API documentation
+func Schemas() openapi.Schemas {
+	return openapi.Schemas{
+		"Problem":    errResponse{},
+		"FieldError": models.FieldError{},
+	}
 }
 
 // BadRequest response code (400) indicates that the
@@ -110,6 +159,19 @@ func UnsupportedMediaType(c echo.Context, message string) (e error) {
 	return HTTPError(c, 415, message)
 }
 
+// TooManyRequests response code (429) indicates that the client
+// sent too many requests and has to wait before sending another one.
+// The wait is sent in the Retry-After header, in seconds.
+func TooManyRequests(c echo.Context, message string, retryAfter time.Duration) (e error) {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Response().Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+
+	return HTTPError(c, 429, message)
+}
+
 // InternalServerError response code (500) indicates that
 // the server encountered an unexpected condition that
 // prevented it from fulfilling the request.
@@ -126,11 +188,27 @@ func NotImplemented(c echo.Context, message string) (e error) {
 
 // HTTPError returns a HTTP error with a descriptive JSON body
 func HTTPError(c echo.Context, statusCode int, message string) (e error) {
-	resBody := errResponse{
-		Message:    message,
-		StatusCode: statusCode,
+	return problem(c, statusCode, message, defaultCodes[statusCode], nil)
+}
+
+// GetHTTPResponseFromErr returns the mapped http error to the generic errors model.
+// The errors are matched by their code, so the models errors can be wrapped.
+func GetHTTPResponseFromErr(c echo.Context, err error) (e error) {
+	var modelErr *models.Error
+	if !errors.As(err, &modelErr) {
+		return InternalServerError(c, "")
 	}
 
+	statusCode, ok := statusCodes[modelErr.Code]
+	if !ok {
+		statusCode = 500
+	}
+
+	return problem(c, statusCode, modelErr.Message, modelErr.Code, modelErr.Fields)
+}
+
+// problem writes an RFC 7807 problem details response
+func problem(c echo.Context, statusCode int, message string, code models.ErrorCode, fields []models.FieldError) error {
 	// Invalid status code supplied, return 500
 	if _, ok := codes[statusCode]; !ok {
 		return HTTPError(c, 500, "Invalid HTTP status code")
@@ -138,10 +216,22 @@ func HTTPError(c echo.Context, statusCode int, message string) (e error) {
 
 	// Set default error message
 	if message == "" {
-		resBody.Message = codes[statusCode]
+		message = codes[statusCode]
+	}
+
+	resBody := errResponse{
+		Type:       "about:blank",
+		Title:      codes[statusCode],
+		Status:     statusCode,
+		Detail:     message,
+		Instance:   c.Request().URL.Path,
+		Code:       code,
+		Errors:     fields,
+		Message:    message,
+		StatusCode: statusCode,
 	}
 
-	_, err := json.Marshal(resBody)
+	b, err := json.Marshal(resBody)
 
 	if err != nil {
 		return err
@@ -149,26 +239,5 @@ func HTTPError(c echo.Context, statusCode int, message string) (e error) {
 
 	middleware.ApiRequestsFailureTotal.WithLabelValues(strconv.Itoa(statusCode), c.Request().Method, c.Request().RequestURI).Inc()
 
-	return c.JSON(statusCode, resBody)
-}
-
-// GetHTTPResponseFromErr returns the mapped http error to the generic errors model.
-func GetHTTPResponseFromErr(c echo.Context, err error) (e error) {
-
-	switch err {
-	case models.ErrInternalServerError:
-		return InternalServerError(c, err.Error())
-	case models.ErrNotFound:
-		return NotFound(c, err.Error())
-	case models.ErrConflict:
-		return Conflict(c, err.Error())
-	case models.ErrBadParamInput:
-		return BadRequest(c, err.Error())
-	case models.ErrUnauthorized:
-		return Unauthorized(c, err.Error())
-	case models.ErrDatabaseError:
-		return InternalServerError(c, err.Error())
-	default:
-		return InternalServerError(c, "")
-	}
+	return c.Blob(statusCode, ProblemContentType, b)
 }
@@ -3,10 +3,13 @@ package httperrors
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aerogear/mobile-security-service/pkg/models"
 	"github.com/labstack/echo"
@@ -115,6 +118,21 @@ func TestGetHTTPResponseFromErr(t *testing.T) {
 			args:     errors.New(""),
 			wantCode: http.StatusInternalServerError,
 		},
+		{
+			name:     "GetHTTPResponseFromErr() should return a HTTP error with a 404 status code when a wrapped ErrNotFound supplied",
+			args:     fmt.Errorf("getting the app: %w", models.ErrNotFound),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "GetHTTPResponseFromErr() should return a HTTP error with a 500 status code when ErrDatabaseError with a cause supplied",
+			args:     models.ErrDatabaseError.Wrap(errors.New("connection refused")),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name:     "GetHTTPResponseFromErr() should return a HTTP error with a 422 status code when a validation error supplied",
+			args:     models.NewValidationError(models.FieldError{Field: "appId", Message: "appId is required"}),
+			wantCode: http.StatusUnprocessableEntity,
+		},
 	}
 
 	for _, tt := range tests {
@@ -140,6 +158,51 @@ func TestGetHTTPResponseFromErr(t *testing.T) {
 	}
 }
 
+func TestGetHTTPResponseFromErr_ProblemDetails(t *testing.T) {
+	// Create a mock echo Context
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/apps", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	cause := errors.New("pq: duplicate key value violates unique constraint")
+	err := models.NewValidationError(models.FieldError{Field: "appId", Message: "appId is required"})
+	err.Cause = cause
+
+	if _ = GetHTTPResponseFromErr(c, err); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("GetHTTPResponseFromErr() statusCode = %v, wantCode %v", rec.Code, http.StatusUnprocessableEntity)
+	}
+
+	if contentType := rec.Header().Get(echo.HeaderContentType); contentType != ProblemContentType {
+		t.Errorf("GetHTTPResponseFromErr() Content-Type = %v, want %v", contentType, ProblemContentType)
+	}
+
+	responseBody := errResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &responseBody); err != nil {
+		t.Fatalf("GetHTTPResponseFromErr() could not unmarshal response body into errResponse struct")
+	}
+
+	want := errResponse{
+		Type:       "about:blank",
+		Title:      "Unprocessable Entity",
+		Status:     http.StatusUnprocessableEntity,
+		Detail:     models.ErrValidationFailed.Message,
+		Instance:   "/api/apps",
+		Code:       models.CodeValidationFailed,
+		Errors:     []models.FieldError{{Field: "appId", Message: "appId is required"}},
+		Message:    models.ErrValidationFailed.Message,
+		StatusCode: http.StatusUnprocessableEntity,
+	}
+
+	if !reflect.DeepEqual(responseBody, want) {
+		t.Errorf("GetHTTPResponseFromErr() body = %+v, want %+v", responseBody, want)
+	}
+
+	if strings.Contains(rec.Body.String(), cause.Error()) {
+		t.Errorf("GetHTTPResponseFromErr() body should not contain the cause of the error, got %v", rec.Body.String())
+	}
+}
+
 func TestBadRequest(t *testing.T) {
 	type args struct {
 		message string
@@ -557,6 +620,46 @@ func TestUnsupportedMediaType(t *testing.T) {
 	}
 }
 
+func TestTooManyRequests(t *testing.T) {
+	tests := []struct {
+		name           string
+		retryAfter     time.Duration
+		wantRetryAfter string
+	}{
+		{
+			name:           "TooManyRequests() should return a 429 response code with the seconds to wait rounded up",
+			retryAfter:     1500 * time.Millisecond,
+			wantRetryAfter: "2",
+		},
+		{
+			name:           "TooManyRequests() should ask to wait at least a second",
+			retryAfter:     0,
+			wantRetryAfter: "1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			if _ = TooManyRequests(c, "", tt.retryAfter); rec.Code != 429 {
+				t.Errorf("TooManyRequests() statusCode = %v, want 429", rec.Code)
+			}
+
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("TooManyRequests() Retry-After = %v, want %v", got, tt.wantRetryAfter)
+			}
+
+			responseBody := errResponse{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &responseBody); err != nil || responseBody.Code != models.CodeTooManyRequests {
+				t.Errorf("TooManyRequests() code = %v, want %v", responseBody.Code, models.CodeTooManyRequests)
+			}
+		})
+	}
+}
+
 func TestInternalServerError(t *testing.T) {
 	type args struct {
 		message string
@@ -0,0 +1,125 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Worker defines a long running background process owned by the server.
+// Run must return when the given context is cancelled.
+type Worker interface {
+	Run(ctx context.Context)
+}
+
+// WorkerFunc allows the use of ordinary functions as a Worker
+type WorkerFunc func(ctx context.Context)
+
+// Run calls f(ctx)
+func (f WorkerFunc) Run(ctx context.Context) {
+	f(ctx)
+}
+
+// Manager keeps track of the server state and of the background workers
+// started with it, so that they can be stopped in order on shutdown
+type Manager struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	draining int32
+
+	mu      sync.Mutex
+	stopped map[string]bool
+}
+
+// NewManager returns a new instance of the lifecycle Manager
+func NewManager() *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		ctx:     ctx,
+		cancel:  cancel,
+		stopped: map[string]bool{},
+	}
+}
+
+// Go starts the worker in its own goroutine. The worker is cancelled and
+// waited for when Stop is called.
+func (m *Manager) Go(name string, w Worker) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		log.WithField("worker", name).Info("Background worker started")
+		w.Run(m.ctx)
+
+		if m.ctx.Err() == nil {
+			// the worker returned on its own, so it is no longer doing its job
+			log.WithField("worker", name).Error("Background worker stopped unexpectedly")
+			m.mu.Lock()
+			m.stopped[name] = true
+			m.mu.Unlock()
+			return
+		}
+
+		log.WithField("worker", name).Info("Background worker stopped")
+	}()
+}
+
+// StartDraining flags the server as draining. From this point the readiness
+// probe reports the server as unavailable so no new traffic is routed to it.
+func (m *Manager) StartDraining() {
+	atomic.StoreInt32(&m.draining, 1)
+}
+
+// IsDraining returns true once the server has started to shut down
+func (m *Manager) IsDraining() bool {
+	return atomic.LoadInt32(&m.draining) == 1
+}
+
+// Check implements the health.Checker contract. It fails while the server is
+// draining or when a background worker has stopped before the server did.
+func (m *Manager) Check(ctx context.Context) error {
+	if m.IsDraining() {
+		return errors.New("server is shutting down")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.stopped) == 0 {
+		return nil
+	}
+
+	var names []string
+	for name := range m.stopped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return fmt.Errorf("background workers stopped unexpectedly: %v", strings.Join(names, ", "))
+}
+
+// Stop cancels all the background workers and waits for them to return.
+// It returns the context error if the workers do not finish in time.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.StartDraining()
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,112 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestManager_Stop(t *testing.T) {
+	tests := []struct {
+		name    string
+		worker  WorkerFunc
+		timeout time.Duration
+		wantErr bool
+	}{
+		{
+			name: "Stop() should cancel and wait for the workers to return",
+			worker: func(ctx context.Context) {
+				<-ctx.Done()
+			},
+			timeout: time.Second,
+			wantErr: false,
+		},
+		{
+			name: "Stop() should return an error when the workers do not return in time",
+			worker: func(ctx context.Context) {
+				<-ctx.Done()
+				time.Sleep(200 * time.Millisecond)
+			},
+			timeout: 10 * time.Millisecond,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager()
+			m.Go("test", tt.worker)
+
+			if m.IsDraining() {
+				t.Errorf("Manager.IsDraining() = true before Stop() was called")
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			if err := m.Stop(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("Manager.Stop() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !m.IsDraining() {
+				t.Errorf("Manager.IsDraining() = false after Stop() was called")
+			}
+		})
+	}
+}
+
+func TestManager_StartDraining(t *testing.T) {
+	m := NewManager()
+	m.StartDraining()
+
+	if !m.IsDraining() {
+		t.Errorf("Manager.IsDraining() = false, want true")
+	}
+}
+
+func TestManager_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		worker   WorkerFunc
+		draining bool
+		wantErr  bool
+	}{
+		{
+			name: "Check() should pass while the workers are running",
+			worker: func(ctx context.Context) {
+				<-ctx.Done()
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Check() should fail when a worker stopped on its own",
+			worker:  func(ctx context.Context) {},
+			wantErr: true,
+		},
+		{
+			name: "Check() should fail while the server is draining",
+			worker: func(ctx context.Context) {
+				<-ctx.Done()
+			},
+			draining: true,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager()
+			m.Go("test", tt.worker)
+			defer m.Stop(context.Background())
+
+			if tt.draining {
+				m.StartDraining()
+			}
+
+			// give the worker the chance to return
+			time.Sleep(20 * time.Millisecond)
+
+			if err := m.Check(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Manager.Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// The kinds of the alerts raised by the analysis of the init requests
+const (
+	// AlertNewDevicesSpike is raised when an app has much more new devices than usual. Its subject is empty.
+	AlertNewDevicesSpike = "new_devices_spike"
+	// AlertUnreleasedVersion is raised when a version launched for the first time is still used by very few
+	// devices after a while, as when a client reports a version which was never released. Its subject is the version.
+	AlertUnreleasedVersion = "unreleased_version"
+	// AlertDeviceOSVersionsCycling is raised when a device reports many OS versions, as when a client sends
+	// made up device information. Its subject is the device id.
+	AlertDeviceOSVersionsCycling = "device_os_versions_cycling"
+)
+
+// Alert is a suspicious pattern of the init requests of an app. An alert is updated by each analysis
+// which detects it again, so an anomaly which lasts has a single alert.
+type Alert struct {
+	ID      string
+	AppID   string
+	Kind    string
+	Subject string
+	// Value is the measure which crossed the threshold, e.g. the number of new devices
+	Value     int64
+	Threshold int64
+	// FirstDetectedAt and LastDetectedAt are the first and the last analysis which detected the alert
+	FirstDetectedAt time.Time
+	LastDetectedAt  time.Time
+}
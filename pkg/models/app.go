@@ -1,7 +1,6 @@
 package models
 
 // App is the model struct for apps
-// swagger:model App
 type App struct {
 	ID                    string     `json:"id"`
 	AppID                 string     `json:"appId"`
@@ -11,6 +10,7 @@ type App struct {
 	NumOfAppLaunches      *int       `json:"numOfAppLaunches,omitempty"`
 	DeployedVersions      *[]Version `json:"deployedVersions,omitempty"`
 	DeletedAt             string     `json:"deletedAt,omitempty"`
+	TenantID              string     `json:"tenantId,omitempty"`
 }
 
 // NewAppByNameAndAppID will create a new App object based on the name and appId which indeed are the only values
@@ -22,3 +22,52 @@ func NewAppByNameAndAppID(name, appId string) *App {
 	app.AppID = appId
 	return app
 }
+
+// AppsQuery holds the parameters used to paginate, sort and filter the list of apps
+type AppsQuery struct {
+	// Limit is the maximum number of apps to return. 0 returns all of them.
+	Limit int
+	// Cursor is the opaque position returned with the previous page
+	Cursor string
+	// Sort is the field to order by, prefixed with "-" for descending order
+	Sort string
+	// Search filters the apps whose name or appId contains the given text
+	Search string
+	// Deleted returns the soft deleted apps instead of the active ones
+	Deleted bool
+	// Tenants filters the apps of the tenants in the scope. Nil returns the apps of every tenant.
+	Tenants *TenantScope
+}
+
+// AppsPage is a page of apps and the cursor used to fetch the following one
+type AppsPage struct {
+	Apps []App
+	// NextCursor is empty when there are no more apps to fetch
+	NextCursor string
+}
+
+// AppArchive is the export of all the data of an app, taken before it is hard deleted.
+// The versions of the app are in App.DeployedVersions, each with its devices.
+type AppArchive struct {
+	ExportedAt    string        `json:"exportedAt"`
+	App           App           `json:"app"`
+	Releases      Releases      `json:"releases"`
+	SigningPolicy SigningPolicy `json:"signingPolicy"`
+	// Webhooks are exported without their secrets
+	Webhooks []Webhook `json:"webhooks"`
+	Alerts   []Alert   `json:"alerts"`
+}
+
+// AppHardDelete is the outcome of the hard delete of an app
+type AppHardDelete struct {
+	// DryRun is true when nothing was deleted and only the counts are reported
+	DryRun              bool `json:"dryRun"`
+	Versions            int  `json:"versions"`
+	Devices             int  `json:"devices"`
+	ReleasedVersions    int  `json:"releasedVersions"`
+	SigningCertificates int  `json:"signingCertificates"`
+	Webhooks            int  `json:"webhooks"`
+	Alerts              int  `json:"alerts"`
+	// Archive is the data of the app which was deleted. It is not set on a dry run.
+	Archive *AppArchive `json:"archive,omitempty"`
+}
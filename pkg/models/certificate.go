@@ -0,0 +1,77 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// The platforms of the builds of an app
+const (
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+)
+
+// The modes of an app for the builds which are not signed by one of its signing certificates
+const (
+	// TamperedBuildsFlag registers the launches of the tampered builds and reports them
+	TamperedBuildsFlag = "flag"
+	// TamperedBuildsBlock disables the tampered builds without registering their launches, and reports them
+	TamperedBuildsBlock = "block"
+)
+
+// SigningCertificate is a certificate which signs the builds of an app for a platform, identified by the SHA-256
+// fingerprint of the certificate in lowercase hex
+type SigningCertificate struct {
+	ID          string    `json:"id"`
+	AppID       string    `json:"appId"`
+	Platform    string    `json:"platform"`
+	Fingerprint string    `json:"fingerprint"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// SigningPolicy are the signing certificates registered for an app and its mode for the tampered builds
+type SigningPolicy struct {
+	AppID          string               `json:"appId"`
+	TamperedBuilds string               `json:"tamperedBuilds"`
+	Certificates   []SigningCertificate `json:"certificates"`
+}
+
+// IsTrusted is true when no certificate is registered for the platform or the fingerprint is one of them.
+// Once a certificate is registered for a platform, a build which reports no fingerprint is not trusted,
+// and once the app has any certificate, a build of an unknown platform is not trusted either.
+func (p *SigningPolicy) IsTrusted(platform, fingerprint string) bool {
+	if platform == "" {
+		return len(p.Certificates) == 0
+	}
+
+	pinned := false
+	fingerprint = NormalizeFingerprint(fingerprint)
+	for _, c := range p.Certificates {
+		if c.Platform != platform {
+			continue
+		}
+		if c.Fingerprint == fingerprint {
+			return true
+		}
+		pinned = true
+	}
+	return !pinned
+}
+
+// NormalizeFingerprint returns a fingerprint in lowercase hex without separators, as keytool prints
+// them with colons and other tools without
+func NormalizeFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(fingerprint), ":", "", -1))
+}
+
+// PlatformOf returns the platform of a device by its type, e.g. Android or iOS, or an empty string when it is unknown
+func PlatformOf(deviceType string) string {
+	switch strings.ToLower(deviceType) {
+	case PlatformAndroid:
+		return PlatformAndroid
+	case PlatformIOS:
+		return PlatformIOS
+	}
+	return ""
+}
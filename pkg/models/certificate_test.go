@@ -0,0 +1,59 @@
+package models
+
+import "testing"
+
+func TestSigningPolicy_IsTrusted(t *testing.T) {
+	const fingerprint = "146de983c5730650d8eeb9952f34fc6416a08342e61dbea88a0496b23fcf44e5"
+	policy := SigningPolicy{Certificates: []SigningCertificate{{Platform: PlatformAndroid, Fingerprint: fingerprint}}}
+
+	tests := []struct {
+		name        string
+		platform    string
+		fingerprint string
+		want        bool
+	}{
+		{
+			name:        "IsTrusted() should trust a build signed by a certificate of its platform",
+			platform:    PlatformAndroid,
+			fingerprint: fingerprint,
+			want:        true,
+		},
+		{
+			name:        "IsTrusted() should compare the fingerprints without colons and case",
+			platform:    PlatformAndroid,
+			fingerprint: "14:6D:E9:83:C5:73:06:50:D8:EE:B9:95:2F:34:FC:64:16:A0:83:42:E6:1D:BE:A8:8A:04:96:B2:3F:CF:44:E5",
+			want:        true,
+		},
+		{
+			name:        "IsTrusted() should not trust a build signed by another certificate",
+			platform:    PlatformAndroid,
+			fingerprint: "00" + fingerprint[2:],
+		},
+		{
+			name:     "IsTrusted() should not trust a build without fingerprint once its platform has certificates",
+			platform: PlatformAndroid,
+		},
+		{
+			name:     "IsTrusted() should trust any build of a platform without certificates",
+			platform: PlatformIOS,
+			want:     true,
+		},
+		{
+			name:        "IsTrusted() should not trust a build of an unknown platform once the app has certificates",
+			platform:    "",
+			fingerprint: fingerprint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.IsTrusted(tt.platform, tt.fingerprint); got != tt.want {
+				t.Errorf("SigningPolicy.IsTrusted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// without certificates, every build is trusted
+	if !(&SigningPolicy{}).IsTrusted("", "") {
+		t.Errorf("SigningPolicy.IsTrusted() = false for an app without certificates, want true")
+	}
+}
@@ -3,7 +3,6 @@ package models
 import "github.com/google/uuid"
 
 // Device model
-// swagger:model Device
 type Device struct {
 	ID            string `json:"id"`
 	VersionID     string `json:"versionId"`
@@ -12,6 +11,8 @@ type Device struct {
 	DeviceID      string `json:"deviceId"`
 	DeviceVersion string `json:"deviceVersion"`
 	DeviceType    string `json:"deviceType"`
+	// SigningCertificate is the SHA-256 fingerprint of the certificate which signed the build, reported by the SDK
+	SigningCertificate string `json:"signingCertificate,omitempty"`
 }
 
 // NewDevice returns a new Device model
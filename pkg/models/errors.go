@@ -1,19 +1,101 @@
 package models
 
-import "errors"
+import "fmt"
+
+// ErrorCode is the machine-readable code of an Error, sent to the clients with the error response
+type ErrorCode string
+
+// The codes of the errors returned by the service
+const (
+	CodeInternalServerError ErrorCode = "internal_server_error"
+	CodeNotFound            ErrorCode = "not_found"
+	CodeConflict            ErrorCode = "conflict"
+	CodeBadParamInput       ErrorCode = "bad_param_input"
+	CodeUnauthorized        ErrorCode = "unauthorized"
+	CodeForbidden           ErrorCode = "forbidden"
+	CodeDatabaseError       ErrorCode = "database_error"
+	CodeValidationFailed    ErrorCode = "validation_failed"
+	CodeTooManyRequests     ErrorCode = "too_many_requests"
+)
+
+// FieldError describes why the value of a single field is not valid
+// swagger:model FieldError
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is the error model of the service. Errors with the same Code are
+// considered equal by errors.Is, so an Error can be wrapped or carry a cause
+// and still be matched against the sentinel errors below.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	// Fields holds the field-level details of a validation error
+	Fields []FieldError
+	// Cause is the underlying error, which is logged but never sent to the clients
+	Cause error
+}
+
+// NewError returns a new Error with the given code and message
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// NewValidationError returns a new validation Error with the details of each invalid field
+func NewValidationError(fields ...FieldError) *Error {
+	return &Error{Code: CodeValidationFailed, Message: ErrValidationFailed.Message, Fields: fields}
+}
+
+// Error returns the message of the error followed by its cause, if any
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%v: %v", e.Message, e.Cause)
+	}
+	return e.Message
+}
+
+// Unwrap returns the cause of the error
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether the target is an Error with the same code
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of the error with the given cause
+func (e *Error) Wrap(cause error) *Error {
+	wrapped := *e
+	wrapped.Cause = cause
+	return &wrapped
+}
+
+// WithMessage returns a copy of the error with the given message
+func (e *Error) WithMessage(message string) *Error {
+	wrapped := *e
+	wrapped.Message = message
+	return &wrapped
+}
 
 //var error model to map to http errors
 var (
 	// ErrInternalServerError returns a new Internal Server Error
-	ErrInternalServerError = errors.New("Internal Server Error")
+	ErrInternalServerError = NewError(CodeInternalServerError, "Internal Server Error")
 	// ErrNotFound returns a new Not Found Error
-	ErrNotFound = errors.New("Your requested Item is not found")
+	ErrNotFound = NewError(CodeNotFound, "Your requested Item is not found")
 	// ErrConflict returns a new Conflict error
-	ErrConflict = errors.New("Your Item already exists")
+	ErrConflict = NewError(CodeConflict, "Your Item already exists")
 	// ErrBadParamInput returns a Bad Parameter Input Error
-	ErrBadParamInput = errors.New("Given Param is not valid")
+	ErrBadParamInput = NewError(CodeBadParamInput, "Given Param is not valid")
 	// ErrUnauthorized returns a new Unauthorized Error
-	ErrUnauthorized = errors.New("Missing or Invalid authentication token")
+	ErrUnauthorized = NewError(CodeUnauthorized, "Missing or Invalid authentication token")
+	// ErrForbidden returns a new Forbidden Error
+	ErrForbidden = NewError(CodeForbidden, "You are not allowed to perform this operation")
 	// ErrDatabaseError returns a New Database Error
-	ErrDatabaseError = errors.New("An error has occurred in the database")
+	ErrDatabaseError = NewError(CodeDatabaseError, "An error has occurred in the database")
+	// ErrValidationFailed returns a new Validation Error, use NewValidationError to add the invalid fields
+	ErrValidationFailed = NewError(CodeValidationFailed, "The request is not valid")
 )
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestError_Is(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{
+			name:   "errors.Is() should match the same error",
+			err:    ErrNotFound,
+			target: ErrNotFound,
+			want:   true,
+		},
+		{
+			name:   "errors.Is() should match a wrapped error",
+			err:    fmt.Errorf("getting the app: %w", ErrNotFound),
+			target: ErrNotFound,
+			want:   true,
+		},
+		{
+			name:   "errors.Is() should match an error with a cause by its code",
+			err:    ErrDatabaseError.Wrap(errors.New("connection refused")),
+			target: ErrDatabaseError,
+			want:   true,
+		},
+		{
+			name:   "errors.Is() should match the cause of an error",
+			err:    ErrDatabaseError.Wrap(ErrConflict),
+			target: ErrConflict,
+			want:   true,
+		},
+		{
+			name:   "errors.Is() should match a validation error with fields",
+			err:    NewValidationError(FieldError{Field: "appId", Message: "appId is required"}),
+			target: ErrValidationFailed,
+			want:   true,
+		},
+		{
+			name:   "errors.Is() should not match an error with another code",
+			err:    ErrInternalServerError,
+			target: ErrDatabaseError,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError_As(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("getting the app: %w", ErrDatabaseError.Wrap(cause))
+
+	var modelErr *Error
+	if !errors.As(err, &modelErr) {
+		t.Fatalf("errors.As() = false, want true")
+	}
+
+	if modelErr.Code != CodeDatabaseError || modelErr.Cause != cause {
+		t.Errorf("errors.As() = %+v, want the database error caused by %v", modelErr, cause)
+	}
+
+	if want := "An error has occurred in the database: connection refused"; modelErr.Error() != want {
+		t.Errorf("Error.Error() = %v, want %v", modelErr.Error(), want)
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The types of the security relevant events of the apps
+const (
+	// EventVersionDisabled is emitted when versions of an app are disabled
+	EventVersionDisabled = "version.disabled"
+	// EventVersionEnabled is emitted when disabled versions of an app are enabled again
+	EventVersionEnabled = "version.enabled"
+	// EventAppDeleted is emitted when an app is soft deleted
+	EventAppDeleted = "app.deleted"
+	// EventAppRestored is emitted when a soft deleted app is restored
+	EventAppRestored = "app.restored"
+	// EventDeviceBlocked is emitted when a device launches a disabled version of an app
+	EventDeviceBlocked = "device.blocked"
+	// EventDeviceTampered is emitted when a device launches a build of an app which is not signed by its certificates
+	EventDeviceTampered = "device.tampered"
+)
+
+// EventTypes are the types of all the events
+var EventTypes = []string{EventVersionDisabled, EventVersionEnabled, EventAppDeleted, EventAppRestored, EventDeviceBlocked, EventDeviceTampered}
+
+// Event is a security relevant change of an app, identified by its appId.
+// Data is one of AppEventData, VersionsEventData or DeviceEventData depending on the type.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	AppID      string      `json:"appId"`
+	OccurredAt time.Time   `json:"occurredAt"`
+	Data       interface{} `json:"data"`
+}
+
+// NewEvent returns a new Event which occurred now
+func NewEvent(eventType, appID string, data interface{}) Event {
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		AppID:      appID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// AppEventData is the data of the app.deleted and app.restored events
+type AppEventData struct {
+	ID      string `json:"id"`
+	AppName string `json:"appName,omitempty"`
+}
+
+// VersionsEventData is the data of the version.disabled and version.enabled events
+type VersionsEventData struct {
+	Versions []EventVersion `json:"versions"`
+}
+
+// EventVersion is a version of an app in the data of an event
+type EventVersion struct {
+	ID              string `json:"id"`
+	Version         string `json:"version"`
+	DisabledMessage string `json:"disabledMessage,omitempty"`
+}
+
+// DeviceEventData is the data of the device.blocked and device.tampered events
+type DeviceEventData struct {
+	DeviceID        string `json:"deviceId"`
+	DeviceType      string `json:"deviceType"`
+	DeviceVersion   string `json:"deviceVersion"`
+	Version         string `json:"version"`
+	DisabledMessage string `json:"disabledMessage,omitempty"`
+	// SigningCertificate is the fingerprint reported by a tampered build, if any
+	SigningCertificate string `json:"signingCertificate,omitempty"`
+	// Blocked is true when the launch of a tampered build was rejected
+	Blocked bool `json:"blocked,omitempty"`
+}
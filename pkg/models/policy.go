@@ -0,0 +1,60 @@
+package models
+
+// AppsPolicy is the declarative document of the apps and of the state of their versions.
+// It is exported from a service and imported in another one, e.g. to move the configuration
+// from staging to production or to keep it in git.
+type AppsPolicy struct {
+	Apps []AppPolicy `json:"apps" yaml:"apps"`
+}
+
+// AppPolicy is an app of the policy, identified by its appId
+type AppPolicy struct {
+	AppID    string          `json:"appId" yaml:"appId"`
+	AppName  string          `json:"appName,omitempty" yaml:"appName,omitempty"`
+	Versions []VersionPolicy `json:"versions,omitempty" yaml:"versions,omitempty"`
+	// UnknownVersions is the mode of the app for the versions which are not among its released versions
+	UnknownVersions  string   `json:"unknownVersions,omitempty" yaml:"unknownVersions,omitempty"`
+	ReleasedVersions []string `json:"releasedVersions,omitempty" yaml:"releasedVersions,omitempty"`
+}
+
+// VersionPolicy is the state of a version of an app of the policy, identified by its version
+type VersionPolicy struct {
+	Version         string `json:"version" yaml:"version"`
+	Disabled        bool   `json:"disabled" yaml:"disabled"`
+	DisabledMessage string `json:"disabledMessage,omitempty" yaml:"disabledMessage,omitempty"`
+}
+
+// The actions of the changes made by the import or the reconciliation of a policy
+const (
+	// PolicyActionCreate creates an app which does not exist
+	PolicyActionCreate = "create"
+	// PolicyActionRestore restores an app which was soft deleted
+	PolicyActionRestore = "restore"
+	// PolicyActionUpdate updates a field of an app or of a version
+	PolicyActionUpdate = "update"
+	// PolicyActionDelete soft deletes a managed app which is no longer in the policy
+	PolicyActionDelete = "delete"
+	// PolicyActionSkip reports a version which cannot be updated because it was never launched
+	PolicyActionSkip = "skip"
+)
+
+// PolicyChange is a change made by the import of a policy, or which would be made on a dry run
+type PolicyChange struct {
+	Action string `json:"action"`
+	AppID  string `json:"appId"`
+	// Version is set for the changes of a version
+	Version string `json:"version,omitempty"`
+	// Field is the name of the field updated, e.g. appName or disabled
+	Field string `json:"field,omitempty"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+	// Reason explains why the change is skipped
+	Reason string `json:"reason,omitempty"`
+}
+
+// PolicyImport is the outcome of the import of a policy
+type PolicyImport struct {
+	// DryRun is true when nothing was changed and only the changes which would be made are reported
+	DryRun  bool           `json:"dryRun"`
+	Changes []PolicyChange `json:"changes"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+const (
+	// DefaultTenantID is the id of the tenant owning the apps created before the tenants, or without one
+	DefaultTenantID = "00000000-0000-4000-8000-000000000001"
+	// DefaultTenantName is the name of the default tenant
+	DefaultTenantName = "default"
+)
+
+// Tenant is a team or namespace owning a set of apps, which only its members can see
+type Tenant struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Members   []string  `json:"members"`
+	NumOfApps int       `json:"numOfApps"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TenantScope are the tenants whose apps a user can see. All is set for the super admins, who see every tenant.
+type TenantScope struct {
+	All       bool
+	TenantIDs []string
+}
+
+// Includes is true when the apps of the tenant are in the scope
+func (s *TenantScope) Includes(tenantID string) bool {
+	if s.All {
+		return true
+	}
+	for _, id := range s.TenantIDs {
+		if id == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+// Narrow returns the scope restricted to a tenant, which is empty when the tenant is not in the scope
+func (s *TenantScope) Narrow(tenantID string) *TenantScope {
+	if !s.Includes(tenantID) {
+		return &TenantScope{TenantIDs: []string{}}
+	}
+	return &TenantScope{TenantIDs: []string{tenantID}}
+}
+
+// TenantOf returns the tenant of a new app: the requested one when it is in the scope, the only tenant of the scope
+// when none is requested, or the default tenant for the super admins. It returns a validation error otherwise.
+func (s *TenantScope) TenantOf(requested string) (string, error) {
+	switch {
+	case requested != "" && s.Includes(requested):
+		return requested, nil
+	case requested != "":
+		return "", NewValidationError(FieldError{Field: "tenantId", Message: "tenantId is not a tenant of the user"})
+	case s.All:
+		return DefaultTenantID, nil
+	case len(s.TenantIDs) == 1:
+		return s.TenantIDs[0], nil
+	}
+	return "", NewValidationError(FieldError{Field: "tenantId", Message: "tenantId is required unless the user is a member of a single tenant"})
+}
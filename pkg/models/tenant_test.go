@@ -0,0 +1,68 @@
+package models
+
+import "testing"
+
+func TestTenantScope_TenantOf(t *testing.T) {
+	const (
+		ownTenant   = "6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d"
+		otherTenant = "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d"
+	)
+
+	tests := []struct {
+		name      string
+		scope     TenantScope
+		requested string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "TenantOf() should return the requested tenant of the user",
+			scope:     TenantScope{TenantIDs: []string{ownTenant, otherTenant}},
+			requested: otherTenant,
+			want:      otherTenant,
+		},
+		{
+			name:      "TenantOf() should reject a tenant the user is not a member of",
+			scope:     TenantScope{TenantIDs: []string{ownTenant}},
+			requested: otherTenant,
+			wantErr:   true,
+		},
+		{
+			name:  "TenantOf() should return the only tenant of the user when none is requested",
+			scope: TenantScope{TenantIDs: []string{ownTenant}},
+			want:  ownTenant,
+		},
+		{
+			name:    "TenantOf() should require a tenant from a member of several tenants",
+			scope:   TenantScope{TenantIDs: []string{ownTenant, otherTenant}},
+			wantErr: true,
+		},
+		{
+			name:    "TenantOf() should require a tenant from a user who is in none",
+			scope:   TenantScope{TenantIDs: []string{}},
+			wantErr: true,
+		},
+		{
+			name:  "TenantOf() should return the default tenant for a super admin",
+			scope: TenantScope{All: true},
+			want:  DefaultTenantID,
+		},
+		{
+			name:      "TenantOf() should return any requested tenant for a super admin",
+			scope:     TenantScope{All: true},
+			requested: otherTenant,
+			want:      otherTenant,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.scope.TenantOf(tt.requested)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TenantScope.TenantOf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("TenantScope.TenantOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
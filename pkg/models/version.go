@@ -1,7 +1,6 @@
 package models
 
 // Version model
-// swagger:model Version
 type Version struct {
 	ID                   string   `json:"id"`
 	Version              string   `json:"version"`
@@ -12,4 +11,37 @@ type Version struct {
 	NumOfAppLaunches     int64    `json:"numOfAppLaunches,omitempty"`
 	LastLaunchedAt       string   `json:"lastLaunchedAt,omitempty"`
 	Devices              []Device `json:"devices,omitempty"`
+	// Unknown is true when the app declares its released versions, flags the others and this is not one of them
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// The modes of an app for the versions sent to the init endpoint which are not among its released versions.
+// They only apply when the app declares released versions.
+const (
+	// UnknownVersionsAccept registers the unknown versions as any other version
+	UnknownVersionsAccept = "accept"
+	// UnknownVersionsFlag registers the unknown versions and flags them in the versions of the app
+	UnknownVersionsFlag = "flag"
+	// UnknownVersionsBlock disables the unknown versions without registering them or their devices
+	UnknownVersionsBlock = "block"
+)
+
+// Releases are the released versions declared for an app and its mode for the other versions
+type Releases struct {
+	AppID           string   `json:"appId"`
+	UnknownVersions string   `json:"unknownVersions"`
+	Versions        []string `json:"versions"`
+}
+
+// IsReleased is true when the app declares no released versions or the version is one of them
+func (r *Releases) IsReleased(version string) bool {
+	if len(r.Versions) == 0 {
+		return true
+	}
+	for _, v := range r.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
 }
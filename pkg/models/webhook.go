@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Webhook is a subscription of a URL to some types of the events of an app
+type Webhook struct {
+	ID    string
+	AppID string
+	URL   string
+	// Secret is the key of the HMAC signature of the deliveries
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+// The statuses of a webhook delivery
+const (
+	// DeliveryPending is a delivery waiting for its first attempt or for a retry
+	DeliveryPending = "pending"
+	// DeliveryDelivered is a delivery accepted by the webhook with a 2xx response
+	DeliveryDelivered = "delivered"
+	// DeliveryFailed is a delivery which was not accepted after the maximum number of attempts
+	DeliveryFailed = "failed"
+)
+
+// WebhookDelivery is the delivery of an event to a webhook. The deliveries are stored in an outbox
+// and are kept, once delivered or failed, as the log of the webhook.
+type WebhookDelivery struct {
+	ID        string
+	WebhookID string
+	EventID   string
+	EventType string
+	// Payload is the JSON encoded Event sent as the body of the request
+	Payload       []byte
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastAttemptAt *time.Time
+	// ResponseStatus is the status code of the response to the last attempt, 0 when there was none
+	ResponseStatus int
+	LastError      string
+	// ReplayOf is the id of the delivery this one replays
+	ReplayOf    string
+	CreatedAt   time.Time
+	DeliveredAt *time.Time
+}
@@ -0,0 +1,233 @@
+// Package openapi builds the OpenAPI 3 document of the REST API from the Go types of its requests and responses
+// and validates JSON values against its schemas.
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Version is the version of the OpenAPI specification the documents follow
+const Version = "3.0.2"
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	// types are the names of the component schemas of the registered Go types
+	types map[reflect.Type]string
+	// patterns are the regular expressions of the custom validation tags
+	patterns map[string]string
+}
+
+// Info holds the metadata of the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is a base URL of the API
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by their lower case HTTP method
+type PathItem map[string]*Operation
+
+// Operation is a single API operation on a path
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+// Parameter is a path, query or header parameter of an operation
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body of the request of an operation
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation for a status code
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header is a header of a response
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType is the schema and an example of a body for a content type
+type MediaType struct {
+	Schema  *Schema     `json:"schema"`
+	Example interface{} `json:"example,omitempty"`
+}
+
+// Components holds the schemas referenced in the document
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is the subset of the OpenAPI schema object used to describe the API
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []interface{}      `json:"enum,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+	MinItems    *int               `json:"minItems,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty"`
+	MinLength   *int               `json:"minLength,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	Default     interface{}        `json:"default,omitempty"`
+	// AdditionalProperties is the schema of the values of a map
+	AdditionalProperties *Schema `json:"additionalProperties,omitempty"`
+}
+
+// Schemas are Go values whose types are documented as component schemas, by the name of the schema
+type Schemas map[string]interface{}
+
+// Options are the settings of a new document
+type Options struct {
+	Info Info
+	// ServerURL is the base URL of the paths of the operations, e.g. /api
+	ServerURL string
+	// Schemas are documented as the component schemas
+	Schemas []Schemas
+	// Patterns are the regular expressions checked by the custom validation tags of the fields, e.g. appid
+	Patterns map[string]string
+}
+
+// NewDocument returns a new document with a component schema generated for each of the types of the schemas.
+// The fields whose type is one of the schemas reference it instead of repeating it.
+func NewDocument(o Options) *Document {
+	d := &Document{
+		OpenAPI:    Version,
+		Info:       o.Info,
+		Servers:    []Server{{URL: o.ServerURL}},
+		Paths:      map[string]*PathItem{},
+		Components: Components{Schemas: map[string]*Schema{}},
+		types:      map[reflect.Type]string{},
+		patterns:   o.Patterns,
+	}
+
+	for _, s := range o.Schemas {
+		for name, v := range s {
+			d.types[reflect.TypeOf(v)] = name
+		}
+	}
+
+	for t, name := range d.types {
+		d.Components.Schemas[name] = d.schemaOfStruct(t)
+	}
+
+	return d
+}
+
+// Ref returns a schema referencing the component schema with the given name
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// ArrayOf returns the schema of an array of the given items
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// JSON returns the content of a body in JSON with the given schema
+func JSON(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
+
+// AddOperation documents an operation on a path, e.g. /apps/{id}
+func (d *Document) AddOperation(method, path string, op *Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+}
+
+// Operation returns the operation documented for the method and path, or nil when there is none
+func (d *Document) Operation(method, path string) *Operation {
+	item, ok := d.Paths[path]
+	if !ok {
+		return nil
+	}
+	return (*item)[strings.ToLower(method)]
+}
+
+// Endpoint is the method and path of a documented operation
+type Endpoint struct {
+	Method string
+	Path   string
+}
+
+// Endpoints returns the method and path of every documented operation, sorted by path and method
+func (d *Document) Endpoints() []Endpoint {
+	var endpoints []Endpoint
+	for path, item := range d.Paths {
+		for method := range *item {
+			endpoints = append(endpoints, Endpoint{Method: strings.ToUpper(method), Path: path})
+		}
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+
+	return endpoints
+}
+
+// pathParamRegexp matches the path parameters of echo routes, e.g. :id
+var pathParamRegexp = regexp.MustCompile(`:([^/]+)`)
+
+// PathFromRoute converts the path of an echo route to its OpenAPI form, e.g. /apps/:id to /apps/{id}
+func PathFromRoute(route string) string {
+	return pathParamRegexp.ReplaceAllString(route, "{$1}")
+}
+
+// Resolve returns the component schema referenced by s, or s itself when it is not a reference
+func (d *Document) Resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
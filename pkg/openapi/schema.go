@@ -0,0 +1,189 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOfStruct generates the schema of a struct from its fields, named as they are encoded in JSON
+func (d *Document) schemaOfStruct(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	d.addFields(s, t)
+
+	return s
+}
+
+// addFields adds the properties of the fields of a struct to its schema.
+// The fields of the embedded structs are added as if they were fields of the struct, as encoding/json does.
+func (d *Document) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				d.addFields(s, ft)
+				continue
+			}
+		}
+
+		if f.PkgPath != "" || tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = f.Name
+		}
+		omitempty := strings.Contains(tag, ",omitempty")
+
+		fs := d.schemaOfType(f.Type)
+		if f.Type.Kind() == reflect.Ptr && !omitempty && fs.Ref == "" {
+			fs.Nullable = true
+		}
+
+		if d.applyRules(fs, f.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+
+		s.Properties[name] = fs
+	}
+}
+
+// schemaOfType generates the schema of a type. The registered types are referenced.
+func (d *Document) schemaOfType(t reflect.Type) *Schema {
+	if name, ok := d.types[t]; ok {
+		return Ref(name)
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return d.schemaOfType(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return ArrayOf(d.schemaOfType(t.Elem()))
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaOfType(t.Elem())}
+	case reflect.Struct:
+		return d.schemaOfStruct(t)
+	default:
+		// any value
+		return &Schema{}
+	}
+}
+
+// applyRules documents the validation tag of a field in its schema and returns if the field is required.
+// The rules following dive apply to the items of a list and are documented by the schema of the items.
+// The rules of a referenced schema are documented by the component schema.
+func (d *Document) applyRules(s *Schema, tag string) bool {
+	required := false
+	if tag == "" {
+		return required
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			name, param = rule[:i], rule[i+1:]
+		}
+
+		if name != "required" && s.Ref != "" {
+			continue
+		}
+
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+			if s.Type == "string" {
+				s.MinLength = intPtr(1)
+			}
+		case "uuid":
+			s.Format = "uuid"
+		case "min", "max":
+			applyLimit(s, name, param)
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				s.Enum = append(s.Enum, enumValue(s, v))
+			}
+		default:
+			if pattern, ok := d.patterns[name]; ok {
+				s.Pattern = pattern
+			}
+		}
+	}
+
+	return required
+}
+
+// applyLimit documents a min or max rule, which limits the value of a number, the length of a string
+// or the number of items of a list
+func applyLimit(s *Schema, rule, param string) {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case s.Type == "integer" || s.Type == "number":
+		limit := float64(n)
+		if rule == "min" {
+			s.Minimum = &limit
+		} else {
+			s.Maximum = &limit
+		}
+	case s.Type == "string" && rule == "min":
+		s.MinLength = &n
+	case s.Type == "string":
+		s.MaxLength = &n
+	case s.Type == "array" && rule == "min":
+		s.MinItems = &n
+	case s.Type == "array":
+		s.MaxItems = &n
+	}
+}
+
+// enumValue returns a value of a oneof rule with the type of the schema
+func enumValue(s *Schema, v string) interface{} {
+	if s.Type == "integer" || s.Type == "number" {
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	}
+	return v
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testVersion struct {
+	ID       string `json:"id" validate:"required,uuid"`
+	Launches int64  `json:"launches,omitempty" validate:"min=0"`
+}
+
+type testBase struct {
+	Name string `json:"name" validate:"required,appid"`
+}
+
+type testApp struct {
+	testBase
+	Sort      string        `json:"sort" validate:"omitempty,oneof=name -name"`
+	Versions  []testVersion `json:"versions" validate:"required,min=1,dive"`
+	Latest    *testVersion  `json:"latest,omitempty"`
+	Count     *int          `json:"count"`
+	DeletedAt time.Time     `json:"deletedAt"`
+	Labels    map[string]string
+	Ignored   string `json:"-"`
+	internal  string
+}
+
+func TestNewDocument(t *testing.T) {
+	d := NewDocument(Options{
+		Info:      Info{Title: "Test", Version: "1"},
+		ServerURL: "/api",
+		Schemas:   []Schemas{{"App": testApp{}, "Version": testVersion{}}},
+		Patterns:  map[string]string{"appid": `^[a-z]+(\.[a-z]+)+$`},
+	})
+
+	zero, one := 0.0, 1
+	want := map[string]*Schema{
+		"App": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"name":      {Type: "string", MinLength: &one, Pattern: `^[a-z]+(\.[a-z]+)+$`},
+				"sort":      {Type: "string", Enum: []interface{}{"name", "-name"}},
+				"versions":  {Type: "array", Items: Ref("Version"), MinItems: &one},
+				"latest":    Ref("Version"),
+				"count":     {Type: "integer", Format: "int64", Nullable: true},
+				"deletedAt": {Type: "string", Format: "date-time"},
+				"Labels":    {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+			},
+			Required: []string{"name", "versions"},
+		},
+		"Version": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"id":       {Type: "string", Format: "uuid", MinLength: &one},
+				"launches": {Type: "integer", Format: "int64", Minimum: &zero},
+			},
+			Required: []string{"id"},
+		},
+	}
+
+	if !reflect.DeepEqual(d.Components.Schemas, want) {
+		got, _ := json.MarshalIndent(d.Components.Schemas, "", "  ")
+		t.Errorf("NewDocument() schemas = %s", got)
+	}
+}
+
+func TestPathFromRoute(t *testing.T) {
+	tests := []struct {
+		route string
+		want  string
+	}{
+		{route: "/api/apps", want: "/api/apps"},
+		{route: "/api/apps/:id", want: "/api/apps/{id}"},
+		{route: "/api/apps/:id/versions/disable", want: "/api/apps/{id}/versions/disable"},
+	}
+	for _, tt := range tests {
+		if got := PathFromRoute(tt.route); got != tt.want {
+			t.Errorf("PathFromRoute(%v) = %v, want %v", tt.route, got, tt.want)
+		}
+	}
+}
+
+func TestDocument_Endpoints(t *testing.T) {
+	d := NewDocument(Options{ServerURL: "/api"})
+	d.AddOperation("POST", "/apps", &Operation{OperationID: "createApp"})
+	d.AddOperation("GET", "/apps/{id}", &Operation{OperationID: "getApp"})
+	d.AddOperation("GET", "/apps", &Operation{OperationID: "getApps"})
+
+	want := []Endpoint{{"GET", "/apps"}, {"POST", "/apps"}, {"GET", "/apps/{id}"}}
+	if got := d.Endpoints(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Document.Endpoints() = %v, want %v", got, want)
+	}
+
+	if op := d.Operation("GET", "/apps/{id}"); op == nil || op.OperationID != "getApp" {
+		t.Errorf("Document.Operation() = %v, want getApp", op)
+	}
+}
@@ -0,0 +1,262 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Validate validates a JSON value, as decoded by encoding/json, against a schema of the document.
+// The name is used in the violations of the value itself, e.g. body or the name of a parameter.
+// The properties which are not documented are allowed.
+func (d *Document) Validate(name string, s *Schema, v interface{}) []models.FieldError {
+	vd := validator{doc: d, root: name}
+	vd.validate("", name, s, v)
+	return vd.violations
+}
+
+// ValidateStrict validates a JSON value like Validate, also reporting the properties which are not documented
+func (d *Document) ValidateStrict(name string, s *Schema, v interface{}) []models.FieldError {
+	vd := validator{doc: d, root: name, strict: true}
+	vd.validate("", name, s, v)
+	return vd.violations
+}
+
+// ParseParameter converts the raw value of a path, query or header parameter to the JSON type of its schema
+func (d *Document) ParseParameter(s *Schema, raw string) (interface{}, error) {
+	switch d.Resolve(s).Type {
+	case "integer", "number":
+		return strconv.ParseFloat(raw, 64)
+	case "boolean":
+		return strconv.ParseBool(raw)
+	default:
+		return raw, nil
+	}
+}
+
+// ValidateParameter validates the raw value of a path, query or header parameter against its schema.
+// A value which cannot be converted to the type of the schema is reported as having the wrong type.
+func (d *Document) ValidateParameter(p Parameter, raw string) []models.FieldError {
+	v, err := d.ParseParameter(p.Schema, raw)
+	if err != nil {
+		v = raw
+	}
+	return d.Validate(p.Name, p.Schema, v)
+}
+
+type validator struct {
+	doc *Document
+	// root is the name of the value
+	root       string
+	strict     bool
+	violations []models.FieldError
+}
+
+func (vd *validator) fail(path, format string, args ...interface{}) {
+	if path == "" {
+		path = vd.root
+	}
+	vd.violations = append(vd.violations, models.FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// validate checks v against s. The path locates v in the value, e.g. versions[0].id, and is empty for the value itself.
+// The name is the last part of the path.
+func (vd *validator) validate(path, name string, s *Schema, v interface{}) {
+	s = vd.doc.Resolve(s)
+	if s == nil {
+		return
+	}
+
+	if v == nil {
+		if s.Type != "" && !s.Nullable {
+			vd.fail(path, "%v must be %v", name, article(s.Type))
+		}
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		vd.validateObject(path, name, s, v)
+	case "array":
+		vd.validateArray(path, name, s, v)
+	case "string":
+		vd.validateString(path, name, s, v)
+	case "integer", "number":
+		vd.validateNumber(path, name, s, v)
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			vd.fail(path, "%v must be a boolean", name)
+		}
+	}
+}
+
+func (vd *validator) validateObject(path, name string, s *Schema, v interface{}) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		vd.fail(path, "%v must be an object", name)
+		return
+	}
+
+	for _, required := range s.Required {
+		if _, ok := obj[required]; !ok {
+			vd.fail(childPath(path, required), "%v is required", required)
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if ps, ok := s.Properties[key]; ok {
+			vd.validate(childPath(path, key), key, ps, obj[key])
+			continue
+		}
+
+		if s.AdditionalProperties != nil {
+			vd.validate(childPath(path, key), key, s.AdditionalProperties, obj[key])
+			continue
+		}
+
+		if vd.strict && s.Properties != nil {
+			vd.fail(childPath(path, key), "%v is not allowed", key)
+		}
+	}
+}
+
+func (vd *validator) validateArray(path, name string, s *Schema, v interface{}) {
+	items, ok := v.([]interface{})
+	if !ok {
+		vd.fail(path, "%v must be an array", name)
+		return
+	}
+
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		vd.fail(path, "%v must contain at least %v items", name, *s.MinItems)
+	}
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		vd.fail(path, "%v must contain at most %v items", name, *s.MaxItems)
+	}
+
+	base := path
+	if base == "" {
+		base = vd.root
+	}
+	for i, item := range items {
+		vd.validate(fmt.Sprintf("%v[%d]", base, i), name, s.Items, item)
+	}
+}
+
+func (vd *validator) validateString(path, name string, s *Schema, v interface{}) {
+	str, ok := v.(string)
+	if !ok {
+		vd.fail(path, "%v must be a string", name)
+		return
+	}
+
+	// the other rules are not checked on a value which is too short, e.g. an empty required string
+	if s.MinLength != nil && len(str) < *s.MinLength {
+		if *s.MinLength == 1 {
+			vd.fail(path, "%v must not be empty", name)
+		} else {
+			vd.fail(path, "%v must be at least %v characters long", name, *s.MinLength)
+		}
+		return
+	}
+	if s.MaxLength != nil && len(str) > *s.MaxLength {
+		vd.fail(path, "%v must be at most %v characters long", name, *s.MaxLength)
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, str) {
+		vd.fail(path, "%v must be one of %v", name, s.Enum)
+	}
+
+	if s.Pattern != "" && !compile(s.Pattern).MatchString(str) {
+		vd.fail(path, "%v must match the pattern %v", name, s.Pattern)
+	}
+
+	switch s.Format {
+	case "uuid":
+		if _, err := uuid.Parse(str); err != nil {
+			vd.fail(path, "%v must be a valid UUID", name)
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			vd.fail(path, "%v must be a valid date-time", name)
+		}
+	}
+}
+
+func (vd *validator) validateNumber(path, name string, s *Schema, v interface{}) {
+	n, ok := v.(float64)
+	if !ok {
+		vd.fail(path, "%v must be %v", name, article(s.Type))
+		return
+	}
+
+	if s.Type == "integer" && n != math.Trunc(n) {
+		vd.fail(path, "%v must be an integer", name)
+	}
+
+	if s.Minimum != nil && n < *s.Minimum {
+		vd.fail(path, "%v must be at least %v", name, *s.Minimum)
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		vd.fail(path, "%v must be at most %v", name, *s.Maximum)
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, n) {
+		vd.fail(path, "%v must be one of %v", name, s.Enum)
+	}
+}
+
+// childPath returns the path of a property. The properties of the value itself are named without the name of the value.
+func childPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func article(t string) string {
+	if t == "integer" || t == "object" || t == "array" {
+		return "an " + t
+	}
+	return "a " + t
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	for _, e := range enum {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	patternsMu sync.Mutex
+	patterns   = map[string]*regexp.Regexp{}
+)
+
+// compile returns the compiled pattern of a schema, cached as the schemas do not change
+func compile(pattern string) *regexp.Regexp {
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+
+	re, ok := patterns[pattern]
+	if !ok {
+		re = regexp.MustCompile(pattern)
+		patterns[pattern] = re
+	}
+	return re
+}
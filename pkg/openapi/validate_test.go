@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+func TestDocument_Validate(t *testing.T) {
+	d := NewDocument(Options{
+		Schemas:  []Schemas{{"App": testApp{}, "Version": testVersion{}}},
+		Patterns: map[string]string{"appid": `^[a-z]+(\.[a-z]+)+$`},
+	})
+
+	tests := []struct {
+		name   string
+		schema *Schema
+		body   string
+		strict bool
+		want   []models.FieldError
+	}{
+		{
+			name:   "Validate() should accept a valid value",
+			schema: Ref("App"),
+			body:   `{"name": "com.example", "versions": [{"id": "55ebd387-9c68-4137-a367-a12025cc2cdb"}], "count": null, "deletedAt": "2019-08-20T10:00:00Z"}`,
+		},
+		{
+			name:   "Validate() should allow the properties which are not documented",
+			schema: Ref("App"),
+			body:   `{"name": "com.example", "versions": [{"id": "55ebd387-9c68-4137-a367-a12025cc2cdb", "version": "1.0"}]}`,
+		},
+		{
+			name:   "ValidateStrict() should report the properties which are not documented",
+			schema: Ref("App"),
+			body:   `{"name": "com.example", "versions": [{"id": "55ebd387-9c68-4137-a367-a12025cc2cdb", "version": "1.0"}]}`,
+			strict: true,
+			want:   []models.FieldError{{Field: "versions[0].version", Message: "version is not allowed"}},
+		},
+		{
+			name:   "Validate() should report every violation with its path",
+			schema: Ref("App"),
+			body:   `{"name": "Example", "sort": "size", "versions": [{"launches": 1.5}, {"id": "1", "launches": -1}], "latest": "1.0"}`,
+			want: []models.FieldError{
+				{Field: "latest", Message: "latest must be an object"},
+				{Field: "name", Message: `name must match the pattern ^[a-z]+(\.[a-z]+)+$`},
+				{Field: "sort", Message: "sort must be one of [name -name]"},
+				{Field: "versions[0].id", Message: "id is required"},
+				{Field: "versions[0].launches", Message: "launches must be an integer"},
+				{Field: "versions[1].id", Message: "id must be a valid UUID"},
+				{Field: "versions[1].launches", Message: "launches must be at least 0"},
+			},
+		},
+		{
+			name:   "Validate() should report the missing and empty required properties",
+			schema: Ref("App"),
+			body:   `{"name": "", "versions": []}`,
+			want: []models.FieldError{
+				{Field: "name", Message: "name must not be empty"},
+				{Field: "versions", Message: "versions must contain at least 1 items"},
+			},
+		},
+		{
+			name:   "Validate() should name the value itself",
+			schema: ArrayOf(Ref("Version")),
+			body:   `{"id": "55ebd387-9c68-4137-a367-a12025cc2cdb"}`,
+			want:   []models.FieldError{{Field: "body", Message: "body must be an array"}},
+		},
+		{
+			name:   "Validate() should name the items of the value itself",
+			schema: ArrayOf(Ref("Version")),
+			body:   `[{"id": null}]`,
+			want:   []models.FieldError{{Field: "body[0].id", Message: "id must be a string"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body interface{}
+			if err := json.Unmarshal([]byte(tt.body), &body); err != nil {
+				t.Fatal(err)
+			}
+
+			validate := d.Validate
+			if tt.strict {
+				validate = d.ValidateStrict
+			}
+
+			if got := validate("body", tt.schema, body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Document.Validate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_ParseParameter(t *testing.T) {
+	d := NewDocument(Options{})
+
+	tests := []struct {
+		name    string
+		schema  *Schema
+		raw     string
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "ParseParameter() should parse an integer", schema: &Schema{Type: "integer"}, raw: "10", want: 10.0},
+		{name: "ParseParameter() should parse a boolean", schema: &Schema{Type: "boolean"}, raw: "true", want: true},
+		{name: "ParseParameter() should keep a string", schema: &Schema{Type: "string"}, raw: "name", want: "name"},
+		{name: "ParseParameter() should fail on an invalid integer", schema: &Schema{Type: "integer"}, raw: "ten", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := d.ParseParameter(tt.schema, tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Document.ParseParameter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Document.ParseParameter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocument_ValidateParameter(t *testing.T) {
+	d := NewDocument(Options{})
+	max := 100.0
+	limit := Parameter{Name: "limit", In: "query", Schema: &Schema{Type: "integer", Maximum: &max}}
+
+	tests := []struct {
+		name string
+		raw  string
+		want []models.FieldError
+	}{
+		{name: "ValidateParameter() should accept a valid value", raw: "10"},
+		{
+			name: "ValidateParameter() should report a value of the wrong type",
+			raw:  "ten",
+			want: []models.FieldError{{Field: "limit", Message: "limit must be an integer"}},
+		},
+		{
+			name: "ValidateParameter() should check the parsed value against the schema",
+			raw:  "200",
+			want: []models.FieldError{{Field: "limit", Message: "limit must be at most 100"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.ValidateParameter(limit, tt.raw); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Document.ValidateParameter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
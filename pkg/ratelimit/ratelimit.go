@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// The dimensions by which the requests are limited
+const (
+	ClientIP = "ip"
+	App      = "app"
+	Device   = "device"
+)
+
+var RejectedRequestsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "rate_limit_rejected_requests_total",
+		Help: "A counter for the requests rejected by the rate limits, by the dimension whose limit was reached",
+	},
+	[]string{"dimension"},
+)
+
+// Limit is a token bucket: it holds up to Burst requests and is refilled with Rate requests per second.
+// A zero Limit does not limit the requests.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute returns a Limit of n requests per minute, all of which can be made at once. 0 is no limit.
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: n}
+}
+
+// Enabled reports whether the limit limits the requests
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Result is the outcome of taking a request from a bucket
+type Result struct {
+	Allowed bool
+	// RetryAfter is how long the client has to wait before a request is allowed again, when it is not
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets of the limited keys. Several instances of the service share their buckets
+// through a distributed store, while the memory store limits each instance on its own.
+type Store interface {
+	// Take refills the bucket of a key since it was last taken from and takes a request from it
+	Take(key string, limit Limit, now time.Time) (Result, error)
+	// Prune removes the buckets which are full at now, as a full bucket is the same as no bucket
+	Prune(now time.Time) error
+}
+
+// Limiter limits the requests by the values of several dimensions, e.g. the client IP and the app
+type Limiter struct {
+	store  Store
+	limits map[string]Limit
+}
+
+// NewLimiter returns a new Limiter of the buckets of the store with the limit of each dimension.
+// The dimensions without an enabled limit are not limited.
+func NewLimiter(store Store, limits map[string]Limit) *Limiter {
+	return &Limiter{
+		store:  store,
+		limits: limits,
+	}
+}
+
+// Allow takes a request from the bucket of the value of a dimension. The requests are allowed when
+// the store fails, so an outage of a distributed store does not stop the clients.
+func (l *Limiter) Allow(dimension, value string) Result {
+	limit := l.limits[dimension]
+	if !limit.Enabled() {
+		return Result{Allowed: true}
+	}
+
+	res, err := l.store.Take(dimension+":"+strings.ToLower(value), limit, time.Now())
+	if err != nil {
+		log.WithField("dimension", dimension).Errorf("Failed to take a request from the rate limit bucket, it is allowed: %v", err)
+		return Result{Allowed: true}
+	}
+
+	if !res.Allowed {
+		RejectedRequestsTotal.WithLabelValues(dimension).Inc()
+	}
+	return res
+}
+
+// take refills a bucket which had tokens requests left when it was updated and takes a request from it.
+// It returns the requests left and when the bucket is full again.
+func take(tokens float64, updated time.Time, limit Limit, now time.Time) (float64, time.Time, Result) {
+	burst := float64(limit.Burst)
+	if elapsed := now.Sub(updated).Seconds(); elapsed > 0 {
+		tokens = math.Min(burst, tokens+elapsed*limit.Rate)
+	}
+
+	res := Result{Allowed: tokens >= 1}
+	if res.Allowed {
+		tokens--
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return tokens, now.Add(seconds((burst - tokens) / limit.Rate)), res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
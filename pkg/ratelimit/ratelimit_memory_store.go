@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	fullAt  time.Time
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewMemoryStore returns a Store keeping the buckets in memory, which limits each instance of the service
+// on its own and forgets the buckets when it restarts
+func NewMemoryStore() Store {
+	return &memoryStore{buckets: map[string]*bucket{}}
+}
+
+// Take implements the Store contract
+func (s *memoryStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+
+	tokens, fullAt, res := take(b.tokens, b.updated, limit, now)
+	b.tokens, b.updated, b.fullAt = tokens, now, fullAt
+
+	return res, nil
+}
+
+// Prune implements the Store contract
+func (s *memoryStore) Prune(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if !b.fullAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
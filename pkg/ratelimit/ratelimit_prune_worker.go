@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/lifecycle"
+	log "github.com/sirupsen/logrus"
+)
+
+type pruneWorker struct {
+	store    Store
+	interval time.Duration
+}
+
+// NewPruneWorker returns a background worker which removes the full buckets of the store every interval,
+// so the buckets of the clients which stopped sending requests do not pile up
+func NewPruneWorker(s Store, interval time.Duration) lifecycle.Worker {
+	return &pruneWorker{
+		store:    s,
+		interval: interval,
+	}
+}
+
+// Run prunes the buckets until the context is cancelled
+func (w *pruneWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.store.Prune(time.Now()); err != nil {
+				log.Errorf("Failed to prune the rate limit buckets: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type postgreSQLStore struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLStore returns a Store keeping the buckets in the database, shared by all the instances
+// of the service. Each request takes a row lock on its bucket, so the buckets are taken from in turn.
+func NewPostgreSQLStore(db *sql.DB) Store {
+	return &postgreSQLStore{db}
+}
+
+// Take implements the Store contract
+func (s *postgreSQLStore) Take(key string, limit Limit, now time.Time) (Result, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return Result{}, err
+	}
+
+	res, err := s.take(tx, key, limit, now)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error(rbErr)
+		}
+		return Result{}, err
+	}
+
+	return res, tx.Commit()
+}
+
+func (s *postgreSQLStore) take(tx *sql.Tx, key string, limit Limit, now time.Time) (Result, error) {
+	// the bucket is created full, unless another request created it first
+	if _, err := tx.Exec(`
+	INSERT INTO rate_limit_bucket(key, tokens, updated_at, full_at)
+	VALUES($1, $2, $3, $3)
+	ON CONFLICT (key) DO NOTHING;`, key, limit.Burst, now); err != nil {
+		return Result{}, err
+	}
+
+	var tokens float64
+	var updated time.Time
+	if err := tx.QueryRow(`
+	SELECT tokens,updated_at
+	FROM rate_limit_bucket
+	WHERE key=$1
+	FOR UPDATE;`, key).Scan(&tokens, &updated); err != nil {
+		return Result{}, err
+	}
+
+	tokens, fullAt, res := take(tokens, updated, limit, now)
+
+	if _, err := tx.Exec(`
+	UPDATE rate_limit_bucket
+	SET tokens=$2, updated_at=$3, full_at=$4
+	WHERE key=$1;`, key, tokens, now, fullAt); err != nil {
+		return Result{}, err
+	}
+
+	return res, nil
+}
+
+// Prune implements the Store contract
+func (s *postgreSQLStore) Prune(now time.Time) error {
+	_, err := s.db.Exec(`DELETE FROM rate_limit_bucket WHERE full_at <= $1;`, now)
+	return err
+}
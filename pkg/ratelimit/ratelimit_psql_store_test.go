@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	insertBucketQueryString = `INSERT INTO rate_limit_bucket\(key, tokens, updated_at, full_at\)
+	VALUES\(\$1, \$2, \$3, \$3\)
+	ON CONFLICT \(key\) DO NOTHING;`
+	selectBucketQueryString = `SELECT tokens,updated_at
+	FROM rate_limit_bucket
+	WHERE key=\$1
+	FOR UPDATE;`
+	updateBucketQueryString = `UPDATE rate_limit_bucket
+	SET tokens=\$2, updated_at=\$3, full_at=\$4
+	WHERE key=\$1;`
+)
+
+func Test_postgreSQLStore_Take(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	limit := Limit{Rate: 1, Burst: 2}
+
+	tests := []struct {
+		name    string
+		expect  func()
+		want    Result
+		wantErr bool
+	}{
+		{
+			name: "Should take a request from the refilled bucket",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(insertBucketQueryString).WithArgs("ip:10.0.0.1", 2, now).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(selectBucketQueryString).WithArgs("ip:10.0.0.1").
+					WillReturnRows(sqlmock.NewRows([]string{"tokens", "updated_at"}).AddRow(0.5, now.Add(-time.Second)))
+				mock.ExpectExec(updateBucketQueryString).WithArgs("ip:10.0.0.1", 0.5, now, now.Add(1500*time.Millisecond)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: Result{Allowed: true},
+		},
+		{
+			name: "Should reject the request of an empty bucket",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(insertBucketQueryString).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(selectBucketQueryString).
+					WillReturnRows(sqlmock.NewRows([]string{"tokens", "updated_at"}).AddRow(0, now))
+				mock.ExpectExec(updateBucketQueryString).WithArgs("ip:10.0.0.1", 0.0, now, now.Add(2*time.Second)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: Result{RetryAfter: time.Second},
+		},
+		{
+			name: "Should roll back and return the error of the database",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(insertBucketQueryString).WillReturnError(errors.New("connection refused"))
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			got, err := NewPostgreSQLStore(db).Take("ip:10.0.0.1", limit, now)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("postgreSQLStore.Take() = %+v, %v, want %+v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_postgreSQLStore_Prune(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`DELETE FROM rate_limit_bucket WHERE full_at <= \$1;`).WithArgs(now).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	if err := NewPostgreSQLStore(db).Prune(now); err != nil {
+		t.Errorf("postgreSQLStore.Prune() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,185 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gatherRejectedRequests returns the rejected requests counted by dimension
+func gatherRejectedRequests(t *testing.T) map[string]float64 {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(RejectedRequestsTotal)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error gathering the metrics: %v", err)
+	}
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values[metric.GetLabel()[0].GetValue()] = metric.GetCounter().GetValue()
+		}
+	}
+	return values
+}
+
+func Test_take(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	limit := Limit{Rate: 2, Burst: 4}
+
+	tests := []struct {
+		name       string
+		tokens     float64
+		updated    time.Time
+		wantTokens float64
+		wantFullAt time.Time
+		want       Result
+	}{
+		{
+			name:       "Should take a request from a full bucket",
+			tokens:     4,
+			updated:    now,
+			wantTokens: 3,
+			wantFullAt: now.Add(500 * time.Millisecond),
+			want:       Result{Allowed: true},
+		},
+		{
+			name:       "Should refill the bucket since it was updated",
+			tokens:     0,
+			updated:    now.Add(-time.Second),
+			wantTokens: 1,
+			wantFullAt: now.Add(1500 * time.Millisecond),
+			want:       Result{Allowed: true},
+		},
+		{
+			name:       "Should not refill the bucket over its burst",
+			tokens:     3,
+			updated:    now.Add(-time.Hour),
+			wantTokens: 3,
+			wantFullAt: now.Add(500 * time.Millisecond),
+			want:       Result{Allowed: true},
+		},
+		{
+			name:       "Should reject the request of an empty bucket with the time until the next one",
+			tokens:     0.5,
+			updated:    now,
+			wantTokens: 0.5,
+			wantFullAt: now.Add(1750 * time.Millisecond),
+			want:       Result{Allowed: false, RetryAfter: 250 * time.Millisecond},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, fullAt, got := take(tt.tokens, tt.updated, limit, now)
+
+			if tokens != tt.wantTokens || !fullAt.Equal(tt.wantFullAt) || got != tt.want {
+				t.Errorf("take() = %v, %v, %+v, want %v, %v, %+v", tokens, fullAt, got, tt.wantTokens, tt.wantFullAt, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	tests := []struct {
+		name         string
+		store        *StoreMock
+		dimension    string
+		want         Result
+		wantKey      string
+		wantRejected float64
+	}{
+		{
+			name: "Should take the request from the bucket of the value of the dimension",
+			store: &StoreMock{
+				TakeFunc: func(key string, limit Limit, now time.Time) (Result, error) {
+					return Result{Allowed: true}, nil
+				},
+			},
+			dimension: Device,
+			want:      Result{Allowed: true},
+			wantKey:   "device:a7b4fd2f-7f5e-4b4a-a9e1-7d3c4d0e6b1f",
+		},
+		{
+			name: "Should count the rejected requests",
+			store: &StoreMock{
+				TakeFunc: func(key string, limit Limit, now time.Time) (Result, error) {
+					return Result{RetryAfter: time.Second}, nil
+				},
+			},
+			dimension:    Device,
+			want:         Result{RetryAfter: time.Second},
+			wantKey:      "device:a7b4fd2f-7f5e-4b4a-a9e1-7d3c4d0e6b1f",
+			wantRejected: 1,
+		},
+		{
+			name: "Should allow the request when the store fails",
+			store: &StoreMock{
+				TakeFunc: func(key string, limit Limit, now time.Time) (Result, error) {
+					return Result{}, errors.New("connection refused")
+				},
+			},
+			dimension: Device,
+			want:      Result{Allowed: true},
+			wantKey:   "device:a7b4fd2f-7f5e-4b4a-a9e1-7d3c4d0e6b1f",
+		},
+		{
+			name:      "Should allow the requests of a dimension without limit",
+			store:     &StoreMock{},
+			dimension: App,
+			want:      Result{Allowed: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RejectedRequestsTotal.Reset()
+
+			l := NewLimiter(tt.store, map[string]Limit{Device: PerMinute(10), App: PerMinute(0)})
+			if got := l.Allow(tt.dimension, "A7B4FD2F-7F5E-4B4A-A9E1-7D3C4D0E6B1F"); got != tt.want {
+				t.Errorf("Limiter.Allow() = %+v, want %+v", got, tt.want)
+			}
+
+			var keys []string
+			for _, call := range tt.store.TakeCalls() {
+				keys = append(keys, call.Key)
+			}
+			if got := strings.Join(keys, ","); got != tt.wantKey {
+				t.Errorf("Limiter.Allow() took from %v, want %v", got, tt.wantKey)
+			}
+
+			if got := gatherRejectedRequests(t)[tt.dimension]; got != tt.wantRejected {
+				t.Errorf("Limiter.Allow() rejected %v requests, want %v", got, tt.wantRejected)
+			}
+		})
+	}
+}
+
+func Test_memoryStore(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	limit := Limit{Rate: 1, Burst: 2}
+	s := NewMemoryStore().(*memoryStore)
+
+	var got []string
+	for i, at := range []time.Duration{0, 0, 0, 500 * time.Millisecond, time.Second} {
+		res, _ := s.Take("ip:10.0.0.1", limit, now.Add(at))
+		got = append(got, fmt.Sprintf("%v:%v", i, res.Allowed))
+	}
+
+	if want := "0:true,1:true,2:false,3:false,4:true"; strings.Join(got, ",") != want {
+		t.Errorf("memoryStore.Take() = %v, want %v", strings.Join(got, ","), want)
+	}
+
+	s.Take("ip:10.0.0.2", limit, now)
+
+	if err := s.Prune(now.Add(1500 * time.Millisecond)); err != nil {
+		t.Fatalf("memoryStore.Prune() error = %v", err)
+	}
+	if _, ok := s.buckets["ip:10.0.0.2"]; ok || len(s.buckets) != 1 {
+		t.Errorf("memoryStore.Prune() kept %v buckets, want the bucket which is not full", len(s.buckets))
+	}
+}
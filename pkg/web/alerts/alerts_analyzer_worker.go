@@ -0,0 +1,50 @@
+package alerts
+
+import (
+	"context"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/lifecycle"
+	log "github.com/sirupsen/logrus"
+)
+
+type analyzerWorker struct {
+	service  Service
+	interval time.Duration
+}
+
+// NewAnalyzerWorker returns a background worker which analyses, every interval, the recent init requests
+func NewAnalyzerWorker(s Service, interval time.Duration) lifecycle.Worker {
+	return &analyzerWorker{
+		service:  s,
+		interval: interval,
+	}
+}
+
+// Run analyses the init requests until the context is cancelled
+func (w *analyzerWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.analyze()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *analyzerWorker) analyze() {
+	detected, err := w.service.Analyze(time.Now())
+	if err != nil {
+		log.Errorf("Failed to analyse the init requests: %v", err)
+		return
+	}
+
+	if detected > 0 {
+		log.WithField("alerts", detected).Warn("Detected suspicious init requests")
+	}
+}
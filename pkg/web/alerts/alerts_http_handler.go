@@ -0,0 +1,42 @@
+package alerts
+
+import (
+	"net/http"
+
+	"github.com/aerogear/mobile-security-service/pkg/httperrors"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/labstack/echo"
+)
+
+type (
+	HTTPHandler interface {
+		GetAlerts(c echo.Context) error
+	}
+
+	// httpHandler instance
+	httpHandler struct {
+		Service Service
+	}
+)
+
+// NewHTTPHandler returns a new instance of alerts.Handler
+func NewHTTPHandler(e *echo.Echo, s Service) HTTPHandler {
+	return &httpHandler{
+		Service: s,
+	}
+}
+
+// GetAlerts returns the alerts raised by the analysis of the init requests of an app
+func (h *httpHandler) GetAlerts(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	alerts, err := h.Service.GetAlerts(params.ID)
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newAlertsResponse(alerts))
+}
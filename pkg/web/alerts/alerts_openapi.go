@@ -0,0 +1,10 @@
+package alerts
+
+import "github.com/aerogear/mobile-security-service/pkg/openapi"
+
+// Schemas returns the responses of the alerts endpoints by the name of their schema in the API documentation
+func Schemas() openapi.Schemas {
+	return openapi.Schemas{
+		"AlertResponse": alertResponse{},
+	}
+}
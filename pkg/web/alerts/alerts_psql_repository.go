@@ -0,0 +1,238 @@
+package alerts
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	alertsPostgreSQLRepository struct {
+		db *sql.DB
+	}
+)
+
+// NewPostgreSQLRepository creates a new instance of alertsPostgreSQLRepository
+func NewPostgreSQLRepository(db *sql.DB) Repository {
+	return &alertsPostgreSQLRepository{db}
+}
+
+// GetAlertsByAppID returns the alerts of an app by its app ID, the last detected first
+func (a *alertsPostgreSQLRepository) GetAlertsByAppID(appID string) ([]models.Alert, error) {
+	rows, err := a.db.Query(`
+	SELECT id,app_id,kind,subject,value,threshold,first_detected_at,last_detected_at
+	FROM alert
+	WHERE LOWER(app_id)=$1
+	ORDER BY last_detected_at DESC, kind, subject;`, strings.ToLower(appID))
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	alerts := []models.Alert{}
+	err = scanRows(rows, func() error {
+		var alert models.Alert
+		if err := rows.Scan(&alert.ID, &alert.AppID, &alert.Kind, &alert.Subject, &alert.Value, &alert.Threshold, &alert.FirstDetectedAt, &alert.LastDetectedAt); err != nil {
+			return err
+		}
+		alerts = append(alerts, alert)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+// ExportAppAlerts adds the alerts of an app to the archive of its hard delete, the last detected first
+func ExportAppAlerts(tx *sql.Tx, appID string, archive *models.AppArchive) error {
+	rows, err := tx.Query(`
+	SELECT id,app_id,kind,subject,value,threshold,first_detected_at,last_detected_at
+	FROM alert
+	WHERE app_id=$1
+	ORDER BY last_detected_at DESC, kind, subject;`, appID)
+
+	if err != nil {
+		return err
+	}
+
+	return scanRows(rows, func() error {
+		var alert models.Alert
+		if err := rows.Scan(&alert.ID, &alert.AppID, &alert.Kind, &alert.Subject, &alert.Value, &alert.Threshold, &alert.FirstDetectedAt, &alert.LastDetectedAt); err != nil {
+			return err
+		}
+		archive.Alerts = append(archive.Alerts, alert)
+		return nil
+	})
+}
+
+// SaveAlerts creates the alerts, or updates the value, the threshold and the last detection of the alerts
+// of the same app, kind and subject
+func (a *alertsPostgreSQLRepository) SaveAlerts(alerts []models.Alert) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	for _, alert := range alerts {
+		_, err := tx.Exec(`
+		INSERT INTO alert(id,app_id,kind,subject,value,threshold,first_detected_at,last_detected_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (app_id, kind, subject)
+		DO UPDATE
+		SET value=$5, threshold=$6, last_detected_at=$8;`,
+			alert.ID, alert.AppID, alert.Kind, alert.Subject, alert.Value, alert.Threshold, alert.FirstDetectedAt, alert.LastDetectedAt)
+
+		if err != nil {
+			log.Error(err)
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Error(rbErr)
+			}
+			return models.ErrDatabaseError
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// DeleteAlertsDetectedBefore deletes the alerts which were last detected before the given time
+// and returns how many were deleted
+func (a *alertsPostgreSQLRepository) DeleteAlertsDetectedBefore(before time.Time) (int64, error) {
+	res, err := a.db.Exec(`DELETE FROM alert WHERE last_detected_at < $1;`, before)
+	if err != nil {
+		log.Error(err)
+		return 0, models.ErrDatabaseError
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		log.Error(err)
+		return 0, models.ErrDatabaseError
+	}
+
+	return deleted, nil
+}
+
+// GetNewDevicesCounts returns, for each active app with devices first seen since the given time, how many
+// were first seen from until on, the recent ones, and how many before
+func (a *alertsPostgreSQLRepository) GetNewDevicesCounts(since, until time.Time) ([]NewDevicesCount, error) {
+	rows, err := a.db.Query(`
+	SELECT d.app_id,
+		COUNT(*) FILTER (WHERE d.created_at >= $2),
+		COUNT(*) FILTER (WHERE d.created_at < $2)
+	FROM device d
+	JOIN app a ON a.app_id = d.app_id AND a.deleted_at IS NULL
+	WHERE d.created_at >= $1
+	GROUP BY d.app_id;`, since, until)
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	var counts []NewDevicesCount
+	err = scanRows(rows, func() error {
+		var c NewDevicesCount
+		if err := rows.Scan(&c.AppID, &c.Recent, &c.Baseline); err != nil {
+			return err
+		}
+		counts = append(counts, c)
+		return nil
+	})
+
+	return counts, err
+}
+
+// GetVersionsUsedBy returns the versions of the active apps which were launched for the first time between
+// since and until, and are used by less than maxDevices devices
+func (a *alertsPostgreSQLRepository) GetVersionsUsedBy(since, until time.Time, maxDevices int) ([]VersionDevicesCount, error) {
+	rows, err := a.db.Query(`
+	SELECT v.app_id, v.version, COUNT(d.id)
+	FROM version v
+	JOIN app a ON a.app_id = v.app_id AND a.deleted_at IS NULL
+	LEFT JOIN device d ON d.version_id = v.id
+	WHERE v.created_at >= $1 AND v.created_at < $2
+	GROUP BY v.app_id, v.version
+	HAVING COUNT(d.id) < $3;`, since, until, maxDevices)
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	var counts []VersionDevicesCount
+	err = scanRows(rows, func() error {
+		var c VersionDevicesCount
+		if err := rows.Scan(&c.AppID, &c.Version, &c.Devices); err != nil {
+			return err
+		}
+		counts = append(counts, c)
+		return nil
+	})
+
+	return counts, err
+}
+
+// GetDevicesWithOSVersions returns the devices of the active apps which reported at least minVersions
+// OS versions for the first time since the given time
+func (a *alertsPostgreSQLRepository) GetDevicesWithOSVersions(since time.Time, minVersions int) ([]DeviceOSVersionsCount, error) {
+	rows, err := a.db.Query(`
+	SELECT d.app_id, d.device_id, COUNT(*)
+	FROM device_os_version o
+	JOIN device d ON d.id = o.device_id
+	JOIN app a ON a.app_id = d.app_id AND a.deleted_at IS NULL
+	WHERE o.first_seen_at >= $1
+	GROUP BY d.app_id, d.device_id
+	HAVING COUNT(*) >= $2;`, since, minVersions)
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	var counts []DeviceOSVersionsCount
+	err = scanRows(rows, func() error {
+		var c DeviceOSVersionsCount
+		if err := rows.Scan(&c.AppID, &c.DeviceID, &c.OSVersions); err != nil {
+			return err
+		}
+		counts = append(counts, c)
+		return nil
+	})
+
+	return counts, err
+}
+
+// scanRows calls scan for each row, then closes the rows
+func scanRows(rows *sql.Rows, scan func() error) error {
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		if err := scan(); err != nil {
+			log.Error(err)
+			return models.ErrDatabaseError
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	return nil
+}
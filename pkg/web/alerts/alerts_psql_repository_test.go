@@ -0,0 +1,194 @@
+package alerts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+const (
+	saveAlertQueryString = `INSERT INTO alert\(id,app_id,kind,subject,value,threshold,first_detected_at,last_detected_at\)
+		VALUES\(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\)
+		ON CONFLICT \(app_id, kind, subject\)
+		DO UPDATE
+		SET value=\$5, threshold=\$6, last_detected_at=\$8;`
+	getNewDevicesCountsQueryString = `SELECT d.app_id,
+		COUNT\(\*\) FILTER \(WHERE d.created_at >= \$2\),
+		COUNT\(\*\) FILTER \(WHERE d.created_at < \$2\)
+	FROM device d`
+)
+
+var alertRowColumns = []string{"id", "app_id", "kind", "subject", "value", "threshold", "first_detected_at", "last_detected_at"}
+
+func Test_alertsPostgreSQLRepository_GetAlertsByAppID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	detectedAt := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	want := []models.Alert{{
+		ID: "5b1e2f3a-4c5d-4e6f-8a9b-0c1d2e3f4a5b", AppID: "com.aerogear.mobile_app_one", Kind: models.AlertUnreleasedVersion,
+		Subject: "9.9.9", Value: 1, Threshold: 3, FirstDetectedAt: detectedAt, LastDetectedAt: detectedAt,
+	}}
+
+	mock.ExpectQuery(`SELECT id,app_id,kind,subject,value,threshold,first_detected_at,last_detected_at
+	FROM alert
+	WHERE LOWER\(app_id\)=\$1`).WithArgs("com.aerogear.mobile_app_one").
+		WillReturnRows(sqlmock.NewRows(alertRowColumns).
+			AddRow(want[0].ID, want[0].AppID, want[0].Kind, want[0].Subject, want[0].Value, want[0].Threshold, detectedAt, detectedAt))
+
+	got, err := NewPostgreSQLRepository(db).GetAlertsByAppID("com.aerogear.MOBILE_app_one")
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("alertsPostgreSQLRepository.GetAlertsByAppID() = %+v, %v, want %+v", got, err, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestExportAppAlerts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	detectedAt := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	want := []models.Alert{{
+		ID: "5b1e2f3a-4c5d-4e6f-8a9b-0c1d2e3f4a5b", AppID: "com.aerogear.mobile_app_one", Kind: models.AlertUnreleasedVersion,
+		Subject: "9.9.9", Value: 1, Threshold: 3, FirstDetectedAt: detectedAt, LastDetectedAt: detectedAt,
+	}}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id,app_id,kind,subject,value,threshold,first_detected_at,last_detected_at
+	FROM alert
+	WHERE app_id=\$1`).WithArgs("com.aerogear.mobile_app_one").
+		WillReturnRows(sqlmock.NewRows(alertRowColumns).
+			AddRow(want[0].ID, want[0].AppID, want[0].Kind, want[0].Subject, want[0].Value, want[0].Threshold, detectedAt, detectedAt))
+	mock.ExpectRollback()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Unexpected error beginning a stub transaction: %v", err)
+	}
+
+	archive := models.AppArchive{}
+	if err := ExportAppAlerts(tx, "com.aerogear.mobile_app_one", &archive); err != nil || !reflect.DeepEqual(archive.Alerts, want) {
+		t.Errorf("ExportAppAlerts() archived = %+v, %v, want %+v", archive.Alerts, err, want)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Errorf("Unexpected error rolling back the stub transaction: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_alertsPostgreSQLRepository_SaveAlerts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	detectedAt := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	alert := models.Alert{
+		ID: "5b1e2f3a-4c5d-4e6f-8a9b-0c1d2e3f4a5b", AppID: "com.aerogear.mobile_app_one", Kind: models.AlertNewDevicesSpike,
+		Value: 150, Threshold: 100, FirstDetectedAt: detectedAt, LastDetectedAt: detectedAt,
+	}
+
+	tests := []struct {
+		name    string
+		expect  func()
+		wantErr error
+	}{
+		{
+			name: "Should save the alerts in a transaction",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(saveAlertQueryString).
+					WithArgs(alert.ID, alert.AppID, alert.Kind, alert.Subject, alert.Value, alert.Threshold, detectedAt, detectedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Should roll back and return a database error when an alert can not be saved",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(saveAlertQueryString).WillReturnError(models.ErrInternalServerError)
+				mock.ExpectRollback()
+			},
+			wantErr: models.ErrDatabaseError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			if err := NewPostgreSQLRepository(db).SaveAlerts([]models.Alert{alert}); err != tt.wantErr {
+				t.Errorf("alertsPostgreSQLRepository.SaveAlerts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_alertsPostgreSQLRepository_GetNewDevicesCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	since := time.Date(2020, 1, 1, 11, 0, 0, 0, time.UTC)
+	until := since.Add(24 * time.Hour)
+
+	mock.ExpectQuery(getNewDevicesCountsQueryString).WithArgs(since, until).
+		WillReturnRows(sqlmock.NewRows([]string{"app_id", "recent", "baseline"}).AddRow("com.aerogear.mobile_app_one", 150, 480))
+
+	got, err := NewPostgreSQLRepository(db).GetNewDevicesCounts(since, until)
+	want := []NewDevicesCount{{AppID: "com.aerogear.mobile_app_one", Recent: 150, Baseline: 480}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("alertsPostgreSQLRepository.GetNewDevicesCounts() = %+v, %v, want %+v", got, err, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_alertsPostgreSQLRepository_DeleteAlertsDetectedBefore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	before := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`DELETE FROM alert WHERE last_detected_at < \$1;`).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if got, err := NewPostgreSQLRepository(db).DeleteAlertsDetectedBefore(before); err != nil || got != 2 {
+		t.Errorf("alertsPostgreSQLRepository.DeleteAlertsDetectedBefore() = %v, %v, want 2", got, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,38 @@
+package alerts
+
+import (
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// Repository represent the alert's repository contract
+type Repository interface {
+	GetAlertsByAppID(appID string) ([]models.Alert, error)
+	SaveAlerts(alerts []models.Alert) error
+	DeleteAlertsDetectedBefore(before time.Time) (int64, error)
+	GetNewDevicesCounts(since, until time.Time) ([]NewDevicesCount, error)
+	GetVersionsUsedBy(since, until time.Time, maxDevices int) ([]VersionDevicesCount, error)
+	GetDevicesWithOSVersions(since time.Time, minVersions int) ([]DeviceOSVersionsCount, error)
+}
+
+// NewDevicesCount is the number of devices of an app which were first seen in the recent window, and before it
+type NewDevicesCount struct {
+	AppID    string
+	Recent   int64
+	Baseline int64
+}
+
+// VersionDevicesCount is the number of devices using a version
+type VersionDevicesCount struct {
+	AppID   string
+	Version string
+	Devices int64
+}
+
+// DeviceOSVersionsCount is the number of OS versions a device reported for the first time
+type DeviceOSVersionsCount struct {
+	AppID      string
+	DeviceID   string
+	OSVersions int64
+}
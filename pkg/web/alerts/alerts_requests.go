@@ -0,0 +1,6 @@
+package alerts
+
+// appIDParams holds the id path parameter of the alerts of an app
+type appIDParams struct {
+	ID string `param:"id" validate:"uuid"`
+}
@@ -0,0 +1,42 @@
+package alerts
+
+import (
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// alertResponse is an alert returned by GET /apps/{id}/alerts
+// swagger:model AlertResponse
+type alertResponse struct {
+	ID    string `json:"id"`
+	AppID string `json:"appId"`
+	// Kind is new_devices_spike, unreleased_version or device_os_versions_cycling
+	Kind string `json:"kind"`
+	// Subject is the version of an unreleased_version alert and the device id of a device_os_versions_cycling one
+	Subject string `json:"subject,omitempty"`
+	// Value is the measure which crossed the threshold: the new devices, the devices using the version,
+	// or the OS versions of the device
+	Value           int64     `json:"value"`
+	Threshold       int64     `json:"threshold"`
+	FirstDetectedAt time.Time `json:"firstDetectedAt"`
+	LastDetectedAt  time.Time `json:"lastDetectedAt"`
+}
+
+// newAlertsResponse maps the alerts to their responses
+func newAlertsResponse(alerts []models.Alert) []alertResponse {
+	res := make([]alertResponse, 0, len(alerts))
+	for _, alert := range alerts {
+		res = append(res, alertResponse{
+			ID:              alert.ID,
+			AppID:           alert.AppID,
+			Kind:            alert.Kind,
+			Subject:         alert.Subject,
+			Value:           alert.Value,
+			Threshold:       alert.Threshold,
+			FirstDetectedAt: alert.FirstDetectedAt,
+			LastDetectedAt:  alert.LastDetectedAt,
+		})
+	}
+	return res
+}
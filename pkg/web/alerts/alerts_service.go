@@ -0,0 +1,156 @@
+package alerts
+
+import (
+	"math"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// baselineWindows is the number of windows before the analysed one whose average of new devices is the
+// usual number of new devices of an app
+const baselineWindows = 24
+
+type (
+	// Service defines the interface methods to be used
+	Service interface {
+		GetAlerts(id string) ([]models.Alert, error)
+		Analyze(now time.Time) (int, error)
+	}
+
+	alertsService struct {
+		repository     Repository
+		appsRepository apps.Repository
+		config         config.AlertsConfig
+	}
+)
+
+// NewService instantiates this service. The apps of the alerts are read from the apps repository.
+func NewService(repository Repository, appsRepository apps.Repository, c config.AlertsConfig) Service {
+	return &alertsService{
+		repository:     repository,
+		appsRepository: appsRepository,
+		config:         c,
+	}
+}
+
+// GetAlerts returns the alerts of the app with the given id, the last detected first
+func (a *alertsService) GetAlerts(id string) ([]models.Alert, error) {
+	app, err := a.appsRepository.GetActiveAppByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.repository.GetAlertsByAppID(app.AppID)
+}
+
+// Analyze looks for the suspicious patterns of the init requests of the window before now, saves the alerts
+// and deletes the ones past the retention period. It returns the number of alerts detected.
+func (a *alertsService) Analyze(now time.Time) (int, error) {
+	var alerts []models.Alert
+	for _, detect := range []func(now time.Time) ([]models.Alert, error){a.newDevicesSpikes, a.unreleasedVersions, a.devicesCyclingOSVersions} {
+		detected, err := detect(now)
+		if err != nil {
+			return 0, err
+		}
+		alerts = append(alerts, detected...)
+	}
+
+	if len(alerts) > 0 {
+		if err := a.repository.SaveAlerts(alerts); err != nil {
+			return 0, err
+		}
+	}
+
+	if a.config.Retention > 0 {
+		deleted, err := a.repository.DeleteAlertsDetectedBefore(now.Add(-a.config.Retention))
+		if err != nil {
+			return 0, err
+		}
+
+		if deleted > 0 {
+			log.WithField("alerts", deleted).Info("Deleted the alerts past the retention period")
+		}
+	}
+
+	return len(alerts), nil
+}
+
+// newDevicesSpikes detects the apps with more new devices in the window than the threshold,
+// and than the spike factor times their average of the previous windows
+func (a *alertsService) newDevicesSpikes(now time.Time) ([]models.Alert, error) {
+	if a.config.NewDevices <= 0 {
+		return nil, nil
+	}
+
+	since := now.Add(-a.config.Window * (baselineWindows + 1))
+	counts, err := a.repository.GetNewDevicesCounts(since, now.Add(-a.config.Window))
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []models.Alert
+	for _, c := range counts {
+		average := float64(c.Baseline) / baselineWindows
+		threshold := int64(math.Max(float64(a.config.NewDevices), math.Ceil(average*float64(a.config.NewDevicesSpikeFactor))))
+
+		if c.Recent >= threshold {
+			alerts = append(alerts, newAlert(c.AppID, models.AlertNewDevicesSpike, "", c.Recent, threshold, now))
+		}
+	}
+	return alerts, nil
+}
+
+// unreleasedVersions detects the versions launched for the first time in the window before the analysed one,
+// which had the time to be adopted, that are still used by less devices than the threshold
+func (a *alertsService) unreleasedVersions(now time.Time) ([]models.Alert, error) {
+	if a.config.UnreleasedVersionMaxDevices <= 0 {
+		return nil, nil
+	}
+
+	counts, err := a.repository.GetVersionsUsedBy(now.Add(-2*a.config.Window), now.Add(-a.config.Window), a.config.UnreleasedVersionMaxDevices)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []models.Alert
+	for _, c := range counts {
+		alerts = append(alerts, newAlert(c.AppID, models.AlertUnreleasedVersion, c.Version, c.Devices, int64(a.config.UnreleasedVersionMaxDevices), now))
+	}
+	return alerts, nil
+}
+
+// devicesCyclingOSVersions detects the devices which reported more new OS versions in the window than the threshold
+func (a *alertsService) devicesCyclingOSVersions(now time.Time) ([]models.Alert, error) {
+	if a.config.DeviceOSVersions <= 0 {
+		return nil, nil
+	}
+
+	counts, err := a.repository.GetDevicesWithOSVersions(now.Add(-a.config.Window), a.config.DeviceOSVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []models.Alert
+	for _, c := range counts {
+		alerts = append(alerts, newAlert(c.AppID, models.AlertDeviceOSVersionsCycling, c.DeviceID, c.OSVersions, int64(a.config.DeviceOSVersions), now))
+	}
+	return alerts, nil
+}
+
+func newAlert(appID, kind, subject string, value, threshold int64, now time.Time) models.Alert {
+	return models.Alert{
+		ID:              uuid.New().String(),
+		AppID:           appID,
+		Kind:            kind,
+		Subject:         subject,
+		Value:           value,
+		Threshold:       threshold,
+		FirstDetectedAt: now,
+		LastDetectedAt:  now,
+	}
+}
@@ -0,0 +1,205 @@
+package alerts
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+)
+
+var testConfig = config.AlertsConfig{
+	Interval:                    time.Minute,
+	Window:                      time.Hour,
+	NewDevices:                  100,
+	NewDevicesSpikeFactor:       5,
+	UnreleasedVersionMaxDevices: 3,
+	DeviceOSVersions:            5,
+	Retention:                   24 * time.Hour,
+}
+
+// newRepository returns a repository without init requests to analyse, which saves the alerts
+func newRepository() *RepositoryMock {
+	return &RepositoryMock{
+		GetNewDevicesCountsFunc: func(since time.Time, until time.Time) ([]NewDevicesCount, error) {
+			return nil, nil
+		},
+		GetVersionsUsedByFunc: func(since time.Time, until time.Time, maxDevices int) ([]VersionDevicesCount, error) {
+			return nil, nil
+		},
+		GetDevicesWithOSVersionsFunc: func(since time.Time, minVersions int) ([]DeviceOSVersionsCount, error) {
+			return nil, nil
+		},
+		SaveAlertsFunc: func(alerts []models.Alert) error {
+			return nil
+		},
+		DeleteAlertsDetectedBeforeFunc: func(before time.Time) (int64, error) {
+			return 0, nil
+		},
+	}
+}
+
+// withoutIDs returns the alerts without their generated ids
+func withoutIDs(alerts []models.Alert) []models.Alert {
+	for i := range alerts {
+		alerts[i].ID = ""
+	}
+	return alerts
+}
+
+func Test_alertsService_Analyze(t *testing.T) {
+	now := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	appID := "com.aerogear.mobile_app_one"
+
+	alert := func(kind, subject string, value, threshold int64) models.Alert {
+		return models.Alert{AppID: appID, Kind: kind, Subject: subject, Value: value, Threshold: threshold, FirstDetectedAt: now, LastDetectedAt: now}
+	}
+
+	tests := []struct {
+		name       string
+		config     config.AlertsConfig
+		repository func(r *RepositoryMock)
+		want       []models.Alert
+	}{
+		{
+			name:   "Should raise a spike when the new devices cross the threshold and the usual number",
+			config: testConfig,
+			repository: func(r *RepositoryMock) {
+				r.GetNewDevicesCountsFunc = func(since time.Time, until time.Time) ([]NewDevicesCount, error) {
+					return []NewDevicesCount{
+						{AppID: appID, Recent: 150, Baseline: 24 * 20},
+						{AppID: "com.aerogear.mobile_app_two", Recent: 150, Baseline: 24 * 40},
+						{AppID: "com.aerogear.mobile_app_three", Recent: 99},
+					}, nil
+				}
+			},
+			want: []models.Alert{alert(models.AlertNewDevicesSpike, "", 150, 100)},
+		},
+		{
+			name:   "Should raise the spike threshold to the spike factor times the usual number",
+			config: testConfig,
+			repository: func(r *RepositoryMock) {
+				r.GetNewDevicesCountsFunc = func(since time.Time, until time.Time) ([]NewDevicesCount, error) {
+					return []NewDevicesCount{{AppID: appID, Recent: 250, Baseline: 24 * 41}}, nil
+				}
+			},
+			want: []models.Alert{alert(models.AlertNewDevicesSpike, "", 250, 205)},
+		},
+		{
+			name:   "Should raise the versions used by less devices than the threshold and the devices cycling OS versions",
+			config: testConfig,
+			repository: func(r *RepositoryMock) {
+				r.GetVersionsUsedByFunc = func(since time.Time, until time.Time, maxDevices int) ([]VersionDevicesCount, error) {
+					return []VersionDevicesCount{{AppID: appID, Version: "9.9.9", Devices: 1}}, nil
+				}
+				r.GetDevicesWithOSVersionsFunc = func(since time.Time, minVersions int) ([]DeviceOSVersionsCount, error) {
+					return []DeviceOSVersionsCount{{AppID: appID, DeviceID: "a7b4fd2f-7f5e-4b4a-a9e1-7d3c4d0e6b1f", OSVersions: 7}}, nil
+				}
+			},
+			want: []models.Alert{
+				alert(models.AlertUnreleasedVersion, "9.9.9", 1, 3),
+				alert(models.AlertDeviceOSVersionsCycling, "a7b4fd2f-7f5e-4b4a-a9e1-7d3c4d0e6b1f", 7, 5),
+			},
+		},
+		{
+			name:   "Should not run the checks whose threshold is 0",
+			config: config.AlertsConfig{Window: time.Hour},
+			repository: func(r *RepositoryMock) {
+				r.GetNewDevicesCountsFunc, r.GetVersionsUsedByFunc, r.GetDevicesWithOSVersionsFunc = nil, nil, nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRepository()
+			tt.repository(r)
+
+			got, err := NewService(r, &apps.RepositoryMock{}, tt.config).Analyze(now)
+			if err != nil || got != len(tt.want) {
+				t.Fatalf("alertsService.Analyze() = %v, %v, want %v", got, err, len(tt.want))
+			}
+
+			var saved []models.Alert
+			for _, call := range r.SaveAlertsCalls() {
+				saved = append(saved, withoutIDs(call.Alerts)...)
+			}
+			if !reflect.DeepEqual(saved, tt.want) {
+				t.Errorf("alertsService.Analyze() saved %+v, want %+v", saved, tt.want)
+			}
+		})
+	}
+}
+
+func Test_alertsService_Analyze_windows(t *testing.T) {
+	now := time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)
+	r := newRepository()
+
+	if _, err := NewService(r, &apps.RepositoryMock{}, testConfig).Analyze(now); err != nil {
+		t.Fatalf("alertsService.Analyze() error = %v", err)
+	}
+
+	if call := r.GetNewDevicesCountsCalls()[0]; !call.Since.Equal(now.Add(-25*time.Hour)) || !call.Until.Equal(now.Add(-time.Hour)) {
+		t.Errorf("alertsService.Analyze() counted the new devices from %v until %v, want the 24 windows before the last one", call.Since, call.Until)
+	}
+
+	if call := r.GetVersionsUsedByCalls()[0]; !call.Since.Equal(now.Add(-2*time.Hour)) || !call.Until.Equal(now.Add(-time.Hour)) || call.MaxDevices != 3 {
+		t.Errorf("alertsService.Analyze() looked for the versions from %v until %v, want the window before the last one", call.Since, call.Until)
+	}
+
+	if call := r.GetDevicesWithOSVersionsCalls()[0]; !call.Since.Equal(now.Add(-time.Hour)) || call.MinVersions != 5 {
+		t.Errorf("alertsService.Analyze() looked for the OS versions since %v, want the last window", call.Since)
+	}
+
+	if calls := r.DeleteAlertsDetectedBeforeCalls(); len(calls) != 1 || !calls[0].Before.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("alertsService.Analyze() deleted the alerts detected before %+v, want the retention period", calls)
+	}
+
+	if calls := r.SaveAlertsCalls(); len(calls) != 0 {
+		t.Errorf("alertsService.Analyze() saved %v times, want no save without alerts", len(calls))
+	}
+}
+
+func Test_alertsService_Analyze_error(t *testing.T) {
+	r := newRepository()
+	r.GetVersionsUsedByFunc = func(since time.Time, until time.Time, maxDevices int) ([]VersionDevicesCount, error) {
+		return nil, models.ErrDatabaseError
+	}
+
+	if _, err := NewService(r, &apps.RepositoryMock{}, testConfig).Analyze(time.Now()); !errors.Is(err, models.ErrDatabaseError) {
+		t.Errorf("alertsService.Analyze() error = %v, want %v", err, models.ErrDatabaseError)
+	}
+
+	if len(r.SaveAlertsCalls()) != 0 || len(r.DeleteAlertsDetectedBeforeCalls()) != 0 {
+		t.Error("alertsService.Analyze() saved or deleted alerts after a failed check")
+	}
+}
+
+func Test_alertsService_GetAlerts(t *testing.T) {
+	app := helpers.GetMockApp()
+	appsRepository := &apps.RepositoryMock{
+		GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
+			if ID == app.ID {
+				return app, nil
+			}
+			return nil, models.ErrNotFound
+		},
+	}
+	r := &RepositoryMock{
+		GetAlertsByAppIDFunc: func(appID string) ([]models.Alert, error) {
+			return []models.Alert{{AppID: appID, Kind: models.AlertNewDevicesSpike}}, nil
+		},
+	}
+	s := NewService(r, appsRepository, testConfig)
+
+	if got, err := s.GetAlerts(app.ID); err != nil || len(got) != 1 || got[0].AppID != app.AppID {
+		t.Errorf("alertsService.GetAlerts() = %+v, %v, want the alerts of the app", got, err)
+	}
+
+	if _, err := s.GetAlerts("53d4e6c6-6b7f-4a50-8d2c-0fe5b4c1a2b9"); !errors.Is(err, models.ErrNotFound) {
+		t.Errorf("alertsService.GetAlerts() error = %v, want %v", err, models.ErrNotFound)
+	}
+}
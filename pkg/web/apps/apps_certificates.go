@@ -0,0 +1,94 @@
+package apps
+
+import (
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// TamperedBuildMessage is the disabled message returned by the init endpoint for a build which is not signed by
+// the certificates of an app which blocks the tampered builds
+const TamperedBuildMessage = "This build of the app is not signed by its publisher"
+
+// GetSigningPolicy returns the signing certificates registered for an active app by its id
+func (a *appsService) GetSigningPolicy(id string) (*models.SigningPolicy, error) {
+	app, err := a.repository.GetActiveAppByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.repository.GetSigningPolicyByAppID(app.AppID)
+}
+
+// CreateSigningCertificate registers a signing certificate of an active app by its id and returns it.
+// It returns ErrConflict when the app already has it.
+func (a *appsService) CreateSigningCertificate(id string, certificate models.SigningCertificate) (*models.SigningCertificate, error) {
+	app, err := a.repository.GetActiveAppByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate.Fingerprint = models.NormalizeFingerprint(certificate.Fingerprint)
+	if certificate.Fingerprint == "" || (certificate.Platform != models.PlatformAndroid && certificate.Platform != models.PlatformIOS) {
+		return nil, models.ErrBadParamInput
+	}
+
+	certificate.ID = helpers.GetUUID()
+	certificate.AppID = app.AppID
+	certificate.CreatedAt = time.Now().UTC()
+
+	if err := a.repository.CreateSigningCertificate(certificate); err != nil {
+		return nil, err
+	}
+
+	return &certificate, nil
+}
+
+// DeleteSigningCertificate deletes a signing certificate of an active app by their ids
+func (a *appsService) DeleteSigningCertificate(id, certificateID string) error {
+	app, err := a.repository.GetActiveAppByID(id)
+	if err != nil {
+		return err
+	}
+
+	return a.repository.DeleteSigningCertificate(app.AppID, certificateID)
+}
+
+// SetTamperedBuilds sets the mode of an active app by its id for the builds which are not signed by its certificates
+func (a *appsService) SetTamperedBuilds(id, mode string) error {
+	app, err := a.repository.GetActiveAppByID(id)
+	if err != nil {
+		return err
+	}
+
+	if mode != models.TamperedBuildsFlag && mode != models.TamperedBuildsBlock {
+		return models.ErrBadParamInput
+	}
+
+	return a.repository.SetTamperedBuilds(app.AppID, mode)
+}
+
+// verifyBuild checks the signing certificate reported by a device against the ones registered for its platform.
+// It returns if the build is tampered and if its launch must be blocked.
+func (a *appsService) verifyBuild(deviceInfo *models.Device) (tampered bool, blocked bool, err error) {
+	policy, err := a.repository.GetSigningPolicyByAppID(deviceInfo.AppID)
+	if err != nil {
+		return false, false, err
+	}
+
+	if policy.IsTrusted(models.PlatformOf(deviceInfo.DeviceType), deviceInfo.SigningCertificate) {
+		return false, false, nil
+	}
+
+	blocked = policy.TamperedBuilds == models.TamperedBuildsBlock
+	log.WithFields(log.Fields{
+		"appId":              deviceInfo.AppID,
+		"deviceId":           deviceInfo.DeviceID,
+		"signingCertificate": deviceInfo.SigningCertificate,
+		"blocked":            blocked,
+	}).Warn("Launch of a tampered build")
+
+	return true, blocked, nil
+}
@@ -0,0 +1,170 @@
+package apps
+
+import (
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/events"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+const testFingerprint = "146de983c5730650d8eeb9952f34fc6416a08342e61dbea88a0496b23fcf44e5"
+
+func Test_appsService_CreateSigningCertificate(t *testing.T) {
+	app := helpers.GetMockApp()
+
+	tests := []struct {
+		name        string
+		certificate models.SigningCertificate
+		wantErr     error
+	}{
+		{
+			name:        "Should store the fingerprint in lowercase hex without colons",
+			certificate: models.SigningCertificate{Platform: models.PlatformAndroid, Fingerprint: "14:6D:E9:83:C5:73:06:50:D8:EE:B9:95:2F:34:FC:64:16:A0:83:42:E6:1D:BE:A8:8A:04:96:B2:3F:CF:44:E5"},
+		},
+		{
+			name:        "Should return ErrBadParamInput when the platform is not valid",
+			certificate: models.SigningCertificate{Platform: "windows", Fingerprint: testFingerprint},
+			wantErr:     models.ErrBadParamInput,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := *mockRepositoryWithSuccessResults
+
+			got, err := NewService(&repo, nil).CreateSigningCertificate(app.ID, tt.certificate)
+			if err != tt.wantErr {
+				t.Fatalf("appsService.CreateSigningCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			calls := repo.CreateSigningCertificateCalls()
+			if tt.wantErr != nil {
+				if len(calls) > 0 {
+					t.Errorf("appsService.CreateSigningCertificate() stored %v, want nothing stored", calls[0].Certificate)
+				}
+				return
+			}
+			if len(calls) != 1 || calls[0].Certificate != *got {
+				t.Fatalf("appsService.CreateSigningCertificate() stored %v, want %v", calls, *got)
+			}
+			if got.ID == "" || got.AppID != app.AppID || got.Fingerprint != testFingerprint {
+				t.Errorf("appsService.CreateSigningCertificate() = %v", got)
+			}
+		})
+	}
+}
+
+func Test_appsService_InitClientApp_tamperedBuilds(t *testing.T) {
+	app := helpers.GetMockApp()
+	android := models.SigningCertificate{AppID: app.AppID, Platform: models.PlatformAndroid, Fingerprint: testFingerprint}
+
+	tests := []struct {
+		name         string
+		mode         string
+		certificates []models.SigningCertificate
+		deviceType   string
+		fingerprint  string
+		wantTampered bool
+		wantBlocked  bool
+	}{
+		{
+			name:       "Should register any build when the app has no certificates",
+			mode:       models.TamperedBuildsBlock,
+			deviceType: "Android",
+		},
+		{
+			name:         "Should register a build signed by a certificate of the app",
+			mode:         models.TamperedBuildsBlock,
+			certificates: []models.SigningCertificate{android},
+			deviceType:   "Android",
+			fingerprint:  "14:6D:E9:83:C5:73:06:50:D8:EE:B9:95:2F:34:FC:64:16:A0:83:42:E6:1D:BE:A8:8A:04:96:B2:3F:CF:44:E5",
+		},
+		{
+			name:         "Should register a build of a platform without certificates",
+			mode:         models.TamperedBuildsBlock,
+			certificates: []models.SigningCertificate{android},
+			deviceType:   "iOS",
+		},
+		{
+			name:         "Should register and report a tampered build when the app flags them",
+			mode:         models.TamperedBuildsFlag,
+			certificates: []models.SigningCertificate{android},
+			deviceType:   "Android",
+			fingerprint:  "00" + testFingerprint[2:],
+			wantTampered: true,
+		},
+		{
+			name:         "Should disable and report a build without device type when the app blocks the tampered builds",
+			mode:         models.TamperedBuildsBlock,
+			certificates: []models.SigningCertificate{android},
+			fingerprint:  testFingerprint,
+			wantTampered: true,
+			wantBlocked:  true,
+		},
+		{
+			name:         "Should register and report a build of an unknown device type when the app flags them",
+			mode:         models.TamperedBuildsFlag,
+			certificates: []models.SigningCertificate{android},
+			deviceType:   "x",
+			fingerprint:  testFingerprint,
+			wantTampered: true,
+		},
+		{
+			name:         "Should disable and report a build without fingerprint when the app blocks the tampered builds",
+			mode:         models.TamperedBuildsBlock,
+			certificates: []models.SigningCertificate{android},
+			deviceType:   "Android",
+			wantTampered: true,
+			wantBlocked:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := *mockRepositoryWithSuccessResults
+			repo.GetSigningPolicyByAppIDFunc = func(appID string) (*models.SigningPolicy, error) {
+				return &models.SigningPolicy{AppID: appID, TamperedBuilds: tt.mode, Certificates: tt.certificates}, nil
+			}
+			repo.GetVersionByAppIDAndVersionFunc = func(appID string, version string) (*models.Version, error) {
+				return nil, models.ErrNotFound
+			}
+			repo.UpsertVersionWithAppLaunchesAndLastLaunchedFunc = func(version *models.Version) error {
+				return nil
+			}
+			repo.GetDeviceByDeviceIDAndAppIDFunc = func(deviceID string, appID string) (*models.Device, error) {
+				return nil, models.ErrNotFound
+			}
+			repo.InsertDeviceOrUpdateVersionIDFunc = func(device models.Device) error {
+				return nil
+			}
+			publisher := &EventPublisherMock{PublishFunc: func(event events.Event) {}}
+
+			device := &models.Device{AppID: app.AppID, DeviceID: "a742f8b7-5e2f-43f4-a5b8-2e8bb3e5a3ad", DeviceType: tt.deviceType, Version: "1.0", SigningCertificate: tt.fingerprint}
+			got, err := NewService(&repo, publisher).InitClientApp(device)
+			if err != nil {
+				t.Fatalf("appsService.InitClientApp() error = %v", err)
+			}
+
+			if got.Disabled != tt.wantBlocked {
+				t.Errorf("appsService.InitClientApp() disabled = %v, want %v", got.Disabled, tt.wantBlocked)
+			}
+			if tt.wantBlocked && got.DisabledMessage != TamperedBuildMessage {
+				t.Errorf("appsService.InitClientApp() disabledMessage = %v, want %v", got.DisabledMessage, TamperedBuildMessage)
+			}
+
+			registered := len(repo.UpsertVersionWithAppLaunchesAndLastLaunchedCalls()) + len(repo.InsertDeviceOrUpdateVersionIDCalls())
+			if tt.wantBlocked != (registered == 0) {
+				t.Errorf("appsService.InitClientApp() made %v writes, want blocked %v", registered, tt.wantBlocked)
+			}
+
+			var reported []events.TamperedBuild
+			for _, call := range publisher.PublishCalls() {
+				if e, ok := call.Event.(events.TamperedBuild); ok {
+					reported = append(reported, e)
+				}
+			}
+			if tt.wantTampered != (len(reported) == 1) || (tt.wantTampered && reported[0].Blocked != tt.wantBlocked) {
+				t.Errorf("appsService.InitClientApp() published %v, want tampered %v and blocked %v", reported, tt.wantTampered, tt.wantBlocked)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package apps
+
+import (
+	"github.com/aerogear/mobile-security-service/pkg/events"
+)
+
+// EventPublisher receives the events of the changes of the apps, e.g. the events.Bus of the server
+type EventPublisher interface {
+	Publish(event events.Event)
+}
+
+// publish gives an event to the publisher of the service, if it has one
+func (a *appsService) publish(event events.Event) {
+	if a.publisher == nil {
+		return
+	}
+
+	a.publisher.Publish(event)
+}
@@ -0,0 +1,180 @@
+package apps
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/events"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+func Test_appsService_events(t *testing.T) {
+	app := helpers.GetMockApp()
+
+	deletedRepo := *mockRepositoryWithSuccessResults
+	deletedRepo.GetAppByIDFunc = func(id string) (*models.App, error) {
+		deleted := helpers.GetMockApp()
+		deleted.DeletedAt = "2019-01-01 00:00:00"
+		return deleted, nil
+	}
+
+	launchRepo := *mockRepositoryWithSuccessResults
+	launchRepo.GetVersionByAppIDAndVersionFunc = func(appID string, versionNumber string) (*models.Version, error) {
+		return &models.Version{ID: "55ebd387-9c68-4137-a367-a12025cc2cdb", Version: versionNumber, AppID: appID, Disabled: true, DisabledMessage: "Please update"}, nil
+	}
+	launchRepo.UpsertVersionWithAppLaunchesAndLastLaunchedFunc = func(version *models.Version) error {
+		return nil
+	}
+	launchRepo.GetDeviceByDeviceIDAndAppIDFunc = func(deviceID string, appID string) (*models.Device, error) {
+		return nil, models.ErrNotFound
+	}
+	launchRepo.InsertDeviceOrUpdateVersionIDFunc = func(device models.Device) error {
+		return nil
+	}
+
+	enabledRepo := launchRepo
+	enabledRepo.GetVersionByAppIDAndVersionFunc = func(appID string, versionNumber string) (*models.Version, error) {
+		return &models.Version{ID: "55ebd387-9c68-4137-a367-a12025cc2cdb", Version: versionNumber, AppID: appID}, nil
+	}
+	enabledRepo.GetDeviceByDeviceIDAndAppIDFunc = func(deviceID string, appID string) (*models.Device, error) {
+		return &models.Device{ID: "0ae8a2b1-4c2f-4d5e-9f6a-7b8c9d0e1f2a", VersionID: "55ebd387-9c68-4137-a367-a12025cc2cdb", Version: "1.0",
+			AppID: appID, DeviceID: deviceID, DeviceVersion: "9", DeviceType: "Android"}, nil
+	}
+
+	firstSeenRepo := launchRepo
+	firstSeenRepo.GetVersionByAppIDAndVersionFunc = func(appID string, versionNumber string) (*models.Version, error) {
+		return nil, models.ErrNotFound
+	}
+
+	newAppRepo := *mockRepositoryWithSuccessResults
+	newAppRepo.GetAppByAppIDFunc = func(appID string) (*models.App, error) {
+		return nil, models.ErrNotFound
+	}
+
+	device := &models.Device{AppID: app.AppID, DeviceID: "a742f8b7-5e2f-43f4-a5b8-2e8bb3e5a3ad", DeviceType: "Android", DeviceVersion: "9", Version: "1.0"}
+	registered := models.Device{Version: "1.0", AppID: app.AppID, DeviceID: device.DeviceID, DeviceVersion: "9", DeviceType: "Android"}
+	disabled := models.Version{Version: "1.0", AppID: app.AppID, Disabled: true, DisabledMessage: "Please update"}
+	restored := *helpers.GetMockApp()
+
+	update := []models.Version{
+		{ID: "55ebd387-9c68-4137-a367-a12025cc2cdb", AppID: app.AppID, Disabled: true, DisabledMessage: "Please update"},
+		{ID: "59ebd387-9c68-4137-a367-a12025cc1cdb", AppID: app.AppID},
+	}
+
+	tests := []struct {
+		name string
+		repo *RepositoryMock
+		call func(s Service) error
+		want []events.Event
+	}{
+		{
+			name: "Should publish the update of the versions with their previous state",
+			repo: mockRepositoryWithSuccessResults,
+			call: func(s Service) error { return s.UpdateAppVersions(app.ID, update) },
+			want: []events.Event{events.VersionsUpdated{AppID: app.AppID, Previous: helpers.GetMockAppVersionList(), Versions: update}},
+		},
+		{
+			name: "Should publish the disable of all the versions of an app",
+			repo: mockRepositoryWithSuccessResults,
+			call: func(s Service) error { return s.DisableAllAppVersionsByAppID(app.ID, "Blocked") },
+			want: []events.Event{events.AllVersionsDisabled{AppID: app.AppID, DisabledMessage: "Blocked", Previous: helpers.GetMockAppVersionList()}},
+		},
+		{
+			name: "Should publish the creation of an app",
+			repo: &newAppRepo,
+			call: func(s Service) error {
+				return s.CreateApp(models.App{AppID: "com.aerogear.new_app", AppName: "New App"})
+			},
+			want: []events.Event{events.AppCreated{App: models.App{AppID: "com.aerogear.new_app", AppName: "New App", TenantID: models.DefaultTenantID}}},
+		},
+		{
+			name: "Should publish the delete of an app",
+			repo: mockRepositoryWithSuccessResults,
+			call: func(s Service) error { return s.DeleteAppById(app.ID) },
+			want: []events.Event{events.AppDeleted{App: *app}},
+		},
+		{
+			name: "Should publish the restore of an app",
+			repo: &deletedRepo,
+			call: func(s Service) error { return s.RestoreAppByID(app.ID) },
+			want: []events.Event{events.AppRestored{App: restored}},
+		},
+		{
+			name: "Should publish the first launch of a device on a disabled version",
+			repo: &launchRepo,
+			call: func(s Service) error {
+				_, err := s.InitClientApp(device)
+				return err
+			},
+			want: []events.Event{
+				events.DeviceRegistered{Device: registered},
+				events.AppLaunched{Device: registered, Version: disabled},
+				events.DeviceBlocked{Device: registered, Version: disabled},
+			},
+		},
+		{
+			name: "Should publish the first launch of a version",
+			repo: &firstSeenRepo,
+			call: func(s Service) error {
+				_, err := s.InitClientApp(device)
+				return err
+			},
+			want: []events.Event{
+				events.VersionFirstSeen{Version: models.Version{Version: "1.0", AppID: app.AppID}},
+				events.DeviceRegistered{Device: registered},
+				events.AppLaunched{Device: registered, Version: models.Version{Version: "1.0", AppID: app.AppID}},
+			},
+		},
+		{
+			name: "Should only publish the launch of an enabled version by a known device",
+			repo: &enabledRepo,
+			call: func(s Service) error {
+				_, err := s.InitClientApp(device)
+				return err
+			},
+			want: []events.Event{events.AppLaunched{Device: registered, Version: models.Version{Version: "1.0", AppID: app.AppID}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &EventPublisherMock{
+				PublishFunc: func(event events.Event) {},
+			}
+
+			if err := tt.call(NewService(tt.repo, publisher)); err != nil {
+				t.Fatalf("appsService error = %v", err)
+			}
+
+			var got []events.Event
+			for _, call := range publisher.PublishCalls() {
+				got = append(got, withoutGeneratedIDs(call.Event))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appsService published %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// withoutGeneratedIDs clears the ids of the apps, versions and devices, which are random when they are created by the service
+func withoutGeneratedIDs(event events.Event) events.Event {
+	switch e := event.(type) {
+	case events.AppCreated:
+		e.App.ID = ""
+		return e
+	case events.VersionFirstSeen:
+		e.Version.ID = ""
+		return e
+	case events.DeviceRegistered:
+		e.Device.ID, e.Device.VersionID = "", ""
+		return e
+	case events.DeviceBlocked:
+		e.Device.ID, e.Device.VersionID, e.Version.ID = "", "", ""
+		return e
+	case events.AppLaunched:
+		e.Device.ID, e.Device.VersionID, e.Version.ID = "", "", ""
+		return e
+	}
+	return event
+}
@@ -1,25 +1,43 @@
 package apps
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 
-	"github.com/aerogear/mobile-security-service/pkg/helpers"
 	"github.com/aerogear/mobile-security-service/pkg/httperrors"
 	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/tenants"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
 	"github.com/labstack/echo"
 	"github.com/labstack/gommon/log"
+	yaml "gopkg.in/yaml.v2"
 )
 
+// NextCursorHeader is the response header holding the cursor of the next page of a list
+const NextCursorHeader = "X-Next-Cursor"
+
 type (
 	HTTPHandler interface {
 		GetApps(c echo.Context) error
+		GetAppsPage(c echo.Context) error
 		GetActiveAppByID(c echo.Context) error
 		UpdateAppVersions(c echo.Context) error
 		DisableAllAppVersionsByAppID(c echo.Context) error
 		DeleteAppById(c echo.Context) error
+		RestoreAppByID(c echo.Context) error
+		HardDeleteAppByID(c echo.Context) error
 		CreateApp(c echo.Context) error
 		UpdateAppNameByID(c echo.Context) error
+		ExportApps(c echo.Context) error
+		ImportApps(c echo.Context) error
+		GetReleases(c echo.Context) error
+		SetReleases(c echo.Context) error
+		AddRelease(c echo.Context) error
+		GetSigningCertificates(c echo.Context) error
+		CreateSigningCertificate(c echo.Context) error
+		DeleteSigningCertificate(c echo.Context) error
+		SetTamperedBuilds(c echo.Context) error
 	}
 
 	// httpHandler instance
@@ -35,12 +53,13 @@ func NewHTTPHandler(e *echo.Echo, s Service) HTTPHandler {
 	}
 }
 
-// GetApps returns all apps as JSON from the AppService
+// GetApps returns a page of apps as JSON from the AppService.
+// The cursor of the following page is sent in the X-Next-Cursor and Link headers.
 func (a *httpHandler) GetApps(c echo.Context) error {
-	apps, err := a.HandleGetApp(c)
+	page, err := a.HandleGetApp(c)
 
 	// If no apps have been found, return a HTTP Status code of 204 with no response body
-	if err == models.ErrNotFound {
+	if errors.Is(err, models.ErrNotFound) {
 		return c.NoContent(http.StatusNoContent)
 	}
 
@@ -48,60 +67,104 @@ func (a *httpHandler) GetApps(c echo.Context) error {
 		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
 
-	return c.JSON(http.StatusOK, apps)
+	if page.NextCursor != "" {
+		next := *c.Request().URL
+		q := next.Query()
+		q.Set("cursor", page.NextCursor)
+		next.RawQuery = q.Encode()
+
+		c.Response().Header().Set(NextCursorHeader, page.NextCursor)
+		c.Response().Header().Add("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+	}
+
+	return c.JSON(http.StatusOK, newAppsResponse(page.Apps))
+}
+
+// GetAppsPage returns a page of apps with the cursor of the following page in the body.
+// An empty page is returned when no apps are found.
+func (a *httpHandler) GetAppsPage(c echo.Context) error {
+	page, err := a.HandleGetApp(c)
+
+	if errors.Is(err, models.ErrNotFound) {
+		page, err = &models.AppsPage{}, nil
+	}
+
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newAppsPageResponse(*page))
 }
 
 // HandleGetApp will return handle the request according to the data provided
-func (a *httpHandler) HandleGetApp(c echo.Context) (*[]models.App, error) {
-	appId := c.QueryParam("appId")
-	var apps *[]models.App
-	var err error
-	if len(appId) > 1 {
-		var app *models.App
-		app, err = a.Service.GetActiveAppByAppID(appId)
-		if app != nil {
-			apps = &[]models.App{*app}
+func (a *httpHandler) HandleGetApp(c echo.Context) (*models.AppsPage, error) {
+	req, err := newGetAppsRequest(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validation.Params(c, req); err != nil {
+		return nil, err
+	}
+
+	scope := tenants.ScopeFromContext(c)
+
+	if len(req.AppID) > 1 {
+		app, err := a.Service.GetActiveAppByAppID(req.AppID)
+		if err != nil {
+			return nil, err
+		}
+		if (scope != nil && !scope.Includes(app.TenantID)) || (req.TenantID != "" && req.TenantID != app.TenantID) {
+			return nil, models.ErrNotFound
 		}
-	} else {
-		apps, err = a.Service.GetApps()
+		return &models.AppsPage{Apps: []models.App{*app}}, nil
 	}
-	return apps, err
+
+	query := req.toModel()
+	switch {
+	case scope != nil && req.TenantID != "":
+		query.Tenants = scope.Narrow(req.TenantID)
+	case scope != nil:
+		query.Tenants = scope
+	case req.TenantID != "":
+		query.Tenants = &models.TenantScope{TenantIDs: []string{req.TenantID}}
+	}
+
+	return a.Service.GetApps(query)
 }
 
 // GetActiveAppByID returns apps by id as JSON from the AppService
 func (a *httpHandler) GetActiveAppByID(c echo.Context) error {
 
-	id := c.Param("id")
-	if !helpers.IsValidUUID(id) {
-		return httperrors.BadRequest(c, "Invalid id supplied")
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
+	id := params.ID
 
-	apps, err := a.Service.GetActiveAppByID(id)
+	app, err := a.Service.GetActiveAppByID(id)
 
 	if err != nil {
 		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
-	return c.JSON(http.StatusOK, apps)
+	return c.JSON(http.StatusOK, newAppResponse(*app))
 
 }
 
 func (a *httpHandler) UpdateAppNameByID(c echo.Context) error {
 
-	id := c.Param("id")
-	if !helpers.IsValidUUID(id) {
-		return httperrors.BadRequest(c, "Invalid id supplied")
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
+	id := params.ID
 
-	// Transform the body request in the version struct
-	app := models.App{}
-	errV := json.NewDecoder(c.Request().Body).Decode(&app)
-
-	// check if the data sent is in the correct format
-	if errV != nil || len(app.AppID) < 1 {
-		return httperrors.BadRequest(c, "Invalid data")
+	req := updateAppNameRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
 
-	err := a.Service.UpdateAppNameByID(id, app.AppName)
+	err := a.Service.UpdateAppNameByID(id, req.AppName)
 	if err != nil {
 		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
@@ -109,29 +172,22 @@ func (a *httpHandler) UpdateAppNameByID(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
-//UpdateApp returns a app updated with the ID in JSON format from the AppService
+// UpdateApp returns a app updated with the ID in JSON format from the AppService
 func (a *httpHandler) UpdateAppVersions(c echo.Context) error {
 	// Validations
-	id := c.Param("id")
-	if !helpers.IsValidUUID(id) {
-		return httperrors.BadRequest(c, "Invalid id supplied")
-	}
-
-	versions := []models.Version{}
-	errV := json.NewDecoder(c.Request().Body).Decode(&versions)
-
-	// check if the data sent is in the correct format
-	if errV != nil {
-		return httperrors.BadRequest(c, "Invalid data")
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
+	id := params.ID
 
-	// Check if versions were sent all the body is empty
-	if len(versions) == 0 {
-		return httperrors.BadRequest(c, "No version(s) was sent.")
+	req := updateAppVersionsRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
 
 	// Call service
-	errUpdate := a.Service.UpdateAppVersions(id, versions)
+	errUpdate := a.Service.UpdateAppVersions(id, req.toModel())
 	if errUpdate != nil {
 		return httperrors.GetHTTPResponseFromErr(c, errUpdate)
 	}
@@ -139,22 +195,21 @@ func (a *httpHandler) UpdateAppVersions(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
-//UpdateApp returns a app updated with the ID in JSON format from the AppService
+// UpdateApp returns a app updated with the ID in JSON format from the AppService
 func (a *httpHandler) DisableAllAppVersionsByAppID(c echo.Context) error {
-	id := c.Param("id")
-	if !helpers.IsValidUUID(id) {
-		return httperrors.BadRequest(c, "Invalid id supplied")
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
+	id := params.ID
 
-	// Transform the body request in the version struct
-	version := models.Version{}
-
-	if err := json.NewDecoder(c.Request().Body).Decode(&version); err != nil {
+	req := disableAppVersionsRequest{}
+	if err := validation.Body(c, &req); err != nil {
 		log.Error(err)
-		return httperrors.BadRequest(c, "Invalid data")
+		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
 
-	err := a.Service.DisableAllAppVersionsByAppID(id, version.DisabledMessage)
+	err := a.Service.DisableAllAppVersionsByAppID(id, req.DisabledMessage)
 
 	if err != nil {
 		return httperrors.GetHTTPResponseFromErr(c, err)
@@ -166,13 +221,19 @@ func (a *httpHandler) DisableAllAppVersionsByAppID(c echo.Context) error {
 
 func (a *httpHandler) CreateApp(c echo.Context) error {
 
-	// Transform the body request in the version struct
-	app := models.App{}
-	errV := json.NewDecoder(c.Request().Body).Decode(&app)
+	req := createAppRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
 
-	// check if the data sent is in the correct format
-	if errV != nil || len(app.AppID) < 1 {
-		return httperrors.BadRequest(c, "Invalid data")
+	// the super admins create the apps in the requested or the default tenant, and restore the deleted apps of
+	// every tenant, so only the tenant of the other users is resolved here
+	app := req.toModel()
+	if scope := tenants.ScopeFromContext(c); scope != nil && !scope.All {
+		var err error
+		if app.TenantID, err = scope.TenantOf(req.TenantID); err != nil {
+			return httperrors.GetHTTPResponseFromErr(c, err)
+		}
 	}
 
 	err := a.Service.CreateApp(app)
@@ -186,10 +247,11 @@ func (a *httpHandler) CreateApp(c echo.Context) error {
 }
 
 func (a *httpHandler) DeleteAppById(c echo.Context) error {
-	id := c.Param("id")
-	if !helpers.IsValidUUID(id) {
-		return httperrors.BadRequest(c, "Invalid id supplied")
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
+	id := params.ID
 
 	err := a.Service.DeleteAppById(id)
 
@@ -199,3 +261,232 @@ func (a *httpHandler) DeleteAppById(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// RestoreAppByID restores an app which was soft deleted
+func (a *httpHandler) RestoreAppByID(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+	id := params.ID
+
+	err := a.Service.RestoreAppByID(id)
+
+	if errors.Is(err, models.ErrConflict) {
+		return httperrors.Conflict(c, "The app is not deleted")
+	}
+
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HardDeleteAppByID permanently deletes an app with all its data and returns it as a JSON archive.
+// Nothing is deleted when the dryRun query parameter is true, only the numbers of what would be deleted are returned.
+func (a *httpHandler) HardDeleteAppByID(c echo.Context) error {
+	req, err := newHardDeleteAppRequest(c)
+	if err == nil {
+		err = validation.Params(c, req)
+	}
+
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	result, err := a.Service.HardDeleteAppByID(req.ID, req.DryRun)
+
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newHardDeleteAppResponse(*result))
+}
+
+// ExportApps returns the policy document of the active apps and of the state of their versions.
+// It is returned in YAML when the format query parameter is yaml or, without it, when the Accept header asks for YAML.
+// Only the super admins can export the apps when the tenants are enabled.
+func (a *httpHandler) ExportApps(c echo.Context) error {
+	if scope := tenants.ScopeFromContext(c); scope != nil && !scope.All {
+		return httperrors.Forbidden(c, "Only super admin users can export the apps of every tenant")
+	}
+
+	req := exportAppsRequest{Format: c.QueryParam("format")}
+	if err := validation.Params(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	policy, err := a.Service.ExportApps()
+
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	doc := newAppsPolicyDocument(*policy)
+
+	if req.Format == "yaml" || (req.Format == "" && acceptsYAML(c)) {
+		b, err := yaml.Marshal(doc)
+		if err != nil {
+			return httperrors.GetHTTPResponseFromErr(c, models.ErrInternalServerError.Wrap(err))
+		}
+		return c.Blob(http.StatusOK, MIMEApplicationYAML, b)
+	}
+
+	return c.JSON(http.StatusOK, doc)
+}
+
+// ImportApps applies the policy document of the body, in JSON or YAML, and returns the changes made.
+// Nothing is changed when the dryRun query parameter is true, only the changes which would be made are returned.
+// Only the super admins can import the apps when the tenants are enabled.
+func (a *httpHandler) ImportApps(c echo.Context) error {
+	if scope := tenants.ScopeFromContext(c); scope != nil && !scope.All {
+		return httperrors.Forbidden(c, "Only super admin users can import the apps of every tenant")
+	}
+
+	req, err := newImportAppsRequest(c)
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	doc, err := decodeAppsPolicyDocument(c)
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	result, err := a.Service.ImportApps(doc.toModel(), req.DryRun)
+
+	if err != nil {
+		// the changes of the apps imported before the error are kept
+		if result != nil && !result.DryRun && len(result.Changes) > 0 {
+			log.Warnf("The import of the apps failed after %v changes were made: %v", len(result.Changes), err)
+		}
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newImportAppsResponse(*result))
+}
+
+// GetReleases returns the released versions declared for an app and its mode for the other versions
+func (a *httpHandler) GetReleases(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	releases, err := a.Service.GetReleases(params.ID)
+
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newReleasesResponse(*releases))
+}
+
+// SetReleases replaces the released versions declared for an app and its mode for the other versions
+func (a *httpHandler) SetReleases(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	req := setReleasesRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	if err := a.Service.SetReleases(params.ID, req.toModel()); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AddRelease declares a released version of an app
+func (a *httpHandler) AddRelease(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	req := addReleaseRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	if err := a.Service.AddRelease(params.ID, req.Version); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetSigningCertificates returns the signing certificates registered for an app and its mode for the tampered builds
+func (a *httpHandler) GetSigningCertificates(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	policy, err := a.Service.GetSigningPolicy(params.ID)
+
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newSigningPolicyResponse(*policy))
+}
+
+// CreateSigningCertificate registers a signing certificate of an app
+func (a *httpHandler) CreateSigningCertificate(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	req := createSigningCertificateRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	certificate, err := a.Service.CreateSigningCertificate(params.ID, req.toModel())
+
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newSigningCertificateResponse(*certificate))
+}
+
+// DeleteSigningCertificate deletes a signing certificate of an app
+func (a *httpHandler) DeleteSigningCertificate(c echo.Context) error {
+	params := signingCertificateParams{ID: c.Param("id"), CertificateID: c.Param("certificateId")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	if err := a.Service.DeleteSigningCertificate(params.ID, params.CertificateID); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SetTamperedBuilds sets the mode of an app for the builds which are not signed by its certificates
+func (a *httpHandler) SetTamperedBuilds(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	req := setTamperedBuildsRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	if err := a.Service.SetTamperedBuilds(params.ID, req.TamperedBuilds); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
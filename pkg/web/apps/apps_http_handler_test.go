@@ -5,12 +5,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/aerogear/mobile-security-service/pkg/config"
 	"github.com/aerogear/mobile-security-service/pkg/helpers"
 	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/tenants"
+	"github.com/aerogear/mobile-security-service/pkg/web/user"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
 	"github.com/labstack/echo"
 )
 
@@ -34,10 +38,8 @@ var (
 			}
 			return nil, models.ErrNotFound
 		},
-		GetAppsFunc: func() (*[]models.App, error) {
-			return &[]models.App{
-				*helpers.GetMockApp(),
-			}, nil
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+			return &models.AppsPage{Apps: []models.App{*helpers.GetMockApp()}}, nil
 		},
 		UpdateAppVersionsFunc: func(id string, versions []models.Version) error {
 			return nil
@@ -51,6 +53,13 @@ var (
 		UpdateAppNameByIDFunc: func(id string, name string) error {
 			return nil
 		},
+		SetReleasesFunc: func(id string, releases models.Releases) error {
+			return nil
+		},
+		CreateSigningCertificateFunc: func(id string, certificate models.SigningCertificate) (*models.SigningCertificate, error) {
+			certificate.ID = "9e1f3b2a-6c4d-4f8e-a7b5-2d1c0e9f8a7b"
+			return &certificate, nil
+		},
 	}
 
 	// make and configure a mocked Service which will return the scenarios with errors
@@ -64,7 +73,7 @@ var (
 		GetActiveAppByAppIDFunc: func(appId string) (*models.App, error) {
 			return nil, models.ErrNotFound
 		},
-		GetAppsFunc: func() (*[]models.App, error) {
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
 			return nil, models.ErrNotFound
 		},
 		UpdateAppVersionsFunc: func(id string, versions []models.Version) error {
@@ -85,7 +94,7 @@ var (
 func Test_HttpHandler_GetApps(t *testing.T) {
 	// make and configure a mocked Service which will return the scenarios with errors
 	mockedServiceWithInternalError := &ServiceMock{
-		GetAppsFunc: func() (*[]models.App, error) {
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
 			return nil, models.ErrInternalServerError
 		},
 	}
@@ -122,6 +131,7 @@ func Test_HttpHandler_GetApps(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Setup
 			e := echo.New()
+			e.Validator = validation.NewValidator()
 			req := httptest.NewRequest(http.MethodGet, "/", nil)
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
@@ -138,6 +148,168 @@ func Test_HttpHandler_GetApps(t *testing.T) {
 	}
 }
 
+func Test_HttpHandler_GetAppsWithPagination(t *testing.T) {
+	var gotQuery models.AppsQuery
+	mockedServiceWithNextPage := &ServiceMock{
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+			gotQuery = query
+			return &models.AppsPage{Apps: helpers.GetMockAppList()[:2], NextCursor: "next"}, nil
+		},
+	}
+	mockedServiceWithBadParam := &ServiceMock{
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+			return nil, models.ErrBadParamInput
+		},
+	}
+
+	tests := []struct {
+		name        string
+		query       url.Values
+		wantCode    int
+		wantQuery   models.AppsQuery
+		wantLink    string
+		mockService *ServiceMock
+	}{
+		{
+			name:        "Should pass the pagination parameters to the service and return the next page link",
+			query:       url.Values{"limit": {"2"}, "sort": {"-launches"}, "q": {"aerogear"}},
+			wantCode:    200,
+			wantQuery:   models.AppsQuery{Limit: 2, Sort: "-launches", Search: "aerogear"},
+			wantLink:    `</api/apps?cursor=next&limit=2&q=aerogear&sort=-launches>; rel="next"`,
+			mockService: mockedServiceWithNextPage,
+		},
+		{
+			name:        "Should return a bad request when the limit is not a number",
+			query:       url.Values{"limit": {"ten"}},
+			wantCode:    400,
+			mockService: mockedServiceWithNextPage,
+		},
+		{
+			name:        "Should return a bad request when the service rejects the parameters",
+			query:       url.Values{"sort": {"unknown"}},
+			wantCode:    400,
+			mockService: mockedServiceWithBadParam,
+		},
+		{
+			name:        "Should pass the deleted parameter to the service",
+			query:       url.Values{"limit": {"2"}, "deleted": {"true"}},
+			wantCode:    200,
+			wantQuery:   models.AppsQuery{Limit: 2, Deleted: true},
+			wantLink:    `</api/apps?cursor=next&deleted=true&limit=2>; rel="next"`,
+			mockService: mockedServiceWithNextPage,
+		},
+		{
+			name:        "Should return a bad request when the deleted parameter is not a boolean",
+			query:       url.Values{"deleted": {"maybe"}},
+			wantCode:    400,
+			mockService: mockedServiceWithNextPage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery = models.AppsQuery{}
+
+			// Setup
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			req := httptest.NewRequest(http.MethodGet, "/api/apps?"+tt.query.Encode(), nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/apps")
+			h := NewHTTPHandler(e, tt.mockService)
+			if err := h.GetApps(c); err != nil {
+				t.Errorf("httpHandler.GetApps() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("HTTPHandler.GetApps() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode != 200 {
+				return
+			}
+			if gotQuery != tt.wantQuery {
+				t.Errorf("HTTPHandler.GetApps() query = %+v, wantQuery = %+v", gotQuery, tt.wantQuery)
+			}
+			if link := rec.Header().Get("Link"); link != tt.wantLink {
+				t.Errorf("HTTPHandler.GetApps() Link = %v, wantLink = %v", link, tt.wantLink)
+			}
+			if cursor := rec.Header().Get(NextCursorHeader); cursor != "next" {
+				t.Errorf("HTTPHandler.GetApps() %v = %v, want next", NextCursorHeader, cursor)
+			}
+		})
+	}
+}
+
+func Test_HttpHandler_GetAppsPage(t *testing.T) {
+	mockedServiceWithNextPage := &ServiceMock{
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+			return &models.AppsPage{Apps: helpers.GetMockAppList()[:2], NextCursor: "next"}, nil
+		},
+	}
+	mockedServiceWithInternalError := &ServiceMock{
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+			return nil, models.ErrInternalServerError
+		},
+	}
+
+	tests := []struct {
+		name           string
+		wantCode       int
+		wantApps       int
+		wantNextCursor string
+		mockService    *ServiceMock
+	}{
+		{
+			name:           "Should return the page of apps with the cursor of the next page",
+			wantCode:       200,
+			wantApps:       2,
+			wantNextCursor: "next",
+			mockService:    mockedServiceWithNextPage,
+		},
+		{
+			name:        "Should return an empty page when no apps have been found",
+			wantCode:    200,
+			wantApps:    0,
+			mockService: mockedServiceWithError,
+		},
+		{
+			name:        "Should return error when an error occurs in the database",
+			wantCode:    500,
+			mockService: mockedServiceWithInternalError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			req := httptest.NewRequest(http.MethodGet, "/api/v2/apps", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v2/apps")
+			h := NewHTTPHandler(e, tt.mockService)
+			if err := h.GetAppsPage(c); err != nil {
+				t.Errorf("httpHandler.GetAppsPage() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("HTTPHandler.GetAppsPage() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode != 200 {
+				return
+			}
+			page := appsPageResponse{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+				t.Fatalf("HTTPHandler.GetAppsPage() body is not a page of apps: %v", err)
+			}
+			if page.Apps == nil || len(page.Apps) != tt.wantApps {
+				t.Errorf("HTTPHandler.GetAppsPage() apps = %v, wantApps = %v", page.Apps, tt.wantApps)
+			}
+			if page.NextCursor != tt.wantNextCursor {
+				t.Errorf("HTTPHandler.GetAppsPage() nextCursor = %v, wantNextCursor = %v", page.NextCursor, tt.wantNextCursor)
+			}
+		})
+	}
+}
+
 func Test_HttpHandler_GetAppsWithQueryParameter(t *testing.T) {
 	mockedServiceWithInternalError := &ServiceMock{
 		GetActiveAppByAppIDFunc: func(appId string) (*models.App, error) {
@@ -182,6 +354,7 @@ func Test_HttpHandler_GetAppsWithQueryParameter(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Setup
 			e := echo.New()
+			e.Validator = validation.NewValidator()
 			q := make(url.Values)
 			q.Set("appId", tt.appId)
 			req := httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil)
@@ -244,6 +417,7 @@ func Test_HttpHandler_DeleteAppById(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Setup
 			e := echo.New()
+			e.Validator = validation.NewValidator()
 			req := httptest.NewRequest(http.MethodDelete, "/", nil)
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
@@ -262,6 +436,151 @@ func Test_HttpHandler_DeleteAppById(t *testing.T) {
 	}
 }
 
+func Test_HttpHandler_RestoreAppByID(t *testing.T) {
+	mockedServiceWithResult := func(err error) *ServiceMock {
+		return &ServiceMock{
+			RestoreAppByIDFunc: func(id string) error {
+				return err
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		id          string
+		wantCode    int
+		mockService *ServiceMock
+	}{
+		{
+			name:        "Should return success to restore app by id",
+			id:          helpers.GetMockApp().ID,
+			mockService: mockedServiceWithResult(nil),
+			wantCode:    204,
+		},
+		{
+			name:        "Should return a bad request when the id is invalid",
+			id:          "invalid",
+			mockService: mockedServiceWithResult(nil),
+			wantCode:    400,
+		},
+		{
+			name:        "Should return error when no app has been found",
+			id:          helpers.GetMockApp().ID,
+			mockService: mockedServiceWithResult(models.ErrNotFound),
+			wantCode:    404,
+		},
+		{
+			name:        "Should return a conflict when the app is not deleted",
+			id:          helpers.GetMockApp().ID,
+			mockService: mockedServiceWithResult(models.ErrConflict),
+			wantCode:    409,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/apps/:id/restore")
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			h := NewHTTPHandler(e, tt.mockService)
+			if err := h.RestoreAppByID(c); err != nil {
+				t.Errorf("httpHandler.RestoreAppByID() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("HTTPHandler.RestoreAppByID() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_HttpHandler_HardDeleteAppByID(t *testing.T) {
+	var gotDryRun bool
+	mockedServiceWithResult := func(err error) *ServiceMock {
+		return &ServiceMock{
+			HardDeleteAppByIDFunc: func(id string, dryRun bool) (*models.AppHardDelete, error) {
+				gotDryRun = dryRun
+				if err != nil {
+					return nil, err
+				}
+				return &models.AppHardDelete{DryRun: dryRun, Versions: 2, Devices: 3}, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		id          string
+		query       string
+		wantCode    int
+		wantDryRun  bool
+		mockService *ServiceMock
+	}{
+		{
+			name:        "Should return success to hard delete app by id",
+			id:          helpers.GetMockApp().ID,
+			mockService: mockedServiceWithResult(nil),
+			wantCode:    200,
+		},
+		{
+			name:        "Should pass the dry run parameter to the service",
+			id:          helpers.GetMockApp().ID,
+			query:       "?dryRun=true",
+			mockService: mockedServiceWithResult(nil),
+			wantCode:    200,
+			wantDryRun:  true,
+		},
+		{
+			name:        "Should return a bad request when the dry run parameter is not a boolean",
+			id:          helpers.GetMockApp().ID,
+			query:       "?dryRun=maybe",
+			mockService: mockedServiceWithResult(nil),
+			wantCode:    400,
+		},
+		{
+			name:        "Should return a bad request when the id is invalid",
+			id:          "invalid",
+			mockService: mockedServiceWithResult(nil),
+			wantCode:    400,
+		},
+		{
+			name:        "Should return error when no app has been found",
+			id:          helpers.GetMockApp().ID,
+			mockService: mockedServiceWithResult(models.ErrNotFound),
+			wantCode:    404,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDryRun = false
+
+			// Setup
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			req := httptest.NewRequest(http.MethodPost, "/"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/apps/:id/purge")
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			h := NewHTTPHandler(e, tt.mockService)
+			if err := h.HardDeleteAppByID(c); err != nil {
+				t.Errorf("httpHandler.HardDeleteAppByID() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("HTTPHandler.HardDeleteAppByID() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+			if gotDryRun != tt.wantDryRun {
+				t.Errorf("HTTPHandler.HardDeleteAppByID() dryRun = %v, wantDryRun = %v", gotDryRun, tt.wantDryRun)
+			}
+		})
+	}
+}
+
 func Test_httpHandler_UpdateAllAppVersionsByAppID(t *testing.T) {
 	config := config.Get()
 	APIRoutePrefix := config.APIRoutePrefix
@@ -299,7 +618,7 @@ func Test_httpHandler_UpdateAllAppVersionsByAppID(t *testing.T) {
 			data:        []models.Version{},
 			wantErr:     true,
 			mockService: *mockedService,
-			wantCode:    400,
+			wantCode:    422,
 		},
 		{
 			name:        "Update all versions should return return error in the database",
@@ -312,6 +631,7 @@ func Test_httpHandler_UpdateAllAppVersionsByAppID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		e := echo.New()
+		e.Validator = validation.NewValidator()
 		allVersions, _ := json.Marshal(tt.data)
 		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(allVersions)))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -345,7 +665,7 @@ func Test_httpHandler_UpdateAppNameByID(t *testing.T) {
 		id          string
 		wantErr     bool
 		wantCode    int
-		data        models.App
+		data        interface{}
 		mockService ServiceMock
 	}{
 		{
@@ -364,9 +684,26 @@ func Test_httpHandler_UpdateAppNameByID(t *testing.T) {
 			mockService: *mockedService,
 			wantCode:    400,
 		},
+		{
+			name:        "Should update the name when only the appName is sent",
+			id:          helpers.GetMockApp().ID,
+			data:        updateAppNameRequest{AppName: "new name"},
+			wantErr:     false,
+			mockService: *mockedService,
+			wantCode:    204,
+		},
+		{
+			name:        "Should return a validation error when the appName is not sent",
+			id:          helpers.GetMockApp().ID,
+			data:        map[string]string{"appId": helpers.GetMockApp().AppID},
+			wantErr:     true,
+			mockService: *mockedService,
+			wantCode:    422,
+		},
 	}
 	for _, tt := range tests {
 		e := echo.New()
+		e.Validator = validation.NewValidator()
 		app, _ := json.Marshal(tt.data)
 		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(string(app)))
 		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -411,6 +748,7 @@ func Test_HttpHandler_UpdateAllAppVersionsByAppID_WithInvalidJsonData(t *testing
 	}
 	for _, tt := range tests {
 		e := echo.New()
+		e.Validator = validation.NewValidator()
 		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string("")))
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
@@ -469,6 +807,7 @@ func Test_HttpHandler_DisableAllAppVersionsByAppID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		e := echo.New()
+		e.Validator = validation.NewValidator()
 		version, _ := json.Marshal(tt.data)
 		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(version)))
 		rec := httptest.NewRecorder()
@@ -513,6 +852,7 @@ func Test_HttpHandler_DisableAllAppVersionsByAppID_WithInvalidJsonData(t *testin
 	}
 	for _, tt := range tests {
 		e := echo.New()
+		e.Validator = validation.NewValidator()
 		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string("")))
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
@@ -567,6 +907,7 @@ func Test_httpHandler_GetActiveAppByID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		e := echo.New()
+		e.Validator = validation.NewValidator()
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
@@ -624,9 +965,16 @@ func Test_HttpHandler_CreateApp(t *testing.T) {
 		{
 			name:        "Create app without appId should return error",
 			data:        mockAppWithoutID,
-			want:        http.StatusBadRequest,
+			want:        http.StatusUnprocessableEntity,
 			mockService: *mockedService,
-			wantCode:    400,
+			wantCode:    422,
+		},
+		{
+			name:        "Create app with an appId which is not in reverse-DNS format should return error",
+			data:        &models.App{AppID: "mobile app one"},
+			want:        http.StatusUnprocessableEntity,
+			mockService: *mockedService,
+			wantCode:    422,
 		},
 		{
 			name:        "Error in the database should return error in the post request",
@@ -638,6 +986,7 @@ func Test_HttpHandler_CreateApp(t *testing.T) {
 	}
 	for _, tt := range tests {
 		e := echo.New()
+		e.Validator = validation.NewValidator()
 		app, _ := json.Marshal(tt.data)
 		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(app)))
 		rec := httptest.NewRecorder()
@@ -653,3 +1002,503 @@ func Test_HttpHandler_CreateApp(t *testing.T) {
 		})
 	}
 }
+
+func Test_HttpHandler_ExportApps(t *testing.T) {
+	mockService := &ServiceMock{
+		ExportAppsFunc: func() (*models.AppsPolicy, error) {
+			return &models.AppsPolicy{Apps: []models.AppPolicy{{
+				AppID:    "com.aerogear.mobile_app_one",
+				AppName:  "App One",
+				Versions: []models.VersionPolicy{{Version: "1.0", Disabled: true, DisabledMessage: "Please update"}},
+			}}}, nil
+		},
+	}
+	wantYAML := `apps:
+- appId: com.aerogear.mobile_app_one
+  appName: App One
+  versions:
+  - version: "1.0"
+    disabled: true
+    disabledMessage: Please update
+`
+
+	tests := []struct {
+		name            string
+		query           string
+		accept          string
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "Should export the policy in JSON",
+			wantCode:        200,
+			wantContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			wantBody:        `{"apps":[{"appId":"com.aerogear.mobile_app_one","appName":"App One","versions":[{"version":"1.0","disabled":true,"disabledMessage":"Please update"}]}]}` + "\n",
+		},
+		{
+			name:            "Should export the policy in YAML with the format parameter",
+			query:           "?format=yaml",
+			wantCode:        200,
+			wantContentType: MIMEApplicationYAML,
+			wantBody:        wantYAML,
+		},
+		{
+			name:            "Should export the policy in YAML when the Accept header asks for it",
+			accept:          "text/html, application/x-yaml;q=0.9",
+			wantCode:        200,
+			wantContentType: MIMEApplicationYAML,
+			wantBody:        wantYAML,
+		},
+		{
+			name:     "Should return a bad request when the format is unknown",
+			query:    "?format=xml",
+			wantCode: 400,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			req := httptest.NewRequest(http.MethodGet, "/"+tt.query, nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/export")
+			h := NewHTTPHandler(e, mockService)
+			if err := h.ExportApps(c); err != nil {
+				t.Errorf("httpHandler.ExportApps() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Fatalf("HTTPHandler.ExportApps() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			if got := rec.Header().Get(echo.HeaderContentType); got != tt.wantContentType {
+				t.Errorf("HTTPHandler.ExportApps() Content-Type = %v, want %v", got, tt.wantContentType)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("HTTPHandler.ExportApps() body = %v, want %v", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func Test_HttpHandler_ImportApps(t *testing.T) {
+	var gotPolicy models.AppsPolicy
+	var gotDryRun bool
+	mockService := &ServiceMock{
+		ImportAppsFunc: func(policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error) {
+			gotPolicy, gotDryRun = policy, dryRun
+			return &models.PolicyImport{DryRun: dryRun, Changes: []models.PolicyChange{}}, nil
+		},
+	}
+	wantPolicy := models.AppsPolicy{Apps: []models.AppPolicy{{
+		AppID:    "com.aerogear.mobile_app_one",
+		AppName:  "App One",
+		Versions: []models.VersionPolicy{{Version: "1.0", Disabled: true, DisabledMessage: "Please update"}},
+	}}}
+
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		wantCode    int
+		wantDryRun  bool
+	}{
+		{
+			name:        "Should import a JSON document",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"apps":[{"appId":"com.aerogear.mobile_app_one","appName":"App One","versions":[{"version":"1.0","disabled":true,"disabledMessage":"Please update"}]}]}`,
+			wantCode:    200,
+		},
+		{
+			name:        "Should import a YAML document on a dry run",
+			query:       "?dryRun=true",
+			contentType: "application/yaml",
+			body:        "apps:\n- appId: com.aerogear.mobile_app_one\n  appName: App One\n  versions:\n  - version: \"1.0\"\n    disabled: true\n    disabledMessage: Please update\n",
+			wantCode:    200,
+			wantDryRun:  true,
+		},
+		{
+			name:        "Should return a bad request when the YAML document has an unknown field",
+			contentType: "application/yaml",
+			body:        "apps:\n- appId: com.aerogear.mobile_app_one\n  disable: true\n",
+			wantCode:    400,
+		},
+		{
+			name:        "Should return a bad request when the dry run parameter is not a boolean",
+			query:       "?dryRun=maybe",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"apps":[]}`,
+			wantCode:    400,
+		},
+		{
+			name:        "Should return a validation error when an appId is not in reverse-DNS format",
+			contentType: "application/yaml",
+			body:        "apps:\n- appId: mobile app one\n",
+			wantCode:    422,
+		},
+		{
+			name:        "Should return a validation error when an app is twice in the document",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"apps":[{"appId":"com.aerogear.mobile_app_one"},{"appId":"com.aerogear.Mobile_App_One"}]}`,
+			wantCode:    422,
+		},
+		{
+			name:        "Should return a validation error when a version is twice in an app",
+			contentType: echo.MIMEApplicationJSON,
+			body:        `{"apps":[{"appId":"com.aerogear.mobile_app_one","versions":[{"version":"1.0"},{"version":"1.0"}]}]}`,
+			wantCode:    422,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPolicy, gotDryRun = models.AppsPolicy{}, false
+
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			req := httptest.NewRequest(http.MethodPost, "/"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/import")
+			h := NewHTTPHandler(e, mockService)
+			if err := h.ImportApps(c); err != nil {
+				t.Errorf("httpHandler.ImportApps() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Fatalf("HTTPHandler.ImportApps() statusCode = %v, wantCode = %v, body = %v", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != 200 {
+				return
+			}
+			if !reflect.DeepEqual(gotPolicy, wantPolicy) {
+				t.Errorf("HTTPHandler.ImportApps() policy = %v, want %v", gotPolicy, wantPolicy)
+			}
+			if gotDryRun != tt.wantDryRun {
+				t.Errorf("HTTPHandler.ImportApps() dryRun = %v, wantDryRun = %v", gotDryRun, tt.wantDryRun)
+			}
+		})
+	}
+}
+
+func Test_httpHandler_SetReleases(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		data     interface{}
+		wantCode int
+	}{
+		{
+			name:     "Should replace the released versions",
+			id:       helpers.GetMockApp().ID,
+			data:     setReleasesRequest{UnknownVersions: models.UnknownVersionsBlock, Versions: []string{"1.0", "1.1"}},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "Should return a bad request when the id is invalid",
+			id:       "invalid",
+			data:     setReleasesRequest{UnknownVersions: models.UnknownVersionsBlock},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Should return a validation error when the mode is not valid",
+			id:       helpers.GetMockApp().ID,
+			data:     setReleasesRequest{UnknownVersions: "reject", Versions: []string{"1.0"}},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Should return a validation error when a version is empty",
+			id:       helpers.GetMockApp().ID,
+			data:     setReleasesRequest{UnknownVersions: models.UnknownVersionsFlag, Versions: []string{""}},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			body, _ := json.Marshal(tt.data)
+			req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/apps/:id/releases")
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+
+			if err := NewHTTPHandler(e, mockedService).SetReleases(c); err != nil {
+				t.Errorf("httpHandler.SetReleases() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("httpHandler.SetReleases() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_httpHandler_CreateSigningCertificate(t *testing.T) {
+	const fingerprint = "14:6D:E9:83:C5:73:06:50:D8:EE:B9:95:2F:34:FC:64:16:A0:83:42:E6:1D:BE:A8:8A:04:96:B2:3F:CF:44:E5"
+
+	tests := []struct {
+		name     string
+		id       string
+		data     interface{}
+		wantCode int
+	}{
+		{
+			name:     "Should register the certificate",
+			id:       helpers.GetMockApp().ID,
+			data:     createSigningCertificateRequest{Platform: models.PlatformAndroid, Fingerprint: fingerprint},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Should return a bad request when the id is invalid",
+			id:       "invalid",
+			data:     createSigningCertificateRequest{Platform: models.PlatformAndroid, Fingerprint: fingerprint},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Should return a validation error when the platform is not valid",
+			id:       helpers.GetMockApp().ID,
+			data:     createSigningCertificateRequest{Platform: "windows", Fingerprint: fingerprint},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Should return a validation error when the fingerprint is not a SHA-256 one",
+			id:       helpers.GetMockApp().ID,
+			data:     createSigningCertificateRequest{Platform: models.PlatformIOS, Fingerprint: "14:6D:E9:83"},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			body, _ := json.Marshal(tt.data)
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/apps/:id/certificates")
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+
+			if err := NewHTTPHandler(e, mockedService).CreateSigningCertificate(c); err != nil {
+				t.Errorf("httpHandler.CreateSigningCertificate() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("httpHandler.CreateSigningCertificate() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_httpHandler_GetApps_WithTenants(t *testing.T) {
+	const (
+		ownTenant   = "6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d"
+		otherTenant = "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d"
+	)
+	tenantsService := &tenants.ServiceMock{
+		GetScopeFunc: func(username string) (*models.TenantScope, error) {
+			return &models.TenantScope{TenantIDs: []string{ownTenant}}, nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		wantTenants *models.TenantScope
+	}{
+		{
+			name:        "Should only return the apps of the tenants of the user",
+			wantTenants: &models.TenantScope{TenantIDs: []string{ownTenant}},
+		},
+		{
+			name:        "Should only return the apps of the requested tenant of the user",
+			query:       "?tenantId=" + ownTenant,
+			wantTenants: &models.TenantScope{TenantIDs: []string{ownTenant}},
+		},
+		{
+			name:        "Should return no app for a tenant the user is not a member of",
+			query:       "?tenantId=" + otherTenant,
+			wantTenants: &models.TenantScope{TenantIDs: []string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *models.TenantScope
+			service := &ServiceMock{
+				GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+					got = query.Tenants
+					return &models.AppsPage{Apps: []models.App{}}, nil
+				},
+			}
+
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			req := httptest.NewRequest(http.MethodGet, "/apps"+tt.query, nil)
+			req.Header.Set(user.USER_NAME_HEADER, "alice")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/apps")
+
+			if err := tenants.Scope(tenantsService)(NewHTTPHandler(e, service).GetAppsPage)(c); err != nil {
+				t.Fatalf("httpHandler.GetAppsPage() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.wantTenants) {
+				t.Errorf("httpHandler.GetAppsPage() tenants = %v, want %v", got, tt.wantTenants)
+			}
+		})
+	}
+}
+
+func Test_httpHandler_CreateApp_WithTenants(t *testing.T) {
+	const ownTenant = "6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d"
+
+	tests := []struct {
+		name         string
+		tenants      []string
+		data         createAppRequest
+		wantCode     int
+		wantTenantID string
+	}{
+		{
+			name:         "Should create the app in the only tenant of the user",
+			tenants:      []string{ownTenant},
+			data:         createAppRequest{AppID: "com.aerogear.testapp"},
+			wantCode:     http.StatusCreated,
+			wantTenantID: ownTenant,
+		},
+		{
+			name:     "Should require a tenant from a member of several tenants",
+			tenants:  []string{ownTenant, "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d"},
+			data:     createAppRequest{AppID: "com.aerogear.testapp"},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Should not create the app in a tenant the user is not a member of",
+			tenants:  []string{ownTenant},
+			data:     createAppRequest{AppID: "com.aerogear.testapp", TenantID: models.DefaultTenantID},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ServiceMock{
+				CreateAppFunc: func(app models.App) error {
+					return nil
+				},
+			}
+			tenantsService := &tenants.ServiceMock{
+				GetScopeFunc: func(username string) (*models.TenantScope, error) {
+					return &models.TenantScope{TenantIDs: tt.tenants}, nil
+				},
+			}
+
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			body, _ := json.Marshal(tt.data)
+			req := httptest.NewRequest(http.MethodPost, "/apps", strings.NewReader(string(body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(user.USER_NAME_HEADER, "alice")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/apps")
+
+			if err := tenants.Scope(tenantsService)(NewHTTPHandler(e, service).CreateApp)(c); err != nil {
+				t.Fatalf("httpHandler.CreateApp() error = %v", err)
+			}
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("httpHandler.CreateApp() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+			if calls := service.CreateAppCalls(); tt.wantTenantID != "" && (len(calls) != 1 || calls[0].App.TenantID != tt.wantTenantID) {
+				t.Errorf("httpHandler.CreateApp() created %v, want the app in the tenant %v", calls, tt.wantTenantID)
+			}
+		})
+	}
+}
+
+func Test_httpHandler_CreateApp_DeletedAppOfAnotherTenant(t *testing.T) {
+	const (
+		tenantA = "6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d"
+		tenantB = "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d"
+	)
+
+	tests := []struct {
+		name        string
+		scope       *models.TenantScope
+		wantCode    int
+		wantRestore bool
+	}{
+		{
+			name:        "Should restore the deleted app for a member of its tenant",
+			scope:       &models.TenantScope{TenantIDs: []string{tenantA}},
+			wantCode:    http.StatusCreated,
+			wantRestore: true,
+		},
+		{
+			name:     "Should report a conflict without restoring the deleted app for a member of another tenant",
+			scope:    &models.TenantScope{TenantIDs: []string{tenantB}},
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:        "Should restore the deleted app of any tenant for a super admin",
+			scope:       &models.TenantScope{All: true},
+			wantCode:    http.StatusCreated,
+			wantRestore: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := &RepositoryMock{
+				GetAppByAppIDFunc: func(appID string) (*models.App, error) {
+					deletedApp := helpers.GetMockApp()
+					deletedApp.TenantID = tenantA
+					deletedApp.DeletedAt = "2019-02-15T09:38:33+00:00"
+					return deletedApp, nil
+				},
+				UnDeleteAppByAppIDFunc: func(appID string) error {
+					return nil
+				},
+				GetAppVersionsByAppIDFunc: func(ID string) (*[]models.Version, error) {
+					return nil, models.ErrNotFound
+				},
+			}
+			tenantsService := &tenants.ServiceMock{
+				GetScopeFunc: func(username string) (*models.TenantScope, error) {
+					return tt.scope, nil
+				},
+			}
+
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			body, _ := json.Marshal(createAppRequest{AppID: helpers.GetMockApp().AppID})
+			req := httptest.NewRequest(http.MethodPost, "/apps", strings.NewReader(string(body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(user.USER_NAME_HEADER, "alice")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/apps")
+
+			if err := tenants.Scope(tenantsService)(NewHTTPHandler(e, NewService(repository, nil)).CreateApp)(c); err != nil {
+				t.Fatalf("httpHandler.CreateApp() error = %v", err)
+			}
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("httpHandler.CreateApp() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+			if restored := len(repository.UnDeleteAppByAppIDCalls()) == 1; restored != tt.wantRestore {
+				t.Errorf("httpHandler.CreateApp() restored = %v, want %v", restored, tt.wantRestore)
+			}
+			if strings.Contains(rec.Body.String(), tenantA) {
+				t.Errorf("httpHandler.CreateApp() body = %v, reveals the tenant of the app", rec.Body.String())
+			}
+		})
+	}
+}
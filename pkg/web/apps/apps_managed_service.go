@@ -0,0 +1,117 @@
+package apps
+
+import (
+	"fmt"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// ErrManagedApp is returned when an app managed by the policy controller is changed through the API
+var ErrManagedApp = models.ErrConflict.WithMessage("The app is managed by the policy directory and can only be changed there")
+
+// managedService rejects the changes of the apps managed by a policy controller
+// and forwards everything else to the service it wraps
+type managedService struct {
+	Service
+	repository Repository
+	controller *PolicyController
+}
+
+// NewManagedService returns a Service which rejects with ErrManagedApp the changes of the apps managed by
+// the controller, so they are not reverted by its next reconciliation. The controller itself must be given
+// the wrapped service. The repository finds the apps by their id, including the soft deleted ones.
+func NewManagedService(s Service, repository Repository, controller *PolicyController) Service {
+	return &managedService{Service: s, repository: repository, controller: controller}
+}
+
+// UpdateAppVersions rejects the update of the versions of a managed app
+func (m *managedService) UpdateAppVersions(id string, versions []models.Version) error {
+	if m.isManaged(id) {
+		return ErrManagedApp
+	}
+	return m.Service.UpdateAppVersions(id, versions)
+}
+
+// DisableAllAppVersionsByAppID rejects disabling the versions of a managed app
+func (m *managedService) DisableAllAppVersionsByAppID(id string, message string) error {
+	if m.isManaged(id) {
+		return ErrManagedApp
+	}
+	return m.Service.DisableAllAppVersionsByAppID(id, message)
+}
+
+// DeleteAppById rejects the soft delete of a managed app
+func (m *managedService) DeleteAppById(id string) error {
+	if m.isManaged(id) {
+		return ErrManagedApp
+	}
+	return m.Service.DeleteAppById(id)
+}
+
+// RestoreAppByID rejects the restore of a deleted managed app, which is restored by its reconciliation
+// while it is in the policy directory
+func (m *managedService) RestoreAppByID(id string) error {
+	if m.isManaged(id) {
+		return ErrManagedApp
+	}
+	return m.Service.RestoreAppByID(id)
+}
+
+// HardDeleteAppByID rejects the hard delete of a managed app. A dry run is allowed.
+func (m *managedService) HardDeleteAppByID(id string, dryRun bool) (*models.AppHardDelete, error) {
+	if !dryRun && m.isManaged(id) {
+		return nil, ErrManagedApp
+	}
+	return m.Service.HardDeleteAppByID(id, dryRun)
+}
+
+// CreateApp rejects the creation of an app declared in the policy directory
+func (m *managedService) CreateApp(app models.App) error {
+	if m.controller.IsManaged(app.AppID) {
+		return ErrManagedApp
+	}
+	return m.Service.CreateApp(app)
+}
+
+// UpdateAppNameByID rejects renaming a managed app
+func (m *managedService) UpdateAppNameByID(id, name string) error {
+	if m.isManaged(id) {
+		return ErrManagedApp
+	}
+	return m.Service.UpdateAppNameByID(id, name)
+}
+
+// ImportApps rejects a policy with managed apps. A dry run is allowed.
+func (m *managedService) ImportApps(policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error) {
+	if !dryRun {
+		for _, app := range policy.Apps {
+			if m.controller.IsManaged(app.AppID) {
+				return nil, ErrManagedApp.WithMessage(fmt.Sprintf("The app %v is managed by the policy directory and can only be changed there", app.AppID))
+			}
+		}
+	}
+	return m.Service.ImportApps(policy, dryRun)
+}
+
+// SetReleases rejects the update of the released versions of a managed app
+func (m *managedService) SetReleases(id string, releases models.Releases) error {
+	if m.isManaged(id) {
+		return ErrManagedApp
+	}
+	return m.Service.SetReleases(id, releases)
+}
+
+// AddRelease rejects the declaration of a released version of a managed app
+func (m *managedService) AddRelease(id, version string) error {
+	if m.isManaged(id) {
+		return ErrManagedApp
+	}
+	return m.Service.AddRelease(id, version)
+}
+
+// isManaged is true when the app with the given id, active or soft deleted, is managed. The apps which
+// cannot be found are left to the wrapped service, which returns the error.
+func (m *managedService) isManaged(id string) bool {
+	app, err := m.repository.GetAppByID(id)
+	return err == nil && m.controller.IsManaged(app.AppID)
+}
@@ -0,0 +1,171 @@
+package apps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+func Test_managedService(t *testing.T) {
+	const (
+		managedID        = "7f89ce49-a736-459e-9110-e52d049fc027"
+		deletedManagedID = "c5a7d1a2-0c54-4d36-9a6b-4b3c0d7b5e21"
+		unmanagedID      = "0890506c-3dd1-43ad-8a09-21a4111a65a6"
+	)
+
+	repository := &RepositoryMock{
+		GetAppByIDFunc: func(id string) (*models.App, error) {
+			switch id {
+			case managedID:
+				return &models.App{ID: id, AppID: "com.aerogear.managed"}, nil
+			case deletedManagedID:
+				return &models.App{ID: id, AppID: "com.aerogear.deleted", DeletedAt: "2019-06-01 10:00:00"}, nil
+			case unmanagedID:
+				return &models.App{ID: id, AppID: "com.aerogear.unmanaged"}, nil
+			}
+			return nil, models.ErrNotFound
+		},
+	}
+
+	newService := func() *ServiceMock {
+		return &ServiceMock{
+			UpdateAppVersionsFunc:            func(id string, versions []models.Version) error { return nil },
+			DisableAllAppVersionsByAppIDFunc: func(id string, message string) error { return nil },
+			DeleteAppByIdFunc:                func(id string) error { return nil },
+			HardDeleteAppByIDFunc: func(id string, dryRun bool) (*models.AppHardDelete, error) {
+				return &models.AppHardDelete{DryRun: dryRun}, nil
+			},
+			CreateAppFunc:         func(app models.App) error { return nil },
+			UpdateAppNameByIDFunc: func(id, name string) error { return nil },
+			RestoreAppByIDFunc:    func(id string) error { return nil },
+			ImportAppsFunc: func(policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error) {
+				return &models.PolicyImport{DryRun: dryRun}, nil
+			},
+			SetReleasesFunc: func(id string, releases models.Releases) error { return nil },
+			AddReleaseFunc:  func(id, version string) error { return nil },
+		}
+	}
+
+	controller := NewPolicyController(nil, "", 0)
+	controller.setManaged(models.AppsPolicy{Apps: []models.AppPolicy{{AppID: "com.aerogear.managed"}, {AppID: "com.aerogear.deleted"}}})
+
+	managedPolicy := models.AppsPolicy{Apps: []models.AppPolicy{{AppID: "com.aerogear.unmanaged"}, {AppID: "COM.aerogear.managed"}}}
+
+	tests := []struct {
+		name    string
+		call    func(s Service) error
+		wantErr bool
+	}{
+		{
+			name:    "Should reject the update of the versions of a managed app",
+			call:    func(s Service) error { return s.UpdateAppVersions(managedID, nil) },
+			wantErr: true,
+		},
+		{
+			name: "Should update the versions of an app which is not managed",
+			call: func(s Service) error { return s.UpdateAppVersions(unmanagedID, nil) },
+		},
+		{
+			name:    "Should reject disabling the versions of a managed app",
+			call:    func(s Service) error { return s.DisableAllAppVersionsByAppID(managedID, "") },
+			wantErr: true,
+		},
+		{
+			name:    "Should reject the delete of a managed app",
+			call:    func(s Service) error { return s.DeleteAppById(managedID) },
+			wantErr: true,
+		},
+		{
+			name: "Should delete an app which is not managed",
+			call: func(s Service) error { return s.DeleteAppById(unmanagedID) },
+		},
+		{
+			name: "Should reject the hard delete of a managed app",
+			call: func(s Service) error {
+				_, err := s.HardDeleteAppByID(managedID, false)
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should allow the dry run of the hard delete of a managed app",
+			call: func(s Service) error {
+				_, err := s.HardDeleteAppByID(managedID, true)
+				return err
+			},
+		},
+		{
+			name:    "Should reject the creation of a managed app",
+			call:    func(s Service) error { return s.CreateApp(models.App{AppID: "com.aerogear.Managed"}) },
+			wantErr: true,
+		},
+		{
+			name:    "Should reject renaming a managed app",
+			call:    func(s Service) error { return s.UpdateAppNameByID(managedID, "Managed") },
+			wantErr: true,
+		},
+		{
+			name:    "Should reject the restore of a deleted managed app",
+			call:    func(s Service) error { return s.RestoreAppByID(deletedManagedID) },
+			wantErr: true,
+		},
+		{
+			name: "Should restore an app which is not managed",
+			call: func(s Service) error { return s.RestoreAppByID(unmanagedID) },
+		},
+		{
+			name: "Should reject the hard delete of a deleted managed app",
+			call: func(s Service) error {
+				_, err := s.HardDeleteAppByID(deletedManagedID, false)
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should reject the import of a policy with a managed app",
+			call: func(s Service) error {
+				_, err := s.ImportApps(managedPolicy, false)
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name: "Should allow the dry run of the import of a policy with a managed app",
+			call: func(s Service) error {
+				_, err := s.ImportApps(managedPolicy, true)
+				return err
+			},
+		},
+		{
+			name:    "Should reject the update of the released versions of a managed app",
+			call:    func(s Service) error { return s.SetReleases(managedID, models.Releases{}) },
+			wantErr: true,
+		},
+		{
+			name:    "Should reject the declaration of a released version of a managed app",
+			call:    func(s Service) error { return s.AddRelease(managedID, "1.0") },
+			wantErr: true,
+		},
+		{
+			name: "Should declare a released version of an app which is not managed",
+			call: func(s Service) error { return s.AddRelease(unmanagedID, "1.0") },
+		},
+		{
+			name: "Should leave the apps which are not found to the wrapped service",
+			call: func(s Service) error { return s.DeleteAppById("53d4e6c6-6b7f-4a50-8d2c-0fe5b4c1a2b9") },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call(NewManagedService(newService(), repository, controller))
+
+			if tt.wantErr != errors.Is(err, ErrManagedApp) {
+				t.Errorf("managedService error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("managedService error = %v, want nil", err)
+			}
+		})
+	}
+}
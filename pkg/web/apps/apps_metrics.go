@@ -0,0 +1,195 @@
+package apps
+
+import (
+	"sync"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// The reasons of the launches blocked by the init endpoint
+const (
+	// BlockedDisabledVersion is a launch of a version disabled by an admin
+	BlockedDisabledVersion = "disabled_version"
+	// BlockedUnknownVersion is a launch of a version which is not released, blocked by the policy of the app
+	BlockedUnknownVersion = "unknown_version"
+	// BlockedTamperedBuild is a launch of a build which is not signed by the certificates of the app
+	BlockedTamperedBuild = "tampered_build"
+)
+
+// otherLabel is the label of the versions over the limit of an app and of the platforms which are not known
+const otherLabel = "other"
+
+var (
+	AppLaunchesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "app_launches_total",
+			Help: "A counter for the launches registered by the init requests, by app, version and platform",
+		},
+		[]string{"app_id", "version", "platform"},
+	)
+	AppNewDevicesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "app_new_devices_total",
+			Help: "A counter for the devices registered by their first init request, by app and platform",
+		},
+		[]string{"app_id", "platform"},
+	)
+	AppBlockedLaunchesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "app_blocked_launches_total",
+			Help: "A counter for the launches disabled by the init requests, by app and reason",
+		},
+		[]string{"app_id", "reason"},
+	)
+	AppDisabledVersions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "app_disabled_versions",
+			Help: "The number of disabled versions of the active apps",
+		},
+		[]string{"app_id"},
+	)
+)
+
+// metricPlatforms are the platform labels of the metrics
+var metricPlatforms = []string{models.PlatformAndroid, models.PlatformIOS, otherLabel}
+
+// blockedReasons are the reason labels of the blocked launches
+var blockedReasons = []string{BlockedDisabledVersion, BlockedUnknownVersion, BlockedTamperedBuild}
+
+// metricVersions bounds the version labels of the metrics, which are sent by the devices
+var metricVersions = &versionLabels{max: 20, versions: map[string]map[string]bool{}}
+
+// SetMaxMetricVersions sets the number of versions of each app which have their own label in the metrics
+func SetMaxMetricVersions(max int) {
+	metricVersions.mu.Lock()
+	defer metricVersions.mu.Unlock()
+	metricVersions.max = max
+}
+
+// versionLabels keeps the versions of each app which have their own label in the metrics: the first ones seen,
+// up to max. The other versions are labelled "other" so a client cannot create any number of series.
+type versionLabels struct {
+	mu       sync.Mutex
+	max      int
+	versions map[string]map[string]bool
+}
+
+// label returns the version label of a version of an app
+func (v *versionLabels) label(appID, version string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	versions := v.versions[appID]
+	if versions[version] {
+		return version
+	}
+	if len(versions) >= v.max {
+		return otherLabel
+	}
+
+	if versions == nil {
+		versions = map[string]bool{}
+		v.versions[appID] = versions
+	}
+	versions[version] = true
+	return version
+}
+
+// forget removes the versions of an app and returns their labels, with the "other" one
+func (v *versionLabels) forget(appID string) []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	labels := []string{otherLabel}
+	for version := range v.versions[appID] {
+		labels = append(labels, version)
+	}
+	delete(v.versions, appID)
+	return labels
+}
+
+// platformLabel returns the platform label of a device type
+func platformLabel(deviceType string) string {
+	if platform := models.PlatformOf(deviceType); platform != "" {
+		return platform
+	}
+	return otherLabel
+}
+
+// countLaunch counts a launch registered by the init endpoint, with its device when it is new
+// and as blocked when its version is disabled
+func countLaunch(device models.Device, version models.Version, newDevice bool) {
+	platform := platformLabel(device.DeviceType)
+	AppLaunchesTotal.WithLabelValues(device.AppID, metricVersions.label(device.AppID, version.Version), platform).Inc()
+	if newDevice {
+		AppNewDevicesTotal.WithLabelValues(device.AppID, platform).Inc()
+	}
+	if version.Disabled {
+		AppBlockedLaunchesTotal.WithLabelValues(device.AppID, BlockedDisabledVersion).Inc()
+	}
+}
+
+// countBlockedLaunch counts a launch blocked by the init endpoint before it is registered
+func countBlockedLaunch(appID, reason string) {
+	AppBlockedLaunchesTotal.WithLabelValues(appID, reason).Inc()
+}
+
+// setDisabledVersions sets the number of disabled versions of an app
+func setDisabledVersions(appID string, versions []models.Version) {
+	disabled := 0
+	for _, version := range versions {
+		if version.Disabled {
+			disabled++
+		}
+	}
+	AppDisabledVersions.WithLabelValues(appID).Set(float64(disabled))
+}
+
+// removeAppMetrics removes the series of an app which is deleted
+func removeAppMetrics(appID string) {
+	for _, version := range metricVersions.forget(appID) {
+		for _, platform := range metricPlatforms {
+			AppLaunchesTotal.DeleteLabelValues(appID, version, platform)
+		}
+	}
+	for _, platform := range metricPlatforms {
+		AppNewDevicesTotal.DeleteLabelValues(appID, platform)
+	}
+	for _, reason := range blockedReasons {
+		AppBlockedLaunchesTotal.DeleteLabelValues(appID, reason)
+	}
+	AppDisabledVersions.DeleteLabelValues(appID)
+}
+
+// refreshDisabledVersions sets the number of disabled versions of an app after they were changed.
+// The change is not failed when they cannot be read.
+func (a *appsService) refreshDisabledVersions(appID string) {
+	versions, err := a.getAppVersions(appID)
+	if err != nil {
+		log.WithField("appId", appID).Warnf("Could not count the disabled versions of the app: %v", err)
+		return
+	}
+	setDisabledVersions(appID, versions)
+}
+
+// LoadMetrics sets the number of disabled versions of every active app, which are then kept up to date
+// by the changes of their versions
+func LoadMetrics(repository Repository) error {
+	page, err := repository.GetApps(models.AppsQuery{})
+	if err != nil {
+		return err
+	}
+
+	s := &appsService{repository: repository}
+	for _, app := range page.Apps {
+		versions, err := s.getAppVersions(app.AppID)
+		if err != nil {
+			return err
+		}
+		setDisabledVersions(app.AppID, versions)
+	}
+
+	return nil
+}
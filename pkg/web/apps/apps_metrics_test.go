@@ -0,0 +1,273 @@
+package apps
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gatherAppMetrics returns the values of the business metrics of the apps by name and labels,
+// e.g. app_blocked_launches_total{app_id=com.aerogear.mobile_app_one,reason=tampered_build}
+func gatherAppMetrics(t *testing.T) map[string]float64 {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(AppLaunchesTotal, AppNewDevicesTotal, AppBlockedLaunchesTotal, AppDisabledVersions)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error gathering the metrics: %v", err)
+	}
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			var labels []string
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%v=%v", label.GetName(), label.GetValue()))
+			}
+			value := metric.GetCounter().GetValue()
+			if metric.GetGauge() != nil {
+				value = metric.GetGauge().GetValue()
+			}
+			values[family.GetName()+"{"+strings.Join(labels, ",")+"}"] = value
+		}
+	}
+	return values
+}
+
+// resetAppMetrics clears the business metrics of the apps and the versions with their own label
+func resetAppMetrics() {
+	AppLaunchesTotal.Reset()
+	AppNewDevicesTotal.Reset()
+	AppBlockedLaunchesTotal.Reset()
+	AppDisabledVersions.Reset()
+	metricVersions = &versionLabels{max: 20, versions: map[string]map[string]bool{}}
+}
+
+func Test_appsService_InitClientApp_metrics(t *testing.T) {
+	app := helpers.GetMockApp()
+
+	tests := []struct {
+		name        string
+		appID       string
+		version     *models.Version
+		device      *models.Device
+		released    []string
+		tampered    bool
+		deviceType  string
+		wantMetrics map[string]float64
+	}{
+		{
+			name:       "Should count the launch and the new device of a new version",
+			deviceType: "Android",
+			wantMetrics: map[string]float64{
+				"app_launches_total{app_id=com.aerogear.mobile_app_one,platform=android,version=1.0}": 1,
+				"app_new_devices_total{app_id=com.aerogear.mobile_app_one,platform=android}":          1,
+			},
+		},
+		{
+			name:       "Should count the launch of a disabled version as blocked",
+			version:    &models.Version{ID: "55ebd387-9c68-4137-a367-a12025cc2cdb", AppID: app.AppID, Version: "1.0", Disabled: true},
+			device:     &models.Device{ID: "a742f8b7-5e2f-43f4-a5b8-2e8bb3e5a3ad", VersionID: "55ebd387-9c68-4137-a367-a12025cc2cdb", AppID: app.AppID, DeviceID: "a742f8b7-5e2f-43f4-a5b8-2e8bb3e5a3ad", DeviceVersion: "10", DeviceType: "iOS"},
+			deviceType: "iOS",
+			wantMetrics: map[string]float64{
+				"app_launches_total{app_id=com.aerogear.mobile_app_one,platform=ios,version=1.0}":        1,
+				"app_blocked_launches_total{app_id=com.aerogear.mobile_app_one,reason=disabled_version}": 1,
+			},
+		},
+		{
+			name:       "Should count the launch of an unknown platform as other",
+			deviceType: "Windows",
+			wantMetrics: map[string]float64{
+				"app_launches_total{app_id=com.aerogear.mobile_app_one,platform=other,version=1.0}": 1,
+				"app_new_devices_total{app_id=com.aerogear.mobile_app_one,platform=other}":          1,
+			},
+		},
+		{
+			name:       "Should only count an unknown version blocked by the app as blocked",
+			released:   []string{"2.0"},
+			deviceType: "Android",
+			wantMetrics: map[string]float64{
+				"app_blocked_launches_total{app_id=com.aerogear.mobile_app_one,reason=unknown_version}": 1,
+			},
+		},
+		{
+			name:       "Should only count a tampered build blocked by the app as blocked",
+			tampered:   true,
+			deviceType: "Android",
+			wantMetrics: map[string]float64{
+				"app_blocked_launches_total{app_id=com.aerogear.mobile_app_one,reason=tampered_build}": 1,
+			},
+		},
+		{
+			name:       "Should label a blocked build with the stored app id when the device sends it in another case",
+			appID:      "COM.aerogear.Mobile_App_One",
+			tampered:   true,
+			deviceType: "Android",
+			wantMetrics: map[string]float64{
+				"app_blocked_launches_total{app_id=com.aerogear.mobile_app_one,reason=tampered_build}": 1,
+			},
+		},
+		{
+			name:       "Should label a launch with the stored app id when the device sends it in another case",
+			appID:      "COM.aerogear.Mobile_App_One",
+			deviceType: "Android",
+			wantMetrics: map[string]float64{
+				"app_launches_total{app_id=com.aerogear.mobile_app_one,platform=android,version=1.0}": 1,
+				"app_new_devices_total{app_id=com.aerogear.mobile_app_one,platform=android}":          1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetAppMetrics()
+
+			repo := *mockRepositoryWithSuccessResults
+			// the app ids are compared without their case, as by the repository
+			repo.GetActiveAppByAppIDFunc = func(appID string) (*models.App, error) {
+				if strings.EqualFold(appID, app.AppID) {
+					return helpers.GetMockApp(), nil
+				}
+				return nil, models.ErrNotFound
+			}
+			repo.GetVersionByAppIDAndVersionFunc = func(appID string, version string) (*models.Version, error) {
+				if tt.version == nil {
+					return nil, models.ErrNotFound
+				}
+				return tt.version, nil
+			}
+			repo.GetReleasesByAppIDFunc = func(appID string) (*models.Releases, error) {
+				return &models.Releases{AppID: appID, UnknownVersions: models.UnknownVersionsBlock, Versions: tt.released}, nil
+			}
+			repo.GetSigningPolicyByAppIDFunc = func(appID string) (*models.SigningPolicy, error) {
+				policy := &models.SigningPolicy{AppID: appID, TamperedBuilds: models.TamperedBuildsBlock}
+				if tt.tampered {
+					policy.Certificates = []models.SigningCertificate{{AppID: appID, Platform: models.PlatformAndroid, Fingerprint: testFingerprint}}
+				}
+				return policy, nil
+			}
+			repo.UpsertVersionWithAppLaunchesAndLastLaunchedFunc = func(version *models.Version) error {
+				return nil
+			}
+			repo.GetDeviceByDeviceIDAndAppIDFunc = func(deviceID string, appID string) (*models.Device, error) {
+				if tt.device == nil {
+					return nil, models.ErrNotFound
+				}
+				return tt.device, nil
+			}
+			repo.InsertDeviceOrUpdateVersionIDFunc = func(device models.Device) error {
+				return nil
+			}
+
+			appID := app.AppID
+			if tt.appID != "" {
+				appID = tt.appID
+			}
+
+			device := &models.Device{AppID: appID, DeviceID: "a742f8b7-5e2f-43f4-a5b8-2e8bb3e5a3ad", DeviceVersion: "10", DeviceType: tt.deviceType, Version: "1.0"}
+			if _, err := NewService(&repo, nil).InitClientApp(device); err != nil {
+				t.Fatalf("appsService.InitClientApp() error = %v", err)
+			}
+
+			if got := gatherAppMetrics(t); !reflect.DeepEqual(got, tt.wantMetrics) {
+				t.Errorf("appsService.InitClientApp() metrics = %v, want %v", got, tt.wantMetrics)
+			}
+
+			for labelled := range metricVersions.versions {
+				if labelled != app.AppID {
+					t.Errorf("appsService.InitClientApp() labelled the versions of %v, want %v", labelled, app.AppID)
+				}
+			}
+		})
+	}
+}
+
+func Test_versionLabels_label(t *testing.T) {
+	labels := &versionLabels{max: 2, versions: map[string]map[string]bool{}}
+
+	for _, tt := range []struct{ appID, version, want string }{
+		{"app-one", "1.0", "1.0"},
+		{"app-one", "1.1", "1.1"},
+		{"app-one", "1.2-typo", "other"},
+		{"app-one", "1.0", "1.0"},
+		{"app-two", "1.2-typo", "1.2-typo"},
+	} {
+		if got := labels.label(tt.appID, tt.version); got != tt.want {
+			t.Errorf("versionLabels.label(%v, %v) = %v, want %v", tt.appID, tt.version, got, tt.want)
+		}
+	}
+
+	// the labels of a deleted app are freed
+	labels.forget("app-one")
+	if got := labels.label("app-one", "1.2-typo"); got != "1.2-typo" {
+		t.Errorf("versionLabels.label() after forget = %v, want 1.2-typo", got)
+	}
+}
+
+func Test_appsService_metricsOfTheAdminChanges(t *testing.T) {
+	resetAppMetrics()
+	app := helpers.GetMockApp()
+
+	disabled := helpers.GetMockAppVersionList()[:2]
+	disabled[1].Disabled = true
+
+	repo := *mockRepositoryWithSuccessResults
+	repo.GetAppVersionsByAppIDFunc = func(ID string) (*[]models.Version, error) {
+		return &disabled, nil
+	}
+	s := NewService(&repo, nil)
+
+	if err := s.UpdateAppVersions(app.ID, disabled); err != nil {
+		t.Fatalf("appsService.UpdateAppVersions() error = %v", err)
+	}
+	countLaunch(models.Device{AppID: app.AppID, DeviceType: "Android"}, disabled[1], true)
+
+	want := map[string]float64{
+		"app_disabled_versions{app_id=com.aerogear.mobile_app_one}":                              1,
+		"app_launches_total{app_id=com.aerogear.mobile_app_one,platform=android,version=1.1}":    1,
+		"app_new_devices_total{app_id=com.aerogear.mobile_app_one,platform=android}":             1,
+		"app_blocked_launches_total{app_id=com.aerogear.mobile_app_one,reason=disabled_version}": 1,
+	}
+	if got := gatherAppMetrics(t); !reflect.DeepEqual(got, want) {
+		t.Errorf("appsService.UpdateAppVersions() metrics = %v, want %v", got, want)
+	}
+
+	// the series of a deleted app are removed
+	if err := s.DeleteAppById(app.ID); err != nil {
+		t.Fatalf("appsService.DeleteAppById() error = %v", err)
+	}
+	if got := gatherAppMetrics(t); len(got) != 0 {
+		t.Errorf("appsService.DeleteAppById() metrics = %v, want none", got)
+	}
+}
+
+func Test_LoadMetrics(t *testing.T) {
+	resetAppMetrics()
+
+	repo := *mockRepositoryWithSuccessResults
+	repo.GetAppVersionsByAppIDFunc = func(ID string) (*[]models.Version, error) {
+		if ID != "com.aerogear.mobile_app_one" {
+			return nil, models.ErrNotFound
+		}
+		versions := helpers.GetMockAppVersionList()[:2]
+		versions[0].Disabled = true
+		versions[1].Disabled = true
+		return &versions, nil
+	}
+
+	if err := LoadMetrics(&repo); err != nil {
+		t.Fatalf("LoadMetrics() error = %v", err)
+	}
+
+	got := gatherAppMetrics(t)
+	if got["app_disabled_versions{app_id=com.aerogear.mobile_app_one}"] != 2 {
+		t.Errorf("LoadMetrics() metrics = %v, want 2 disabled versions of com.aerogear.mobile_app_one", got)
+	}
+	if v, ok := got["app_disabled_versions{app_id=com.aerogear.mobile_app_three}"]; !ok || v != 0 {
+		t.Errorf("LoadMetrics() metrics = %v, want no disabled versions of com.aerogear.mobile_app_three", got)
+	}
+}
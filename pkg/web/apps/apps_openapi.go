@@ -0,0 +1,35 @@
+package apps
+
+import "github.com/aerogear/mobile-security-service/pkg/openapi"
+
+// Schemas returns the requests and responses of the apps endpoints by the name of their schema in the API documentation
+func Schemas() openapi.Schemas {
+	return openapi.Schemas{
+		"CreateAppRequest":                createAppRequest{},
+		"UpdateAppNameRequest":            updateAppNameRequest{},
+		"UpdateVersionRequest":            updateVersionRequest{},
+		"DisableAppVersionsRequest":       disableAppVersionsRequest{},
+		"SetReleasesRequest":              setReleasesRequest{},
+		"AddReleaseRequest":               addReleaseRequest{},
+		"CreateSigningCertificateRequest": createSigningCertificateRequest{},
+		"SetTamperedBuildsRequest":        setTamperedBuildsRequest{},
+		"AppResponse":                     appResponse{},
+		"AppsPageResponse":                appsPageResponse{},
+		"VersionResponse":                 versionResponse{},
+		"ArchivedVersionResponse":         archivedVersionResponse{},
+		"DeviceResponse":                  deviceResponse{},
+		"ArchivedAppResponse":             archivedAppResponse{},
+		"ArchivedWebhookResponse":         archivedWebhookResponse{},
+		"ArchivedAlertResponse":           archivedAlertResponse{},
+		"AppArchiveResponse":              appArchiveResponse{},
+		"HardDeleteAppResponse":           hardDeleteAppResponse{},
+		"ReleasesResponse":                releasesResponse{},
+		"SigningCertificateResponse":      signingCertificateResponse{},
+		"SigningPolicyResponse":           signingPolicyResponse{},
+		"AppsPolicy":                      appsPolicyDocument{},
+		"AppPolicy":                       appPolicyDocument{},
+		"VersionPolicy":                   versionPolicyDocument{},
+		"ImportAppsResponse":              importAppsResponse{},
+		"PolicyChangeResponse":            policyChangeResponse{},
+	}
+}
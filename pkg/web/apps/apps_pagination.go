@@ -0,0 +1,105 @@
+package apps
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+const (
+	// DefaultAppsSort is the order used when no sort parameter is supplied
+	DefaultAppsSort = "name"
+	// MaxAppsLimit is the maximum page size allowed when listing apps
+	MaxAppsLimit = 100
+)
+
+// appsSortColumns maps the sort fields of GET /apps to the expression they order by
+var appsSortColumns = map[string]string{
+	"name":     "COALESCE(LOWER(app_name),'')",
+	"launches": "num_of_app_launches",
+	"installs": "num_of_current_installs",
+}
+
+// appsCursor is the position of the last app of a page.
+// It is sent to the clients base64 encoded so they treat it as opaque.
+type appsCursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
+
+// parseAppsSort splits the sort parameter into the field and its direction
+func parseAppsSort(sort string) (field string, desc bool, ok bool) {
+	if sort == "" {
+		sort = DefaultAppsSort
+	}
+
+	field = strings.TrimPrefix(sort, "-")
+	_, ok = appsSortColumns[field]
+
+	return field, field != sort, ok
+}
+
+func encodeAppsCursor(c appsCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeAppsCursor(s string) (*appsCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, models.ErrBadParamInput
+	}
+
+	var c appsCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return nil, models.ErrBadParamInput
+	}
+
+	return &c, nil
+}
+
+// validateAppsQuery checks the pagination parameters and returns ErrBadParamInput
+// when the limit is out of range, the sort field is unknown or the cursor does
+// not belong to the requested sort
+func validateAppsQuery(query models.AppsQuery) error {
+	if query.Limit < 0 || query.Limit > MaxAppsLimit {
+		return invalidQueryParam("limit", fmt.Sprintf("limit must be between 0 and %d", MaxAppsLimit))
+	}
+
+	field, _, ok := parseAppsSort(query.Sort)
+	if !ok {
+		return invalidQueryParam("sort", "sort is not a known field")
+	}
+
+	if query.Cursor == "" {
+		return nil
+	}
+
+	c, err := decodeAppsCursor(query.Cursor)
+	if err != nil {
+		return invalidQueryParam("cursor", "cursor is not valid")
+	}
+
+	sort := query.Sort
+	if sort == "" {
+		sort = DefaultAppsSort
+	}
+
+	if c.Sort != sort {
+		return invalidQueryParam("cursor", "cursor was issued for another sort")
+	}
+
+	// the numeric sort keys are compared to integer columns in the database
+	if field != "name" {
+		if _, err := strconv.ParseInt(c.Key, 10, 64); err != nil {
+			return invalidQueryParam("cursor", "cursor is not valid")
+		}
+	}
+
+	return nil
+}
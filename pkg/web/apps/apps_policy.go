@@ -0,0 +1,334 @@
+package apps
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aerogear/mobile-security-service/pkg/events"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// skipVersionReason explains why the versions which were never launched are not imported.
+// The versions are registered by the first launch of the app, with the init endpoint.
+const skipVersionReason = "the version was never launched"
+
+// ExportApps returns the policy of the active apps with the state of their versions.
+// The apps are sorted by appId and the versions by version so the document can be compared between exports.
+func (a *appsService) ExportApps() (*models.AppsPolicy, error) {
+	policy := &models.AppsPolicy{Apps: []models.AppPolicy{}}
+
+	page, err := a.repository.GetApps(models.AppsQuery{})
+	if errors.Is(err, models.ErrNotFound) {
+		return policy, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, app := range page.Apps {
+		versions, err := a.getAppVersions(app.AppID)
+		if err != nil {
+			return nil, err
+		}
+
+		appPolicy := models.AppPolicy{AppID: app.AppID, AppName: app.AppName}
+		for _, v := range versions {
+			appPolicy.Versions = append(appPolicy.Versions, models.VersionPolicy{
+				Version:         v.Version,
+				Disabled:        v.Disabled,
+				DisabledMessage: v.DisabledMessage,
+			})
+		}
+		sort.Slice(appPolicy.Versions, func(i, j int) bool {
+			return appPolicy.Versions[i].Version < appPolicy.Versions[j].Version
+		})
+
+		// the mode for the unknown versions only applies to an app with released versions
+		releases, err := a.repository.GetReleasesByAppID(app.AppID)
+		if err != nil {
+			return nil, err
+		}
+		if len(releases.Versions) > 0 {
+			appPolicy.UnknownVersions = releases.UnknownVersions
+			appPolicy.ReleasedVersions = releases.Versions
+		}
+
+		policy.Apps = append(policy.Apps, appPolicy)
+	}
+
+	sort.Slice(policy.Apps, func(i, j int) bool {
+		return policy.Apps[i].AppID < policy.Apps[j].AppID
+	})
+
+	return policy, nil
+}
+
+// ImportApps applies a policy and returns the changes made. When dryRun is true nothing is changed
+// and the changes which would be made are returned.
+// The apps are created, restored and renamed as with CreateApp and UpdateAppNameByID, and the versions
+// are updated as with UpdateAppVersions. The apps and the versions which are not in the policy are left
+// as they are, so importing the same policy twice makes no change the second time.
+// The apps are imported one at a time: on an error, the result with the changes of the apps imported
+// before it is returned with the error, since those changes are kept. The app which failed may be
+// partially changed.
+func (a *appsService) ImportApps(policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error) {
+	result := &models.PolicyImport{DryRun: dryRun, Changes: []models.PolicyChange{}}
+
+	for _, appPolicy := range policy.Apps {
+		changes, err := a.importApp(appPolicy, dryRun)
+		if err != nil {
+			return result, err
+		}
+		result.Changes = append(result.Changes, changes...)
+	}
+
+	return result, nil
+}
+
+// ReconcileApps makes the apps match a policy which declares all the apps it manages.
+// The policy is imported as with ImportApps and its apps are marked as managed, then the managed
+// apps which are no longer in the policy are soft deleted and stop being managed.
+// As with ImportApps, the result with the changes made is returned with an error.
+func (a *appsService) ReconcileApps(policy models.AppsPolicy) (*models.PolicyImport, error) {
+	result, err := a.ImportApps(policy, false)
+	if err != nil {
+		return result, err
+	}
+
+	managed, err := a.repository.GetManagedApps()
+	if err != nil {
+		return result, err
+	}
+
+	declared := map[string]bool{}
+	for _, appPolicy := range policy.Apps {
+		declared[strings.ToLower(appPolicy.AppID)] = true
+	}
+
+	wasManaged := map[string]bool{}
+	for _, app := range managed {
+		appID := strings.ToLower(app.AppID)
+		wasManaged[appID] = true
+		if declared[appID] {
+			continue
+		}
+
+		if err := a.repository.DeleteAppById(app.ID); err != nil {
+			return result, err
+		}
+		result.Changes = append(result.Changes, models.PolicyChange{Action: models.PolicyActionDelete, AppID: app.AppID})
+		if err := a.repository.SetAppManaged(app.AppID, false); err != nil {
+			return result, err
+		}
+		a.publish(events.AppDeleted{App: app})
+		removeAppMetrics(app.AppID)
+	}
+
+	for _, appPolicy := range policy.Apps {
+		if !wasManaged[strings.ToLower(appPolicy.AppID)] {
+			if err := a.repository.SetAppManaged(appPolicy.AppID, true); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// importApp applies the policy of a single app and returns the changes made
+func (a *appsService) importApp(policy models.AppPolicy, dryRun bool) ([]models.PolicyChange, error) {
+	var changes []models.PolicyChange
+
+	app, err := a.repository.GetAppByAppID(policy.AppID)
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		app = nil
+		changes = append(changes, models.PolicyChange{
+			Action: models.PolicyActionCreate,
+			AppID:  policy.AppID,
+			Field:  "appName",
+			To:     policy.AppName,
+		})
+	case err != nil:
+		return nil, err
+	case app.DeletedAt != "":
+		changes = append(changes, models.PolicyChange{Action: models.PolicyActionRestore, AppID: app.AppID})
+	}
+
+	rename := app != nil && policy.AppName != "" && app.AppName != policy.AppName
+	if rename {
+		changes = append(changes, models.PolicyChange{
+			Action: models.PolicyActionUpdate,
+			AppID:  app.AppID,
+			Field:  "appName",
+			From:   app.AppName,
+			To:     policy.AppName,
+		})
+	}
+
+	versions, versionChanges, err := a.planAppVersions(app, policy)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, versionChanges...)
+
+	releases, releaseChanges, err := a.planReleases(app, policy)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, releaseChanges...)
+
+	if dryRun {
+		return changes, nil
+	}
+
+	// create the app, or restore it when it was deleted
+	if app == nil || app.DeletedAt != "" {
+		if err := a.CreateApp(*models.NewAppByNameAndAppID(policy.AppName, policy.AppID)); err != nil {
+			return nil, err
+		}
+	}
+
+	if rename {
+		if err := a.UpdateAppNameByID(app.ID, policy.AppName); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(versions) > 0 {
+		if err := a.UpdateAppVersions(app.ID, versions); err != nil {
+			return nil, err
+		}
+	}
+
+	if releases != nil {
+		if err := a.repository.SetReleases(*releases); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// planAppVersions returns the versions of an app to update to match its policy with the changes of each one.
+// The versions of the policy which were never launched are skipped, as are all of them when the app does not exist.
+func (a *appsService) planAppVersions(app *models.App, policy models.AppPolicy) ([]models.Version, []models.PolicyChange, error) {
+	stored := map[string]models.Version{}
+	if app != nil {
+		versions, err := a.getAppVersions(app.AppID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, v := range versions {
+			stored[v.Version] = v
+		}
+	}
+
+	var updated []models.Version
+	var changes []models.PolicyChange
+	for _, versionPolicy := range policy.Versions {
+		version, ok := stored[versionPolicy.Version]
+		if !ok {
+			changes = append(changes, models.PolicyChange{
+				Action:  models.PolicyActionSkip,
+				AppID:   policy.AppID,
+				Version: versionPolicy.Version,
+				Reason:  skipVersionReason,
+			})
+			continue
+		}
+
+		change := models.PolicyChange{Action: models.PolicyActionUpdate, AppID: version.AppID, Version: version.Version}
+		changed := false
+
+		if version.Disabled != versionPolicy.Disabled {
+			change.Field, change.From, change.To = "disabled", strconv.FormatBool(version.Disabled), strconv.FormatBool(versionPolicy.Disabled)
+			changes = append(changes, change)
+			changed = true
+		}
+
+		if version.DisabledMessage != versionPolicy.DisabledMessage {
+			change.Field, change.From, change.To = "disabledMessage", version.DisabledMessage, versionPolicy.DisabledMessage
+			changes = append(changes, change)
+			changed = true
+		}
+
+		if changed {
+			version.Disabled = versionPolicy.Disabled
+			version.DisabledMessage = versionPolicy.DisabledMessage
+			updated = append(updated, version)
+		}
+	}
+
+	return updated, changes, nil
+}
+
+// planReleases returns the released versions of an app to set to match its policy with the change of each field,
+// or nil when they already match. The fields which are not in the policy are left as they are.
+func (a *appsService) planReleases(app *models.App, policy models.AppPolicy) (*models.Releases, []models.PolicyChange, error) {
+	if policy.UnknownVersions == "" && policy.ReleasedVersions == nil {
+		return nil, nil, nil
+	}
+
+	// a new app flags the unknown versions and has no released versions
+	stored := &models.Releases{AppID: policy.AppID, UnknownVersions: models.UnknownVersionsFlag, Versions: []string{}}
+	if app != nil {
+		var err error
+		if stored, err = a.repository.GetReleasesByAppID(app.AppID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	planned := *stored
+	if policy.UnknownVersions != "" {
+		if err := validateUnknownVersions(policy.UnknownVersions); err != nil {
+			return nil, nil, err
+		}
+		planned.UnknownVersions = policy.UnknownVersions
+	}
+	if policy.ReleasedVersions != nil {
+		planned.Versions = uniqueVersions(policy.ReleasedVersions)
+	}
+
+	var changes []models.PolicyChange
+	if planned.UnknownVersions != stored.UnknownVersions {
+		changes = append(changes, models.PolicyChange{
+			Action: models.PolicyActionUpdate,
+			AppID:  planned.AppID,
+			Field:  "unknownVersions",
+			From:   stored.UnknownVersions,
+			To:     planned.UnknownVersions,
+		})
+	}
+
+	from, to := strings.Join(stored.Versions, ", "), strings.Join(planned.Versions, ", ")
+	if from != to {
+		changes = append(changes, models.PolicyChange{
+			Action: models.PolicyActionUpdate,
+			AppID:  planned.AppID,
+			Field:  "releasedVersions",
+			From:   from,
+			To:     to,
+		})
+	}
+
+	if len(changes) == 0 {
+		return nil, nil, nil
+	}
+
+	return &planned, changes, nil
+}
+
+// getAppVersions returns the versions of an app by its appId, with an empty list when it has none
+func (a *appsService) getAppVersions(appID string) ([]models.Version, error) {
+	versions, err := a.repository.GetAppVersionsByAppID(appID)
+	if errors.Is(err, models.ErrNotFound) {
+		return []models.Version{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return *versions, nil
+}
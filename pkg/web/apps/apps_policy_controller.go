@@ -0,0 +1,236 @@
+package apps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	PolicyReconciliationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "policy_reconciliations_total",
+			Help: "A counter for the reconciliations of the apps with the policy directory, by result",
+		},
+		[]string{"result"},
+	)
+	PolicyLastSuccessfulReconciliation = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "policy_last_successful_reconciliation_timestamp_seconds",
+		Help: "The time of the last reconciliation which made the apps match the policy directory",
+	})
+	PolicyDrift = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "policy_drift_changes",
+			Help: "The changes the last reconciliation made for the apps to match the policy directory, by action",
+		},
+		[]string{"action"},
+	)
+	PolicyManagedApps = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "policy_managed_apps",
+		Help: "The number of apps declared in the policy directory",
+	})
+)
+
+// policyFileExtensions are the extensions of the files read from the policy directory.
+// JSON is read as YAML, of which it is a subset.
+var policyFileExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// PolicyController reconciles the apps with the policy documents of a directory, every interval.
+// The apps of the documents are managed by the controller and can no longer be changed through the API,
+// see NewManagedService.
+type PolicyController struct {
+	service   Service
+	dir       string
+	interval  time.Duration
+	validator *validation.Validator
+
+	mu      sync.RWMutex
+	managed map[string]bool
+}
+
+// NewPolicyController returns a background worker which reconciles, every interval, the apps with the
+// policy documents of a directory
+func NewPolicyController(s Service, dir string, interval time.Duration) *PolicyController {
+	return &PolicyController{
+		service:   s,
+		dir:       dir,
+		interval:  interval,
+		validator: validation.NewValidator(),
+		managed:   map[string]bool{},
+	}
+}
+
+// Run reconciles the apps until the context is cancelled
+func (c *PolicyController) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Reconcile(); err != nil {
+			log.Errorf("Failed to reconcile the apps with the policy directory %v: %v", c.dir, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile reads the policy directory and makes the apps match it. The files are read again every time,
+// so an edit is applied by the next reconciliation, as is a change made to a managed app in the database.
+// Nothing is changed when a document cannot be read or is not valid.
+func (c *PolicyController) Reconcile() error {
+	policy, err := readPolicyDir(c.dir, c.validator)
+	if err == nil {
+		c.setManaged(policy)
+
+		// the changes made before an error are reported too, since they are kept
+		var result *models.PolicyImport
+		if result, err = c.service.ReconcileApps(policy); result != nil {
+			reportDrift(result)
+		}
+	}
+
+	if err != nil {
+		PolicyReconciliationsTotal.WithLabelValues("failure").Inc()
+		return err
+	}
+
+	PolicyReconciliationsTotal.WithLabelValues("success").Inc()
+	PolicyLastSuccessfulReconciliation.SetToCurrentTime()
+	return nil
+}
+
+// IsManaged is true when the app with the given appId is declared in the policy directory
+func (c *PolicyController) IsManaged(appID string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.managed[strings.ToLower(appID)]
+}
+
+// setManaged replaces the managed apps with the apps of the policy
+func (c *PolicyController) setManaged(policy models.AppsPolicy) {
+	managed := make(map[string]bool, len(policy.Apps))
+	for _, app := range policy.Apps {
+		managed[strings.ToLower(app.AppID)] = true
+	}
+
+	c.mu.Lock()
+	c.managed = managed
+	c.mu.Unlock()
+
+	PolicyManagedApps.Set(float64(len(managed)))
+}
+
+// reportDrift logs the changes of a reconciliation and counts them by action.
+// The versions skipped because they were never launched are not drift and are only logged at debug level.
+func reportDrift(result *models.PolicyImport) {
+	PolicyDrift.Reset()
+	for _, action := range []string{models.PolicyActionCreate, models.PolicyActionRestore, models.PolicyActionUpdate, models.PolicyActionDelete} {
+		PolicyDrift.WithLabelValues(action).Set(0)
+	}
+
+	for _, change := range result.Changes {
+		entry := log.WithFields(log.Fields{
+			"action":  change.Action,
+			"appId":   change.AppID,
+			"version": change.Version,
+			"field":   change.Field,
+			"from":    change.From,
+			"to":      change.To,
+		})
+
+		if change.Action == models.PolicyActionSkip {
+			entry.WithField("reason", change.Reason).Debug("Skipped a change of the policy directory")
+			continue
+		}
+
+		entry.Info("Reconciled an app with the policy directory")
+		PolicyDrift.WithLabelValues(change.Action).Inc()
+	}
+}
+
+// readPolicyDir reads the YAML and JSON documents of a directory, sorted by name, and merges them into a single
+// policy. The hidden files are ignored, such as the ..data directory of a mounted Kubernetes ConfigMap.
+// An app may only be declared once across all the documents.
+func readPolicyDir(dir string, v *validation.Validator) (models.AppsPolicy, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return models.AppsPolicy{}, err
+	}
+
+	merged := appsPolicyDocument{}
+	declaredIn := map[string]string{}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.HasPrefix(name, ".") || !policyFileExtensions[strings.ToLower(filepath.Ext(name))] {
+			continue
+		}
+
+		d, err := readPolicyFile(filepath.Join(dir, name), v)
+		if err != nil {
+			return models.AppsPolicy{}, fmt.Errorf("%v: %v", name, err)
+		}
+
+		for _, app := range d.Apps {
+			appID := strings.ToLower(app.AppID)
+			if other, ok := declaredIn[appID]; ok {
+				return models.AppsPolicy{}, fmt.Errorf("%v: appId %v is already in %v", name, app.AppID, other)
+			}
+			declaredIn[appID] = name
+		}
+		merged.Apps = append(merged.Apps, d.Apps...)
+	}
+
+	return merged.toModel(), nil
+}
+
+// readPolicyFile reads and validates a policy document. The unknown fields are rejected, as by POST /import.
+func readPolicyFile(path string, v *validation.Validator) (*appsPolicyDocument, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &appsPolicyDocument{}
+	if err := yaml.UnmarshalStrict(b, d); err != nil {
+		return nil, err
+	}
+
+	if err := v.Validate(d); err != nil {
+		var validationErr *models.Error
+		if errors.As(err, &validationErr) && len(validationErr.Fields) > 0 {
+			return nil, describeFieldErrors(validationErr.Fields)
+		}
+		return nil, err
+	}
+
+	if fields := d.duplicates(); len(fields) > 0 {
+		return nil, describeFieldErrors(fields)
+	}
+
+	return d, nil
+}
+
+// describeFieldErrors returns an error listing the invalid fields of a document, which are otherwise
+// only sent in the body of an error response
+func describeFieldErrors(fields []models.FieldError) error {
+	descriptions := make([]string, 0, len(fields))
+	for _, field := range fields {
+		descriptions = append(descriptions, fmt.Sprintf("%v: %v", field.Field, field.Message))
+	}
+	return errors.New(strings.Join(descriptions, "; "))
+}
@@ -0,0 +1,294 @@
+package apps
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newPolicyDir returns a temporary directory with the given files, to be removed by the test
+func newPolicyDir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "policy")
+	if err != nil {
+		t.Fatalf("Unexpected error creating the policy directory: %v", err)
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unexpected error creating %v: %v", path, err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error writing %v: %v", path, err)
+		}
+	}
+
+	return dir
+}
+
+// gatherPolicyMetrics returns the values of the gauges of the policy controller by name and labels,
+// e.g. policy_drift_changes{action=update}
+func gatherPolicyMetrics(t *testing.T) map[string]float64 {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(PolicyDrift, PolicyManagedApps)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error gathering the metrics: %v", err)
+	}
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			var labels []string
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%v=%v", label.GetName(), label.GetValue()))
+			}
+			name := family.GetName()
+			if len(labels) > 0 {
+				name += "{" + strings.Join(labels, ",") + "}"
+			}
+			values[name] = metric.GetGauge().GetValue()
+		}
+	}
+	return values
+}
+
+func Test_readPolicyDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    models.AppsPolicy
+		wantErr string
+	}{
+		{
+			name: "Should merge the YAML and JSON documents sorted by name and ignore the other files",
+			files: map[string]string{
+				"b.yaml": "apps:\n- appId: com.aerogear.app_two\n  versions:\n  - version: \"1.0\"\n    disabled: true\n",
+				"a.json": `{"apps": [{"appId": "com.aerogear.app_one", "appName": "App One"}]}`,
+				"c.yml":  "apps: []\n",
+				// the files of a mounted ConfigMap are links to a hidden directory
+				"..data/b.yaml":  "apps:\n- appId: com.aerogear.app_two\n",
+				".hidden.yaml":   "apps:\n- appId: com.aerogear.hidden\n",
+				"README.md":      "# Policy",
+				"nested/d.yaml":  "apps:\n- appId: com.aerogear.nested\n",
+				"e.YAML":         "apps:\n- appId: com.aerogear.app_three\n",
+				"empty.yaml":     "",
+				"only-apps.yaml": "apps:\n",
+			},
+			want: models.AppsPolicy{Apps: []models.AppPolicy{
+				{AppID: "com.aerogear.app_one", AppName: "App One"},
+				{AppID: "com.aerogear.app_two", Versions: []models.VersionPolicy{{Version: "1.0", Disabled: true}}},
+				{AppID: "com.aerogear.app_three"},
+			}},
+		},
+		{
+			name:  "Should read an empty directory as a policy without apps",
+			files: map[string]string{},
+			want:  models.AppsPolicy{Apps: []models.AppPolicy{}},
+		},
+		{
+			name:    "Should fail on an unknown field",
+			files:   map[string]string{"apps.yaml": "apps:\n- appId: com.aerogear.app_one\n  name: App One\n"},
+			wantErr: "apps.yaml: yaml: unmarshal errors:\n  line 3: field name not found in type apps.appPolicyDocument",
+		},
+		{
+			name:    "Should fail on an invalid document",
+			files:   map[string]string{"apps.yaml": "apps:\n- appId: app_one\n  versions:\n  - disabled: true\n"},
+			wantErr: "apps.yaml: apps[0].appId: appId must be in reverse-DNS format, e.g. com.example.app; apps[0].versions[0].version: version is required",
+		},
+		{
+			name:    "Should fail on a version declared twice",
+			files:   map[string]string{"apps.yaml": "apps:\n- appId: com.aerogear.app_one\n  versions:\n  - version: \"1.0\"\n  - version: \"1.0\"\n"},
+			wantErr: "apps.yaml: apps[0].versions[1].version: version 1.0 is already in the versions of the app",
+		},
+		{
+			name: "Should fail on an app declared in two documents",
+			files: map[string]string{
+				"a.yaml": "apps:\n- appId: com.aerogear.app_one\n",
+				"b.yaml": "apps:\n- appId: COM.aerogear.app_one\n",
+			},
+			wantErr: "b.yaml: appId COM.aerogear.app_one is already in a.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newPolicyDir(t, tt.files)
+			defer os.RemoveAll(dir)
+
+			got, err := readPolicyDir(dir, validation.NewValidator())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("readPolicyDir() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readPolicyDir() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readPolicyDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Should fail when the directory does not exist", func(t *testing.T) {
+		dir := newPolicyDir(t, nil)
+		defer os.RemoveAll(dir)
+
+		if _, err := readPolicyDir(filepath.Join(dir, "missing"), validation.NewValidator()); err == nil {
+			t.Error("readPolicyDir() error = nil, want an error")
+		}
+	})
+}
+
+func TestPolicyController_Reconcile(t *testing.T) {
+	t.Run("Should reconcile the apps with the directory and report the drift", func(t *testing.T) {
+		dir := newPolicyDir(t, map[string]string{
+			"apps.yaml": "apps:\n- appId: com.aerogear.app_one\n  appName: App One\n",
+		})
+		defer os.RemoveAll(dir)
+
+		service := &ServiceMock{
+			ReconcileAppsFunc: func(policy models.AppsPolicy) (*models.PolicyImport, error) {
+				return &models.PolicyImport{Changes: []models.PolicyChange{
+					{Action: models.PolicyActionCreate, AppID: "com.aerogear.app_one", Field: "appName", To: "App One"},
+					{Action: models.PolicyActionSkip, AppID: "com.aerogear.app_one", Version: "1.0", Reason: skipVersionReason},
+					{Action: models.PolicyActionDelete, AppID: "com.aerogear.app_two"},
+					{Action: models.PolicyActionDelete, AppID: "com.aerogear.app_three"},
+				}}, nil
+			},
+		}
+		c := NewPolicyController(service, dir, 0)
+
+		if err := c.Reconcile(); err != nil {
+			t.Fatalf("PolicyController.Reconcile() error = %v", err)
+		}
+
+		want := models.AppsPolicy{Apps: []models.AppPolicy{{AppID: "com.aerogear.app_one", AppName: "App One"}}}
+		if calls := service.ReconcileAppsCalls(); len(calls) != 1 || !reflect.DeepEqual(calls[0].Policy, want) {
+			t.Errorf("PolicyController.Reconcile() reconciled %v, want %v", calls, want)
+		}
+
+		if !c.IsManaged("COM.aerogear.app_one") || c.IsManaged("com.aerogear.app_two") {
+			t.Error("PolicyController.IsManaged() should be true only for the apps of the directory")
+		}
+
+		wantMetrics := map[string]float64{
+			"policy_drift_changes{action=create}":  1,
+			"policy_drift_changes{action=delete}":  2,
+			"policy_drift_changes{action=restore}": 0,
+			"policy_drift_changes{action=update}":  0,
+			"policy_managed_apps":                  1,
+		}
+		if got := gatherPolicyMetrics(t); !reflect.DeepEqual(got, wantMetrics) {
+			t.Errorf("PolicyController.Reconcile() metrics = %v, want %v", got, wantMetrics)
+		}
+	})
+
+	t.Run("Should not change anything when a document is not valid", func(t *testing.T) {
+		dir := newPolicyDir(t, map[string]string{"apps.yaml": "apps:\n- appName: App One\n"})
+		defer os.RemoveAll(dir)
+
+		service := &ServiceMock{}
+
+		c := NewPolicyController(service, dir, 0)
+		if err := c.Reconcile(); err == nil {
+			t.Fatal("PolicyController.Reconcile() error = nil, want an error")
+		}
+
+		if len(service.ReconcileAppsCalls()) > 0 {
+			t.Error("PolicyController.Reconcile() reconciled the apps with an invalid document")
+		}
+	})
+
+	t.Run("Should return the errors of the service", func(t *testing.T) {
+		dir := newPolicyDir(t, map[string]string{"apps.yaml": "apps: []\n"})
+		defer os.RemoveAll(dir)
+
+		service := &ServiceMock{
+			ReconcileAppsFunc: func(policy models.AppsPolicy) (*models.PolicyImport, error) {
+				return nil, models.ErrDatabaseError
+			},
+		}
+
+		if err := NewPolicyController(service, dir, 0).Reconcile(); err != models.ErrDatabaseError {
+			t.Errorf("PolicyController.Reconcile() error = %v, want %v", err, models.ErrDatabaseError)
+		}
+	})
+}
+
+func Test_appsService_ReconcileApps(t *testing.T) {
+	resetAppMetrics()
+	apps, versions, policy := policyFixtures()
+	repo := newPolicyRepository(apps, versions)
+
+	// app two was declared in the directory and was removed from it
+	managed := map[string]bool{"com.aerogear.mobile_app_two": true, "com.aerogear.mobile_app_one": true}
+	repo.GetManagedAppsFunc = func() ([]models.App, error) {
+		var found []models.App
+		for _, app := range apps {
+			if managed[app.AppID] {
+				found = append(found, app)
+			}
+		}
+		return found, nil
+	}
+	repo.SetAppManagedFunc = func(appID string, isManaged bool) error {
+		managed[appID] = isManaged
+		return nil
+	}
+	repo.DeleteAppByIdFunc = func(id string) error {
+		return nil
+	}
+	countLaunch(models.Device{AppID: "com.aerogear.mobile_app_one", DeviceType: "iOS"}, models.Version{Version: "1.0"}, false)
+	countLaunch(models.Device{AppID: "com.aerogear.mobile_app_two", DeviceType: "Android"}, models.Version{Version: "1.0", Disabled: true}, true)
+
+	got, err := NewService(repo, nil).ReconcileApps(policy)
+	if err != nil {
+		t.Fatalf("appsService.ReconcileApps() error = %v", err)
+	}
+
+	wantDelete := models.PolicyChange{Action: models.PolicyActionDelete, AppID: "com.aerogear.mobile_app_two"}
+	if last := got.Changes[len(got.Changes)-1]; !reflect.DeepEqual(last, wantDelete) {
+		t.Errorf("appsService.ReconcileApps() last change = %v, want %v", last, wantDelete)
+	}
+
+	if calls := repo.DeleteAppByIdCalls(); len(calls) != 1 || calls[0].ID != "0890506c-3dd1-43ad-8a09-21a4111a65a6" {
+		t.Errorf("appsService.ReconcileApps() deleted %v, want only app two", calls)
+	}
+
+	// the series of the deleted app are removed, the ones of the other apps are kept
+	metrics := gatherAppMetrics(t)
+	for series := range metrics {
+		if strings.Contains(series, "app_id=com.aerogear.mobile_app_two") {
+			t.Errorf("appsService.ReconcileApps() kept the series %v of the deleted app", series)
+		}
+	}
+	if metrics["app_launches_total{app_id=com.aerogear.mobile_app_one,platform=ios,version=1.0}"] != 1 {
+		t.Errorf("appsService.ReconcileApps() metrics = %v, want the launch of app one", metrics)
+	}
+
+	wantManaged := map[string]bool{
+		"com.aerogear.mobile_app_one": true,
+		"com.aerogear.mobile_app_two": false,
+		"com.aerogear.deleted_app":    true,
+		"com.aerogear.new_app":        true,
+	}
+	if !reflect.DeepEqual(managed, wantManaged) {
+		t.Errorf("appsService.ReconcileApps() managed apps = %v, want %v", managed, wantManaged)
+	}
+
+	// app one was already managed
+	if calls := repo.SetAppManagedCalls(); len(calls) != 3 {
+		t.Errorf("appsService.ReconcileApps() marked %v apps, want 3", len(calls))
+	}
+}
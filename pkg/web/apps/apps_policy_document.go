@@ -0,0 +1,149 @@
+package apps
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"strings"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/labstack/echo"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// MIMEApplicationYAML is the media type of the policy document in YAML
+const MIMEApplicationYAML = "application/yaml"
+
+// yamlMediaTypes are the media types accepted for a YAML policy document
+var yamlMediaTypes = map[string]bool{
+	MIMEApplicationYAML:  true,
+	"application/x-yaml": true,
+	"text/yaml":          true,
+	"text/x-yaml":        true,
+}
+
+// appsPolicyDocument is the document of the apps and of the state of their versions.
+// It is returned by GET /export and sent to POST /import, in JSON or YAML.
+// swagger:model AppsPolicy
+type appsPolicyDocument struct {
+	Apps []appPolicyDocument `json:"apps" yaml:"apps" validate:"dive"`
+}
+
+// appPolicyDocument is an app of the policy document, identified by its appId
+// swagger:model AppPolicy
+type appPolicyDocument struct {
+	// required: true
+	AppID    string                  `json:"appId" yaml:"appId" validate:"required,appid"`
+	AppName  string                  `json:"appName,omitempty" yaml:"appName,omitempty"`
+	Versions []versionPolicyDocument `json:"versions,omitempty" yaml:"versions,omitempty" validate:"dive"`
+	// UnknownVersions is accept, flag or block. It applies to the versions which are not among the released versions.
+	UnknownVersions string `json:"unknownVersions,omitempty" yaml:"unknownVersions,omitempty" validate:"omitempty,oneof=accept flag block"`
+	// ReleasedVersions replace the released versions declared for the app. They are left as they are when omitted.
+	ReleasedVersions []string `json:"releasedVersions,omitempty" yaml:"releasedVersions,omitempty" validate:"dive,required"`
+}
+
+// versionPolicyDocument is the state of a version of an app of the policy document
+// swagger:model VersionPolicy
+type versionPolicyDocument struct {
+	// required: true
+	Version         string `json:"version" yaml:"version" validate:"required"`
+	Disabled        bool   `json:"disabled" yaml:"disabled"`
+	DisabledMessage string `json:"disabledMessage,omitempty" yaml:"disabledMessage,omitempty"`
+}
+
+// newAppsPolicyDocument maps a policy to its document
+func newAppsPolicyDocument(policy models.AppsPolicy) appsPolicyDocument {
+	d := appsPolicyDocument{Apps: make([]appPolicyDocument, 0, len(policy.Apps))}
+	for _, app := range policy.Apps {
+		appDoc := appPolicyDocument{AppID: app.AppID, AppName: app.AppName, UnknownVersions: app.UnknownVersions, ReleasedVersions: app.ReleasedVersions}
+		for _, v := range app.Versions {
+			appDoc.Versions = append(appDoc.Versions, versionPolicyDocument(v))
+		}
+		d.Apps = append(d.Apps, appDoc)
+	}
+	return d
+}
+
+// toModel maps the document to the policy to import
+func (d *appsPolicyDocument) toModel() models.AppsPolicy {
+	policy := models.AppsPolicy{Apps: make([]models.AppPolicy, 0, len(d.Apps))}
+	for _, appDoc := range d.Apps {
+		app := models.AppPolicy{AppID: appDoc.AppID, AppName: appDoc.AppName, UnknownVersions: appDoc.UnknownVersions, ReleasedVersions: appDoc.ReleasedVersions}
+		for _, v := range appDoc.Versions {
+			app.Versions = append(app.Versions, models.VersionPolicy(v))
+		}
+		policy.Apps = append(policy.Apps, app)
+	}
+	return policy
+}
+
+// duplicates returns the apps and the versions of an app which are more than once in the document.
+// The appIds are compared without case, as they are stored.
+func (d *appsPolicyDocument) duplicates() []models.FieldError {
+	var fields []models.FieldError
+
+	apps := map[string]bool{}
+	for i, app := range d.Apps {
+		appID := strings.ToLower(app.AppID)
+		if apps[appID] {
+			fields = append(fields, models.FieldError{
+				Field:   fmt.Sprintf("apps[%d].appId", i),
+				Message: fmt.Sprintf("appId %v is already in the document", app.AppID),
+			})
+		}
+		apps[appID] = true
+
+		versions := map[string]bool{}
+		for j, v := range app.Versions {
+			if versions[v.Version] {
+				fields = append(fields, models.FieldError{
+					Field:   fmt.Sprintf("apps[%d].versions[%d].version", i, j),
+					Message: fmt.Sprintf("version %v is already in the versions of the app", v.Version),
+				})
+			}
+			versions[v.Version] = true
+		}
+	}
+
+	return fields
+}
+
+// decodeAppsPolicyDocument decodes the body of a request in JSON, or in YAML when its Content-Type is YAML,
+// and validates it. The unknown fields of a YAML document are rejected, so a typo is not ignored.
+func decodeAppsPolicyDocument(c echo.Context) (*appsPolicyDocument, error) {
+	d := &appsPolicyDocument{}
+
+	contentType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if yamlMediaTypes[contentType] {
+		b, err := ioutil.ReadAll(c.Request().Body)
+		if err == nil {
+			err = yaml.UnmarshalStrict(b, d)
+		}
+		if err != nil {
+			return nil, models.ErrBadParamInput.WithMessage("Invalid data").Wrap(err)
+		}
+
+		if err := c.Validate(d); err != nil {
+			return nil, err
+		}
+	} else if err := validation.Body(c, d); err != nil {
+		return nil, err
+	}
+
+	if fields := d.duplicates(); len(fields) > 0 {
+		return nil, models.NewValidationError(fields...)
+	}
+
+	return d, nil
+}
+
+// acceptsYAML is true when the Accept header of a request asks for YAML
+func acceptsYAML(c echo.Context) bool {
+	for _, accept := range strings.Split(c.Request().Header.Get(echo.HeaderAccept), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && yamlMediaTypes[mediaType] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,276 @@
+package apps
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// newPolicyRepository returns a repository mock storing the apps, the versions and the released versions in memory
+func newPolicyRepository(apps []models.App, versions []models.Version) *RepositoryMock {
+	releases := map[string]models.Releases{}
+
+	find := func(match func(app *models.App) bool) *models.App {
+		for i := range apps {
+			if match(&apps[i]) {
+				return &apps[i]
+			}
+		}
+		return nil
+	}
+
+	return &RepositoryMock{
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+			page := &models.AppsPage{}
+			for _, app := range apps {
+				if app.DeletedAt == "" {
+					page.Apps = append(page.Apps, app)
+				}
+			}
+			if len(page.Apps) == 0 {
+				return nil, models.ErrNotFound
+			}
+			return page, nil
+		},
+		GetAppVersionsByAppIDFunc: func(appID string) (*[]models.Version, error) {
+			var found []models.Version
+			for _, v := range versions {
+				if v.AppID == appID {
+					found = append(found, v)
+				}
+			}
+			if len(found) == 0 {
+				return &found, models.ErrNotFound
+			}
+			return &found, nil
+		},
+		GetAppByAppIDFunc: func(appID string) (*models.App, error) {
+			if app := find(func(app *models.App) bool { return strings.EqualFold(app.AppID, appID) }); app != nil {
+				found := *app
+				return &found, nil
+			}
+			return nil, models.ErrNotFound
+		},
+		GetActiveAppByIDFunc: func(id string) (*models.App, error) {
+			if app := find(func(app *models.App) bool { return app.ID == id && app.DeletedAt == "" }); app != nil {
+				found := *app
+				return &found, nil
+			}
+			return nil, models.ErrNotFound
+		},
+		CreateAppFunc: func(id, appID, name, tenantID string) error {
+			apps = append(apps, models.App{ID: id, AppID: appID, AppName: name, TenantID: tenantID})
+			return nil
+		},
+		UnDeleteAppByAppIDFunc: func(appID string) error {
+			find(func(app *models.App) bool { return app.AppID == appID }).DeletedAt = ""
+			return nil
+		},
+		UpdateAppNameByIDFunc: func(id, name string) error {
+			find(func(app *models.App) bool { return app.ID == id }).AppName = name
+			return nil
+		},
+		UpdateAppVersionsFunc: func(updated []models.Version) error {
+			for _, u := range updated {
+				for i := range versions {
+					if versions[i].ID == u.ID {
+						versions[i].Disabled, versions[i].DisabledMessage = u.Disabled, u.DisabledMessage
+					}
+				}
+			}
+			return nil
+		},
+		GetReleasesByAppIDFunc: func(appID string) (*models.Releases, error) {
+			if r, ok := releases[appID]; ok {
+				return &r, nil
+			}
+			return &models.Releases{AppID: appID, UnknownVersions: models.UnknownVersionsFlag, Versions: []string{}}, nil
+		},
+		SetReleasesFunc: func(r models.Releases) error {
+			releases[r.AppID] = r
+			return nil
+		},
+	}
+}
+
+// policyFixtures returns an active app with two versions, a deleted app and the policy changing both
+func policyFixtures() ([]models.App, []models.Version, models.AppsPolicy) {
+	apps := []models.App{
+		{ID: "0890506c-3dd1-43ad-8a09-21a4111a65a6", AppID: "com.aerogear.mobile_app_two", AppName: "App Two"},
+		{ID: "7f89ce49-a736-459e-9110-e52d049fc027", AppID: "com.aerogear.mobile_app_one", AppName: "App One"},
+		{ID: "53d4e6c6-6b7f-4a50-8d2c-0fe5b4c1a2b9", AppID: "com.aerogear.deleted_app", AppName: "Deleted App", DeletedAt: "2019-02-15T09:38:33+00:00"},
+	}
+	versions := []models.Version{
+		{ID: "55ebd387-9c68-4137-a367-a12025cc2cdb", AppID: "com.aerogear.mobile_app_one", Version: "1.1"},
+		{ID: "59ebd387-9c68-4137-a367-a12025cc1cdb", AppID: "com.aerogear.mobile_app_one", Version: "1.0", Disabled: true, DisabledMessage: "Please update"},
+	}
+	policy := models.AppsPolicy{Apps: []models.AppPolicy{
+		{
+			AppID:   "com.aerogear.mobile_app_one",
+			AppName: "Mobile App One",
+			Versions: []models.VersionPolicy{
+				{Version: "1.0", Disabled: true, DisabledMessage: "Please update"},
+				{Version: "1.1", Disabled: true, DisabledMessage: "Please update to 1.2"},
+				{Version: "1.2"},
+			},
+			UnknownVersions:  models.UnknownVersionsBlock,
+			ReleasedVersions: []string{"1.1", "1.0", "1.1"},
+		},
+		{AppID: "com.aerogear.deleted_app"},
+		{AppID: "com.aerogear.new_app", AppName: "New App", Versions: []models.VersionPolicy{{Version: "1.0"}}},
+	}}
+	return apps, versions, policy
+}
+
+func Test_appsService_ExportApps(t *testing.T) {
+	apps, versions, _ := policyFixtures()
+
+	tests := []struct {
+		name     string
+		apps     []models.App
+		versions []models.Version
+		want     *models.AppsPolicy
+	}{
+		{
+			name:     "Should export the active apps and their versions sorted by appId and version",
+			apps:     apps,
+			versions: versions,
+			want: &models.AppsPolicy{Apps: []models.AppPolicy{
+				{
+					AppID:   "com.aerogear.mobile_app_one",
+					AppName: "App One",
+					Versions: []models.VersionPolicy{
+						{Version: "1.0", Disabled: true, DisabledMessage: "Please update"},
+						{Version: "1.1"},
+					},
+				},
+				{AppID: "com.aerogear.mobile_app_two", AppName: "App Two"},
+			}},
+		},
+		{
+			name: "Should export an empty list of apps when there are none",
+			want: &models.AppsPolicy{Apps: []models.AppPolicy{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewService(newPolicyRepository(tt.apps, tt.versions), nil)
+
+			got, err := a.ExportApps()
+			if err != nil {
+				t.Fatalf("appsService.ExportApps() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appsService.ExportApps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_appsService_ImportApps(t *testing.T) {
+	wantChanges := []models.PolicyChange{
+		{Action: models.PolicyActionUpdate, AppID: "com.aerogear.mobile_app_one", Field: "appName", From: "App One", To: "Mobile App One"},
+		{Action: models.PolicyActionUpdate, AppID: "com.aerogear.mobile_app_one", Version: "1.1", Field: "disabled", From: "false", To: "true"},
+		{Action: models.PolicyActionUpdate, AppID: "com.aerogear.mobile_app_one", Version: "1.1", Field: "disabledMessage", To: "Please update to 1.2"},
+		{Action: models.PolicyActionSkip, AppID: "com.aerogear.mobile_app_one", Version: "1.2", Reason: skipVersionReason},
+		{Action: models.PolicyActionUpdate, AppID: "com.aerogear.mobile_app_one", Field: "unknownVersions", From: "flag", To: "block"},
+		{Action: models.PolicyActionUpdate, AppID: "com.aerogear.mobile_app_one", Field: "releasedVersions", To: "1.0, 1.1"},
+		{Action: models.PolicyActionRestore, AppID: "com.aerogear.deleted_app"},
+		{Action: models.PolicyActionCreate, AppID: "com.aerogear.new_app", Field: "appName", To: "New App"},
+		{Action: models.PolicyActionSkip, AppID: "com.aerogear.new_app", Version: "1.0", Reason: skipVersionReason},
+	}
+
+	t.Run("Should report the changes without making them on a dry run", func(t *testing.T) {
+		apps, versions, policy := policyFixtures()
+		repo := newPolicyRepository(apps, versions)
+
+		got, err := NewService(repo, nil).ImportApps(policy, true)
+		if err != nil {
+			t.Fatalf("appsService.ImportApps() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, &models.PolicyImport{DryRun: true, Changes: wantChanges}) {
+			t.Errorf("appsService.ImportApps() = %v, want %v", got, wantChanges)
+		}
+
+		if writes := len(repo.CreateAppCalls()) + len(repo.UnDeleteAppByAppIDCalls()) + len(repo.UpdateAppNameByIDCalls()) +
+			len(repo.UpdateAppVersionsCalls()) + len(repo.SetReleasesCalls()); writes > 0 {
+			t.Errorf("appsService.ImportApps() made %v changes on a dry run", writes)
+		}
+	})
+
+	t.Run("Should make the changes once when the policy is imported twice", func(t *testing.T) {
+		apps, versions, policy := policyFixtures()
+		a := NewService(newPolicyRepository(apps, versions), nil)
+
+		got, err := a.ImportApps(policy, false)
+		if err != nil {
+			t.Fatalf("appsService.ImportApps() error = %v", err)
+		}
+		if !reflect.DeepEqual(got.Changes, wantChanges) {
+			t.Errorf("appsService.ImportApps() changes = %v, want %v", got.Changes, wantChanges)
+		}
+
+		got, err = a.ImportApps(policy, false)
+		if err != nil {
+			t.Fatalf("appsService.ImportApps() error = %v", err)
+		}
+		for _, change := range got.Changes {
+			if change.Action != models.PolicyActionSkip {
+				t.Errorf("appsService.ImportApps() made the change %v again", change)
+			}
+		}
+
+		exported, err := a.ExportApps()
+		if err != nil {
+			t.Fatalf("appsService.ExportApps() error = %v", err)
+		}
+		want := models.AppPolicy{
+			AppID:   "com.aerogear.mobile_app_one",
+			AppName: "Mobile App One",
+			Versions: []models.VersionPolicy{
+				{Version: "1.0", Disabled: true, DisabledMessage: "Please update"},
+				{Version: "1.1", Disabled: true, DisabledMessage: "Please update to 1.2"},
+			},
+			UnknownVersions:  models.UnknownVersionsBlock,
+			ReleasedVersions: []string{"1.0", "1.1"},
+		}
+		if len(exported.Apps) != 4 || !reflect.DeepEqual(exported.Apps[1], want) {
+			t.Errorf("appsService.ExportApps() after the import = %v, want %v in 4 apps", exported.Apps, want)
+		}
+	})
+
+	t.Run("Should return the changes made before an error with the error", func(t *testing.T) {
+		apps, versions, policy := policyFixtures()
+		repo := newPolicyRepository(apps, versions)
+		repo.CreateAppFunc = func(id, appID, name, tenantID string) error {
+			return models.ErrDatabaseError
+		}
+
+		got, err := NewService(repo, nil).ImportApps(policy, false)
+		if err != models.ErrDatabaseError {
+			t.Fatalf("appsService.ImportApps() error = %v, want %v", err, models.ErrDatabaseError)
+		}
+		if got == nil || !reflect.DeepEqual(got.Changes, wantChanges[:7]) {
+			t.Errorf("appsService.ImportApps() = %v, want the changes %v", got, wantChanges[:7])
+		}
+
+		if len(repo.UpdateAppNameByIDCalls()) != 1 || len(repo.UnDeleteAppByAppIDCalls()) != 1 {
+			t.Errorf("appsService.ImportApps() did not make the changes of the apps before the error")
+		}
+	})
+
+	t.Run("Should return the errors of the repository", func(t *testing.T) {
+		_, _, policy := policyFixtures()
+		repo := &RepositoryMock{
+			GetAppByAppIDFunc: func(appID string) (*models.App, error) {
+				return nil, models.ErrInternalServerError
+			},
+		}
+
+		if _, err := NewService(repo, nil).ImportApps(policy, false); err != models.ErrInternalServerError {
+			t.Errorf("appsService.ImportApps() error = %v, want %v", err, models.ErrInternalServerError)
+		}
+	})
+}
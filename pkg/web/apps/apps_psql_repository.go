@@ -2,37 +2,99 @@ package apps
 
 import (
 	"database/sql"
+	"fmt"
 	"strings"
 
 	"time"
 
 	"github.com/aerogear/mobile-security-service/pkg/models"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	log "github.com/sirupsen/logrus"
 )
 
 type (
 	appsPostgreSQLRepository struct {
-		db *sql.DB
+		db        *sql.DB
+		exporters []AppExporter
 	}
+
+	// AppExporter adds the data kept for an app by another package to its archive, in the transaction of its hard delete
+	AppExporter func(tx *sql.Tx, appID string, archive *models.AppArchive) error
 )
 
-// NewPostgreSQLRepository creates a new instance of appsPostgreSQLRepository
-func NewPostgreSQLRepository(db *sql.DB) Repository {
-	return &appsPostgreSQLRepository{db}
+// NewPostgreSQLRepository creates a new instance of appsPostgreSQLRepository. The exporters add the data of the
+// other packages to the archive of a hard deleted app.
+func NewPostgreSQLRepository(db *sql.DB, exporters ...AppExporter) Repository {
+	return &appsPostgreSQLRepository{db, exporters}
 }
 
-// GetApps retrieves all apps from the database
-func (a *appsPostgreSQLRepository) GetApps() (*[]models.App, error) {
-	rows, err := a.db.Query(`
-	SELECT a.id,a.app_id,a.app_name,
-	COALESCE(COUNT(DISTINCT v.id),0) as num_of_deployed_versions,
-	COALESCE(SUM(DISTINCT v.num_of_app_launches),0) as num_of_app_launches,
-	COALESCE(COUNT(DISTINCT d.id),0) as num_of_current_installs
-	FROM app as a LEFT JOIN version as v on a.app_id = v.app_id 
-	LEFT JOIN device as d on v.id = d.version_id 
-	WHERE a.deleted_at IS NULL 
-	GROUP BY a.id;`)
+// GetApps retrieves a page of the apps which are not deleted from the database
+func (a *appsPostgreSQLRepository) GetApps(query models.AppsQuery) (*models.AppsPage, error) {
+	field, desc, _ := parseAppsSort(query.Sort)
+	sortColumn := appsSortColumns[field]
+
+	var args []interface{}
+	placeholder := func(arg interface{}) string {
+		args = append(args, arg)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var search string
+	if query.Search != "" {
+		p := placeholder("%" + escapeLike(query.Search) + "%")
+		search = fmt.Sprintf("AND (a.app_name ILIKE %[1]s OR a.app_id ILIKE %[1]s)", p)
+	}
+
+	if query.Tenants != nil && !query.Tenants.All {
+		search += fmt.Sprintf(" AND a.tenant_id = ANY(%s::uuid[])", placeholder(pq.Array(query.Tenants.TenantIDs)))
+	}
+
+	var after string
+	if query.Cursor != "" {
+		c, err := decodeAppsCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		operator := ">"
+		if desc {
+			operator = "<"
+		}
+		after = fmt.Sprintf("WHERE (%s, id) %s (%s, %s)", sortColumn, operator, placeholder(c.Key), placeholder(c.ID))
+	}
+
+	sort, direction := field, "ASC"
+	if desc {
+		sort, direction = "-"+field, "DESC"
+	}
+
+	var limit string
+	if query.Limit > 0 {
+		// fetch one extra row to know if there is a next page
+		limit = "LIMIT " + placeholder(query.Limit+1)
+	}
+
+	deleted := "a.deleted_at IS NULL"
+	if query.Deleted {
+		deleted = "a.deleted_at IS NOT NULL"
+	}
+
+	rows, err := a.db.Query(fmt.Sprintf(`
+	SELECT id,app_id,app_name,deleted_at,tenant_id,num_of_deployed_versions,num_of_app_launches,num_of_current_installs,
+	%[1]s as sort_key
+	FROM (
+		SELECT a.id,a.app_id,a.app_name,a.deleted_at,a.tenant_id,
+		COALESCE(COUNT(DISTINCT v.id),0) as num_of_deployed_versions,
+		COALESCE(SUM(DISTINCT v.num_of_app_launches),0) as num_of_app_launches,
+		COALESCE(COUNT(DISTINCT d.id),0) as num_of_current_installs
+		FROM app as a LEFT JOIN version as v on a.app_id = v.app_id
+		LEFT JOIN device as d on v.id = d.version_id
+		WHERE %[6]s %[2]s
+		GROUP BY a.id
+	) as apps
+	%[3]s
+	ORDER BY %[1]s %[4]s, id %[4]s
+	%[5]s;`, sortColumn, search, after, direction, limit, deleted), args...)
 
 	if err != nil {
 		log.Error(err)
@@ -46,31 +108,50 @@ func (a *appsPostgreSQLRepository) GetApps() (*[]models.App, error) {
 		}
 	}()
 
-	apps := []models.App{}
+	page := models.AppsPage{Apps: []models.App{}}
+	var lastKey string
 	for rows.Next() {
+		if query.Limit > 0 && len(page.Apps) == query.Limit {
+			// there is at least one more app after this page
+			last := page.Apps[len(page.Apps)-1]
+			page.NextCursor = encodeAppsCursor(appsCursor{Sort: sort, Key: lastKey, ID: last.ID})
+			break
+		}
+
 		var a models.App
-		if err = rows.Scan(&a.ID, &a.AppID, &a.AppName, &a.NumOfDeployedVersions, &a.NumOfAppLaunches, &a.NumOfCurrentInstalls); err != nil {
+		var deletedAt sql.NullString
+		if err = rows.Scan(&a.ID, &a.AppID, &a.AppName, &deletedAt, &a.TenantID, &a.NumOfDeployedVersions, &a.NumOfAppLaunches, &a.NumOfCurrentInstalls, &lastKey); err != nil {
 			log.Error(err)
 		}
 
-		apps = append(apps, a)
+		a.DeletedAt = deletedAt.String
+		page.Apps = append(page.Apps, a)
 	}
 
-	if len(apps) == 0 {
+	if len(page.Apps) == 0 {
 		return nil, models.ErrNotFound
 	}
 
-	return &apps, nil
+	return &page, nil
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 // GetAppVersionsByAppID returns app app versions with the provided app ID
 func (a *appsPostgreSQLRepository) GetAppVersionsByAppID(id string) (*[]models.Version, error) {
+	// a version is unknown when the app flags or blocks the versions which are not among its declared releases
 	rows, err := a.db.Query(`
 	SELECT v.id,v.version,v.app_id, v.disabled, v.disabled_message, v.num_of_app_launches, v.last_launched_at,
-	COALESCE(COUNT(DISTINCT d.id),0) as num_of_current_installs
-	FROM version as v LEFT JOIN device as d on v.id = d.version_id
+	COALESCE(COUNT(DISTINCT d.id),0) as num_of_current_installs,
+	(a.unknown_versions <> $2
+		AND EXISTS (SELECT 1 FROM released_version as r WHERE r.app_id = v.app_id)
+		AND NOT EXISTS (SELECT 1 FROM released_version as r WHERE r.app_id = v.app_id AND r.version = v.version)) as unknown
+	FROM version as v JOIN app as a on a.app_id = v.app_id LEFT JOIN device as d on v.id = d.version_id
 	WHERE v.app_id = $1 
-	GROUP BY v.id;`, id)
+	GROUP BY v.id, a.unknown_versions;`, id, models.UnknownVersionsAccept)
 
 	if err != nil {
 		log.Error(err)
@@ -90,7 +171,7 @@ func (a *appsPostgreSQLRepository) GetAppVersionsByAppID(id string) (*[]models.V
 	for rows.Next() {
 		var v models.Version
 		var disabledMessage sql.NullString
-		if err = rows.Scan(&v.ID, &v.Version, &v.AppID, &v.Disabled, &disabledMessage, &v.NumOfAppLaunches, &v.LastLaunchedAt, &v.NumOfCurrentInstalls); err != nil {
+		if err = rows.Scan(&v.ID, &v.Version, &v.AppID, &v.Disabled, &disabledMessage, &v.NumOfAppLaunches, &v.LastLaunchedAt, &v.NumOfCurrentInstalls, &v.Unknown); err != nil {
 			log.Error(err)
 		}
 
@@ -189,14 +270,35 @@ func (a *appsPostgreSQLRepository) GetDeviceByVersionAndAppID(version string, ap
 	return &device, nil
 }
 
-// GetAppByID retrieves an app by id from the database
+// GetAppByID retrieves an app by id from the database, including the soft deleted ones
+func (a *appsPostgreSQLRepository) GetAppByID(id string) (*models.App, error) {
+	app := models.App{}
+
+	sqlStatement := `SELECT id,app_id,app_name,deleted_at FROM app WHERE id=$1;`
+
+	var deletedAt sql.NullString
+	err := a.db.QueryRow(sqlStatement, id).Scan(&app.ID, &app.AppID, &app.AppName, &deletedAt)
+	app.DeletedAt = deletedAt.String
+
+	if err != nil {
+		log.Error(err)
+		if err == sql.ErrNoRows {
+			return nil, models.ErrNotFound
+		}
+		return nil, models.ErrInternalServerError
+	}
+
+	return &app, nil
+}
+
+// GetAppByAppID retrieves an app by app id from the database
 func (a *appsPostgreSQLRepository) GetAppByAppID(appID string) (*models.App, error) {
 	app := models.App{}
 
-	sqlStatement := `SELECT id,app_id,app_name,deleted_at FROM app WHERE LOWER(app_id)=$1;`
+	sqlStatement := `SELECT id,app_id,app_name,deleted_at,tenant_id FROM app WHERE LOWER(app_id)=$1;`
 
 	var deletedAt sql.NullString
-	err := a.db.QueryRow(sqlStatement, strings.ToLower(appID)).Scan(&app.ID, &app.AppID, &app.AppName, &deletedAt)
+	err := a.db.QueryRow(sqlStatement, strings.ToLower(appID)).Scan(&app.ID, &app.AppID, &app.AppName, &deletedAt, &app.TenantID)
 	app.DeletedAt = deletedAt.String
 
 	if err != nil {
@@ -214,9 +316,9 @@ func (a *appsPostgreSQLRepository) GetAppByAppID(appID string) (*models.App, err
 func (a *appsPostgreSQLRepository) GetActiveAppByAppID(appID string) (*models.App, error) {
 	app := models.App{}
 
-	sqlStatement := `SELECT id,app_id,app_name FROM app WHERE LOWER(app_id)=$1 AND deleted_at IS NULL;`
+	sqlStatement := `SELECT id,app_id,app_name,tenant_id FROM app WHERE LOWER(app_id)=$1 AND deleted_at IS NULL;`
 
-	err := a.db.QueryRow(sqlStatement, strings.ToLower(appID)).Scan(&app.ID, &app.AppID, &app.AppName)
+	err := a.db.QueryRow(sqlStatement, strings.ToLower(appID)).Scan(&app.ID, &app.AppID, &app.AppName, &app.TenantID)
 
 	if err != nil {
 		log.Error(err)
@@ -252,12 +354,19 @@ func (a *appsPostgreSQLRepository) UpsertVersionWithAppLaunchesAndLastLaunched(v
 
 // InsertDeviceOrUpdateVersionID creates a new device row in the device table
 func (a *appsPostgreSQLRepository) InsertDeviceOrUpdateVersionID(device models.Device) error {
+	// The OS versions of the device are kept for the analysis of the init requests
 	sqlStatement := `
-		INSERT INTO device(id,version_id,app_id,device_id,device_type,device_version)
-		VALUES($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (id)
-		DO UPDATE
-		SET version_id = $2, device_version = $6`
+		WITH d AS (
+			INSERT INTO device(id,version_id,app_id,device_id,device_type,device_version)
+			VALUES($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (id)
+			DO UPDATE
+			SET version_id = $2, device_version = $6
+			RETURNING id, device_version
+		)
+		INSERT INTO device_os_version(device_id,device_version)
+		SELECT id, device_version FROM d WHERE device_version <> ''
+		ON CONFLICT DO NOTHING`
 
 	_, err := a.db.Exec(sqlStatement, device.ID, device.VersionID, device.AppID, device.DeviceID, device.DeviceType, device.DeviceVersion)
 
@@ -338,10 +447,10 @@ func (a *appsPostgreSQLRepository) DeleteAppById(id string) error {
 	return nil
 }
 
-func (a *appsPostgreSQLRepository) CreateApp(id, appId, name string) error {
+func (a *appsPostgreSQLRepository) CreateApp(id, appId, name, tenantID string) error {
 
 	// Update Version
-	_, err := a.db.Exec(`INSERT INTO app (id, app_id, app_name) VALUES ($1,$2,$3)`, id, appId, name)
+	_, err := a.db.Exec(`INSERT INTO app (id, app_id, app_name, tenant_id) VALUES ($1,$2,$3,$4)`, id, appId, name, tenantID)
 
 	if err != nil {
 		log.Error(err)
@@ -380,3 +489,526 @@ func (a *appsPostgreSQLRepository) UpdateAppNameByID(id, name string) error {
 
 	return nil
 }
+
+// GetManagedApps returns the active apps managed by the policy controller, sorted by appId
+func (a *appsPostgreSQLRepository) GetManagedApps() ([]models.App, error) {
+	rows, err := a.db.Query(`
+		SELECT id,app_id,app_name
+		FROM app
+		WHERE managed AND deleted_at IS NULL
+		ORDER BY app_id;`)
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	defer rows.Close()
+
+	apps := []models.App{}
+	for rows.Next() {
+		var app models.App
+		if err := rows.Scan(&app.ID, &app.AppID, &app.AppName); err != nil {
+			log.Error(err)
+			return nil, models.ErrDatabaseError
+		}
+		apps = append(apps, app)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	return apps, nil
+}
+
+// SetAppManaged marks an app, by its appId, as managed or not by the policy controller
+func (a *appsPostgreSQLRepository) SetAppManaged(appID string, managed bool) error {
+
+	_, err := a.db.Exec(`
+		UPDATE app
+		SET managed=$1
+		WHERE LOWER(app_id)=$2;`, managed, strings.ToLower(appID))
+
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetReleasesByAppID returns the released versions declared for an app by its appId, sorted by version.
+// The list is empty when the app declares none.
+func (a *appsPostgreSQLRepository) GetReleasesByAppID(appID string) (*models.Releases, error) {
+	releases := models.Releases{Versions: []string{}}
+
+	err := a.db.QueryRow(`SELECT app_id,unknown_versions FROM app WHERE LOWER(app_id)=$1;`, strings.ToLower(appID)).
+		Scan(&releases.AppID, &releases.UnknownVersions)
+
+	if err != nil {
+		log.Error(err)
+		if err == sql.ErrNoRows {
+			return nil, models.ErrNotFound
+		}
+		return nil, models.ErrDatabaseError
+	}
+
+	rows, err := a.db.Query(`
+		SELECT version
+		FROM released_version
+		WHERE app_id=$1
+		ORDER BY version;`, releases.AppID)
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			log.Error(err)
+			return nil, models.ErrDatabaseError
+		}
+		releases.Versions = append(releases.Versions, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	return &releases, nil
+}
+
+// SetReleases replaces the released versions declared for an app and its mode for the other versions, in a single transaction
+func (a *appsPostgreSQLRepository) SetReleases(releases models.Releases) error {
+	tx, err := a.db.Begin()
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	rollback := func(err error) error {
+		log.Error(err)
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error(rbErr)
+		}
+		return models.ErrDatabaseError
+	}
+
+	if _, err := tx.Exec(`UPDATE app SET unknown_versions=$1 WHERE app_id=$2;`, releases.UnknownVersions, releases.AppID); err != nil {
+		return rollback(err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM released_version WHERE app_id=$1;`, releases.AppID); err != nil {
+		return rollback(err)
+	}
+
+	for _, version := range releases.Versions {
+		if _, err := tx.Exec(`INSERT INTO released_version (app_id, version) VALUES ($1,$2);`, releases.AppID, version); err != nil {
+			return rollback(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// AddReleasedVersion declares a released version of an app. Declaring it again makes no change.
+func (a *appsPostgreSQLRepository) AddReleasedVersion(appID, version string) error {
+
+	_, err := a.db.Exec(`
+		INSERT INTO released_version (app_id, version)
+		VALUES ($1,$2)
+		ON CONFLICT DO NOTHING;`, appID, version)
+
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// GetSigningPolicyByAppID returns the signing certificates registered for an app by its appId, sorted by platform
+// and creation, with its mode for the tampered builds. The list is empty when the app has none.
+func (a *appsPostgreSQLRepository) GetSigningPolicyByAppID(appID string) (*models.SigningPolicy, error) {
+	policy := models.SigningPolicy{Certificates: []models.SigningCertificate{}}
+
+	err := a.db.QueryRow(`SELECT app_id,tampered_builds FROM app WHERE LOWER(app_id)=$1;`, strings.ToLower(appID)).
+		Scan(&policy.AppID, &policy.TamperedBuilds)
+
+	if err != nil {
+		log.Error(err)
+		if err == sql.ErrNoRows {
+			return nil, models.ErrNotFound
+		}
+		return nil, models.ErrDatabaseError
+	}
+
+	rows, err := a.db.Query(`
+		SELECT id,app_id,platform,fingerprint,description,created_at
+		FROM signing_certificate
+		WHERE app_id=$1
+		ORDER BY platform, created_at, id;`, policy.AppID)
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.SigningCertificate
+		if err := rows.Scan(&c.ID, &c.AppID, &c.Platform, &c.Fingerprint, &c.Description, &c.CreatedAt); err != nil {
+			log.Error(err)
+			return nil, models.ErrDatabaseError
+		}
+		policy.Certificates = append(policy.Certificates, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	return &policy, nil
+}
+
+// CreateSigningCertificate registers a signing certificate of an app. It returns ErrConflict when the app
+// already has a certificate with the same platform and fingerprint.
+func (a *appsPostgreSQLRepository) CreateSigningCertificate(c models.SigningCertificate) error {
+	result, err := a.db.Exec(`
+		INSERT INTO signing_certificate (id,app_id,platform,fingerprint,description,created_at)
+		VALUES ($1,$2,$3,$4,$5,$6)
+		ON CONFLICT (app_id, platform, fingerprint) DO NOTHING;`, c.ID, c.AppID, c.Platform, c.Fingerprint, c.Description, c.CreatedAt)
+
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	if created, err := result.RowsAffected(); err == nil && created == 0 {
+		return models.ErrConflict
+	}
+
+	return nil
+}
+
+// DeleteSigningCertificate deletes a signing certificate of an app by its id
+func (a *appsPostgreSQLRepository) DeleteSigningCertificate(appID, id string) error {
+	result, err := a.db.Exec(`DELETE FROM signing_certificate WHERE app_id=$1 AND id=$2;`, appID, id)
+
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	if deleted, err := result.RowsAffected(); err == nil && deleted == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetTamperedBuilds sets the mode of an app, by its appId, for the builds which are not signed by its certificates
+func (a *appsPostgreSQLRepository) SetTamperedBuilds(appID, mode string) error {
+
+	_, err := a.db.Exec(`
+		UPDATE app
+		SET tampered_builds=$1
+		WHERE app_id=$2;`, mode, appID)
+
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// PurgeDeletedApps hard deletes the apps soft deleted before the given time,
+// along with their versions and devices. It returns the number of apps deleted.
+func (a *appsPostgreSQLRepository) PurgeDeletedApps(before time.Time) (int64, error) {
+	tx, err := a.db.Begin()
+	if err != nil {
+		log.Error(err)
+		return 0, models.ErrDatabaseError
+	}
+
+	// the devices and versions reference the rows below them, so delete from the bottom up
+	statements := []string{`
+		DELETE FROM device
+		WHERE app_id IN (SELECT app_id FROM app WHERE deleted_at < $1)
+		OR version_id IN (SELECT v.id FROM version as v JOIN app as a on v.app_id = a.app_id WHERE a.deleted_at < $1);`, `
+		DELETE FROM version
+		WHERE app_id IN (SELECT app_id FROM app WHERE deleted_at < $1);`, `
+		DELETE FROM app
+		WHERE deleted_at < $1;`,
+	}
+
+	var res sql.Result
+	for _, statement := range statements {
+		if res, err = tx.Exec(statement, before); err != nil {
+			log.Error(err)
+			if err := tx.Rollback(); err != nil {
+				log.Error(err)
+			}
+			return 0, models.ErrDatabaseError
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		return 0, models.ErrDatabaseError
+	}
+
+	// the result of the last statement holds the number of apps deleted
+	return res.RowsAffected()
+}
+
+// HardDeleteAppByID exports an app with all its versions and devices, released versions, signing certificates and the data of
+// the exporters and then deletes
+// them, in a single transaction. Nothing is deleted when dryRun is true.
+func (a *appsPostgreSQLRepository) HardDeleteAppByID(id string, dryRun bool) (*models.AppHardDelete, error) {
+	tx, err := a.db.Begin()
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	rollback := func(err error) (*models.AppHardDelete, error) {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error(rbErr)
+		}
+		return nil, err
+	}
+
+	// lock the app so that no version is added to it while it is exported
+	app := models.App{}
+	archive := models.AppArchive{}
+	var deletedAt sql.NullString
+	err = tx.QueryRow(`SELECT id,app_id,app_name,deleted_at,unknown_versions,tampered_builds FROM app WHERE id=$1 FOR UPDATE;`, id).
+		Scan(&app.ID, &app.AppID, &app.AppName, &deletedAt, &archive.Releases.UnknownVersions, &archive.SigningPolicy.TamperedBuilds)
+	app.DeletedAt = deletedAt.String
+
+	if err != nil {
+		log.Error(err)
+		if err == sql.ErrNoRows {
+			return rollback(models.ErrNotFound)
+		}
+		return rollback(models.ErrDatabaseError)
+	}
+
+	versions, err := exportAppVersions(tx, app.AppID)
+	if err != nil {
+		log.Error(err)
+		return rollback(models.ErrDatabaseError)
+	}
+
+	archive.Releases.AppID = app.AppID
+	if archive.Releases.Versions, err = exportReleasedVersions(tx, app.AppID); err != nil {
+		log.Error(err)
+		return rollback(models.ErrDatabaseError)
+	}
+
+	archive.SigningPolicy.AppID = app.AppID
+	if archive.SigningPolicy.Certificates, err = exportSigningCertificates(tx, app.AppID); err != nil {
+		log.Error(err)
+		return rollback(models.ErrDatabaseError)
+	}
+
+	archive.Webhooks = []models.Webhook{}
+	archive.Alerts = []models.Alert{}
+	for _, export := range a.exporters {
+		if err := export(tx, app.AppID, &archive); err != nil {
+			log.Error(err)
+			return rollback(models.ErrDatabaseError)
+		}
+	}
+
+	result := models.AppHardDelete{
+		DryRun:              dryRun,
+		Versions:            len(versions),
+		ReleasedVersions:    len(archive.Releases.Versions),
+		SigningCertificates: len(archive.SigningPolicy.Certificates),
+		Webhooks:            len(archive.Webhooks),
+		Alerts:              len(archive.Alerts),
+	}
+	for _, v := range versions {
+		result.Devices += len(v.Devices)
+	}
+
+	if dryRun {
+		if err := tx.Rollback(); err != nil {
+			log.Error(err)
+		}
+		return &result, nil
+	}
+
+	// the devices reference the versions which reference the app, so delete from the bottom up.
+	// The released versions, signing certificates, webhooks with their deliveries and alerts are deleted with
+	// the app by the cascade of their foreign keys.
+	statements := []struct {
+		query string
+		arg   string
+	}{
+		{`DELETE FROM device WHERE version_id IN (SELECT id FROM version WHERE app_id=$1);`, app.AppID},
+		{`DELETE FROM version WHERE app_id=$1;`, app.AppID},
+		{`DELETE FROM app WHERE id=$1;`, app.ID},
+	}
+
+	for _, s := range statements {
+		if _, err := tx.Exec(s.query, s.arg); err != nil {
+			log.Error(err)
+			return rollback(models.ErrDatabaseError)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	app.DeployedVersions = &versions
+	archive.ExportedAt = time.Now().UTC().Format(time.RFC3339)
+	archive.App = app
+	result.Archive = &archive
+
+	return &result, nil
+}
+
+// exportReleasedVersions returns the released versions declared for an app, sorted by version
+func exportReleasedVersions(tx *sql.Tx, appID string) ([]string, error) {
+	versions := []string{}
+
+	rows, err := tx.Query(`
+	SELECT version
+	FROM released_version
+	WHERE app_id=$1
+	ORDER BY version;`, appID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+// exportSigningCertificates returns the signing certificates registered for an app, sorted by platform
+func exportSigningCertificates(tx *sql.Tx, appID string) ([]models.SigningCertificate, error) {
+	certificates := []models.SigningCertificate{}
+
+	rows, err := tx.Query(`
+	SELECT id,app_id,platform,fingerprint,description,created_at
+	FROM signing_certificate
+	WHERE app_id=$1
+	ORDER BY platform, created_at, id;`, appID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		var c models.SigningCertificate
+		if err := rows.Scan(&c.ID, &c.AppID, &c.Platform, &c.Fingerprint, &c.Description, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, c)
+	}
+
+	return certificates, rows.Err()
+}
+
+// exportAppVersions returns all the versions of an app, each with all its devices
+func exportAppVersions(tx *sql.Tx, appID string) ([]models.Version, error) {
+	versions := []models.Version{}
+	index := map[string]int{}
+
+	rows, err := tx.Query(`
+	SELECT id,version,app_id,disabled,disabled_message,num_of_app_launches,last_launched_at
+	FROM version
+	WHERE app_id=$1
+	ORDER BY version;`, appID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var v models.Version
+		var disabledMessage sql.NullString
+		if err := rows.Scan(&v.ID, &v.Version, &v.AppID, &v.Disabled, &disabledMessage, &v.NumOfAppLaunches, &v.LastLaunchedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		v.DisabledMessage = disabledMessage.String
+		v.Devices = []models.Device{}
+		index[v.ID] = len(versions)
+		versions = append(versions, v)
+	}
+
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	rows, err = tx.Query(`
+	SELECT d.id, d.version_id, d.app_id, d.device_id, d.device_type, d.device_version
+	FROM device as d
+	WHERE d.version_id IN (SELECT id FROM version WHERE app_id=$1)
+	ORDER BY d.device_id;`, appID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		var d models.Device
+		if err := rows.Scan(&d.ID, &d.VersionID, &d.AppID, &d.DeviceID, &d.DeviceType, &d.DeviceVersion); err != nil {
+			return nil, err
+		}
+
+		v := &versions[index[d.VersionID]]
+		d.Version = v.Version
+		v.Devices = append(v.Devices, d)
+	}
+
+	return versions, rows.Err()
+}
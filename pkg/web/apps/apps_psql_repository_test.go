@@ -1,33 +1,61 @@
 package apps
 
 import (
+	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aerogear/mobile-security-service/pkg/helpers"
 	"github.com/aerogear/mobile-security-service/pkg/models"
 
 	"github.com/google/uuid"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
 var (
-	getAppsQueryString = `SELECT a.id,a.app_id,a.app_name,
-	COALESCE\(COUNT\(DISTINCT v.id\),0\) as num_of_deployed_versions,
-	COALESCE\(SUM\(DISTINCT v.num_of_app_launches\),0\) as num_of_app_launches,
-	COALESCE\(COUNT\(DISTINCT d.id\),0\) as num_of_current_installs
-	FROM app as a LEFT JOIN version as v on a.app_id = v.app_id 
-	LEFT JOIN device as d on v.id = d.version_id 
-	WHERE a.deleted_at IS NULL 
-	GROUP BY a.id;`
+	getAppsQueryString = `SELECT id,app_id,app_name,deleted_at,tenant_id,num_of_deployed_versions,num_of_app_launches,num_of_current_installs,
+	COALESCE\(LOWER\(app_name\),''\) as sort_key
+	FROM \(
+		SELECT a.id,a.app_id,a.app_name,a.deleted_at,a.tenant_id,
+		COALESCE\(COUNT\(DISTINCT v.id\),0\) as num_of_deployed_versions,
+		COALESCE\(SUM\(DISTINCT v.num_of_app_launches\),0\) as num_of_app_launches,
+		COALESCE\(COUNT\(DISTINCT d.id\),0\) as num_of_current_installs
+		FROM app as a LEFT JOIN version as v on a.app_id = v.app_id
+		LEFT JOIN device as d on v.id = d.version_id
+		WHERE a.deleted_at IS NULL 
+		GROUP BY a.id
+	\) as apps
+	
+	ORDER BY COALESCE\(LOWER\(app_name\),''\) ASC, id ASC`
+
+	getAppsPageQueryString = `SELECT id,app_id,app_name,deleted_at,tenant_id,num_of_deployed_versions,num_of_app_launches,num_of_current_installs,
+	num_of_app_launches as sort_key
+	FROM \(
+		SELECT a.id,a.app_id,a.app_name,a.deleted_at,a.tenant_id,
+		COALESCE\(COUNT\(DISTINCT v.id\),0\) as num_of_deployed_versions,
+		COALESCE\(SUM\(DISTINCT v.num_of_app_launches\),0\) as num_of_app_launches,
+		COALESCE\(COUNT\(DISTINCT d.id\),0\) as num_of_current_installs
+		FROM app as a LEFT JOIN version as v on a.app_id = v.app_id
+		LEFT JOIN device as d on v.id = d.version_id
+		WHERE a.deleted_at IS NULL AND \(a.app_name ILIKE \$1 OR a.app_id ILIKE \$1\)
+		GROUP BY a.id
+	\) as apps
+	WHERE \(num_of_app_launches, id\) < \(\$2, \$3\)
+	ORDER BY num_of_app_launches DESC, id DESC
+	LIMIT \$4;`
 
 	getAppVersionsQueryString = `SELECT v.id,v.version,v.app_id, v.disabled, v.disabled_message, v.num_of_app_launches, v.last_launched_at,
-	COALESCE\(COUNT\(DISTINCT d.id\),0\) as num_of_current_installs
-	FROM version as v LEFT JOIN device as d on v.id = d.version_id
+	COALESCE\(COUNT\(DISTINCT d.id\),0\) as num_of_current_installs,
+	\(a.unknown_versions <> \$2
+		AND EXISTS \(SELECT 1 FROM released_version as r WHERE r.app_id = v.app_id\)
+		AND NOT EXISTS \(SELECT 1 FROM released_version as r WHERE r.app_id = v.app_id AND r.version = v.version\)\) as unknown
+	FROM version as v JOIN app as a on a.app_id = v.app_id LEFT JOIN device as d on v.id = d.version_id
 	WHERE v.app_id = \$1 
-	GROUP BY v.id;`
+	GROUP BY v.id, a.unknown_versions;`
 
 	GetActiveAppByIDQueryString = `SELECT id,app_id,app_name FROM app WHERE deleted_at IS NULL AND id=\$1;`
 
@@ -73,9 +101,17 @@ var (
 		FROM device as d
 		WHERE d.app_id = \$1 AND d.device_version = \$2;`
 
-	GetActiveAppByAppIDQuery = `SELECT id,app_id,app_name FROM app WHERE LOWER\(app_id\)=\$1 AND deleted_at IS NULL;`
+	GetActiveAppByAppIDQuery = `SELECT id,app_id,app_name,tenant_id FROM app WHERE LOWER\(app_id\)=\$1 AND deleted_at IS NULL;`
 
-	GetAppByAppIDQuery = `SELECT id,app_id,app_name,deleted_at FROM app WHERE LOWER\(app_id\)=\$1;`
+	GetAppByAppIDQuery = `SELECT id,app_id,app_name,deleted_at,tenant_id FROM app WHERE LOWER\(app_id\)=\$1;`
+
+	GetAppByIDQuery = `SELECT id,app_id,app_name,deleted_at FROM app WHERE id=\$1;`
+
+	purgeDeletedDevicesStatement = `DELETE FROM device`
+
+	purgeDeletedVersionsStatement = `DELETE FROM version`
+
+	purgeDeletedAppsStatement = `DELETE FROM app`
 
 	upsertVersionWithAppLaunchesAndLastLaunchedStatement = `INSERT INTO version as v \(id, version, app_id, disabled, disabled_message, last_launched_at\)
 		VALUES\(\$1, \$2, \$3, \$4, \$5, NOW\(\)\)
@@ -84,11 +120,33 @@ var (
 		SET num_of_app_launches = v\.num_of_app_launches \+ 1,
 		last_launched_at = NOW\(\);`
 
-	insertDeviceOrUpdateVersionIDStatement = `INSERT INTO device\(id,version_id,app_id,device_id,device_type,device_version\)
-		VALUES\(\$1, \$2, \$3, \$4, \$5, \$6\)
-		ON CONFLICT \(id\)
-		DO UPDATE
-		SET version_id = \$2, device_version = \$6`
+	getManagedAppsQueryString = `SELECT id,app_id,app_name
+		FROM app
+		WHERE managed AND deleted_at IS NULL
+		ORDER BY app_id;`
+
+	setAppManagedStatement = `UPDATE app
+		SET managed=\$1
+		WHERE LOWER\(app_id\)=\$2;`
+
+	insertDeviceOrUpdateVersionIDStatement = `WITH d AS \(
+			INSERT INTO device\(id,version_id,app_id,device_id,device_type,device_version\)
+			VALUES\(\$1, \$2, \$3, \$4, \$5, \$6\)
+			ON CONFLICT \(id\)
+			DO UPDATE
+			SET version_id = \$2, device_version = \$6
+			RETURNING id, device_version
+		\)
+		INSERT INTO device_os_version\(device_id,device_version\)
+		SELECT id, device_version FROM d WHERE device_version <> ''
+		ON CONFLICT DO NOTHING`
+
+	getReleasesModeQueryString = `SELECT app_id,unknown_versions FROM app WHERE LOWER\(app_id\)=\$1;`
+
+	getReleasedVersionsQueryString = `SELECT version
+		FROM released_version
+		WHERE app_id=\$1
+		ORDER BY version;`
 )
 
 func Test_appsPostgreSQLRepository_GetApps_WillReturnTwoApps(t *testing.T) {
@@ -101,28 +159,108 @@ func Test_appsPostgreSQLRepository_GetApps_WillReturnTwoApps(t *testing.T) {
 
 	mockApps := helpers.GetMockAppList()
 
-	cols := []string{"id", "app_id", "app_name", "deleted_at"}
+	cols := []string{"id", "app_id", "app_name", "deleted_at", "tenant_id", "num_of_deployed_versions", "num_of_app_launches", "num_of_current_installs", "sort_key"}
 
 	timestamp := "2019-02-15T09:38:33+00:00"
 
 	// Insert an app where the deleted_at column is set
-	sqlmock.NewRows(cols).AddRow(mockApps[0].ID, mockApps[0].AppID, mockApps[0].AppName, timestamp)
+	sqlmock.NewRows([]string{"id", "app_id", "app_name", "deleted_at"}).AddRow(mockApps[0].ID, mockApps[0].AppID, mockApps[0].AppName, timestamp)
 
 	// Insert 2 apps which are not soft deleted
-	rows := sqlmock.NewRows(cols).AddRow(mockApps[1].ID, mockApps[1].AppID, mockApps[1].AppName, "").AddRow(mockApps[2].ID, mockApps[2].AppID, mockApps[2].AppName, "")
+	rows := sqlmock.NewRows(cols).AddRow(mockApps[1].ID, mockApps[1].AppID, mockApps[1].AppName, nil, models.DefaultTenantID, 1, 10, 5, "mobile app two").AddRow(mockApps[2].ID, mockApps[2].AppID, mockApps[2].AppName, nil, models.DefaultTenantID, 1, 20, 8, "mobile app three")
 
 	// We should expected to get back only the apps which are not soft deleted
 	mock.ExpectQuery(getAppsQueryString).WillReturnRows(rows)
 	a := NewPostgreSQLRepository(db)
 
-	apps, err := a.GetApps()
+	page, err := a.GetApps(models.AppsQuery{})
 
 	if err != nil {
 		t.Fatalf("Got error trying to get apps from database: %v", err)
 	}
 
-	if len(*apps) != 2 {
-		t.Fatalf("Expected 2 apps to be returned from the database, got %v", len(*apps))
+	if len(page.Apps) != 2 {
+		t.Fatalf("Expected 2 apps to be returned from the database, got %v", len(page.Apps))
+	}
+
+	if page.NextCursor != "" {
+		t.Fatalf("Expected no cursor to be returned when all apps are fetched, got %v", page.NextCursor)
+	}
+}
+
+func Test_appsPostgreSQLRepository_GetApps_WillReturnAPageAndTheNextCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	mockApps := helpers.GetMockAppList()
+	cursorID := uuid.New().String()
+
+	cols := []string{"id", "app_id", "app_name", "deleted_at", "tenant_id", "num_of_deployed_versions", "num_of_app_launches", "num_of_current_installs", "sort_key"}
+
+	// One row more than the limit is returned when there is a next page
+	rows := sqlmock.NewRows(cols).
+		AddRow(mockApps[0].ID, mockApps[0].AppID, mockApps[0].AppName, nil, models.DefaultTenantID, 1, 30, 5, "30").
+		AddRow(mockApps[1].ID, mockApps[1].AppID, mockApps[1].AppName, nil, models.DefaultTenantID, 1, 20, 5, "20").
+		AddRow(mockApps[2].ID, mockApps[2].AppID, mockApps[2].AppName, nil, models.DefaultTenantID, 1, 10, 5, "10")
+
+	mock.ExpectQuery(getAppsPageQueryString).WithArgs("%foo\\_bar%", "40", cursorID, 3).WillReturnRows(rows)
+	a := NewPostgreSQLRepository(db)
+
+	page, err := a.GetApps(models.AppsQuery{
+		Limit:  2,
+		Sort:   "-launches",
+		Search: "foo_bar",
+		Cursor: encodeAppsCursor(appsCursor{Sort: "-launches", Key: "40", ID: cursorID}),
+	})
+
+	if err != nil {
+		t.Fatalf("Got error trying to get apps from database: %v", err)
+	}
+
+	if len(page.Apps) != 2 {
+		t.Fatalf("Expected 2 apps to be returned from the database, got %v", len(page.Apps))
+	}
+
+	want := appsCursor{Sort: "-launches", Key: "20", ID: mockApps[1].ID}
+	if got, err := decodeAppsCursor(page.NextCursor); err != nil || *got != want {
+		t.Fatalf("Expected the next cursor to be %v, got %v", want, got)
+	}
+}
+
+func Test_appsPostgreSQLRepository_GetApps_WillReturnTheAppsOfTheTenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	mockApps := helpers.GetMockAppList()
+	tenantID := uuid.New().String()
+
+	cols := []string{"id", "app_id", "app_name", "deleted_at", "tenant_id", "num_of_deployed_versions", "num_of_app_launches", "num_of_current_installs", "sort_key"}
+	rows := sqlmock.NewRows(cols).AddRow(mockApps[0].ID, mockApps[0].AppID, mockApps[0].AppName, nil, tenantID, 1, 10, 5, "mobile app one")
+
+	// Only the apps of the tenants of the scope should be queried
+	mock.ExpectQuery(`WHERE a.deleted_at IS NULL AND a.tenant_id = ANY\(\$1::uuid\[\]\)`).WithArgs(pq.Array([]string{tenantID})).WillReturnRows(rows)
+	a := NewPostgreSQLRepository(db)
+
+	page, err := a.GetApps(models.AppsQuery{Tenants: &models.TenantScope{TenantIDs: []string{tenantID}}})
+
+	if err != nil {
+		t.Fatalf("Got error trying to get the apps of the tenants from database: %v", err)
+	}
+
+	if len(page.Apps) != 1 || page.Apps[0].TenantID != tenantID {
+		t.Fatalf("Expected 1 app of the tenant to be returned from the database, got %v", page.Apps)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
 
@@ -145,14 +283,45 @@ func Test_appsPostgreSQLRepository_GetApps_WillReturnNoApps(t *testing.T) {
 	mock.ExpectQuery(getAppsQueryString).WillReturnRows(&sqlmock.Rows{})
 	a := NewPostgreSQLRepository(db)
 
-	apps, err := a.GetApps()
+	page, err := a.GetApps(models.AppsQuery{})
 
 	if err != nil && err != models.ErrNotFound {
 		t.Fatalf("Expected ErrNotFound error to be returned from database, got %v", err)
 	}
 
-	if apps != nil && len(*apps) != 0 {
-		t.Fatalf("Expected 0 apps to be returned from the database, got %v", len(*apps))
+	if page != nil && len(page.Apps) != 0 {
+		t.Fatalf("Expected 0 apps to be returned from the database, got %v", len(page.Apps))
+	}
+}
+
+func Test_appsPostgreSQLRepository_GetApps_WillReturnTheDeletedApps(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	mockApps := helpers.GetMockAppList()
+
+	cols := []string{"id", "app_id", "app_name", "deleted_at", "tenant_id", "num_of_deployed_versions", "num_of_app_launches", "num_of_current_installs", "sort_key"}
+
+	timestamp := "2019-02-15T09:38:33+00:00"
+
+	rows := sqlmock.NewRows(cols).AddRow(mockApps[0].ID, mockApps[0].AppID, mockApps[0].AppName, timestamp, models.DefaultTenantID, 1, 10, 5, "mobile app one")
+
+	// Only the soft deleted apps should be queried
+	mock.ExpectQuery(`WHERE a.deleted_at IS NOT NULL`).WillReturnRows(rows)
+	a := NewPostgreSQLRepository(db)
+
+	page, err := a.GetApps(models.AppsQuery{Deleted: true})
+
+	if err != nil {
+		t.Fatalf("Got error trying to get the deleted apps from database: %v", err)
+	}
+
+	if len(page.Apps) != 1 || page.Apps[0].DeletedAt != timestamp {
+		t.Fatalf("Expected 1 deleted app to be returned from the database, got %v", page.Apps)
 	}
 }
 
@@ -180,7 +349,7 @@ func Test_appsPostgreSQLRepository_GetAppVersionsByAppID(t *testing.T) {
 	sqlmock.NewRows(cols).
 		AddRow(mockVersions[2].ID, mockVersions[0].Version, mockVersions[2].AppID, mockVersions[2].Disabled, mockVersions[2].DisabledMessage, mockVersions[2].NumOfAppLaunches)
 
-	mock.ExpectQuery(``).WithArgs(appID).WillReturnRows(rows)
+	mock.ExpectQuery(``).WithArgs(appID, models.UnknownVersionsAccept).WillReturnRows(rows)
 
 	a := NewPostgreSQLRepository(db)
 
@@ -215,7 +384,7 @@ func Test_appsPostgreSQLRepository_GetAppVersions_WillReturnNoAppVersions(t *tes
 		AddRow(mockVersions[0].ID, mockVersions[0].Version, mockVersions[0].AppID, mockVersions[0].Disabled, mockVersions[0].DisabledMessage, mockVersions[0].NumOfAppLaunches).
 		AddRow(mockVersions[1].ID, mockVersions[1].Version, mockVersions[1].AppID, mockVersions[1].Disabled, mockVersions[1].DisabledMessage, mockVersions[1].NumOfAppLaunches)
 
-	mock.ExpectQuery(getAppVersionsQueryString).WithArgs(appID).WillReturnRows(&sqlmock.Rows{})
+	mock.ExpectQuery(getAppVersionsQueryString).WithArgs(appID, models.UnknownVersionsAccept).WillReturnRows(&sqlmock.Rows{})
 
 	a := NewPostgreSQLRepository(db)
 
@@ -781,17 +950,17 @@ func Test_appsPostgreSQLRepository_GetActiveAppByAppID(t *testing.T) {
 
 	defer db.Close()
 
-	cols := []string{"id", "app_id", "app_name"}
+	cols := []string{"id", "app_id", "app_name", "tenant_id"}
 
 	mockApps := helpers.GetMockAppList()
 
 	for _, a := range mockApps {
-		sqlmock.NewRows(cols).AddRow(a.ID, a.AppID, a.AppName)
+		sqlmock.NewRows(cols).AddRow(a.ID, a.AppID, a.AppName, models.DefaultTenantID)
 	}
 
 	wantApp := helpers.GetMockApp()
 
-	wantRow := sqlmock.NewRows(cols).AddRow(wantApp.ID, wantApp.AppID, wantApp.AppName)
+	wantRow := sqlmock.NewRows(cols).AddRow(wantApp.ID, wantApp.AppID, wantApp.AppName, models.DefaultTenantID)
 
 	type args struct {
 		appID string
@@ -918,17 +1087,18 @@ func Test_appsPostgreSQLRepository_GetAppByAppID(t *testing.T) {
 
 	defer db.Close()
 
-	cols := []string{"id", "app_id", "app_name", "deleted_at"}
+	cols := []string{"id", "app_id", "app_name", "deleted_at", "tenant_id"}
 
 	mockApps := helpers.GetMockAppList()
 
 	for _, a := range mockApps {
-		sqlmock.NewRows(cols).AddRow(a.ID, a.AppID, a.AppName, a.DeletedAt)
+		sqlmock.NewRows(cols).AddRow(a.ID, a.AppID, a.AppName, a.DeletedAt, models.DefaultTenantID)
 	}
 
 	wantApp := helpers.GetMockApp()
+	wantApp.TenantID = models.DefaultTenantID
 
-	wantRow := sqlmock.NewRows(cols).AddRow(wantApp.ID, wantApp.AppID, wantApp.AppName, wantApp.DeletedAt)
+	wantRow := sqlmock.NewRows(cols).AddRow(wantApp.ID, wantApp.AppID, wantApp.AppName, wantApp.DeletedAt, wantApp.TenantID)
 
 	type args struct {
 		appID string
@@ -975,8 +1145,8 @@ func Test_appsPostgreSQLRepository_GetAppByAppID(t *testing.T) {
 			repo := NewPostgreSQLRepository(db)
 			got, err := repo.GetAppByAppID(tt.args.appID)
 
-			if (got != nil) && !reflect.DeepEqual(got.AppID, tt.want.app.AppID) {
-				t.Errorf("appsPostgreSQLRepository.GetActiveAppByAppID() = %v, want %v", got.AppID, tt.want.app.ID)
+			if (got != nil) && (!reflect.DeepEqual(got.AppID, tt.want.app.AppID) || got.TenantID != tt.want.app.TenantID) {
+				t.Errorf("appsPostgreSQLRepository.GetActiveAppByAppID() = %v, want %v", got, tt.want.app)
 			}
 
 			if !reflect.DeepEqual(err, tt.want.err) {
@@ -986,6 +1156,315 @@ func Test_appsPostgreSQLRepository_GetAppByAppID(t *testing.T) {
 	}
 }
 
+func Test_appsPostgreSQLRepository_GetAppByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	cols := []string{"id", "app_id", "app_name", "deleted_at"}
+
+	timestamp := "2019-02-15T09:38:33+00:00"
+
+	wantApp := helpers.GetMockApp()
+
+	tests := []struct {
+		name    string
+		id      string
+		rows    *sqlmock.Rows
+		want    *models.App
+		wantErr error
+	}{
+		{
+			name: "Should return an app",
+			id:   wantApp.ID,
+			rows: sqlmock.NewRows(cols).AddRow(wantApp.ID, wantApp.AppID, wantApp.AppName, nil),
+			want: &models.App{ID: wantApp.ID, AppID: wantApp.AppID, AppName: wantApp.AppName},
+		},
+		{
+			name: "Should return an app which is soft deleted",
+			id:   wantApp.ID,
+			rows: sqlmock.NewRows(cols).AddRow(wantApp.ID, wantApp.AppID, wantApp.AppName, timestamp),
+			want: &models.App{ID: wantApp.ID, AppID: wantApp.AppID, AppName: wantApp.AppName, DeletedAt: timestamp},
+		},
+		{
+			name:    "Should not return an app given an unknown id",
+			id:      uuid.New().String(),
+			rows:    &sqlmock.Rows{},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			mock.ExpectQuery(GetAppByIDQuery).WithArgs(tt.id).WillReturnRows(tt.rows)
+
+			repo := NewPostgreSQLRepository(db)
+			got, err := repo.GetAppByID(tt.id)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appsPostgreSQLRepository.GetAppByID() = %v, want %v", got, tt.want)
+			}
+
+			if err != tt.wantErr {
+				t.Errorf("appsPostgreSQLRepository.GetAppByID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_appsPostgreSQLRepository_PurgeDeletedApps(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	before := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		expect  func()
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "Should delete the devices, versions and apps soft deleted before the given time",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(purgeDeletedDevicesStatement).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectExec(purgeDeletedVersionsStatement).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(purgeDeletedAppsStatement).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			want: 2,
+		},
+		{
+			name: "Should rollback when a statement fails",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(purgeDeletedDevicesStatement).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 4))
+				mock.ExpectExec(purgeDeletedVersionsStatement).WithArgs(before).WillReturnError(models.ErrDatabaseError)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			repo := NewPostgreSQLRepository(db)
+			got, err := repo.PurgeDeletedApps(before)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("appsPostgreSQLRepository.PurgeDeletedApps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("appsPostgreSQLRepository.PurgeDeletedApps() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_appsPostgreSQLRepository_HardDeleteAppByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	app := helpers.GetMockApp()
+	versions := helpers.GetMockAppVersionList()
+
+	appCols := []string{"id", "app_id", "app_name", "deleted_at", "unknown_versions", "tampered_builds"}
+	certificateCols := []string{"id", "app_id", "platform", "fingerprint", "description", "created_at"}
+	versionCols := []string{"id", "version", "app_id", "disabled", "disabled_message", "num_of_app_launches", "last_launched_at"}
+	deviceCols := []string{"id", "version_id", "app_id", "device_id", "device_type", "device_version"}
+
+	expectExport := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT id,app_id,app_name,deleted_at,unknown_versions,tampered_builds FROM app WHERE id=\$1 FOR UPDATE;`).WithArgs(app.ID).
+			WillReturnRows(sqlmock.NewRows(appCols).AddRow(app.ID, app.AppID, app.AppName, nil, models.UnknownVersionsBlock, models.TamperedBuildsFlag))
+		mock.ExpectQuery(`FROM version WHERE app_id=\$1 ORDER BY version;`).WithArgs(app.AppID).
+			WillReturnRows(sqlmock.NewRows(versionCols).
+				AddRow(versions[0].ID, versions[0].Version, app.AppID, false, nil, 10, "2019-02-15T09:38:33+00:00").
+				AddRow(versions[1].ID, versions[1].Version, app.AppID, true, "disabled", 5, "2019-02-15T09:38:33+00:00"))
+		mock.ExpectQuery(`FROM device as d WHERE d.version_id IN`).WithArgs(app.AppID).
+			WillReturnRows(sqlmock.NewRows(deviceCols).
+				AddRow(uuid.New().String(), versions[0].ID, app.AppID, "device-one", "Android", "9").
+				AddRow(uuid.New().String(), versions[0].ID, app.AppID, "device-two", "iOS", "12").
+				AddRow(uuid.New().String(), versions[1].ID, app.AppID, "device-three", "Android", "8"))
+		mock.ExpectQuery(`FROM released_version WHERE app_id=\$1 ORDER BY version;`).WithArgs(app.AppID).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(versions[0].Version))
+		mock.ExpectQuery(`FROM signing_certificate WHERE app_id=\$1`).WithArgs(app.AppID).
+			WillReturnRows(sqlmock.NewRows(certificateCols).
+				AddRow(uuid.New().String(), app.AppID, models.PlatformAndroid, testFingerprint, "release key", time.Now()).
+				AddRow(uuid.New().String(), app.AppID, models.PlatformIOS, testFingerprint, "", time.Now()))
+	}
+
+	// the exporters of other packages add a webhook and an alert to the archive, or fail with exportErr
+	webhook := models.Webhook{ID: uuid.New().String(), AppID: app.AppID, URL: "https://example.com/hook", Events: []string{"app.deleted"}}
+	var exportErr error
+	exporter := func(tx *sql.Tx, appID string, archive *models.AppArchive) error {
+		if exportErr != nil {
+			return exportErr
+		}
+		archive.Webhooks = append(archive.Webhooks, webhook)
+		return nil
+	}
+	alert := models.Alert{ID: uuid.New().String(), AppID: app.AppID, Kind: models.AlertUnreleasedVersion, Subject: "1.1", Value: 12, Threshold: 10}
+	alertsExporter := func(tx *sql.Tx, appID string, archive *models.AppArchive) error {
+		archive.Alerts = append(archive.Alerts, alert)
+		return nil
+	}
+
+	tests := []struct {
+		name         string
+		dryRun       bool
+		exportErr    error
+		expect       func()
+		wantVersions int
+		wantDevices  int
+		wantArchive  bool
+		wantErr      error
+	}{
+		{
+			name:   "Should export and delete the app with its versions and devices",
+			dryRun: false,
+			expect: func() {
+				expectExport()
+				mock.ExpectExec(`DELETE FROM device WHERE version_id IN`).WithArgs(app.AppID).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(`DELETE FROM version WHERE app_id=\$1;`).WithArgs(app.AppID).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(`DELETE FROM app WHERE id=\$1;`).WithArgs(app.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantVersions: 2,
+			wantDevices:  3,
+			wantArchive:  true,
+		},
+		{
+			name:   "Should only count the versions and devices on a dry run",
+			dryRun: true,
+			expect: func() {
+				expectExport()
+				mock.ExpectRollback()
+			},
+			wantVersions: 2,
+			wantDevices:  3,
+		},
+		{
+			name: "Should rollback when the export fails",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`FROM app WHERE id=\$1 FOR UPDATE;`).WithArgs(app.ID).
+					WillReturnRows(sqlmock.NewRows(appCols).AddRow(app.ID, app.AppID, app.AppName, nil, models.UnknownVersionsBlock, models.TamperedBuildsFlag))
+				mock.ExpectQuery(`FROM version WHERE app_id=\$1 ORDER BY version;`).WithArgs(app.AppID).WillReturnRows(sqlmock.NewRows(versionCols))
+				mock.ExpectQuery(`FROM device as d WHERE d.version_id IN`).WithArgs(app.AppID).WillReturnRows(sqlmock.NewRows(deviceCols))
+				mock.ExpectQuery(`FROM released_version WHERE app_id=\$1`).WithArgs(app.AppID).WillReturnError(models.ErrDatabaseError)
+				mock.ExpectRollback()
+			},
+			wantErr: models.ErrDatabaseError,
+		},
+		{
+			name:      "Should rollback when an exporter fails",
+			exportErr: errors.New("webhooks not exported"),
+			expect: func() {
+				expectExport()
+				mock.ExpectRollback()
+			},
+			wantErr: models.ErrDatabaseError,
+		},
+		{
+			name: "Should rollback when a delete fails",
+			expect: func() {
+				expectExport()
+				mock.ExpectExec(`DELETE FROM device WHERE version_id IN`).WithArgs(app.AppID).WillReturnError(models.ErrDatabaseError)
+				mock.ExpectRollback()
+			},
+			wantErr: models.ErrDatabaseError,
+		},
+		{
+			name: "Should return not found when the app does not exist",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`FROM app WHERE id=\$1 FOR UPDATE;`).WithArgs(app.ID).WillReturnRows(&sqlmock.Rows{})
+				mock.ExpectRollback()
+			},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+			exportErr = tt.exportErr
+
+			repo := NewPostgreSQLRepository(db, exporter, alertsExporter)
+			got, err := repo.HardDeleteAppByID(app.ID, tt.dryRun)
+
+			if err != tt.wantErr {
+				t.Fatalf("appsPostgreSQLRepository.HardDeleteAppByID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.DryRun != tt.dryRun || got.Versions != tt.wantVersions || got.Devices != tt.wantDevices {
+				t.Errorf("appsPostgreSQLRepository.HardDeleteAppByID() = %+v, want %v versions and %v devices", got, tt.wantVersions, tt.wantDevices)
+			}
+
+			if got.ReleasedVersions != 1 || got.SigningCertificates != 2 || got.Webhooks != 1 || got.Alerts != 1 {
+				t.Errorf("appsPostgreSQLRepository.HardDeleteAppByID() = %+v, want 1 released version, 2 signing certificates, 1 webhook and 1 alert", got)
+			}
+
+			if (got.Archive != nil) != tt.wantArchive {
+				t.Fatalf("appsPostgreSQLRepository.HardDeleteAppByID() archive = %v, wantArchive %v", got.Archive, tt.wantArchive)
+			}
+
+			if tt.wantArchive {
+				archived := *got.Archive.App.DeployedVersions
+				if len(archived) != 2 || len(archived[0].Devices) != 2 || archived[0].Devices[0].Version != versions[0].Version {
+					t.Errorf("appsPostgreSQLRepository.HardDeleteAppByID() archived versions = %+v", archived)
+				}
+
+				wantReleases := models.Releases{AppID: app.AppID, UnknownVersions: models.UnknownVersionsBlock, Versions: []string{versions[0].Version}}
+				if !reflect.DeepEqual(got.Archive.Releases, wantReleases) {
+					t.Errorf("appsPostgreSQLRepository.HardDeleteAppByID() archived releases = %+v, want %+v", got.Archive.Releases, wantReleases)
+				}
+
+				if policy := got.Archive.SigningPolicy; policy.TamperedBuilds != models.TamperedBuildsFlag || len(policy.Certificates) != 2 || policy.Certificates[0].Description != "release key" {
+					t.Errorf("appsPostgreSQLRepository.HardDeleteAppByID() archived signing policy = %+v", policy)
+				}
+
+				if !reflect.DeepEqual(got.Archive.Webhooks, []models.Webhook{webhook}) {
+					t.Errorf("appsPostgreSQLRepository.HardDeleteAppByID() archived webhooks = %+v, want %+v", got.Archive.Webhooks, []models.Webhook{webhook})
+				}
+
+				if !reflect.DeepEqual(got.Archive.Alerts, []models.Alert{alert}) {
+					t.Errorf("appsPostgreSQLRepository.HardDeleteAppByID() archived alerts = %+v, want %+v", got.Archive.Alerts, []models.Alert{alert})
+				}
+			}
+		})
+	}
+}
+
 func Test_appsPostgreSQLRepository_UpsertVersionWithAppLaunchesAndLastLaunched(t *testing.T) {
 	db, mock, err := sqlmock.New()
 
@@ -1197,7 +1676,7 @@ func Test_appsPostgreSQLRepository_CreateApp(t *testing.T) {
 	sqlmock.NewRows(cols).AddRow(mockApps.ID, mockApps.AppID, mockApps.AppName)
 
 	// We should expected to get back only the apps which are not soft deleted
-	mock.ExpectExec("INSERT INTO app").WithArgs(mockApps.ID, mockApps.AppID, mockApps.AppName).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO app").WithArgs(mockApps.ID, mockApps.AppID, mockApps.AppName, models.DefaultTenantID).WillReturnResult(sqlmock.NewResult(1, 1))
 	a := NewPostgreSQLRepository(db)
 
 	tests := []struct {
@@ -1222,7 +1701,7 @@ func Test_appsPostgreSQLRepository_CreateApp(t *testing.T) {
 		},
 	}
 	for _, tt := range tests {
-		err = a.CreateApp(tt.id, mockApps.AppID, mockApps.AppName)
+		err = a.CreateApp(tt.id, mockApps.AppID, mockApps.AppName, models.DefaultTenantID)
 
 		if err != nil && !tt.wantErr {
 			t.Fatalf("Got error trying to create a new app from database: %v", err)
@@ -1233,3 +1712,363 @@ func Test_appsPostgreSQLRepository_CreateApp(t *testing.T) {
 		}
 	}
 }
+
+func Test_appsPostgreSQLRepository_GetManagedApps(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	mockApp := helpers.GetMockApp()
+
+	tests := []struct {
+		name    string
+		expect  func()
+		want    []models.App
+		wantErr bool
+	}{
+		{
+			name: "Should return the active managed apps",
+			expect: func() {
+				rows := sqlmock.NewRows([]string{"id", "app_id", "app_name"}).AddRow(mockApp.ID, mockApp.AppID, mockApp.AppName)
+				mock.ExpectQuery(getManagedAppsQueryString).WillReturnRows(rows)
+			},
+			want: []models.App{{ID: mockApp.ID, AppID: mockApp.AppID, AppName: mockApp.AppName}},
+		},
+		{
+			name: "Should return an empty list when no app is managed",
+			expect: func() {
+				mock.ExpectQuery(getManagedAppsQueryString).WillReturnRows(sqlmock.NewRows([]string{"id", "app_id", "app_name"}))
+			},
+			want: []models.App{},
+		},
+		{
+			name: "Should return a database error when the query fails",
+			expect: func() {
+				mock.ExpectQuery(getManagedAppsQueryString).WillReturnError(models.ErrDatabaseError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			got, err := NewPostgreSQLRepository(db).GetManagedApps()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("appsPostgreSQLRepository.GetManagedApps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appsPostgreSQLRepository.GetManagedApps() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_appsPostgreSQLRepository_SetAppManaged(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectExec(setAppManagedStatement).WithArgs(true, "com.aerogear.app").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := NewPostgreSQLRepository(db).SetAppManaged("COM.aerogear.app", true); err != nil {
+		t.Errorf("appsPostgreSQLRepository.SetAppManaged() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_appsPostgreSQLRepository_GetReleasesByAppID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	const appID = "com.aerogear.app"
+
+	tests := []struct {
+		name    string
+		expect  func()
+		want    *models.Releases
+		wantErr error
+	}{
+		{
+			name: "Should return the released versions and the mode of the app",
+			expect: func() {
+				mock.ExpectQuery(getReleasesModeQueryString).WithArgs(appID).
+					WillReturnRows(sqlmock.NewRows([]string{"app_id", "unknown_versions"}).AddRow(appID, models.UnknownVersionsBlock))
+				mock.ExpectQuery(getReleasedVersionsQueryString).WithArgs(appID).
+					WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("1.0").AddRow("1.1"))
+			},
+			want: &models.Releases{AppID: appID, UnknownVersions: models.UnknownVersionsBlock, Versions: []string{"1.0", "1.1"}},
+		},
+		{
+			name: "Should return an empty list when the app declares no released versions",
+			expect: func() {
+				mock.ExpectQuery(getReleasesModeQueryString).WithArgs(appID).
+					WillReturnRows(sqlmock.NewRows([]string{"app_id", "unknown_versions"}).AddRow(appID, models.UnknownVersionsFlag))
+				mock.ExpectQuery(getReleasedVersionsQueryString).WithArgs(appID).WillReturnRows(sqlmock.NewRows([]string{"version"}))
+			},
+			want: &models.Releases{AppID: appID, UnknownVersions: models.UnknownVersionsFlag, Versions: []string{}},
+		},
+		{
+			name: "Should return ErrNotFound when the app is not found",
+			expect: func() {
+				mock.ExpectQuery(getReleasesModeQueryString).WithArgs(appID).WillReturnRows(sqlmock.NewRows([]string{"app_id", "unknown_versions"}))
+			},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			got, err := NewPostgreSQLRepository(db).GetReleasesByAppID("COM.aerogear.app")
+
+			if err != tt.wantErr {
+				t.Errorf("appsPostgreSQLRepository.GetReleasesByAppID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appsPostgreSQLRepository.GetReleasesByAppID() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_appsPostgreSQLRepository_SetReleases(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	releases := models.Releases{AppID: "com.aerogear.app", UnknownVersions: models.UnknownVersionsBlock, Versions: []string{"1.0", "1.1"}}
+
+	tests := []struct {
+		name    string
+		expect  func()
+		wantErr bool
+	}{
+		{
+			name: "Should replace the released versions in a transaction",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE app SET unknown_versions=\$1 WHERE app_id=\$2;`).WithArgs(releases.UnknownVersions, releases.AppID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM released_version WHERE app_id=\$1;`).WithArgs(releases.AppID).WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectExec(`INSERT INTO released_version`).WithArgs(releases.AppID, "1.0").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO released_version`).WithArgs(releases.AppID, "1.1").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Should rollback when a statement fails",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE app SET unknown_versions=\$1 WHERE app_id=\$2;`).WithArgs(releases.UnknownVersions, releases.AppID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM released_version WHERE app_id=\$1;`).WithArgs(releases.AppID).WillReturnError(models.ErrDatabaseError)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			if err := NewPostgreSQLRepository(db).SetReleases(releases); (err != nil) != tt.wantErr {
+				t.Errorf("appsPostgreSQLRepository.SetReleases() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_appsPostgreSQLRepository_GetSigningPolicyByAppID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	const appID = "com.aerogear.app"
+	createdAt := time.Now().UTC()
+	certificate := models.SigningCertificate{
+		ID:          "9e1f3b2a-6c4d-4f8e-a7b5-2d1c0e9f8a7b",
+		AppID:       appID,
+		Platform:    models.PlatformAndroid,
+		Fingerprint: "146de983c5730650d8eeb9952f34fc6416a08342e61dbea88a0496b23fcf44e5",
+		Description: "Play Store",
+		CreatedAt:   createdAt,
+	}
+	columns := []string{"id", "app_id", "platform", "fingerprint", "description", "created_at"}
+
+	tests := []struct {
+		name    string
+		expect  func()
+		want    *models.SigningPolicy
+		wantErr error
+	}{
+		{
+			name: "Should return the certificates and the mode of the app",
+			expect: func() {
+				mock.ExpectQuery(`SELECT app_id,tampered_builds FROM app`).WithArgs(appID).
+					WillReturnRows(sqlmock.NewRows([]string{"app_id", "tampered_builds"}).AddRow(appID, models.TamperedBuildsBlock))
+				mock.ExpectQuery(`FROM signing_certificate`).WithArgs(appID).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(certificate.ID, appID, certificate.Platform, certificate.Fingerprint, certificate.Description, createdAt))
+			},
+			want: &models.SigningPolicy{AppID: appID, TamperedBuilds: models.TamperedBuildsBlock, Certificates: []models.SigningCertificate{certificate}},
+		},
+		{
+			name: "Should return an empty list when the app has no certificates",
+			expect: func() {
+				mock.ExpectQuery(`SELECT app_id,tampered_builds FROM app`).WithArgs(appID).
+					WillReturnRows(sqlmock.NewRows([]string{"app_id", "tampered_builds"}).AddRow(appID, models.TamperedBuildsFlag))
+				mock.ExpectQuery(`FROM signing_certificate`).WithArgs(appID).WillReturnRows(sqlmock.NewRows(columns))
+			},
+			want: &models.SigningPolicy{AppID: appID, TamperedBuilds: models.TamperedBuildsFlag, Certificates: []models.SigningCertificate{}},
+		},
+		{
+			name: "Should return ErrNotFound when the app is not found",
+			expect: func() {
+				mock.ExpectQuery(`SELECT app_id,tampered_builds FROM app`).WithArgs(appID).WillReturnRows(sqlmock.NewRows([]string{"app_id", "tampered_builds"}))
+			},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			got, err := NewPostgreSQLRepository(db).GetSigningPolicyByAppID("COM.aerogear.app")
+
+			if err != tt.wantErr {
+				t.Errorf("appsPostgreSQLRepository.GetSigningPolicyByAppID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appsPostgreSQLRepository.GetSigningPolicyByAppID() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_appsPostgreSQLRepository_CreateSigningCertificate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	certificate := models.SigningCertificate{
+		ID:          "9e1f3b2a-6c4d-4f8e-a7b5-2d1c0e9f8a7b",
+		AppID:       "com.aerogear.app",
+		Platform:    models.PlatformIOS,
+		Fingerprint: "146de983c5730650d8eeb9952f34fc6416a08342e61dbea88a0496b23fcf44e5",
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	tests := []struct {
+		name    string
+		created int64
+		wantErr error
+	}{
+		{
+			name:    "Should insert the certificate",
+			created: 1,
+		},
+		{
+			name:    "Should return ErrConflict when the app already has the certificate",
+			created: 0,
+			wantErr: models.ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectExec(`INSERT INTO signing_certificate`).
+				WithArgs(certificate.ID, certificate.AppID, certificate.Platform, certificate.Fingerprint, certificate.Description, certificate.CreatedAt).
+				WillReturnResult(sqlmock.NewResult(0, tt.created))
+
+			if err := NewPostgreSQLRepository(db).CreateSigningCertificate(certificate); err != tt.wantErr {
+				t.Errorf("appsPostgreSQLRepository.CreateSigningCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_appsPostgreSQLRepository_DeleteSigningCertificate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	const id = "9e1f3b2a-6c4d-4f8e-a7b5-2d1c0e9f8a7b"
+
+	tests := []struct {
+		name    string
+		deleted int64
+		wantErr error
+	}{
+		{
+			name:    "Should delete the certificate",
+			deleted: 1,
+		},
+		{
+			name:    "Should return ErrNotFound when the app has no such certificate",
+			deleted: 0,
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectExec(`DELETE FROM signing_certificate WHERE app_id=\$1 AND id=\$2;`).WithArgs("com.aerogear.app", id).
+				WillReturnResult(sqlmock.NewResult(0, tt.deleted))
+
+			if err := NewPostgreSQLRepository(db).DeleteSigningCertificate("com.aerogear.app", id); err != tt.wantErr {
+				t.Errorf("appsPostgreSQLRepository.DeleteSigningCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package apps
+
+import (
+	"context"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/lifecycle"
+	log "github.com/sirupsen/logrus"
+)
+
+type purgeWorker struct {
+	service   Service
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewPurgeWorker returns a background worker which hard deletes, every interval,
+// the apps that were soft deleted longer than the retention period ago
+func NewPurgeWorker(s Service, retention, interval time.Duration) lifecycle.Worker {
+	return &purgeWorker{
+		service:   s,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run purges the deleted apps until the context is cancelled
+func (w *purgeWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.purge()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *purgeWorker) purge() {
+	purged, err := w.service.PurgeDeletedApps(time.Now().Add(-w.retention))
+	if err != nil {
+		log.Errorf("Failed to purge the deleted apps: %v", err)
+		return
+	}
+
+	if purged > 0 {
+		log.WithField("apps", purged).Info("Purged deleted apps past the retention period")
+	}
+}
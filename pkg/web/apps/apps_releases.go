@@ -0,0 +1,86 @@
+package apps
+
+import (
+	"sort"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// UnknownVersionMessage is the disabled message returned by the init endpoint for a version which is
+// not among the released versions of an app which blocks the unknown versions
+const UnknownVersionMessage = "This version of the app was not released"
+
+// GetReleases returns the released versions declared for an active app by its id
+func (a *appsService) GetReleases(id string) (*models.Releases, error) {
+	app, err := a.repository.GetActiveAppByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.repository.GetReleasesByAppID(app.AppID)
+}
+
+// SetReleases replaces the released versions declared for an active app by its id and its mode for the other versions.
+// The versions already registered are left as they are, an unknown one is only flagged in the versions of the app.
+func (a *appsService) SetReleases(id string, releases models.Releases) error {
+	app, err := a.repository.GetActiveAppByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := validateUnknownVersions(releases.UnknownVersions); err != nil {
+		return err
+	}
+
+	releases.AppID = app.AppID
+	releases.Versions = uniqueVersions(releases.Versions)
+
+	return a.repository.SetReleases(releases)
+}
+
+// AddRelease declares a released version of an active app by its id, e.g. from the pipeline which publishes it
+func (a *appsService) AddRelease(id, version string) error {
+	app, err := a.repository.GetActiveAppByID(id)
+	if err != nil {
+		return err
+	}
+
+	if version == "" {
+		return models.ErrBadParamInput
+	}
+
+	return a.repository.AddReleasedVersion(app.AppID, version)
+}
+
+// isBlockedVersion is true when an app blocks the unknown versions and the version is not among its released versions
+func (a *appsService) isBlockedVersion(appID, version string) (bool, error) {
+	releases, err := a.repository.GetReleasesByAppID(appID)
+	if err != nil {
+		return false, err
+	}
+
+	return releases.UnknownVersions == models.UnknownVersionsBlock && !releases.IsReleased(version), nil
+}
+
+// validateUnknownVersions returns ErrBadParamInput when the mode for the unknown versions is not valid
+func validateUnknownVersions(mode string) error {
+	switch mode {
+	case models.UnknownVersionsAccept, models.UnknownVersionsFlag, models.UnknownVersionsBlock:
+		return nil
+	}
+	return models.ErrBadParamInput
+}
+
+// uniqueVersions returns the versions sorted and without duplicates or blanks
+func uniqueVersions(versions []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, v := range versions {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -0,0 +1,173 @@
+package apps
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+func Test_appsService_SetReleases(t *testing.T) {
+	app := helpers.GetMockApp()
+
+	tests := []struct {
+		name     string
+		id       string
+		releases models.Releases
+		want     *models.Releases
+		wantErr  error
+	}{
+		{
+			name:     "Should store the released versions sorted and without duplicates",
+			id:       app.ID,
+			releases: models.Releases{UnknownVersions: models.UnknownVersionsBlock, Versions: []string{"1.1", "1.0", "", "1.1"}},
+			want:     &models.Releases{AppID: app.AppID, UnknownVersions: models.UnknownVersionsBlock, Versions: []string{"1.0", "1.1"}},
+		},
+		{
+			name:     "Should clear the released versions",
+			id:       app.ID,
+			releases: models.Releases{UnknownVersions: models.UnknownVersionsFlag},
+			want:     &models.Releases{AppID: app.AppID, UnknownVersions: models.UnknownVersionsFlag, Versions: []string{}},
+		},
+		{
+			name:     "Should return ErrBadParamInput when the mode is not valid",
+			id:       app.ID,
+			releases: models.Releases{UnknownVersions: "reject"},
+			wantErr:  models.ErrBadParamInput,
+		},
+		{
+			name:     "Should return ErrNotFound when the app is not found",
+			id:       "53d4e6c6-6b7f-4a50-8d2c-0fe5b4c1a2b9",
+			releases: models.Releases{UnknownVersions: models.UnknownVersionsAccept},
+			wantErr:  models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := *mockRepositoryWithSuccessResults
+			repo.GetActiveAppByIDFunc = func(id string) (*models.App, error) {
+				if id != app.ID {
+					return nil, models.ErrNotFound
+				}
+				return helpers.GetMockApp(), nil
+			}
+
+			err := NewService(&repo, nil).SetReleases(tt.id, tt.releases)
+			if err != tt.wantErr {
+				t.Fatalf("appsService.SetReleases() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			calls := repo.SetReleasesCalls()
+			if tt.want == nil {
+				if len(calls) > 0 {
+					t.Errorf("appsService.SetReleases() stored %v, want nothing stored", calls[0].Releases)
+				}
+				return
+			}
+			if len(calls) != 1 || !reflect.DeepEqual(calls[0].Releases, *tt.want) {
+				t.Errorf("appsService.SetReleases() stored %v, want %v", calls, *tt.want)
+			}
+		})
+	}
+}
+
+func Test_appsService_InitClientApp_unknownVersions(t *testing.T) {
+	app := helpers.GetMockApp()
+
+	tests := []struct {
+		name        string
+		mode        string
+		released    []string
+		version     string
+		existing    bool
+		wantBlocked bool
+	}{
+		{
+			name:    "Should register any version when the app declares no released versions",
+			mode:    models.UnknownVersionsBlock,
+			version: "1.0-typo",
+		},
+		{
+			name:     "Should register a released version",
+			mode:     models.UnknownVersionsBlock,
+			released: []string{"1.0", "1.1"},
+			version:  "1.1",
+		},
+		{
+			name:     "Should register an unknown version when the app flags them",
+			mode:     models.UnknownVersionsFlag,
+			released: []string{"1.0"},
+			version:  "1.0-typo",
+		},
+		{
+			name:     "Should register an unknown version when the app accepts them",
+			mode:     models.UnknownVersionsAccept,
+			released: []string{"1.0"},
+			version:  "1.0-typo",
+		},
+		{
+			name:        "Should disable an unknown version without registering it when the app blocks them",
+			mode:        models.UnknownVersionsBlock,
+			released:    []string{"1.0"},
+			version:     "1.0-typo",
+			wantBlocked: true,
+		},
+		{
+			name:        "Should disable an unreleased version seen before the app blocked them without registering the launch",
+			mode:        models.UnknownVersionsBlock,
+			released:    []string{"1.0"},
+			version:     "1.0-typo",
+			existing:    true,
+			wantBlocked: true,
+		},
+		{
+			name:     "Should register a released version seen before the app blocked the unknown versions",
+			mode:     models.UnknownVersionsBlock,
+			released: []string{"1.0"},
+			version:  "1.0",
+			existing: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := *mockRepositoryWithSuccessResults
+			repo.GetVersionByAppIDAndVersionFunc = func(appID string, version string) (*models.Version, error) {
+				if tt.existing {
+					return &models.Version{ID: "55ebd387-9c68-4137-a367-a12025cc2cdb", AppID: appID, Version: version}, nil
+				}
+				return nil, models.ErrNotFound
+			}
+			repo.GetReleasesByAppIDFunc = func(appID string) (*models.Releases, error) {
+				return &models.Releases{AppID: appID, UnknownVersions: tt.mode, Versions: tt.released}, nil
+			}
+			repo.UpsertVersionWithAppLaunchesAndLastLaunchedFunc = func(version *models.Version) error {
+				return nil
+			}
+			repo.GetDeviceByDeviceIDAndAppIDFunc = func(deviceID string, appID string) (*models.Device, error) {
+				return nil, models.ErrNotFound
+			}
+			repo.InsertDeviceOrUpdateVersionIDFunc = func(device models.Device) error {
+				return nil
+			}
+
+			device := &models.Device{AppID: app.AppID, DeviceID: "a742f8b7-5e2f-43f4-a5b8-2e8bb3e5a3ad", DeviceType: "Android", Version: tt.version}
+			got, err := NewService(&repo, nil).InitClientApp(device)
+			if err != nil {
+				t.Fatalf("appsService.InitClientApp() error = %v", err)
+			}
+
+			if got.Disabled != tt.wantBlocked {
+				t.Errorf("appsService.InitClientApp() disabled = %v, want %v", got.Disabled, tt.wantBlocked)
+			}
+			if tt.wantBlocked && got.DisabledMessage != UnknownVersionMessage {
+				t.Errorf("appsService.InitClientApp() disabledMessage = %v, want %v", got.DisabledMessage, UnknownVersionMessage)
+			}
+
+			registered := len(repo.UpsertVersionWithAppLaunchesAndLastLaunchedCalls()) + len(repo.InsertDeviceOrUpdateVersionIDCalls())
+			if tt.wantBlocked != (registered == 0) {
+				t.Errorf("appsService.InitClientApp() made %v writes, want blocked %v", registered, tt.wantBlocked)
+			}
+		})
+	}
+}
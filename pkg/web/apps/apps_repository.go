@@ -1,19 +1,22 @@
 package apps
 
 import (
+	"time"
+
 	"github.com/aerogear/mobile-security-service/pkg/models"
 )
 
 // Repository represent the app's repository contract
 type Repository interface {
-	GetApps() (*[]models.App, error)
+	GetApps(query models.AppsQuery) (*models.AppsPage, error)
 	GetActiveAppByID(ID string) (*models.App, error)
 	GetAppVersionsByAppID(ID string) (*[]models.Version, error)
 	UpdateAppVersions(versions []models.Version) error
 	DisableAllAppVersionsByAppID(appID string) error
 	DisableAllAppVersionsAndSetDisabledMessageByAppID(appID, message string) error
 	DeleteAppById(id string) error
-	CreateApp(id, appId, name string) error
+	CreateApp(id, appId, name, tenantID string) error
+	GetAppByID(id string) (*models.App, error)
 	GetAppByAppID(appID string) (*models.App, error)
 	GetActiveAppByAppID(appID string) (*models.App, error)
 	UnDeleteAppByAppID(appID string) error
@@ -23,4 +26,15 @@ type Repository interface {
 	GetDeviceByVersionAndAppID(versionID string, appID string) (*models.Device, error)
 	UpsertVersionWithAppLaunchesAndLastLaunched(version *models.Version) error
 	InsertDeviceOrUpdateVersionID(device models.Device) error
+	PurgeDeletedApps(before time.Time) (int64, error)
+	HardDeleteAppByID(id string, dryRun bool) (*models.AppHardDelete, error)
+	GetManagedApps() ([]models.App, error)
+	SetAppManaged(appID string, managed bool) error
+	GetReleasesByAppID(appID string) (*models.Releases, error)
+	SetReleases(releases models.Releases) error
+	AddReleasedVersion(appID, version string) error
+	GetSigningPolicyByAppID(appID string) (*models.SigningPolicy, error)
+	CreateSigningCertificate(certificate models.SigningCertificate) error
+	DeleteSigningCertificate(appID, id string) error
+	SetTamperedBuilds(appID, mode string) error
 }
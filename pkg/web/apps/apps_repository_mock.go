@@ -6,22 +6,35 @@ package apps
 import (
 	"github.com/aerogear/mobile-security-service/pkg/models"
 	"sync"
+	"time"
 )
 
 var (
+	lockRepositoryMockAddReleasedVersion                                sync.RWMutex
 	lockRepositoryMockCreateApp                                         sync.RWMutex
+	lockRepositoryMockCreateSigningCertificate                          sync.RWMutex
 	lockRepositoryMockDeleteAppById                                     sync.RWMutex
+	lockRepositoryMockDeleteSigningCertificate                          sync.RWMutex
 	lockRepositoryMockDisableAllAppVersionsAndSetDisabledMessageByAppID sync.RWMutex
 	lockRepositoryMockDisableAllAppVersionsByAppID                      sync.RWMutex
 	lockRepositoryMockGetActiveAppByAppID                               sync.RWMutex
 	lockRepositoryMockGetActiveAppByID                                  sync.RWMutex
 	lockRepositoryMockGetAppByAppID                                     sync.RWMutex
+	lockRepositoryMockGetAppByID                                        sync.RWMutex
 	lockRepositoryMockGetAppVersionsByAppID                             sync.RWMutex
 	lockRepositoryMockGetApps                                           sync.RWMutex
 	lockRepositoryMockGetDeviceByDeviceIDAndAppID                       sync.RWMutex
 	lockRepositoryMockGetDeviceByVersionAndAppID                        sync.RWMutex
+	lockRepositoryMockGetManagedApps                                    sync.RWMutex
+	lockRepositoryMockGetReleasesByAppID                                sync.RWMutex
+	lockRepositoryMockGetSigningPolicyByAppID                           sync.RWMutex
 	lockRepositoryMockGetVersionByAppIDAndVersion                       sync.RWMutex
+	lockRepositoryMockHardDeleteAppByID                                 sync.RWMutex
 	lockRepositoryMockInsertDeviceOrUpdateVersionID                     sync.RWMutex
+	lockRepositoryMockPurgeDeletedApps                                  sync.RWMutex
+	lockRepositoryMockSetAppManaged                                     sync.RWMutex
+	lockRepositoryMockSetReleases                                       sync.RWMutex
+	lockRepositoryMockSetTamperedBuilds                                 sync.RWMutex
 	lockRepositoryMockUnDeleteAppByAppID                                sync.RWMutex
 	lockRepositoryMockUpdateAppNameByID                                 sync.RWMutex
 	lockRepositoryMockUpdateAppVersions                                 sync.RWMutex
@@ -34,74 +47,119 @@ var _ Repository = &RepositoryMock{}
 
 // RepositoryMock is a mock implementation of Repository.
 //
-//     func TestSomethingThatUsesRepository(t *testing.T) {
-//
-//         // make and configure a mocked Repository
-//         mockedRepository := &RepositoryMock{
-//             CreateAppFunc: func(id string, appId string, name string) error {
-// 	               panic("mock out the CreateApp method")
-//             },
-//             DeleteAppByIdFunc: func(id string) error {
-// 	               panic("mock out the DeleteAppById method")
-//             },
-//             DisableAllAppVersionsAndSetDisabledMessageByAppIDFunc: func(appID string, message string) error {
-// 	               panic("mock out the DisableAllAppVersionsAndSetDisabledMessageByAppID method")
-//             },
-//             DisableAllAppVersionsByAppIDFunc: func(appID string) error {
-// 	               panic("mock out the DisableAllAppVersionsByAppID method")
-//             },
-//             GetActiveAppByAppIDFunc: func(appID string) (*models.App, error) {
-// 	               panic("mock out the GetActiveAppByAppID method")
-//             },
-//             GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
-// 	               panic("mock out the GetActiveAppByID method")
-//             },
-//             GetAppByAppIDFunc: func(appID string) (*models.App, error) {
-// 	               panic("mock out the GetAppByAppID method")
-//             },
-//             GetAppVersionsByAppIDFunc: func(ID string) (*[]models.Version, error) {
-// 	               panic("mock out the GetAppVersionsByAppID method")
-//             },
-//             GetAppsFunc: func() (*[]models.App, error) {
-// 	               panic("mock out the GetApps method")
-//             },
-//             GetDeviceByDeviceIDAndAppIDFunc: func(deviceID string, appID string) (*models.Device, error) {
-// 	               panic("mock out the GetDeviceByDeviceIDAndAppID method")
-//             },
-//             GetDeviceByVersionAndAppIDFunc: func(versionID string, appID string) (*models.Device, error) {
-// 	               panic("mock out the GetDeviceByVersionAndAppID method")
-//             },
-//             GetVersionByAppIDAndVersionFunc: func(appID string, versionNumber string) (*models.Version, error) {
-// 	               panic("mock out the GetVersionByAppIDAndVersion method")
-//             },
-//             InsertDeviceOrUpdateVersionIDFunc: func(device models.Device) error {
-// 	               panic("mock out the InsertDeviceOrUpdateVersionID method")
-//             },
-//             UnDeleteAppByAppIDFunc: func(appID string) error {
-// 	               panic("mock out the UnDeleteAppByAppID method")
-//             },
-//             UpdateAppNameByIDFunc: func(id string, name string) error {
-// 	               panic("mock out the UpdateAppNameByID method")
-//             },
-//             UpdateAppVersionsFunc: func(versions []models.Version) error {
-// 	               panic("mock out the UpdateAppVersions method")
-//             },
-//             UpsertVersionWithAppLaunchesAndLastLaunchedFunc: func(version *models.Version) error {
-// 	               panic("mock out the UpsertVersionWithAppLaunchesAndLastLaunched method")
-//             },
-//         }
-//
-//         // use mockedRepository in code that requires Repository
-//         // and then make assertions.
-//
-//     }
+//	    func TestSomethingThatUsesRepository(t *testing.T) {
+//
+//	        // make and configure a mocked Repository
+//	        mockedRepository := &RepositoryMock{
+//	            AddReleasedVersionFunc: func(appID string, version string) error {
+//		               panic("mock out the AddReleasedVersion method")
+//	            },
+//	            CreateAppFunc: func(id string, appId string, name string, tenantID string) error {
+//		               panic("mock out the CreateApp method")
+//	            },
+//	            CreateSigningCertificateFunc: func(certificate models.SigningCertificate) error {
+//		               panic("mock out the CreateSigningCertificate method")
+//	            },
+//	            DeleteAppByIdFunc: func(id string) error {
+//		               panic("mock out the DeleteAppById method")
+//	            },
+//	            DeleteSigningCertificateFunc: func(appID string, id string) error {
+//		               panic("mock out the DeleteSigningCertificate method")
+//	            },
+//	            DisableAllAppVersionsAndSetDisabledMessageByAppIDFunc: func(appID string, message string) error {
+//		               panic("mock out the DisableAllAppVersionsAndSetDisabledMessageByAppID method")
+//	            },
+//	            DisableAllAppVersionsByAppIDFunc: func(appID string) error {
+//		               panic("mock out the DisableAllAppVersionsByAppID method")
+//	            },
+//	            GetActiveAppByAppIDFunc: func(appID string) (*models.App, error) {
+//		               panic("mock out the GetActiveAppByAppID method")
+//	            },
+//	            GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
+//		               panic("mock out the GetActiveAppByID method")
+//	            },
+//	            GetAppByAppIDFunc: func(appID string) (*models.App, error) {
+//		               panic("mock out the GetAppByAppID method")
+//	            },
+//	            GetAppByIDFunc: func(id string) (*models.App, error) {
+//		               panic("mock out the GetAppByID method")
+//	            },
+//	            GetAppVersionsByAppIDFunc: func(ID string) (*[]models.Version, error) {
+//		               panic("mock out the GetAppVersionsByAppID method")
+//	            },
+//	            GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+//		               panic("mock out the GetApps method")
+//	            },
+//	            GetDeviceByDeviceIDAndAppIDFunc: func(deviceID string, appID string) (*models.Device, error) {
+//		               panic("mock out the GetDeviceByDeviceIDAndAppID method")
+//	            },
+//	            GetDeviceByVersionAndAppIDFunc: func(versionID string, appID string) (*models.Device, error) {
+//		               panic("mock out the GetDeviceByVersionAndAppID method")
+//	            },
+//	            GetManagedAppsFunc: func() ([]models.App, error) {
+//		               panic("mock out the GetManagedApps method")
+//	            },
+//	            GetReleasesByAppIDFunc: func(appID string) (*models.Releases, error) {
+//		               panic("mock out the GetReleasesByAppID method")
+//	            },
+//	            GetSigningPolicyByAppIDFunc: func(appID string) (*models.SigningPolicy, error) {
+//		               panic("mock out the GetSigningPolicyByAppID method")
+//	            },
+//	            GetVersionByAppIDAndVersionFunc: func(appID string, versionNumber string) (*models.Version, error) {
+//		               panic("mock out the GetVersionByAppIDAndVersion method")
+//	            },
+//	            HardDeleteAppByIDFunc: func(id string, dryRun bool) (*models.AppHardDelete, error) {
+//		               panic("mock out the HardDeleteAppByID method")
+//	            },
+//	            InsertDeviceOrUpdateVersionIDFunc: func(device models.Device) error {
+//		               panic("mock out the InsertDeviceOrUpdateVersionID method")
+//	            },
+//	            PurgeDeletedAppsFunc: func(before time.Time) (int64, error) {
+//		               panic("mock out the PurgeDeletedApps method")
+//	            },
+//	            SetAppManagedFunc: func(appID string, managed bool) error {
+//		               panic("mock out the SetAppManaged method")
+//	            },
+//	            SetReleasesFunc: func(releases models.Releases) error {
+//		               panic("mock out the SetReleases method")
+//	            },
+//	            SetTamperedBuildsFunc: func(appID string, mode string) error {
+//		               panic("mock out the SetTamperedBuilds method")
+//	            },
+//	            UnDeleteAppByAppIDFunc: func(appID string) error {
+//		               panic("mock out the UnDeleteAppByAppID method")
+//	            },
+//	            UpdateAppNameByIDFunc: func(id string, name string) error {
+//		               panic("mock out the UpdateAppNameByID method")
+//	            },
+//	            UpdateAppVersionsFunc: func(versions []models.Version) error {
+//		               panic("mock out the UpdateAppVersions method")
+//	            },
+//	            UpsertVersionWithAppLaunchesAndLastLaunchedFunc: func(version *models.Version) error {
+//		               panic("mock out the UpsertVersionWithAppLaunchesAndLastLaunched method")
+//	            },
+//	        }
+//
+//	        // use mockedRepository in code that requires Repository
+//	        // and then make assertions.
+//
+//	    }
 type RepositoryMock struct {
+	// AddReleasedVersionFunc mocks the AddReleasedVersion method.
+	AddReleasedVersionFunc func(appID string, version string) error
+
 	// CreateAppFunc mocks the CreateApp method.
-	CreateAppFunc func(id string, appId string, name string) error
+	CreateAppFunc func(id string, appId string, name string, tenantID string) error
+
+	// CreateSigningCertificateFunc mocks the CreateSigningCertificate method.
+	CreateSigningCertificateFunc func(certificate models.SigningCertificate) error
 
 	// DeleteAppByIdFunc mocks the DeleteAppById method.
 	DeleteAppByIdFunc func(id string) error
 
+	// DeleteSigningCertificateFunc mocks the DeleteSigningCertificate method.
+	DeleteSigningCertificateFunc func(appID string, id string) error
+
 	// DisableAllAppVersionsAndSetDisabledMessageByAppIDFunc mocks the DisableAllAppVersionsAndSetDisabledMessageByAppID method.
 	DisableAllAppVersionsAndSetDisabledMessageByAppIDFunc func(appID string, message string) error
 
@@ -117,11 +175,14 @@ type RepositoryMock struct {
 	// GetAppByAppIDFunc mocks the GetAppByAppID method.
 	GetAppByAppIDFunc func(appID string) (*models.App, error)
 
+	// GetAppByIDFunc mocks the GetAppByID method.
+	GetAppByIDFunc func(id string) (*models.App, error)
+
 	// GetAppVersionsByAppIDFunc mocks the GetAppVersionsByAppID method.
 	GetAppVersionsByAppIDFunc func(ID string) (*[]models.Version, error)
 
 	// GetAppsFunc mocks the GetApps method.
-	GetAppsFunc func() (*[]models.App, error)
+	GetAppsFunc func(query models.AppsQuery) (*models.AppsPage, error)
 
 	// GetDeviceByDeviceIDAndAppIDFunc mocks the GetDeviceByDeviceIDAndAppID method.
 	GetDeviceByDeviceIDAndAppIDFunc func(deviceID string, appID string) (*models.Device, error)
@@ -129,12 +190,36 @@ type RepositoryMock struct {
 	// GetDeviceByVersionAndAppIDFunc mocks the GetDeviceByVersionAndAppID method.
 	GetDeviceByVersionAndAppIDFunc func(versionID string, appID string) (*models.Device, error)
 
+	// GetManagedAppsFunc mocks the GetManagedApps method.
+	GetManagedAppsFunc func() ([]models.App, error)
+
+	// GetReleasesByAppIDFunc mocks the GetReleasesByAppID method.
+	GetReleasesByAppIDFunc func(appID string) (*models.Releases, error)
+
+	// GetSigningPolicyByAppIDFunc mocks the GetSigningPolicyByAppID method.
+	GetSigningPolicyByAppIDFunc func(appID string) (*models.SigningPolicy, error)
+
 	// GetVersionByAppIDAndVersionFunc mocks the GetVersionByAppIDAndVersion method.
 	GetVersionByAppIDAndVersionFunc func(appID string, versionNumber string) (*models.Version, error)
 
+	// HardDeleteAppByIDFunc mocks the HardDeleteAppByID method.
+	HardDeleteAppByIDFunc func(id string, dryRun bool) (*models.AppHardDelete, error)
+
 	// InsertDeviceOrUpdateVersionIDFunc mocks the InsertDeviceOrUpdateVersionID method.
 	InsertDeviceOrUpdateVersionIDFunc func(device models.Device) error
 
+	// PurgeDeletedAppsFunc mocks the PurgeDeletedApps method.
+	PurgeDeletedAppsFunc func(before time.Time) (int64, error)
+
+	// SetAppManagedFunc mocks the SetAppManaged method.
+	SetAppManagedFunc func(appID string, managed bool) error
+
+	// SetReleasesFunc mocks the SetReleases method.
+	SetReleasesFunc func(releases models.Releases) error
+
+	// SetTamperedBuildsFunc mocks the SetTamperedBuilds method.
+	SetTamperedBuildsFunc func(appID string, mode string) error
+
 	// UnDeleteAppByAppIDFunc mocks the UnDeleteAppByAppID method.
 	UnDeleteAppByAppIDFunc func(appID string) error
 
@@ -149,6 +234,13 @@ type RepositoryMock struct {
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// AddReleasedVersion holds details about calls to the AddReleasedVersion method.
+		AddReleasedVersion []struct {
+			// AppID is the appID argument value.
+			AppID string
+			// Version is the version argument value.
+			Version string
+		}
 		// CreateApp holds details about calls to the CreateApp method.
 		CreateApp []struct {
 			// ID is the id argument value.
@@ -157,12 +249,26 @@ type RepositoryMock struct {
 			AppId string
 			// Name is the name argument value.
 			Name string
+			// TenantID is the tenantID argument value.
+			TenantID string
+		}
+		// CreateSigningCertificate holds details about calls to the CreateSigningCertificate method.
+		CreateSigningCertificate []struct {
+			// Certificate is the certificate argument value.
+			Certificate models.SigningCertificate
 		}
 		// DeleteAppById holds details about calls to the DeleteAppById method.
 		DeleteAppById []struct {
 			// ID is the id argument value.
 			ID string
 		}
+		// DeleteSigningCertificate holds details about calls to the DeleteSigningCertificate method.
+		DeleteSigningCertificate []struct {
+			// AppID is the appID argument value.
+			AppID string
+			// ID is the id argument value.
+			ID string
+		}
 		// DisableAllAppVersionsAndSetDisabledMessageByAppID holds details about calls to the DisableAllAppVersionsAndSetDisabledMessageByAppID method.
 		DisableAllAppVersionsAndSetDisabledMessageByAppID []struct {
 			// AppID is the appID argument value.
@@ -190,6 +296,11 @@ type RepositoryMock struct {
 			// AppID is the appID argument value.
 			AppID string
 		}
+		// GetAppByID holds details about calls to the GetAppByID method.
+		GetAppByID []struct {
+			// ID is the id argument value.
+			ID string
+		}
 		// GetAppVersionsByAppID holds details about calls to the GetAppVersionsByAppID method.
 		GetAppVersionsByAppID []struct {
 			// ID is the ID argument value.
@@ -197,6 +308,8 @@ type RepositoryMock struct {
 		}
 		// GetApps holds details about calls to the GetApps method.
 		GetApps []struct {
+			// Query is the query argument value.
+			Query models.AppsQuery
 		}
 		// GetDeviceByDeviceIDAndAppID holds details about calls to the GetDeviceByDeviceIDAndAppID method.
 		GetDeviceByDeviceIDAndAppID []struct {
@@ -212,6 +325,19 @@ type RepositoryMock struct {
 			// AppID is the appID argument value.
 			AppID string
 		}
+		// GetManagedApps holds details about calls to the GetManagedApps method.
+		GetManagedApps []struct {
+		}
+		// GetReleasesByAppID holds details about calls to the GetReleasesByAppID method.
+		GetReleasesByAppID []struct {
+			// AppID is the appID argument value.
+			AppID string
+		}
+		// GetSigningPolicyByAppID holds details about calls to the GetSigningPolicyByAppID method.
+		GetSigningPolicyByAppID []struct {
+			// AppID is the appID argument value.
+			AppID string
+		}
 		// GetVersionByAppIDAndVersion holds details about calls to the GetVersionByAppIDAndVersion method.
 		GetVersionByAppIDAndVersion []struct {
 			// AppID is the appID argument value.
@@ -219,11 +345,42 @@ type RepositoryMock struct {
 			// VersionNumber is the versionNumber argument value.
 			VersionNumber string
 		}
+		// HardDeleteAppByID holds details about calls to the HardDeleteAppByID method.
+		HardDeleteAppByID []struct {
+			// ID is the id argument value.
+			ID string
+			// DryRun is the dryRun argument value.
+			DryRun bool
+		}
 		// InsertDeviceOrUpdateVersionID holds details about calls to the InsertDeviceOrUpdateVersionID method.
 		InsertDeviceOrUpdateVersionID []struct {
 			// Device is the device argument value.
 			Device models.Device
 		}
+		// PurgeDeletedApps holds details about calls to the PurgeDeletedApps method.
+		PurgeDeletedApps []struct {
+			// Before is the before argument value.
+			Before time.Time
+		}
+		// SetAppManaged holds details about calls to the SetAppManaged method.
+		SetAppManaged []struct {
+			// AppID is the appID argument value.
+			AppID string
+			// Managed is the managed argument value.
+			Managed bool
+		}
+		// SetReleases holds details about calls to the SetReleases method.
+		SetReleases []struct {
+			// Releases is the releases argument value.
+			Releases models.Releases
+		}
+		// SetTamperedBuilds holds details about calls to the SetTamperedBuilds method.
+		SetTamperedBuilds []struct {
+			// AppID is the appID argument value.
+			AppID string
+			// Mode is the mode argument value.
+			Mode string
+		}
 		// UnDeleteAppByAppID holds details about calls to the UnDeleteAppByAppID method.
 		UnDeleteAppByAppID []struct {
 			// AppID is the appID argument value.
@@ -249,38 +406,79 @@ type RepositoryMock struct {
 	}
 }
 
+// AddReleasedVersion calls AddReleasedVersionFunc.
+func (mock *RepositoryMock) AddReleasedVersion(appID string, version string) error {
+	if mock.AddReleasedVersionFunc == nil {
+		panic("RepositoryMock.AddReleasedVersionFunc: method is nil but Repository.AddReleasedVersion was just called")
+	}
+	callInfo := struct {
+		AppID   string
+		Version string
+	}{
+		AppID:   appID,
+		Version: version,
+	}
+	lockRepositoryMockAddReleasedVersion.Lock()
+	mock.calls.AddReleasedVersion = append(mock.calls.AddReleasedVersion, callInfo)
+	lockRepositoryMockAddReleasedVersion.Unlock()
+	return mock.AddReleasedVersionFunc(appID, version)
+}
+
+// AddReleasedVersionCalls gets all the calls that were made to AddReleasedVersion.
+// Check the length with:
+//
+//	len(mockedRepository.AddReleasedVersionCalls())
+func (mock *RepositoryMock) AddReleasedVersionCalls() []struct {
+	AppID   string
+	Version string
+} {
+	var calls []struct {
+		AppID   string
+		Version string
+	}
+	lockRepositoryMockAddReleasedVersion.RLock()
+	calls = mock.calls.AddReleasedVersion
+	lockRepositoryMockAddReleasedVersion.RUnlock()
+	return calls
+}
+
 // CreateApp calls CreateAppFunc.
-func (mock *RepositoryMock) CreateApp(id string, appId string, name string) error {
+func (mock *RepositoryMock) CreateApp(id string, appId string, name string, tenantID string) error {
 	if mock.CreateAppFunc == nil {
 		panic("RepositoryMock.CreateAppFunc: method is nil but Repository.CreateApp was just called")
 	}
 	callInfo := struct {
-		ID    string
-		AppId string
-		Name  string
+		ID       string
+		AppId    string
+		Name     string
+		TenantID string
 	}{
-		ID:    id,
-		AppId: appId,
-		Name:  name,
+		ID:       id,
+		AppId:    appId,
+		Name:     name,
+		TenantID: tenantID,
 	}
 	lockRepositoryMockCreateApp.Lock()
 	mock.calls.CreateApp = append(mock.calls.CreateApp, callInfo)
 	lockRepositoryMockCreateApp.Unlock()
-	return mock.CreateAppFunc(id, appId, name)
+	return mock.CreateAppFunc(id, appId, name, tenantID)
 }
 
 // CreateAppCalls gets all the calls that were made to CreateApp.
 // Check the length with:
-//     len(mockedRepository.CreateAppCalls())
+//
+//	len(mockedRepository.CreateAppCalls())
 func (mock *RepositoryMock) CreateAppCalls() []struct {
-	ID    string
-	AppId string
-	Name  string
+	ID       string
+	AppId    string
+	Name     string
+	TenantID string
 } {
 	var calls []struct {
-		ID    string
-		AppId string
-		Name  string
+		ID       string
+		AppId    string
+		Name     string
+		TenantID string
 	}
 	lockRepositoryMockCreateApp.RLock()
 	calls = mock.calls.CreateApp
@@ -288,6 +486,38 @@ func (mock *RepositoryMock) CreateAppCalls() []struct {
 	return calls
 }
 
+// CreateSigningCertificate calls CreateSigningCertificateFunc.
+func (mock *RepositoryMock) CreateSigningCertificate(certificate models.SigningCertificate) error {
+	if mock.CreateSigningCertificateFunc == nil {
+		panic("RepositoryMock.CreateSigningCertificateFunc: method is nil but Repository.CreateSigningCertificate was just called")
+	}
+	callInfo := struct {
+		Certificate models.SigningCertificate
+	}{
+		Certificate: certificate,
+	}
+	lockRepositoryMockCreateSigningCertificate.Lock()
+	mock.calls.CreateSigningCertificate = append(mock.calls.CreateSigningCertificate, callInfo)
+	lockRepositoryMockCreateSigningCertificate.Unlock()
+	return mock.CreateSigningCertificateFunc(certificate)
+}
+
+// CreateSigningCertificateCalls gets all the calls that were made to CreateSigningCertificate.
+// Check the length with:
+//
+//	len(mockedRepository.CreateSigningCertificateCalls())
+func (mock *RepositoryMock) CreateSigningCertificateCalls() []struct {
+	Certificate models.SigningCertificate
+} {
+	var calls []struct {
+		Certificate models.SigningCertificate
+	}
+	lockRepositoryMockCreateSigningCertificate.RLock()
+	calls = mock.calls.CreateSigningCertificate
+	lockRepositoryMockCreateSigningCertificate.RUnlock()
+	return calls
+}
+
 // DeleteAppById calls DeleteAppByIdFunc.
 func (mock *RepositoryMock) DeleteAppById(id string) error {
 	if mock.DeleteAppByIdFunc == nil {
@@ -306,7 +536,8 @@ func (mock *RepositoryMock) DeleteAppById(id string) error {
 
 // DeleteAppByIdCalls gets all the calls that were made to DeleteAppById.
 // Check the length with:
-//     len(mockedRepository.DeleteAppByIdCalls())
+//
+//	len(mockedRepository.DeleteAppByIdCalls())
 func (mock *RepositoryMock) DeleteAppByIdCalls() []struct {
 	ID string
 } {
@@ -319,6 +550,42 @@ func (mock *RepositoryMock) DeleteAppByIdCalls() []struct {
 	return calls
 }
 
+// DeleteSigningCertificate calls DeleteSigningCertificateFunc.
+func (mock *RepositoryMock) DeleteSigningCertificate(appID string, id string) error {
+	if mock.DeleteSigningCertificateFunc == nil {
+		panic("RepositoryMock.DeleteSigningCertificateFunc: method is nil but Repository.DeleteSigningCertificate was just called")
+	}
+	callInfo := struct {
+		AppID string
+		ID    string
+	}{
+		AppID: appID,
+		ID:    id,
+	}
+	lockRepositoryMockDeleteSigningCertificate.Lock()
+	mock.calls.DeleteSigningCertificate = append(mock.calls.DeleteSigningCertificate, callInfo)
+	lockRepositoryMockDeleteSigningCertificate.Unlock()
+	return mock.DeleteSigningCertificateFunc(appID, id)
+}
+
+// DeleteSigningCertificateCalls gets all the calls that were made to DeleteSigningCertificate.
+// Check the length with:
+//
+//	len(mockedRepository.DeleteSigningCertificateCalls())
+func (mock *RepositoryMock) DeleteSigningCertificateCalls() []struct {
+	AppID string
+	ID    string
+} {
+	var calls []struct {
+		AppID string
+		ID    string
+	}
+	lockRepositoryMockDeleteSigningCertificate.RLock()
+	calls = mock.calls.DeleteSigningCertificate
+	lockRepositoryMockDeleteSigningCertificate.RUnlock()
+	return calls
+}
+
 // DisableAllAppVersionsAndSetDisabledMessageByAppID calls DisableAllAppVersionsAndSetDisabledMessageByAppIDFunc.
 func (mock *RepositoryMock) DisableAllAppVersionsAndSetDisabledMessageByAppID(appID string, message string) error {
 	if mock.DisableAllAppVersionsAndSetDisabledMessageByAppIDFunc == nil {
@@ -339,7 +606,8 @@ func (mock *RepositoryMock) DisableAllAppVersionsAndSetDisabledMessageByAppID(ap
 
 // DisableAllAppVersionsAndSetDisabledMessageByAppIDCalls gets all the calls that were made to DisableAllAppVersionsAndSetDisabledMessageByAppID.
 // Check the length with:
-//     len(mockedRepository.DisableAllAppVersionsAndSetDisabledMessageByAppIDCalls())
+//
+//	len(mockedRepository.DisableAllAppVersionsAndSetDisabledMessageByAppIDCalls())
 func (mock *RepositoryMock) DisableAllAppVersionsAndSetDisabledMessageByAppIDCalls() []struct {
 	AppID   string
 	Message string
@@ -372,7 +640,8 @@ func (mock *RepositoryMock) DisableAllAppVersionsByAppID(appID string) error {
 
 // DisableAllAppVersionsByAppIDCalls gets all the calls that were made to DisableAllAppVersionsByAppID.
 // Check the length with:
-//     len(mockedRepository.DisableAllAppVersionsByAppIDCalls())
+//
+//	len(mockedRepository.DisableAllAppVersionsByAppIDCalls())
 func (mock *RepositoryMock) DisableAllAppVersionsByAppIDCalls() []struct {
 	AppID string
 } {
@@ -403,7 +672,8 @@ func (mock *RepositoryMock) GetActiveAppByAppID(appID string) (*models.App, erro
 
 // GetActiveAppByAppIDCalls gets all the calls that were made to GetActiveAppByAppID.
 // Check the length with:
-//     len(mockedRepository.GetActiveAppByAppIDCalls())
+//
+//	len(mockedRepository.GetActiveAppByAppIDCalls())
 func (mock *RepositoryMock) GetActiveAppByAppIDCalls() []struct {
 	AppID string
 } {
@@ -434,7 +704,8 @@ func (mock *RepositoryMock) GetActiveAppByID(ID string) (*models.App, error) {
 
 // GetActiveAppByIDCalls gets all the calls that were made to GetActiveAppByID.
 // Check the length with:
-//     len(mockedRepository.GetActiveAppByIDCalls())
+//
+//	len(mockedRepository.GetActiveAppByIDCalls())
 func (mock *RepositoryMock) GetActiveAppByIDCalls() []struct {
 	ID string
 } {
@@ -465,7 +736,8 @@ func (mock *RepositoryMock) GetAppByAppID(appID string) (*models.App, error) {
 
 // GetAppByAppIDCalls gets all the calls that were made to GetAppByAppID.
 // Check the length with:
-//     len(mockedRepository.GetAppByAppIDCalls())
+//
+//	len(mockedRepository.GetAppByAppIDCalls())
 func (mock *RepositoryMock) GetAppByAppIDCalls() []struct {
 	AppID string
 } {
@@ -478,6 +750,38 @@ func (mock *RepositoryMock) GetAppByAppIDCalls() []struct {
 	return calls
 }
 
+// GetAppByID calls GetAppByIDFunc.
+func (mock *RepositoryMock) GetAppByID(id string) (*models.App, error) {
+	if mock.GetAppByIDFunc == nil {
+		panic("RepositoryMock.GetAppByIDFunc: method is nil but Repository.GetAppByID was just called")
+	}
+	callInfo := struct {
+		ID string
+	}{
+		ID: id,
+	}
+	lockRepositoryMockGetAppByID.Lock()
+	mock.calls.GetAppByID = append(mock.calls.GetAppByID, callInfo)
+	lockRepositoryMockGetAppByID.Unlock()
+	return mock.GetAppByIDFunc(id)
+}
+
+// GetAppByIDCalls gets all the calls that were made to GetAppByID.
+// Check the length with:
+//
+//	len(mockedRepository.GetAppByIDCalls())
+func (mock *RepositoryMock) GetAppByIDCalls() []struct {
+	ID string
+} {
+	var calls []struct {
+		ID string
+	}
+	lockRepositoryMockGetAppByID.RLock()
+	calls = mock.calls.GetAppByID
+	lockRepositoryMockGetAppByID.RUnlock()
+	return calls
+}
+
 // GetAppVersionsByAppID calls GetAppVersionsByAppIDFunc.
 func (mock *RepositoryMock) GetAppVersionsByAppID(ID string) (*[]models.Version, error) {
 	if mock.GetAppVersionsByAppIDFunc == nil {
@@ -496,7 +800,8 @@ func (mock *RepositoryMock) GetAppVersionsByAppID(ID string) (*[]models.Version,
 
 // GetAppVersionsByAppIDCalls gets all the calls that were made to GetAppVersionsByAppID.
 // Check the length with:
-//     len(mockedRepository.GetAppVersionsByAppIDCalls())
+//
+//	len(mockedRepository.GetAppVersionsByAppIDCalls())
 func (mock *RepositoryMock) GetAppVersionsByAppIDCalls() []struct {
 	ID string
 } {
@@ -510,24 +815,30 @@ func (mock *RepositoryMock) GetAppVersionsByAppIDCalls() []struct {
 }
 
 // GetApps calls GetAppsFunc.
-func (mock *RepositoryMock) GetApps() (*[]models.App, error) {
+func (mock *RepositoryMock) GetApps(query models.AppsQuery) (*models.AppsPage, error) {
 	if mock.GetAppsFunc == nil {
 		panic("RepositoryMock.GetAppsFunc: method is nil but Repository.GetApps was just called")
 	}
 	callInfo := struct {
-	}{}
+		Query models.AppsQuery
+	}{
+		Query: query,
+	}
 	lockRepositoryMockGetApps.Lock()
 	mock.calls.GetApps = append(mock.calls.GetApps, callInfo)
 	lockRepositoryMockGetApps.Unlock()
-	return mock.GetAppsFunc()
+	return mock.GetAppsFunc(query)
 }
 
 // GetAppsCalls gets all the calls that were made to GetApps.
 // Check the length with:
-//     len(mockedRepository.GetAppsCalls())
+//
+//	len(mockedRepository.GetAppsCalls())
 func (mock *RepositoryMock) GetAppsCalls() []struct {
+	Query models.AppsQuery
 } {
 	var calls []struct {
+		Query models.AppsQuery
 	}
 	lockRepositoryMockGetApps.RLock()
 	calls = mock.calls.GetApps
@@ -555,7 +866,8 @@ func (mock *RepositoryMock) GetDeviceByDeviceIDAndAppID(deviceID string, appID s
 
 // GetDeviceByDeviceIDAndAppIDCalls gets all the calls that were made to GetDeviceByDeviceIDAndAppID.
 // Check the length with:
-//     len(mockedRepository.GetDeviceByDeviceIDAndAppIDCalls())
+//
+//	len(mockedRepository.GetDeviceByDeviceIDAndAppIDCalls())
 func (mock *RepositoryMock) GetDeviceByDeviceIDAndAppIDCalls() []struct {
 	DeviceID string
 	AppID    string
@@ -590,7 +902,8 @@ func (mock *RepositoryMock) GetDeviceByVersionAndAppID(versionID string, appID s
 
 // GetDeviceByVersionAndAppIDCalls gets all the calls that were made to GetDeviceByVersionAndAppID.
 // Check the length with:
-//     len(mockedRepository.GetDeviceByVersionAndAppIDCalls())
+//
+//	len(mockedRepository.GetDeviceByVersionAndAppIDCalls())
 func (mock *RepositoryMock) GetDeviceByVersionAndAppIDCalls() []struct {
 	VersionID string
 	AppID     string
@@ -605,6 +918,97 @@ func (mock *RepositoryMock) GetDeviceByVersionAndAppIDCalls() []struct {
 	return calls
 }
 
+// GetManagedApps calls GetManagedAppsFunc.
+func (mock *RepositoryMock) GetManagedApps() ([]models.App, error) {
+	if mock.GetManagedAppsFunc == nil {
+		panic("RepositoryMock.GetManagedAppsFunc: method is nil but Repository.GetManagedApps was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockRepositoryMockGetManagedApps.Lock()
+	mock.calls.GetManagedApps = append(mock.calls.GetManagedApps, callInfo)
+	lockRepositoryMockGetManagedApps.Unlock()
+	return mock.GetManagedAppsFunc()
+}
+
+// GetManagedAppsCalls gets all the calls that were made to GetManagedApps.
+// Check the length with:
+//
+//	len(mockedRepository.GetManagedAppsCalls())
+func (mock *RepositoryMock) GetManagedAppsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockRepositoryMockGetManagedApps.RLock()
+	calls = mock.calls.GetManagedApps
+	lockRepositoryMockGetManagedApps.RUnlock()
+	return calls
+}
+
+// GetReleasesByAppID calls GetReleasesByAppIDFunc.
+func (mock *RepositoryMock) GetReleasesByAppID(appID string) (*models.Releases, error) {
+	if mock.GetReleasesByAppIDFunc == nil {
+		panic("RepositoryMock.GetReleasesByAppIDFunc: method is nil but Repository.GetReleasesByAppID was just called")
+	}
+	callInfo := struct {
+		AppID string
+	}{
+		AppID: appID,
+	}
+	lockRepositoryMockGetReleasesByAppID.Lock()
+	mock.calls.GetReleasesByAppID = append(mock.calls.GetReleasesByAppID, callInfo)
+	lockRepositoryMockGetReleasesByAppID.Unlock()
+	return mock.GetReleasesByAppIDFunc(appID)
+}
+
+// GetReleasesByAppIDCalls gets all the calls that were made to GetReleasesByAppID.
+// Check the length with:
+//
+//	len(mockedRepository.GetReleasesByAppIDCalls())
+func (mock *RepositoryMock) GetReleasesByAppIDCalls() []struct {
+	AppID string
+} {
+	var calls []struct {
+		AppID string
+	}
+	lockRepositoryMockGetReleasesByAppID.RLock()
+	calls = mock.calls.GetReleasesByAppID
+	lockRepositoryMockGetReleasesByAppID.RUnlock()
+	return calls
+}
+
+// GetSigningPolicyByAppID calls GetSigningPolicyByAppIDFunc.
+func (mock *RepositoryMock) GetSigningPolicyByAppID(appID string) (*models.SigningPolicy, error) {
+	if mock.GetSigningPolicyByAppIDFunc == nil {
+		panic("RepositoryMock.GetSigningPolicyByAppIDFunc: method is nil but Repository.GetSigningPolicyByAppID was just called")
+	}
+	callInfo := struct {
+		AppID string
+	}{
+		AppID: appID,
+	}
+	lockRepositoryMockGetSigningPolicyByAppID.Lock()
+	mock.calls.GetSigningPolicyByAppID = append(mock.calls.GetSigningPolicyByAppID, callInfo)
+	lockRepositoryMockGetSigningPolicyByAppID.Unlock()
+	return mock.GetSigningPolicyByAppIDFunc(appID)
+}
+
+// GetSigningPolicyByAppIDCalls gets all the calls that were made to GetSigningPolicyByAppID.
+// Check the length with:
+//
+//	len(mockedRepository.GetSigningPolicyByAppIDCalls())
+func (mock *RepositoryMock) GetSigningPolicyByAppIDCalls() []struct {
+	AppID string
+} {
+	var calls []struct {
+		AppID string
+	}
+	lockRepositoryMockGetSigningPolicyByAppID.RLock()
+	calls = mock.calls.GetSigningPolicyByAppID
+	lockRepositoryMockGetSigningPolicyByAppID.RUnlock()
+	return calls
+}
+
 // GetVersionByAppIDAndVersion calls GetVersionByAppIDAndVersionFunc.
 func (mock *RepositoryMock) GetVersionByAppIDAndVersion(appID string, versionNumber string) (*models.Version, error) {
 	if mock.GetVersionByAppIDAndVersionFunc == nil {
@@ -625,7 +1029,8 @@ func (mock *RepositoryMock) GetVersionByAppIDAndVersion(appID string, versionNum
 
 // GetVersionByAppIDAndVersionCalls gets all the calls that were made to GetVersionByAppIDAndVersion.
 // Check the length with:
-//     len(mockedRepository.GetVersionByAppIDAndVersionCalls())
+//
+//	len(mockedRepository.GetVersionByAppIDAndVersionCalls())
 func (mock *RepositoryMock) GetVersionByAppIDAndVersionCalls() []struct {
 	AppID         string
 	VersionNumber string
@@ -640,6 +1045,42 @@ func (mock *RepositoryMock) GetVersionByAppIDAndVersionCalls() []struct {
 	return calls
 }
 
+// HardDeleteAppByID calls HardDeleteAppByIDFunc.
+func (mock *RepositoryMock) HardDeleteAppByID(id string, dryRun bool) (*models.AppHardDelete, error) {
+	if mock.HardDeleteAppByIDFunc == nil {
+		panic("RepositoryMock.HardDeleteAppByIDFunc: method is nil but Repository.HardDeleteAppByID was just called")
+	}
+	callInfo := struct {
+		ID     string
+		DryRun bool
+	}{
+		ID:     id,
+		DryRun: dryRun,
+	}
+	lockRepositoryMockHardDeleteAppByID.Lock()
+	mock.calls.HardDeleteAppByID = append(mock.calls.HardDeleteAppByID, callInfo)
+	lockRepositoryMockHardDeleteAppByID.Unlock()
+	return mock.HardDeleteAppByIDFunc(id, dryRun)
+}
+
+// HardDeleteAppByIDCalls gets all the calls that were made to HardDeleteAppByID.
+// Check the length with:
+//
+//	len(mockedRepository.HardDeleteAppByIDCalls())
+func (mock *RepositoryMock) HardDeleteAppByIDCalls() []struct {
+	ID     string
+	DryRun bool
+} {
+	var calls []struct {
+		ID     string
+		DryRun bool
+	}
+	lockRepositoryMockHardDeleteAppByID.RLock()
+	calls = mock.calls.HardDeleteAppByID
+	lockRepositoryMockHardDeleteAppByID.RUnlock()
+	return calls
+}
+
 // InsertDeviceOrUpdateVersionID calls InsertDeviceOrUpdateVersionIDFunc.
 func (mock *RepositoryMock) InsertDeviceOrUpdateVersionID(device models.Device) error {
 	if mock.InsertDeviceOrUpdateVersionIDFunc == nil {
@@ -658,7 +1099,8 @@ func (mock *RepositoryMock) InsertDeviceOrUpdateVersionID(device models.Device)
 
 // InsertDeviceOrUpdateVersionIDCalls gets all the calls that were made to InsertDeviceOrUpdateVersionID.
 // Check the length with:
-//     len(mockedRepository.InsertDeviceOrUpdateVersionIDCalls())
+//
+//	len(mockedRepository.InsertDeviceOrUpdateVersionIDCalls())
 func (mock *RepositoryMock) InsertDeviceOrUpdateVersionIDCalls() []struct {
 	Device models.Device
 } {
@@ -671,6 +1113,142 @@ func (mock *RepositoryMock) InsertDeviceOrUpdateVersionIDCalls() []struct {
 	return calls
 }
 
+// PurgeDeletedApps calls PurgeDeletedAppsFunc.
+func (mock *RepositoryMock) PurgeDeletedApps(before time.Time) (int64, error) {
+	if mock.PurgeDeletedAppsFunc == nil {
+		panic("RepositoryMock.PurgeDeletedAppsFunc: method is nil but Repository.PurgeDeletedApps was just called")
+	}
+	callInfo := struct {
+		Before time.Time
+	}{
+		Before: before,
+	}
+	lockRepositoryMockPurgeDeletedApps.Lock()
+	mock.calls.PurgeDeletedApps = append(mock.calls.PurgeDeletedApps, callInfo)
+	lockRepositoryMockPurgeDeletedApps.Unlock()
+	return mock.PurgeDeletedAppsFunc(before)
+}
+
+// PurgeDeletedAppsCalls gets all the calls that were made to PurgeDeletedApps.
+// Check the length with:
+//
+//	len(mockedRepository.PurgeDeletedAppsCalls())
+func (mock *RepositoryMock) PurgeDeletedAppsCalls() []struct {
+	Before time.Time
+} {
+	var calls []struct {
+		Before time.Time
+	}
+	lockRepositoryMockPurgeDeletedApps.RLock()
+	calls = mock.calls.PurgeDeletedApps
+	lockRepositoryMockPurgeDeletedApps.RUnlock()
+	return calls
+}
+
+// SetAppManaged calls SetAppManagedFunc.
+func (mock *RepositoryMock) SetAppManaged(appID string, managed bool) error {
+	if mock.SetAppManagedFunc == nil {
+		panic("RepositoryMock.SetAppManagedFunc: method is nil but Repository.SetAppManaged was just called")
+	}
+	callInfo := struct {
+		AppID   string
+		Managed bool
+	}{
+		AppID:   appID,
+		Managed: managed,
+	}
+	lockRepositoryMockSetAppManaged.Lock()
+	mock.calls.SetAppManaged = append(mock.calls.SetAppManaged, callInfo)
+	lockRepositoryMockSetAppManaged.Unlock()
+	return mock.SetAppManagedFunc(appID, managed)
+}
+
+// SetAppManagedCalls gets all the calls that were made to SetAppManaged.
+// Check the length with:
+//
+//	len(mockedRepository.SetAppManagedCalls())
+func (mock *RepositoryMock) SetAppManagedCalls() []struct {
+	AppID   string
+	Managed bool
+} {
+	var calls []struct {
+		AppID   string
+		Managed bool
+	}
+	lockRepositoryMockSetAppManaged.RLock()
+	calls = mock.calls.SetAppManaged
+	lockRepositoryMockSetAppManaged.RUnlock()
+	return calls
+}
+
+// SetReleases calls SetReleasesFunc.
+func (mock *RepositoryMock) SetReleases(releases models.Releases) error {
+	if mock.SetReleasesFunc == nil {
+		panic("RepositoryMock.SetReleasesFunc: method is nil but Repository.SetReleases was just called")
+	}
+	callInfo := struct {
+		Releases models.Releases
+	}{
+		Releases: releases,
+	}
+	lockRepositoryMockSetReleases.Lock()
+	mock.calls.SetReleases = append(mock.calls.SetReleases, callInfo)
+	lockRepositoryMockSetReleases.Unlock()
+	return mock.SetReleasesFunc(releases)
+}
+
+// SetReleasesCalls gets all the calls that were made to SetReleases.
+// Check the length with:
+//
+//	len(mockedRepository.SetReleasesCalls())
+func (mock *RepositoryMock) SetReleasesCalls() []struct {
+	Releases models.Releases
+} {
+	var calls []struct {
+		Releases models.Releases
+	}
+	lockRepositoryMockSetReleases.RLock()
+	calls = mock.calls.SetReleases
+	lockRepositoryMockSetReleases.RUnlock()
+	return calls
+}
+
+// SetTamperedBuilds calls SetTamperedBuildsFunc.
+func (mock *RepositoryMock) SetTamperedBuilds(appID string, mode string) error {
+	if mock.SetTamperedBuildsFunc == nil {
+		panic("RepositoryMock.SetTamperedBuildsFunc: method is nil but Repository.SetTamperedBuilds was just called")
+	}
+	callInfo := struct {
+		AppID string
+		Mode  string
+	}{
+		AppID: appID,
+		Mode:  mode,
+	}
+	lockRepositoryMockSetTamperedBuilds.Lock()
+	mock.calls.SetTamperedBuilds = append(mock.calls.SetTamperedBuilds, callInfo)
+	lockRepositoryMockSetTamperedBuilds.Unlock()
+	return mock.SetTamperedBuildsFunc(appID, mode)
+}
+
+// SetTamperedBuildsCalls gets all the calls that were made to SetTamperedBuilds.
+// Check the length with:
+//
+//	len(mockedRepository.SetTamperedBuildsCalls())
+func (mock *RepositoryMock) SetTamperedBuildsCalls() []struct {
+	AppID string
+	Mode  string
+} {
+	var calls []struct {
+		AppID string
+		Mode  string
+	}
+	lockRepositoryMockSetTamperedBuilds.RLock()
+	calls = mock.calls.SetTamperedBuilds
+	lockRepositoryMockSetTamperedBuilds.RUnlock()
+	return calls
+}
+
 // UnDeleteAppByAppID calls UnDeleteAppByAppIDFunc.
 func (mock *RepositoryMock) UnDeleteAppByAppID(appID string) error {
 	if mock.UnDeleteAppByAppIDFunc == nil {
@@ -689,7 +1267,8 @@ func (mock *RepositoryMock) UnDeleteAppByAppID(appID string) error {
 
 // UnDeleteAppByAppIDCalls gets all the calls that were made to UnDeleteAppByAppID.
 // Check the length with:
-//     len(mockedRepository.UnDeleteAppByAppIDCalls())
+//
+//	len(mockedRepository.UnDeleteAppByAppIDCalls())
 func (mock *RepositoryMock) UnDeleteAppByAppIDCalls() []struct {
 	AppID string
 } {
@@ -722,7 +1301,8 @@ func (mock *RepositoryMock) UpdateAppNameByID(id string, name string) error {
 
 // UpdateAppNameByIDCalls gets all the calls that were made to UpdateAppNameByID.
 // Check the length with:
-//     len(mockedRepository.UpdateAppNameByIDCalls())
+//
+//	len(mockedRepository.UpdateAppNameByIDCalls())
 func (mock *RepositoryMock) UpdateAppNameByIDCalls() []struct {
 	ID   string
 	Name string
@@ -755,7 +1335,8 @@ func (mock *RepositoryMock) UpdateAppVersions(versions []models.Version) error {
 
 // UpdateAppVersionsCalls gets all the calls that were made to UpdateAppVersions.
 // Check the length with:
-//     len(mockedRepository.UpdateAppVersionsCalls())
+//
+//	len(mockedRepository.UpdateAppVersionsCalls())
 func (mock *RepositoryMock) UpdateAppVersionsCalls() []struct {
 	Versions []models.Version
 } {
@@ -786,7 +1367,8 @@ func (mock *RepositoryMock) UpsertVersionWithAppLaunchesAndLastLaunched(version
 
 // UpsertVersionWithAppLaunchesAndLastLaunchedCalls gets all the calls that were made to UpsertVersionWithAppLaunchesAndLastLaunched.
 // Check the length with:
-//     len(mockedRepository.UpsertVersionWithAppLaunchesAndLastLaunchedCalls())
+//
+//	len(mockedRepository.UpsertVersionWithAppLaunchesAndLastLaunchedCalls())
 func (mock *RepositoryMock) UpsertVersionWithAppLaunchesAndLastLaunchedCalls() []struct {
 	Version *models.Version
 } {
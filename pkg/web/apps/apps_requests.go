@@ -0,0 +1,240 @@
+package apps
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/labstack/echo"
+)
+
+// The requests of the apps endpoints, with the validation rules of each field.
+// They are validated with the validator registered in echo before being mapped to the models.
+
+// getAppsRequest holds the query parameters of GET /apps
+type getAppsRequest struct {
+	AppID   string `query:"appId"`
+	Limit   int    `query:"limit" validate:"min=0,max=100"`
+	Cursor  string `query:"cursor"`
+	Sort    string `query:"sort" validate:"omitempty,oneof=name -name launches -launches installs -installs"`
+	Search  string `query:"q"`
+	Deleted bool   `query:"deleted"`
+	// TenantID only returns the apps of a tenant of the user
+	TenantID string `query:"tenantId" validate:"omitempty,uuid"`
+}
+
+// newGetAppsRequest reads the query parameters of GET /apps
+func newGetAppsRequest(c echo.Context) (*getAppsRequest, error) {
+	r := &getAppsRequest{
+		AppID:    c.QueryParam("appId"),
+		Cursor:   c.QueryParam("cursor"),
+		Sort:     c.QueryParam("sort"),
+		Search:   c.QueryParam("q"),
+		TenantID: c.QueryParam("tenantId"),
+	}
+
+	if limit := c.QueryParam("limit"); limit != "" {
+		var err error
+		if r.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, invalidQueryParam("limit", "limit must be a number")
+		}
+	}
+
+	if deleted := c.QueryParam("deleted"); deleted != "" {
+		var err error
+		if r.Deleted, err = strconv.ParseBool(deleted); err != nil {
+			return nil, invalidQueryParam("deleted", "deleted must be a boolean")
+		}
+	}
+
+	return r, nil
+}
+
+// toModel maps the request to the query of the apps service
+func (r *getAppsRequest) toModel() models.AppsQuery {
+	return models.AppsQuery{
+		Limit:   r.Limit,
+		Cursor:  r.Cursor,
+		Sort:    r.Sort,
+		Search:  r.Search,
+		Deleted: r.Deleted,
+	}
+}
+
+// appIDParams holds the id path parameter of the endpoints of a single app
+type appIDParams struct {
+	ID string `param:"id" validate:"uuid"`
+}
+
+// hardDeleteAppRequest holds the parameters of POST /apps/{id}/purge
+type hardDeleteAppRequest struct {
+	ID     string `param:"id" validate:"uuid"`
+	DryRun bool   `query:"dryRun"`
+}
+
+// newHardDeleteAppRequest reads the parameters of POST /apps/{id}/purge
+func newHardDeleteAppRequest(c echo.Context) (*hardDeleteAppRequest, error) {
+	r := &hardDeleteAppRequest{ID: c.Param("id")}
+
+	if dryRun := c.QueryParam("dryRun"); dryRun != "" {
+		var err error
+		if r.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			return nil, invalidQueryParam("dryRun", "dryRun must be a boolean")
+		}
+	}
+
+	return r, nil
+}
+
+// exportAppsRequest holds the query parameters of GET /export
+type exportAppsRequest struct {
+	Format string `query:"format" validate:"omitempty,oneof=json yaml"`
+}
+
+// importAppsRequest holds the query parameters of POST /import. The body is an appsPolicyDocument.
+type importAppsRequest struct {
+	DryRun bool `query:"dryRun"`
+}
+
+// newImportAppsRequest reads the query parameters of POST /import
+func newImportAppsRequest(c echo.Context) (*importAppsRequest, error) {
+	r := &importAppsRequest{}
+
+	if dryRun := c.QueryParam("dryRun"); dryRun != "" {
+		var err error
+		if r.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			return nil, invalidQueryParam("dryRun", "dryRun must be a boolean")
+		}
+	}
+
+	return r, nil
+}
+
+// createAppRequest is the body of POST /apps
+// swagger:model CreateAppRequest
+type createAppRequest struct {
+	// required: true
+	AppID   string `json:"appId" validate:"required,appid"`
+	AppName string `json:"appName"`
+	// TenantID is the tenant which owns the app. It is only required when the tenants are enabled
+	// and the user is a member of several tenants.
+	TenantID string `json:"tenantId" validate:"omitempty,uuid"`
+}
+
+// toModel maps the request to the app to create
+func (r *createAppRequest) toModel() models.App {
+	app := models.NewAppByNameAndAppID(r.AppName, r.AppID)
+	app.TenantID = r.TenantID
+	return *app
+}
+
+// updateAppNameRequest is the body of PATCH /apps/{id}
+// swagger:model UpdateAppNameRequest
+type updateAppNameRequest struct {
+	// required: true
+	AppName string `json:"appName" validate:"required"`
+}
+
+// updateVersionRequest is a single version in the body of PUT /apps/{id}/versions
+// swagger:model UpdateVersionRequest
+type updateVersionRequest struct {
+	// required: true
+	ID string `json:"id" validate:"required,uuid"`
+	// required: true
+	AppID           string `json:"appId" validate:"required"`
+	Version         string `json:"version"`
+	Disabled        bool   `json:"disabled"`
+	DisabledMessage string `json:"disabledMessage"`
+}
+
+// updateAppVersionsRequest is the body of PUT /apps/{id}/versions, sent as a JSON array of versions
+type updateAppVersionsRequest struct {
+	Versions []updateVersionRequest `json:"versions" validate:"required,min=1,dive"`
+}
+
+// UnmarshalJSON decodes the JSON array of versions
+func (r *updateAppVersionsRequest) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &r.Versions)
+}
+
+// toModel maps the request to the versions to update
+func (r *updateAppVersionsRequest) toModel() []models.Version {
+	versions := make([]models.Version, 0, len(r.Versions))
+	for _, v := range r.Versions {
+		versions = append(versions, models.Version{
+			ID:              v.ID,
+			AppID:           v.AppID,
+			Version:         v.Version,
+			Disabled:        v.Disabled,
+			DisabledMessage: v.DisabledMessage,
+		})
+	}
+	return versions
+}
+
+// disableAppVersionsRequest is the body of POST /apps/{id}/versions/disable
+// swagger:model DisableAppVersionsRequest
+type disableAppVersionsRequest struct {
+	DisabledMessage string `json:"disabledMessage"`
+}
+
+// setReleasesRequest is the body of PUT /apps/{id}/releases
+// swagger:model SetReleasesRequest
+type setReleasesRequest struct {
+	// UnknownVersions is accept, flag or block
+	// required: true
+	UnknownVersions string   `json:"unknownVersions" validate:"required,oneof=accept flag block"`
+	Versions        []string `json:"versions" validate:"dive,required"`
+}
+
+// toModel maps the request to the released versions of the app
+func (r *setReleasesRequest) toModel() models.Releases {
+	return models.Releases{UnknownVersions: r.UnknownVersions, Versions: r.Versions}
+}
+
+// addReleaseRequest is the body of POST /apps/{id}/releases
+// swagger:model AddReleaseRequest
+type addReleaseRequest struct {
+	// required: true
+	Version string `json:"version" validate:"required"`
+}
+
+// signingCertificateParams holds the path parameters of DELETE /apps/{id}/certificates/{certificateId}
+type signingCertificateParams struct {
+	ID            string `param:"id" validate:"uuid"`
+	CertificateID string `param:"certificateId" validate:"uuid"`
+}
+
+// createSigningCertificateRequest is the body of POST /apps/{id}/certificates
+// swagger:model CreateSigningCertificateRequest
+type createSigningCertificateRequest struct {
+	// Platform is android or ios
+	// required: true
+	Platform string `json:"platform" validate:"required,oneof=android ios"`
+	// Fingerprint is the SHA-256 fingerprint of the certificate in hex, with or without colons
+	// required: true
+	Fingerprint string `json:"fingerprint" validate:"required,fingerprint"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+// toModel maps the request to the signing certificate to register
+func (r *createSigningCertificateRequest) toModel() models.SigningCertificate {
+	return models.SigningCertificate{Platform: r.Platform, Fingerprint: r.Fingerprint, Description: r.Description}
+}
+
+// setTamperedBuildsRequest is the body of PUT /apps/{id}/certificates/mode
+// swagger:model SetTamperedBuildsRequest
+type setTamperedBuildsRequest struct {
+	// TamperedBuilds is flag or block
+	// required: true
+	TamperedBuilds string `json:"tamperedBuilds" validate:"required,oneof=flag block"`
+}
+
+// invalidQueryParam returns a bad request error for a query parameter which could not be parsed
+func invalidQueryParam(field, message string) error {
+	return &models.Error{
+		Code:    models.CodeBadParamInput,
+		Message: "Invalid parameters supplied",
+		Fields:  []models.FieldError{{Field: field, Message: message}},
+	}
+}
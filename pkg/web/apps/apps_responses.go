@@ -0,0 +1,341 @@
+package apps
+
+import (
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// The responses of the apps endpoints. They are mapped from the models so the
+// persistence fields are not sent to the clients unless they are part of the API.
+
+// appResponse is an app returned by GET /apps and GET /apps/{id}
+// swagger:model AppResponse
+type appResponse struct {
+	ID                    string             `json:"id"`
+	AppID                 string             `json:"appId"`
+	AppName               string             `json:"appName,omitempty"`
+	NumOfDeployedVersions *int               `json:"numOfDeployedVersions,omitempty"`
+	NumOfCurrentInstalls  *int               `json:"numOfCurrentInstalls,omitempty"`
+	NumOfAppLaunches      *int               `json:"numOfAppLaunches,omitempty"`
+	DeployedVersions      *[]versionResponse `json:"deployedVersions,omitempty"`
+	DeletedAt             string             `json:"deletedAt,omitempty"`
+	// TenantID is the tenant which owns the app
+	TenantID string `json:"tenantId,omitempty"`
+}
+
+// appsPageResponse is a page of apps returned by GET /v2/apps
+// swagger:model AppsPageResponse
+type appsPageResponse struct {
+	Apps []appResponse `json:"apps"`
+	// NextCursor is the cursor of the following page. It is not set on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// versionResponse is a version of an app with its usage metrics
+// swagger:model VersionResponse
+type versionResponse struct {
+	ID                   string `json:"id"`
+	Version              string `json:"version"`
+	AppID                string `json:"appId"`
+	Disabled             bool   `json:"disabled"`
+	DisabledMessage      string `json:"disabledMessage"`
+	NumOfCurrentInstalls int64  `json:"numOfCurrentInstalls,omitempty"`
+	NumOfAppLaunches     int64  `json:"numOfAppLaunches,omitempty"`
+	LastLaunchedAt       string `json:"lastLaunchedAt,omitempty"`
+	// Unknown is true when the app flags the versions which are not among its released versions and this is one of them
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// archivedVersionResponse is a version in the archive of a hard deleted app, with its devices
+// swagger:model ArchivedVersionResponse
+type archivedVersionResponse struct {
+	versionResponse
+	Devices []deviceResponse `json:"devices"`
+}
+
+// deviceResponse is a device in the archive of a hard deleted app
+// swagger:model DeviceResponse
+type deviceResponse struct {
+	ID            string `json:"id"`
+	DeviceID      string `json:"deviceId"`
+	DeviceVersion string `json:"deviceVersion"`
+	DeviceType    string `json:"deviceType"`
+}
+
+// archivedAppResponse is a hard deleted app with all its versions
+// swagger:model ArchivedAppResponse
+type archivedAppResponse struct {
+	ID               string                    `json:"id"`
+	AppID            string                    `json:"appId"`
+	AppName          string                    `json:"appName,omitempty"`
+	DeletedAt        string                    `json:"deletedAt,omitempty"`
+	DeployedVersions []archivedVersionResponse `json:"deployedVersions"`
+}
+
+// archivedWebhookResponse is a webhook in the archive of a hard deleted app, without its secret
+// swagger:model ArchivedWebhookResponse
+type archivedWebhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// archivedAlertResponse is an alert in the archive of a hard deleted app
+// swagger:model ArchivedAlertResponse
+type archivedAlertResponse struct {
+	ID              string    `json:"id"`
+	Kind            string    `json:"kind"`
+	Subject         string    `json:"subject,omitempty"`
+	Value           int64     `json:"value"`
+	Threshold       int64     `json:"threshold"`
+	FirstDetectedAt time.Time `json:"firstDetectedAt"`
+	LastDetectedAt  time.Time `json:"lastDetectedAt"`
+}
+
+// appArchiveResponse is the export of a hard deleted app
+// swagger:model AppArchiveResponse
+type appArchiveResponse struct {
+	ExportedAt    string                    `json:"exportedAt"`
+	App           archivedAppResponse       `json:"app"`
+	Releases      releasesResponse          `json:"releases"`
+	SigningPolicy signingPolicyResponse     `json:"signingPolicy"`
+	Webhooks      []archivedWebhookResponse `json:"webhooks"`
+	Alerts        []archivedAlertResponse   `json:"alerts"`
+}
+
+// hardDeleteAppResponse is the response of POST /apps/{id}/purge
+// swagger:model HardDeleteAppResponse
+type hardDeleteAppResponse struct {
+	// DryRun is true when nothing was deleted and only the counts are reported
+	DryRun              bool `json:"dryRun"`
+	Versions            int  `json:"versions"`
+	Devices             int  `json:"devices"`
+	ReleasedVersions    int  `json:"releasedVersions"`
+	SigningCertificates int  `json:"signingCertificates"`
+	Webhooks            int  `json:"webhooks"`
+	Alerts              int  `json:"alerts"`
+	// Archive is the data of the app which was deleted. It is not set on a dry run.
+	Archive *appArchiveResponse `json:"archive,omitempty"`
+}
+
+// policyChangeResponse is a change made by the import of a policy document, or which would be made on a dry run
+// swagger:model PolicyChangeResponse
+type policyChangeResponse struct {
+	// Action is create, restore, update or skip
+	Action string `json:"action"`
+	AppID  string `json:"appId"`
+	// Version is set for the changes of a version
+	Version string `json:"version,omitempty"`
+	// Field is the name of the field updated, e.g. appName or disabled
+	Field string `json:"field,omitempty"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+	// Reason explains why the change is skipped
+	Reason string `json:"reason,omitempty"`
+}
+
+// importAppsResponse is the response of POST /import
+// swagger:model ImportAppsResponse
+type importAppsResponse struct {
+	// DryRun is true when nothing was changed and only the changes which would be made are reported
+	DryRun  bool                   `json:"dryRun"`
+	Changes []policyChangeResponse `json:"changes"`
+}
+
+// releasesResponse is the response of GET /apps/{id}/releases
+// swagger:model ReleasesResponse
+type releasesResponse struct {
+	// UnknownVersions is accept, flag or block
+	UnknownVersions string   `json:"unknownVersions"`
+	Versions        []string `json:"versions"`
+}
+
+// signingCertificateResponse is a signing certificate registered for an app
+// swagger:model SigningCertificateResponse
+type signingCertificateResponse struct {
+	ID string `json:"id"`
+	// Platform is android or ios
+	Platform string `json:"platform"`
+	// Fingerprint is the SHA-256 fingerprint of the certificate in lowercase hex without colons
+	Fingerprint string    `json:"fingerprint"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// signingPolicyResponse is the response of GET /apps/{id}/certificates
+// swagger:model SigningPolicyResponse
+type signingPolicyResponse struct {
+	// TamperedBuilds is flag or block
+	TamperedBuilds string                       `json:"tamperedBuilds"`
+	Certificates   []signingCertificateResponse `json:"certificates"`
+}
+
+// newAppResponse maps an app to its response
+func newAppResponse(app models.App) appResponse {
+	r := appResponse{
+		ID:                    app.ID,
+		AppID:                 app.AppID,
+		AppName:               app.AppName,
+		NumOfDeployedVersions: app.NumOfDeployedVersions,
+		NumOfCurrentInstalls:  app.NumOfCurrentInstalls,
+		NumOfAppLaunches:      app.NumOfAppLaunches,
+		DeletedAt:             app.DeletedAt,
+		TenantID:              app.TenantID,
+	}
+
+	if app.DeployedVersions != nil {
+		versions := make([]versionResponse, 0, len(*app.DeployedVersions))
+		for _, v := range *app.DeployedVersions {
+			versions = append(versions, newVersionResponse(v))
+		}
+		r.DeployedVersions = &versions
+	}
+
+	return r
+}
+
+// newAppsResponse maps a list of apps to their responses
+func newAppsResponse(apps []models.App) []appResponse {
+	r := make([]appResponse, 0, len(apps))
+	for _, app := range apps {
+		r = append(r, newAppResponse(app))
+	}
+	return r
+}
+
+// newAppsPageResponse maps a page of apps to its response
+func newAppsPageResponse(page models.AppsPage) appsPageResponse {
+	return appsPageResponse{
+		Apps:       newAppsResponse(page.Apps),
+		NextCursor: page.NextCursor,
+	}
+}
+
+// newVersionResponse maps a version to its response
+func newVersionResponse(v models.Version) versionResponse {
+	return versionResponse{
+		ID:                   v.ID,
+		Version:              v.Version,
+		AppID:                v.AppID,
+		Disabled:             v.Disabled,
+		DisabledMessage:      v.DisabledMessage,
+		NumOfCurrentInstalls: v.NumOfCurrentInstalls,
+		NumOfAppLaunches:     v.NumOfAppLaunches,
+		LastLaunchedAt:       v.LastLaunchedAt,
+		Unknown:              v.Unknown,
+	}
+}
+
+// newHardDeleteAppResponse maps the outcome of a hard delete to its response
+func newHardDeleteAppResponse(result models.AppHardDelete) hardDeleteAppResponse {
+	r := hardDeleteAppResponse{
+		DryRun:              result.DryRun,
+		Versions:            result.Versions,
+		Devices:             result.Devices,
+		ReleasedVersions:    result.ReleasedVersions,
+		SigningCertificates: result.SigningCertificates,
+		Webhooks:            result.Webhooks,
+		Alerts:              result.Alerts,
+	}
+
+	if result.Archive == nil {
+		return r
+	}
+
+	app := result.Archive.App
+	archived := archivedAppResponse{
+		ID:               app.ID,
+		AppID:            app.AppID,
+		AppName:          app.AppName,
+		DeletedAt:        app.DeletedAt,
+		DeployedVersions: []archivedVersionResponse{},
+	}
+
+	if app.DeployedVersions != nil {
+		for _, v := range *app.DeployedVersions {
+			devices := make([]deviceResponse, 0, len(v.Devices))
+			for _, d := range v.Devices {
+				devices = append(devices, deviceResponse{
+					ID:            d.ID,
+					DeviceID:      d.DeviceID,
+					DeviceVersion: d.DeviceVersion,
+					DeviceType:    d.DeviceType,
+				})
+			}
+			archived.DeployedVersions = append(archived.DeployedVersions, archivedVersionResponse{
+				versionResponse: newVersionResponse(v),
+				Devices:         devices,
+			})
+		}
+	}
+
+	r.Archive = &appArchiveResponse{
+		ExportedAt:    result.Archive.ExportedAt,
+		App:           archived,
+		Releases:      newReleasesResponse(result.Archive.Releases),
+		SigningPolicy: newSigningPolicyResponse(result.Archive.SigningPolicy),
+		Webhooks:      make([]archivedWebhookResponse, 0, len(result.Archive.Webhooks)),
+		Alerts:        make([]archivedAlertResponse, 0, len(result.Archive.Alerts)),
+	}
+	for _, w := range result.Archive.Webhooks {
+		r.Archive.Webhooks = append(r.Archive.Webhooks, archivedWebhookResponse{
+			ID:        w.ID,
+			URL:       w.URL,
+			Events:    w.Events,
+			CreatedAt: w.CreatedAt,
+		})
+	}
+	for _, a := range result.Archive.Alerts {
+		r.Archive.Alerts = append(r.Archive.Alerts, archivedAlertResponse{
+			ID:              a.ID,
+			Kind:            a.Kind,
+			Subject:         a.Subject,
+			Value:           a.Value,
+			Threshold:       a.Threshold,
+			FirstDetectedAt: a.FirstDetectedAt,
+			LastDetectedAt:  a.LastDetectedAt,
+		})
+	}
+
+	return r
+}
+
+// newImportAppsResponse maps the outcome of the import of a policy to its response
+func newImportAppsResponse(result models.PolicyImport) importAppsResponse {
+	r := importAppsResponse{DryRun: result.DryRun, Changes: make([]policyChangeResponse, 0, len(result.Changes))}
+	for _, change := range result.Changes {
+		r.Changes = append(r.Changes, policyChangeResponse(change))
+	}
+	return r
+}
+
+// newReleasesResponse maps the released versions of an app to their response
+func newReleasesResponse(r models.Releases) releasesResponse {
+	versions := r.Versions
+	if versions == nil {
+		versions = []string{}
+	}
+	return releasesResponse{UnknownVersions: r.UnknownVersions, Versions: versions}
+}
+
+// newSigningCertificateResponse maps a signing certificate of an app to its response
+func newSigningCertificateResponse(c models.SigningCertificate) signingCertificateResponse {
+	return signingCertificateResponse{
+		ID:          c.ID,
+		Platform:    c.Platform,
+		Fingerprint: c.Fingerprint,
+		Description: c.Description,
+		CreatedAt:   c.CreatedAt,
+	}
+}
+
+// newSigningPolicyResponse maps the signing certificates of an app to their response
+func newSigningPolicyResponse(p models.SigningPolicy) signingPolicyResponse {
+	r := signingPolicyResponse{TamperedBuilds: p.TamperedBuilds, Certificates: make([]signingCertificateResponse, 0, len(p.Certificates))}
+	for _, c := range p.Certificates {
+		r.Certificates = append(r.Certificates, newSigningCertificateResponse(c))
+	}
+	return r
+}
@@ -0,0 +1,176 @@
+package apps
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+func Test_newAppResponse(t *testing.T) {
+	launches := 10
+	versions := []models.Version{
+		{
+			ID:               "55ebd387-9c68-4137-a367-a12025cc2cdb",
+			AppID:            "com.aerogear.mobile_app_one",
+			Version:          "1.0",
+			Disabled:         true,
+			DisabledMessage:  "Please update",
+			NumOfAppLaunches: 10,
+			Devices:          []models.Device{{ID: "a7b2e1c4-5b0b-4a8e-9e38-2c2f3c3b3a3d"}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		app  models.App
+		want appResponse
+	}{
+		{
+			name: "newAppResponse() should map an app without versions",
+			app:  models.App{ID: "1b9e7a5f-af7c-4055-b488-72f2b5f72266", AppID: "com.aerogear.mobile_app_one", AppName: "One", NumOfAppLaunches: &launches},
+			want: appResponse{ID: "1b9e7a5f-af7c-4055-b488-72f2b5f72266", AppID: "com.aerogear.mobile_app_one", AppName: "One", NumOfAppLaunches: &launches},
+		},
+		{
+			name: "newAppResponse() should map the versions without their devices",
+			app:  models.App{ID: "1b9e7a5f-af7c-4055-b488-72f2b5f72266", AppID: "com.aerogear.mobile_app_one", DeployedVersions: &versions},
+			want: appResponse{
+				ID:    "1b9e7a5f-af7c-4055-b488-72f2b5f72266",
+				AppID: "com.aerogear.mobile_app_one",
+				DeployedVersions: &[]versionResponse{
+					{
+						ID:               "55ebd387-9c68-4137-a367-a12025cc2cdb",
+						AppID:            "com.aerogear.mobile_app_one",
+						Version:          "1.0",
+						Disabled:         true,
+						DisabledMessage:  "Please update",
+						NumOfAppLaunches: 10,
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newAppResponse(tt.app); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newAppResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newHardDeleteAppResponse(t *testing.T) {
+	createdAt := time.Date(2019, 8, 1, 10, 0, 0, 0, time.UTC)
+	versions := []models.Version{
+		{
+			ID:      "55ebd387-9c68-4137-a367-a12025cc2cdb",
+			AppID:   "com.aerogear.mobile_app_one",
+			Version: "1.0",
+			Devices: []models.Device{
+				{
+					ID:            "a7b2e1c4-5b0b-4a8e-9e38-2c2f3c3b3a3d",
+					VersionID:     "55ebd387-9c68-4137-a367-a12025cc2cdb",
+					Version:       "1.0",
+					AppID:         "com.aerogear.mobile_app_one",
+					DeviceID:      "0ebc8e9d-5e6a-4e2b-a9a5-5c2d5d2b3f11",
+					DeviceVersion: "9.0",
+					DeviceType:    "Android",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		result models.AppHardDelete
+		want   hardDeleteAppResponse
+	}{
+		{
+			name:   "newHardDeleteAppResponse() should map a dry run without archive",
+			result: models.AppHardDelete{DryRun: true, Versions: 1, Devices: 1},
+			want:   hardDeleteAppResponse{DryRun: true, Versions: 1, Devices: 1},
+		},
+		{
+			name: "newHardDeleteAppResponse() should map the archive with the devices of each version and the settings of the app",
+			result: models.AppHardDelete{
+				Versions:            1,
+				Devices:             1,
+				ReleasedVersions:    1,
+				SigningCertificates: 1,
+				Webhooks:            1,
+				Alerts:              1,
+				Archive: &models.AppArchive{
+					ExportedAt: "2019-08-20T10:00:00Z",
+					App:        models.App{ID: "1b9e7a5f-af7c-4055-b488-72f2b5f72266", AppID: "com.aerogear.mobile_app_one", DeployedVersions: &versions},
+					Releases:   models.Releases{AppID: "com.aerogear.mobile_app_one", UnknownVersions: models.UnknownVersionsFlag, Versions: []string{"1.0"}},
+					SigningPolicy: models.SigningPolicy{
+						AppID:          "com.aerogear.mobile_app_one",
+						TamperedBuilds: models.TamperedBuildsBlock,
+						Certificates: []models.SigningCertificate{
+							{ID: "0f4c2b1a-3d5e-4f6a-8b7c-9d0e1f2a3b4c", AppID: "com.aerogear.mobile_app_one", Platform: models.PlatformAndroid, Fingerprint: testFingerprint, CreatedAt: createdAt},
+						},
+					},
+					Webhooks: []models.Webhook{
+						{ID: "6d2f8e4a-1b3c-4d5e-8f9a-0b1c2d3e4f5a", AppID: "com.aerogear.mobile_app_one", URL: "https://example.com/hook", Secret: "s3cret", Events: []string{"app.deleted"}, CreatedAt: createdAt},
+					},
+					Alerts: []models.Alert{
+						{ID: "9a8b7c6d-5e4f-4a3b-9c1d-0e1f2a3b4c5d", AppID: "com.aerogear.mobile_app_one", Kind: "unreleased_version", Subject: "1.1", Value: 12, Threshold: 10, FirstDetectedAt: createdAt, LastDetectedAt: createdAt},
+					},
+				},
+			},
+			want: hardDeleteAppResponse{
+				Versions:            1,
+				Devices:             1,
+				ReleasedVersions:    1,
+				SigningCertificates: 1,
+				Webhooks:            1,
+				Alerts:              1,
+				Archive: &appArchiveResponse{
+					ExportedAt: "2019-08-20T10:00:00Z",
+					App: archivedAppResponse{
+						ID:    "1b9e7a5f-af7c-4055-b488-72f2b5f72266",
+						AppID: "com.aerogear.mobile_app_one",
+						DeployedVersions: []archivedVersionResponse{
+							{
+								versionResponse: versionResponse{
+									ID:      "55ebd387-9c68-4137-a367-a12025cc2cdb",
+									AppID:   "com.aerogear.mobile_app_one",
+									Version: "1.0",
+								},
+								Devices: []deviceResponse{
+									{
+										ID:            "a7b2e1c4-5b0b-4a8e-9e38-2c2f3c3b3a3d",
+										DeviceID:      "0ebc8e9d-5e6a-4e2b-a9a5-5c2d5d2b3f11",
+										DeviceVersion: "9.0",
+										DeviceType:    "Android",
+									},
+								},
+							},
+						},
+					},
+					Releases: releasesResponse{UnknownVersions: models.UnknownVersionsFlag, Versions: []string{"1.0"}},
+					SigningPolicy: signingPolicyResponse{
+						TamperedBuilds: models.TamperedBuildsBlock,
+						Certificates: []signingCertificateResponse{
+							{ID: "0f4c2b1a-3d5e-4f6a-8b7c-9d0e1f2a3b4c", Platform: models.PlatformAndroid, Fingerprint: testFingerprint, CreatedAt: createdAt},
+						},
+					},
+					Webhooks: []archivedWebhookResponse{
+						{ID: "6d2f8e4a-1b3c-4d5e-8f9a-0b1c2d3e4f5a", URL: "https://example.com/hook", Events: []string{"app.deleted"}, CreatedAt: createdAt},
+					},
+					Alerts: []archivedAlertResponse{
+						{ID: "9a8b7c6d-5e4f-4a3b-9c1d-0e1f2a3b4c5d", Kind: "unreleased_version", Subject: "1.1", Value: 12, Threshold: 10, FirstDetectedAt: createdAt, LastDetectedAt: createdAt},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newHardDeleteAppResponse(tt.result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newHardDeleteAppResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
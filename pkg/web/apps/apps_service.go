@@ -1,6 +1,10 @@
 package apps
 
 import (
+	"errors"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/events"
 	"github.com/aerogear/mobile-security-service/pkg/helpers"
 	"github.com/aerogear/mobile-security-service/pkg/models"
 	"github.com/google/uuid"
@@ -10,32 +14,51 @@ import (
 type (
 	// Service defines the interface methods to be used
 	Service interface {
-		GetApps() (*[]models.App, error)
+		GetApps(query models.AppsQuery) (*models.AppsPage, error)
 		GetActiveAppByID(ID string) (*models.App, error)
 		GetActiveAppByAppID(appID string) (*models.App, error)
 		UpdateAppVersions(id string, versions []models.Version) error
 		DisableAllAppVersionsByAppID(id string, message string) error
 		DeleteAppById(id string) error
+		RestoreAppByID(id string) error
+		PurgeDeletedApps(before time.Time) (int64, error)
+		HardDeleteAppByID(id string, dryRun bool) (*models.AppHardDelete, error)
 		CreateApp(app models.App) error
 		UpdateAppNameByID(id, name string) error
 		InitClientApp(deviceInfo *models.Device) (*models.Version, error)
+		ExportApps() (*models.AppsPolicy, error)
+		ImportApps(policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error)
+		ReconcileApps(policy models.AppsPolicy) (*models.PolicyImport, error)
+		GetReleases(id string) (*models.Releases, error)
+		SetReleases(id string, releases models.Releases) error
+		AddRelease(id, version string) error
+		GetSigningPolicy(id string) (*models.SigningPolicy, error)
+		CreateSigningCertificate(id string, certificate models.SigningCertificate) (*models.SigningCertificate, error)
+		DeleteSigningCertificate(id, certificateID string) error
+		SetTamperedBuilds(id, mode string) error
 	}
 
 	appsService struct {
 		repository Repository
+		publisher  EventPublisher
 	}
 )
 
-// NewService instantiates this service
-func NewService(repository Repository) Service {
+// NewService instantiates this service. The events of the apps are given to the publisher, which may be nil.
+func NewService(repository Repository, publisher EventPublisher) Service {
 	return &appsService{
 		repository: repository,
+		publisher:  publisher,
 	}
 }
 
-// GetApps retrieves the list of apps from the repository
-func (a *appsService) GetApps() (*[]models.App, error) {
-	apps, err := a.repository.GetApps()
+// GetApps retrieves a page of the list of apps from the repository
+func (a *appsService) GetApps(query models.AppsQuery) (*models.AppsPage, error) {
+	if err := validateAppsQuery(query); err != nil {
+		return nil, err
+	}
+
+	apps, err := a.repository.GetApps(query)
 
 	// Check for errors and return the appropriate error to the handler
 	if err != nil {
@@ -56,7 +79,7 @@ func (a *appsService) GetActiveAppByID(id string) (*models.App, error) {
 
 	deployedVersions, err := a.repository.GetAppVersionsByAppID(app.AppID)
 
-	if err != nil && err != models.ErrNotFound {
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
 		return nil, err
 	}
 
@@ -93,11 +116,22 @@ func (a *appsService) UpdateAppVersions(id string, versions []models.Version) er
 		}
 	}
 
+	// the stored versions are only needed by the event of the update
+	var stored []models.Version
+	if a.publisher != nil {
+		if stored, err = a.getAppVersions(app.AppID); err != nil {
+			return err
+		}
+	}
+
 	// Check for errors and return the appropriate error to the handler
 	if err := a.repository.UpdateAppVersions(versions); err != nil {
 		return err
 	}
 
+	a.publish(events.VersionsUpdated{AppID: app.AppID, Previous: stored, Versions: versions})
+	a.refreshDisabledVersions(app.AppID)
+
 	return nil
 }
 
@@ -111,21 +145,86 @@ func (a *appsService) DisableAllAppVersionsByAppID(id string, message string) er
 		return err
 	}
 
+	var stored []models.Version
+	if a.publisher != nil {
+		if stored, err = a.getAppVersions(app.AppID); err != nil {
+			return err
+		}
+	}
+
 	if message == "" {
-		return a.repository.DisableAllAppVersionsByAppID(app.AppID)
+		err = a.repository.DisableAllAppVersionsByAppID(app.AppID)
+	} else {
+		err = a.repository.DisableAllAppVersionsAndSetDisabledMessageByAppID(app.AppID, message)
 	}
+	if err != nil {
+		return err
+	}
+
+	a.publish(events.AllVersionsDisabled{AppID: app.AppID, DisabledMessage: message, Previous: stored})
+	a.refreshDisabledVersions(app.AppID)
 
-	return a.repository.DisableAllAppVersionsAndSetDisabledMessageByAppID(app.AppID, message)
+	return nil
 }
 
 func (a *appsService) DeleteAppById(id string) error {
-	err := a.repository.DeleteAppById(id)
+	app, err := a.repository.GetActiveAppByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := a.repository.DeleteAppById(id); err != nil {
+		return err
+	}
+
+	a.publish(events.AppDeleted{App: *app})
+	removeAppMetrics(app.AppID)
+	return nil
+}
+
+// RestoreAppByID restores a soft deleted app. It returns ErrConflict if the app is not deleted.
+func (a *appsService) RestoreAppByID(id string) error {
+	app, err := a.repository.GetAppByID(id)
+
 	if err != nil {
 		return err
 	}
+
+	if app.DeletedAt == "" {
+		return models.ErrConflict
+	}
+
+	if err := a.repository.UnDeleteAppByAppID(app.AppID); err != nil {
+		return err
+	}
+
+	app.DeletedAt = ""
+	a.publish(events.AppRestored{App: *app})
+	a.refreshDisabledVersions(app.AppID)
 	return nil
 }
 
+// PurgeDeletedApps hard deletes the apps soft deleted before the given time with all their data
+func (a *appsService) PurgeDeletedApps(before time.Time) (int64, error) {
+	return a.repository.PurgeDeletedApps(before)
+}
+
+// HardDeleteAppByID permanently deletes an app with all its versions and devices, released versions, signing certificates,
+// webhooks and alerts and returns them as an archive. When dryRun is true nothing is deleted and only their numbers are returned.
+func (a *appsService) HardDeleteAppByID(id string, dryRun bool) (*models.AppHardDelete, error) {
+	deleted, err := a.repository.HardDeleteAppByID(id, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if deleted.Archive != nil {
+		removeAppMetrics(deleted.Archive.App.AppID)
+	}
+	return deleted, nil
+}
+
+// CreateApp creates an app in its tenant, or in the default tenant when it has none, or restores it when it was deleted
+// from the same tenant. A deleted app of another tenant is a conflict, unless the app has no tenant.
 func (a *appsService) CreateApp(app models.App) error {
 
 	// Check if it exist
@@ -136,9 +235,17 @@ func (a *appsService) CreateApp(app models.App) error {
 	}
 
 	// If it is new then create an app
-	if err != nil && err == models.ErrNotFound {
+	if errors.Is(err, models.ErrNotFound) {
 		id := helpers.GetUUID()
-		return a.repository.CreateApp(id, app.AppID, app.AppName)
+		tenantID := app.TenantID
+		if tenantID == "" {
+			tenantID = models.DefaultTenantID
+		}
+		if err := a.repository.CreateApp(id, app.AppID, app.AppName, tenantID); err != nil {
+			return err
+		}
+		a.publish(events.AppCreated{App: models.App{ID: id, AppID: app.AppID, AppName: app.AppName, TenantID: tenantID}})
+		return nil
 	}
 
 	// return error in the creation
@@ -148,10 +255,19 @@ func (a *appsService) CreateApp(app models.App) error {
 
 	// check if is disabled
 	if appStored.DeletedAt != "" {
+		// a deleted app of another tenant is reported as an existing app, so its tenant is not revealed.
+		// The tenant is empty when a super admin does not request one, as they restore the apps of every tenant.
+		if app.TenantID != "" && app.TenantID != appStored.TenantID {
+			return models.ErrConflict
+		}
+
 		// if is deleted so just reactive the existent app
 		if err := a.repository.UnDeleteAppByAppID(app.AppID); err != nil {
 			return err
 		}
+		appStored.DeletedAt = ""
+		a.publish(events.AppRestored{App: *appStored})
+		a.refreshDisabledVersions(appStored.AppID)
 	}
 
 	return nil
@@ -177,19 +293,60 @@ func (a *appsService) UpdateAppNameByID(id, name string) error {
 
 // InitClientApp returns information about the current state of the app - its disabled status
 func (a *appsService) InitClientApp(deviceInfo *models.Device) (*models.Version, error) {
-	if _, err := a.repository.GetActiveAppByAppID(deviceInfo.AppID); err != nil {
+	app, err := a.repository.GetActiveAppByAppID(deviceInfo.AppID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the devices may send the app id in any case, while the metrics and the events are labelled with the stored one
+	info := *deviceInfo
+	info.AppID = app.AppID
+	deviceInfo = &info
+
+	tampered, blocked, err := a.verifyBuild(deviceInfo)
+	if err != nil {
 		return nil, err
 	}
 
+	// a blocked build registers neither its version nor its device
+	if blocked {
+		a.publish(events.TamperedBuild{Device: *deviceInfo, Blocked: true})
+		countBlockedLaunch(deviceInfo.AppID, BlockedTamperedBuild)
+		return &models.Version{
+			Version:         deviceInfo.Version,
+			AppID:           deviceInfo.AppID,
+			Disabled:        true,
+			DisabledMessage: TamperedBuildMessage,
+		}, nil
+	}
+
 	version, err := a.repository.GetVersionByAppIDAndVersion(deviceInfo.AppID, deviceInfo.Version)
 
 	// If any error other Not Found error occurred, return
-	if err != nil && err != models.ErrNotFound {
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return nil, err
+	}
+
+	newVersion := errors.Is(err, models.ErrNotFound)
+
+	// the app blocks the versions which are not released, including the ones seen before the block mode was set,
+	// and neither the version nor the device are registered
+	if blocked, err = a.isBlockedVersion(deviceInfo.AppID, deviceInfo.Version); err != nil {
 		return nil, err
 	}
 
+	if blocked {
+		countBlockedLaunch(deviceInfo.AppID, BlockedUnknownVersion)
+		return &models.Version{
+			Version:         deviceInfo.Version,
+			AppID:           deviceInfo.AppID,
+			Disabled:        true,
+			DisabledMessage: UnknownVersionMessage,
+		}, nil
+	}
+
 	// If the version does not exist, create it
-	if err == models.ErrNotFound {
+	if newVersion {
 		version = &models.Version{
 			ID:      uuid.New().String(),
 			Version: deviceInfo.Version,
@@ -207,7 +364,7 @@ func (a *appsService) InitClientApp(deviceInfo *models.Device) (*models.Version,
 	var newDevice = false
 	if err != nil {
 		// If we can't find the device by device ID and app ID
-		if err != models.ErrNotFound {
+		if !errors.Is(err, models.ErrNotFound) {
 			return nil, err
 		}
 
@@ -216,6 +373,10 @@ func (a *appsService) InitClientApp(deviceInfo *models.Device) (*models.Version,
 		newDevice = true
 	}
 
+	// the version and the device registered before may hold the app id as sent by their first device
+	version.AppID = app.AppID
+	device.AppID = app.AppID
+
 	// indicates whether properties of the device are updated
 	var isUpdated bool
 
@@ -237,6 +398,21 @@ func (a *appsService) InitClientApp(deviceInfo *models.Device) (*models.Version,
 		}
 	}
 
+	if newVersion {
+		a.publish(events.VersionFirstSeen{Version: *version})
+	}
+	if newDevice {
+		a.publish(events.DeviceRegistered{Device: *device})
+	}
+	a.publish(events.AppLaunched{Device: *device, Version: *version})
+	if version.Disabled {
+		a.publish(events.DeviceBlocked{Device: *device, Version: *version})
+	}
+	if tampered {
+		a.publish(events.TamperedBuild{Device: *deviceInfo})
+	}
+	countLaunch(*device, *version, newDevice)
+
 	// clear these values before returning the data
 	version.LastLaunchedAt = ""
 	version.NumOfAppLaunches = 0
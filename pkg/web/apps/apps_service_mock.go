@@ -6,16 +6,30 @@ package apps
 import (
 	"github.com/aerogear/mobile-security-service/pkg/models"
 	"sync"
+	"time"
 )
 
 var (
+	lockServiceMockAddRelease                   sync.RWMutex
 	lockServiceMockCreateApp                    sync.RWMutex
+	lockServiceMockCreateSigningCertificate     sync.RWMutex
 	lockServiceMockDeleteAppById                sync.RWMutex
+	lockServiceMockDeleteSigningCertificate     sync.RWMutex
 	lockServiceMockDisableAllAppVersionsByAppID sync.RWMutex
+	lockServiceMockExportApps                   sync.RWMutex
 	lockServiceMockGetActiveAppByAppID          sync.RWMutex
 	lockServiceMockGetActiveAppByID             sync.RWMutex
 	lockServiceMockGetApps                      sync.RWMutex
+	lockServiceMockGetReleases                  sync.RWMutex
+	lockServiceMockGetSigningPolicy             sync.RWMutex
+	lockServiceMockHardDeleteAppByID            sync.RWMutex
+	lockServiceMockImportApps                   sync.RWMutex
 	lockServiceMockInitClientApp                sync.RWMutex
+	lockServiceMockPurgeDeletedApps             sync.RWMutex
+	lockServiceMockReconcileApps                sync.RWMutex
+	lockServiceMockRestoreAppByID               sync.RWMutex
+	lockServiceMockSetReleases                  sync.RWMutex
+	lockServiceMockSetTamperedBuilds            sync.RWMutex
 	lockServiceMockUpdateAppNameByID            sync.RWMutex
 	lockServiceMockUpdateAppVersions            sync.RWMutex
 )
@@ -26,65 +40,143 @@ var _ Service = &ServiceMock{}
 
 // ServiceMock is a mock implementation of Service.
 //
-//     func TestSomethingThatUsesService(t *testing.T) {
+//	    func TestSomethingThatUsesService(t *testing.T) {
 //
-//         // make and configure a mocked Service
-//         mockedService := &ServiceMock{
-//             CreateAppFunc: func(app models.App) error {
-// 	               panic("mock out the CreateApp method")
-//             },
-//             DeleteAppByIdFunc: func(id string) error {
-// 	               panic("mock out the DeleteAppById method")
-//             },
-//             DisableAllAppVersionsByAppIDFunc: func(id string, message string) error {
-// 	               panic("mock out the DisableAllAppVersionsByAppID method")
-//             },
-//             GetActiveAppByAppIDFunc: func(appId string) (*models.App, error) {
-// 	               panic("mock out the GetActiveAppByAppID method")
-//             },
-//             GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
-// 	               panic("mock out the GetActiveAppByID method")
-//             },
-//             GetAppsFunc: func() (*[]models.App, error) {
-// 	               panic("mock out the GetApps method")
-//             },
-//             InitClientAppFunc: func(deviceInfo *models.Device) (*models.Version, error) {
-// 	               panic("mock out the InitClientApp method")
-//             },
-//             UpdateAppNameByIDFunc: func(id string, name string) error {
-// 	               panic("mock out the UpdateAppNameByID method")
-//             },
-//             UpdateAppVersionsFunc: func(id string, versions []models.Version) error {
-// 	               panic("mock out the UpdateAppVersions method")
-//             },
-//         }
+//	        // make and configure a mocked Service
+//	        mockedService := &ServiceMock{
+//	            AddReleaseFunc: func(id string, version string) error {
+//		               panic("mock out the AddRelease method")
+//	            },
+//	            CreateAppFunc: func(app models.App) error {
+//		               panic("mock out the CreateApp method")
+//	            },
+//	            CreateSigningCertificateFunc: func(id string, certificate models.SigningCertificate) (*models.SigningCertificate, error) {
+//		               panic("mock out the CreateSigningCertificate method")
+//	            },
+//	            DeleteAppByIdFunc: func(id string) error {
+//		               panic("mock out the DeleteAppById method")
+//	            },
+//	            DeleteSigningCertificateFunc: func(id string, certificateID string) error {
+//		               panic("mock out the DeleteSigningCertificate method")
+//	            },
+//	            DisableAllAppVersionsByAppIDFunc: func(id string, message string) error {
+//		               panic("mock out the DisableAllAppVersionsByAppID method")
+//	            },
+//	            ExportAppsFunc: func() (*models.AppsPolicy, error) {
+//		               panic("mock out the ExportApps method")
+//	            },
+//	            GetActiveAppByAppIDFunc: func(appID string) (*models.App, error) {
+//		               panic("mock out the GetActiveAppByAppID method")
+//	            },
+//	            GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
+//		               panic("mock out the GetActiveAppByID method")
+//	            },
+//	            GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+//		               panic("mock out the GetApps method")
+//	            },
+//	            GetReleasesFunc: func(id string) (*models.Releases, error) {
+//		               panic("mock out the GetReleases method")
+//	            },
+//	            GetSigningPolicyFunc: func(id string) (*models.SigningPolicy, error) {
+//		               panic("mock out the GetSigningPolicy method")
+//	            },
+//	            HardDeleteAppByIDFunc: func(id string, dryRun bool) (*models.AppHardDelete, error) {
+//		               panic("mock out the HardDeleteAppByID method")
+//	            },
+//	            ImportAppsFunc: func(policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error) {
+//		               panic("mock out the ImportApps method")
+//	            },
+//	            InitClientAppFunc: func(deviceInfo *models.Device) (*models.Version, error) {
+//		               panic("mock out the InitClientApp method")
+//	            },
+//	            PurgeDeletedAppsFunc: func(before time.Time) (int64, error) {
+//		               panic("mock out the PurgeDeletedApps method")
+//	            },
+//	            ReconcileAppsFunc: func(policy models.AppsPolicy) (*models.PolicyImport, error) {
+//		               panic("mock out the ReconcileApps method")
+//	            },
+//	            RestoreAppByIDFunc: func(id string) error {
+//		               panic("mock out the RestoreAppByID method")
+//	            },
+//	            SetReleasesFunc: func(id string, releases models.Releases) error {
+//		               panic("mock out the SetReleases method")
+//	            },
+//	            SetTamperedBuildsFunc: func(id string, mode string) error {
+//		               panic("mock out the SetTamperedBuilds method")
+//	            },
+//	            UpdateAppNameByIDFunc: func(id string, name string) error {
+//		               panic("mock out the UpdateAppNameByID method")
+//	            },
+//	            UpdateAppVersionsFunc: func(id string, versions []models.Version) error {
+//		               panic("mock out the UpdateAppVersions method")
+//	            },
+//	        }
 //
-//         // use mockedService in code that requires Service
-//         // and then make assertions.
+//	        // use mockedService in code that requires Service
+//	        // and then make assertions.
 //
-//     }
+//	    }
 type ServiceMock struct {
+	// AddReleaseFunc mocks the AddRelease method.
+	AddReleaseFunc func(id string, version string) error
+
 	// CreateAppFunc mocks the CreateApp method.
 	CreateAppFunc func(app models.App) error
 
+	// CreateSigningCertificateFunc mocks the CreateSigningCertificate method.
+	CreateSigningCertificateFunc func(id string, certificate models.SigningCertificate) (*models.SigningCertificate, error)
+
 	// DeleteAppByIdFunc mocks the DeleteAppById method.
 	DeleteAppByIdFunc func(id string) error
 
+	// DeleteSigningCertificateFunc mocks the DeleteSigningCertificate method.
+	DeleteSigningCertificateFunc func(id string, certificateID string) error
+
 	// DisableAllAppVersionsByAppIDFunc mocks the DisableAllAppVersionsByAppID method.
 	DisableAllAppVersionsByAppIDFunc func(id string, message string) error
 
+	// ExportAppsFunc mocks the ExportApps method.
+	ExportAppsFunc func() (*models.AppsPolicy, error)
+
 	// GetActiveAppByAppIDFunc mocks the GetActiveAppByAppID method.
-	GetActiveAppByAppIDFunc func(appId string) (*models.App, error)
+	GetActiveAppByAppIDFunc func(appID string) (*models.App, error)
 
 	// GetActiveAppByIDFunc mocks the GetActiveAppByID method.
 	GetActiveAppByIDFunc func(ID string) (*models.App, error)
 
 	// GetAppsFunc mocks the GetApps method.
-	GetAppsFunc func() (*[]models.App, error)
+	GetAppsFunc func(query models.AppsQuery) (*models.AppsPage, error)
+
+	// GetReleasesFunc mocks the GetReleases method.
+	GetReleasesFunc func(id string) (*models.Releases, error)
+
+	// GetSigningPolicyFunc mocks the GetSigningPolicy method.
+	GetSigningPolicyFunc func(id string) (*models.SigningPolicy, error)
+
+	// HardDeleteAppByIDFunc mocks the HardDeleteAppByID method.
+	HardDeleteAppByIDFunc func(id string, dryRun bool) (*models.AppHardDelete, error)
+
+	// ImportAppsFunc mocks the ImportApps method.
+	ImportAppsFunc func(policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error)
 
 	// InitClientAppFunc mocks the InitClientApp method.
 	InitClientAppFunc func(deviceInfo *models.Device) (*models.Version, error)
 
+	// PurgeDeletedAppsFunc mocks the PurgeDeletedApps method.
+	PurgeDeletedAppsFunc func(before time.Time) (int64, error)
+
+	// ReconcileAppsFunc mocks the ReconcileApps method.
+	ReconcileAppsFunc func(policy models.AppsPolicy) (*models.PolicyImport, error)
+
+	// RestoreAppByIDFunc mocks the RestoreAppByID method.
+	RestoreAppByIDFunc func(id string) error
+
+	// SetReleasesFunc mocks the SetReleases method.
+	SetReleasesFunc func(id string, releases models.Releases) error
+
+	// SetTamperedBuildsFunc mocks the SetTamperedBuilds method.
+	SetTamperedBuildsFunc func(id string, mode string) error
+
 	// UpdateAppNameByIDFunc mocks the UpdateAppNameByID method.
 	UpdateAppNameByIDFunc func(id string, name string) error
 
@@ -93,16 +185,37 @@ type ServiceMock struct {
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// AddRelease holds details about calls to the AddRelease method.
+		AddRelease []struct {
+			// ID is the id argument value.
+			ID string
+			// Version is the version argument value.
+			Version string
+		}
 		// CreateApp holds details about calls to the CreateApp method.
 		CreateApp []struct {
 			// App is the app argument value.
 			App models.App
 		}
+		// CreateSigningCertificate holds details about calls to the CreateSigningCertificate method.
+		CreateSigningCertificate []struct {
+			// ID is the id argument value.
+			ID string
+			// Certificate is the certificate argument value.
+			Certificate models.SigningCertificate
+		}
 		// DeleteAppById holds details about calls to the DeleteAppById method.
 		DeleteAppById []struct {
 			// ID is the id argument value.
 			ID string
 		}
+		// DeleteSigningCertificate holds details about calls to the DeleteSigningCertificate method.
+		DeleteSigningCertificate []struct {
+			// ID is the id argument value.
+			ID string
+			// CertificateID is the certificateID argument value.
+			CertificateID string
+		}
 		// DisableAllAppVersionsByAppID holds details about calls to the DisableAllAppVersionsByAppID method.
 		DisableAllAppVersionsByAppID []struct {
 			// ID is the id argument value.
@@ -110,10 +223,13 @@ type ServiceMock struct {
 			// Message is the message argument value.
 			Message string
 		}
+		// ExportApps holds details about calls to the ExportApps method.
+		ExportApps []struct {
+		}
 		// GetActiveAppByAppID holds details about calls to the GetActiveAppByAppID method.
 		GetActiveAppByAppID []struct {
-			// AppId is the appId argument value.
-			AppId string
+			// AppID is the appID argument value.
+			AppID string
 		}
 		// GetActiveAppByID holds details about calls to the GetActiveAppByID method.
 		GetActiveAppByID []struct {
@@ -122,12 +238,67 @@ type ServiceMock struct {
 		}
 		// GetApps holds details about calls to the GetApps method.
 		GetApps []struct {
+			// Query is the query argument value.
+			Query models.AppsQuery
+		}
+		// GetReleases holds details about calls to the GetReleases method.
+		GetReleases []struct {
+			// ID is the id argument value.
+			ID string
+		}
+		// GetSigningPolicy holds details about calls to the GetSigningPolicy method.
+		GetSigningPolicy []struct {
+			// ID is the id argument value.
+			ID string
+		}
+		// HardDeleteAppByID holds details about calls to the HardDeleteAppByID method.
+		HardDeleteAppByID []struct {
+			// ID is the id argument value.
+			ID string
+			// DryRun is the dryRun argument value.
+			DryRun bool
+		}
+		// ImportApps holds details about calls to the ImportApps method.
+		ImportApps []struct {
+			// Policy is the policy argument value.
+			Policy models.AppsPolicy
+			// DryRun is the dryRun argument value.
+			DryRun bool
 		}
 		// InitClientApp holds details about calls to the InitClientApp method.
 		InitClientApp []struct {
 			// DeviceInfo is the deviceInfo argument value.
 			DeviceInfo *models.Device
 		}
+		// PurgeDeletedApps holds details about calls to the PurgeDeletedApps method.
+		PurgeDeletedApps []struct {
+			// Before is the before argument value.
+			Before time.Time
+		}
+		// ReconcileApps holds details about calls to the ReconcileApps method.
+		ReconcileApps []struct {
+			// Policy is the policy argument value.
+			Policy models.AppsPolicy
+		}
+		// RestoreAppByID holds details about calls to the RestoreAppByID method.
+		RestoreAppByID []struct {
+			// ID is the id argument value.
+			ID string
+		}
+		// SetReleases holds details about calls to the SetReleases method.
+		SetReleases []struct {
+			// ID is the id argument value.
+			ID string
+			// Releases is the releases argument value.
+			Releases models.Releases
+		}
+		// SetTamperedBuilds holds details about calls to the SetTamperedBuilds method.
+		SetTamperedBuilds []struct {
+			// ID is the id argument value.
+			ID string
+			// Mode is the mode argument value.
+			Mode string
+		}
 		// UpdateAppNameByID holds details about calls to the UpdateAppNameByID method.
 		UpdateAppNameByID []struct {
 			// ID is the id argument value.
@@ -145,6 +316,42 @@ type ServiceMock struct {
 	}
 }
 
+// AddRelease calls AddReleaseFunc.
+func (mock *ServiceMock) AddRelease(id string, version string) error {
+	if mock.AddReleaseFunc == nil {
+		panic("ServiceMock.AddReleaseFunc: method is nil but Service.AddRelease was just called")
+	}
+	callInfo := struct {
+		ID      string
+		Version string
+	}{
+		ID:      id,
+		Version: version,
+	}
+	lockServiceMockAddRelease.Lock()
+	mock.calls.AddRelease = append(mock.calls.AddRelease, callInfo)
+	lockServiceMockAddRelease.Unlock()
+	return mock.AddReleaseFunc(id, version)
+}
+
+// AddReleaseCalls gets all the calls that were made to AddRelease.
+// Check the length with:
+//
+//	len(mockedService.AddReleaseCalls())
+func (mock *ServiceMock) AddReleaseCalls() []struct {
+	ID      string
+	Version string
+} {
+	var calls []struct {
+		ID      string
+		Version string
+	}
+	lockServiceMockAddRelease.RLock()
+	calls = mock.calls.AddRelease
+	lockServiceMockAddRelease.RUnlock()
+	return calls
+}
+
 // CreateApp calls CreateAppFunc.
 func (mock *ServiceMock) CreateApp(app models.App) error {
 	if mock.CreateAppFunc == nil {
@@ -163,7 +370,8 @@ func (mock *ServiceMock) CreateApp(app models.App) error {
 
 // CreateAppCalls gets all the calls that were made to CreateApp.
 // Check the length with:
-//     len(mockedService.CreateAppCalls())
+//
+//	len(mockedService.CreateAppCalls())
 func (mock *ServiceMock) CreateAppCalls() []struct {
 	App models.App
 } {
@@ -176,6 +384,42 @@ func (mock *ServiceMock) CreateAppCalls() []struct {
 	return calls
 }
 
+// CreateSigningCertificate calls CreateSigningCertificateFunc.
+func (mock *ServiceMock) CreateSigningCertificate(id string, certificate models.SigningCertificate) (*models.SigningCertificate, error) {
+	if mock.CreateSigningCertificateFunc == nil {
+		panic("ServiceMock.CreateSigningCertificateFunc: method is nil but Service.CreateSigningCertificate was just called")
+	}
+	callInfo := struct {
+		ID          string
+		Certificate models.SigningCertificate
+	}{
+		ID:          id,
+		Certificate: certificate,
+	}
+	lockServiceMockCreateSigningCertificate.Lock()
+	mock.calls.CreateSigningCertificate = append(mock.calls.CreateSigningCertificate, callInfo)
+	lockServiceMockCreateSigningCertificate.Unlock()
+	return mock.CreateSigningCertificateFunc(id, certificate)
+}
+
+// CreateSigningCertificateCalls gets all the calls that were made to CreateSigningCertificate.
+// Check the length with:
+//
+//	len(mockedService.CreateSigningCertificateCalls())
+func (mock *ServiceMock) CreateSigningCertificateCalls() []struct {
+	ID          string
+	Certificate models.SigningCertificate
+} {
+	var calls []struct {
+		ID          string
+		Certificate models.SigningCertificate
+	}
+	lockServiceMockCreateSigningCertificate.RLock()
+	calls = mock.calls.CreateSigningCertificate
+	lockServiceMockCreateSigningCertificate.RUnlock()
+	return calls
+}
+
 // DeleteAppById calls DeleteAppByIdFunc.
 func (mock *ServiceMock) DeleteAppById(id string) error {
 	if mock.DeleteAppByIdFunc == nil {
@@ -194,7 +438,8 @@ func (mock *ServiceMock) DeleteAppById(id string) error {
 
 // DeleteAppByIdCalls gets all the calls that were made to DeleteAppById.
 // Check the length with:
-//     len(mockedService.DeleteAppByIdCalls())
+//
+//	len(mockedService.DeleteAppByIdCalls())
 func (mock *ServiceMock) DeleteAppByIdCalls() []struct {
 	ID string
 } {
@@ -207,6 +452,42 @@ func (mock *ServiceMock) DeleteAppByIdCalls() []struct {
 	return calls
 }
 
+// DeleteSigningCertificate calls DeleteSigningCertificateFunc.
+func (mock *ServiceMock) DeleteSigningCertificate(id string, certificateID string) error {
+	if mock.DeleteSigningCertificateFunc == nil {
+		panic("ServiceMock.DeleteSigningCertificateFunc: method is nil but Service.DeleteSigningCertificate was just called")
+	}
+	callInfo := struct {
+		ID            string
+		CertificateID string
+	}{
+		ID:            id,
+		CertificateID: certificateID,
+	}
+	lockServiceMockDeleteSigningCertificate.Lock()
+	mock.calls.DeleteSigningCertificate = append(mock.calls.DeleteSigningCertificate, callInfo)
+	lockServiceMockDeleteSigningCertificate.Unlock()
+	return mock.DeleteSigningCertificateFunc(id, certificateID)
+}
+
+// DeleteSigningCertificateCalls gets all the calls that were made to DeleteSigningCertificate.
+// Check the length with:
+//
+//	len(mockedService.DeleteSigningCertificateCalls())
+func (mock *ServiceMock) DeleteSigningCertificateCalls() []struct {
+	ID            string
+	CertificateID string
+} {
+	var calls []struct {
+		ID            string
+		CertificateID string
+	}
+	lockServiceMockDeleteSigningCertificate.RLock()
+	calls = mock.calls.DeleteSigningCertificate
+	lockServiceMockDeleteSigningCertificate.RUnlock()
+	return calls
+}
+
 // DisableAllAppVersionsByAppID calls DisableAllAppVersionsByAppIDFunc.
 func (mock *ServiceMock) DisableAllAppVersionsByAppID(id string, message string) error {
 	if mock.DisableAllAppVersionsByAppIDFunc == nil {
@@ -227,7 +508,8 @@ func (mock *ServiceMock) DisableAllAppVersionsByAppID(id string, message string)
 
 // DisableAllAppVersionsByAppIDCalls gets all the calls that were made to DisableAllAppVersionsByAppID.
 // Check the length with:
-//     len(mockedService.DisableAllAppVersionsByAppIDCalls())
+//
+//	len(mockedService.DisableAllAppVersionsByAppIDCalls())
 func (mock *ServiceMock) DisableAllAppVersionsByAppIDCalls() []struct {
 	ID      string
 	Message string
@@ -242,30 +524,58 @@ func (mock *ServiceMock) DisableAllAppVersionsByAppIDCalls() []struct {
 	return calls
 }
 
+// ExportApps calls ExportAppsFunc.
+func (mock *ServiceMock) ExportApps() (*models.AppsPolicy, error) {
+	if mock.ExportAppsFunc == nil {
+		panic("ServiceMock.ExportAppsFunc: method is nil but Service.ExportApps was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockServiceMockExportApps.Lock()
+	mock.calls.ExportApps = append(mock.calls.ExportApps, callInfo)
+	lockServiceMockExportApps.Unlock()
+	return mock.ExportAppsFunc()
+}
+
+// ExportAppsCalls gets all the calls that were made to ExportApps.
+// Check the length with:
+//
+//	len(mockedService.ExportAppsCalls())
+func (mock *ServiceMock) ExportAppsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockServiceMockExportApps.RLock()
+	calls = mock.calls.ExportApps
+	lockServiceMockExportApps.RUnlock()
+	return calls
+}
+
 // GetActiveAppByAppID calls GetActiveAppByAppIDFunc.
-func (mock *ServiceMock) GetActiveAppByAppID(appId string) (*models.App, error) {
+func (mock *ServiceMock) GetActiveAppByAppID(appID string) (*models.App, error) {
 	if mock.GetActiveAppByAppIDFunc == nil {
 		panic("ServiceMock.GetActiveAppByAppIDFunc: method is nil but Service.GetActiveAppByAppID was just called")
 	}
 	callInfo := struct {
-		AppId string
+		AppID string
 	}{
-		AppId: appId,
+		AppID: appID,
 	}
 	lockServiceMockGetActiveAppByAppID.Lock()
 	mock.calls.GetActiveAppByAppID = append(mock.calls.GetActiveAppByAppID, callInfo)
 	lockServiceMockGetActiveAppByAppID.Unlock()
-	return mock.GetActiveAppByAppIDFunc(appId)
+	return mock.GetActiveAppByAppIDFunc(appID)
 }
 
 // GetActiveAppByAppIDCalls gets all the calls that were made to GetActiveAppByAppID.
 // Check the length with:
-//     len(mockedService.GetActiveAppByAppIDCalls())
+//
+//	len(mockedService.GetActiveAppByAppIDCalls())
 func (mock *ServiceMock) GetActiveAppByAppIDCalls() []struct {
-	AppId string
+	AppID string
 } {
 	var calls []struct {
-		AppId string
+		AppID string
 	}
 	lockServiceMockGetActiveAppByAppID.RLock()
 	calls = mock.calls.GetActiveAppByAppID
@@ -291,7 +601,8 @@ func (mock *ServiceMock) GetActiveAppByID(ID string) (*models.App, error) {
 
 // GetActiveAppByIDCalls gets all the calls that were made to GetActiveAppByID.
 // Check the length with:
-//     len(mockedService.GetActiveAppByIDCalls())
+//
+//	len(mockedService.GetActiveAppByIDCalls())
 func (mock *ServiceMock) GetActiveAppByIDCalls() []struct {
 	ID string
 } {
@@ -305,24 +616,30 @@ func (mock *ServiceMock) GetActiveAppByIDCalls() []struct {
 }
 
 // GetApps calls GetAppsFunc.
-func (mock *ServiceMock) GetApps() (*[]models.App, error) {
+func (mock *ServiceMock) GetApps(query models.AppsQuery) (*models.AppsPage, error) {
 	if mock.GetAppsFunc == nil {
 		panic("ServiceMock.GetAppsFunc: method is nil but Service.GetApps was just called")
 	}
 	callInfo := struct {
-	}{}
+		Query models.AppsQuery
+	}{
+		Query: query,
+	}
 	lockServiceMockGetApps.Lock()
 	mock.calls.GetApps = append(mock.calls.GetApps, callInfo)
 	lockServiceMockGetApps.Unlock()
-	return mock.GetAppsFunc()
+	return mock.GetAppsFunc(query)
 }
 
 // GetAppsCalls gets all the calls that were made to GetApps.
 // Check the length with:
-//     len(mockedService.GetAppsCalls())
+//
+//	len(mockedService.GetAppsCalls())
 func (mock *ServiceMock) GetAppsCalls() []struct {
+	Query models.AppsQuery
 } {
 	var calls []struct {
+		Query models.AppsQuery
 	}
 	lockServiceMockGetApps.RLock()
 	calls = mock.calls.GetApps
@@ -330,6 +647,142 @@ func (mock *ServiceMock) GetAppsCalls() []struct {
 	return calls
 }
 
+// GetReleases calls GetReleasesFunc.
+func (mock *ServiceMock) GetReleases(id string) (*models.Releases, error) {
+	if mock.GetReleasesFunc == nil {
+		panic("ServiceMock.GetReleasesFunc: method is nil but Service.GetReleases was just called")
+	}
+	callInfo := struct {
+		ID string
+	}{
+		ID: id,
+	}
+	lockServiceMockGetReleases.Lock()
+	mock.calls.GetReleases = append(mock.calls.GetReleases, callInfo)
+	lockServiceMockGetReleases.Unlock()
+	return mock.GetReleasesFunc(id)
+}
+
+// GetReleasesCalls gets all the calls that were made to GetReleases.
+// Check the length with:
+//
+//	len(mockedService.GetReleasesCalls())
+func (mock *ServiceMock) GetReleasesCalls() []struct {
+	ID string
+} {
+	var calls []struct {
+		ID string
+	}
+	lockServiceMockGetReleases.RLock()
+	calls = mock.calls.GetReleases
+	lockServiceMockGetReleases.RUnlock()
+	return calls
+}
+
+// GetSigningPolicy calls GetSigningPolicyFunc.
+func (mock *ServiceMock) GetSigningPolicy(id string) (*models.SigningPolicy, error) {
+	if mock.GetSigningPolicyFunc == nil {
+		panic("ServiceMock.GetSigningPolicyFunc: method is nil but Service.GetSigningPolicy was just called")
+	}
+	callInfo := struct {
+		ID string
+	}{
+		ID: id,
+	}
+	lockServiceMockGetSigningPolicy.Lock()
+	mock.calls.GetSigningPolicy = append(mock.calls.GetSigningPolicy, callInfo)
+	lockServiceMockGetSigningPolicy.Unlock()
+	return mock.GetSigningPolicyFunc(id)
+}
+
+// GetSigningPolicyCalls gets all the calls that were made to GetSigningPolicy.
+// Check the length with:
+//
+//	len(mockedService.GetSigningPolicyCalls())
+func (mock *ServiceMock) GetSigningPolicyCalls() []struct {
+	ID string
+} {
+	var calls []struct {
+		ID string
+	}
+	lockServiceMockGetSigningPolicy.RLock()
+	calls = mock.calls.GetSigningPolicy
+	lockServiceMockGetSigningPolicy.RUnlock()
+	return calls
+}
+
+// HardDeleteAppByID calls HardDeleteAppByIDFunc.
+func (mock *ServiceMock) HardDeleteAppByID(id string, dryRun bool) (*models.AppHardDelete, error) {
+	if mock.HardDeleteAppByIDFunc == nil {
+		panic("ServiceMock.HardDeleteAppByIDFunc: method is nil but Service.HardDeleteAppByID was just called")
+	}
+	callInfo := struct {
+		ID     string
+		DryRun bool
+	}{
+		ID:     id,
+		DryRun: dryRun,
+	}
+	lockServiceMockHardDeleteAppByID.Lock()
+	mock.calls.HardDeleteAppByID = append(mock.calls.HardDeleteAppByID, callInfo)
+	lockServiceMockHardDeleteAppByID.Unlock()
+	return mock.HardDeleteAppByIDFunc(id, dryRun)
+}
+
+// HardDeleteAppByIDCalls gets all the calls that were made to HardDeleteAppByID.
+// Check the length with:
+//
+//	len(mockedService.HardDeleteAppByIDCalls())
+func (mock *ServiceMock) HardDeleteAppByIDCalls() []struct {
+	ID     string
+	DryRun bool
+} {
+	var calls []struct {
+		ID     string
+		DryRun bool
+	}
+	lockServiceMockHardDeleteAppByID.RLock()
+	calls = mock.calls.HardDeleteAppByID
+	lockServiceMockHardDeleteAppByID.RUnlock()
+	return calls
+}
+
+// ImportApps calls ImportAppsFunc.
+func (mock *ServiceMock) ImportApps(policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error) {
+	if mock.ImportAppsFunc == nil {
+		panic("ServiceMock.ImportAppsFunc: method is nil but Service.ImportApps was just called")
+	}
+	callInfo := struct {
+		Policy models.AppsPolicy
+		DryRun bool
+	}{
+		Policy: policy,
+		DryRun: dryRun,
+	}
+	lockServiceMockImportApps.Lock()
+	mock.calls.ImportApps = append(mock.calls.ImportApps, callInfo)
+	lockServiceMockImportApps.Unlock()
+	return mock.ImportAppsFunc(policy, dryRun)
+}
+
+// ImportAppsCalls gets all the calls that were made to ImportApps.
+// Check the length with:
+//
+//	len(mockedService.ImportAppsCalls())
+func (mock *ServiceMock) ImportAppsCalls() []struct {
+	Policy models.AppsPolicy
+	DryRun bool
+} {
+	var calls []struct {
+		Policy models.AppsPolicy
+		DryRun bool
+	}
+	lockServiceMockImportApps.RLock()
+	calls = mock.calls.ImportApps
+	lockServiceMockImportApps.RUnlock()
+	return calls
+}
+
 // InitClientApp calls InitClientAppFunc.
 func (mock *ServiceMock) InitClientApp(deviceInfo *models.Device) (*models.Version, error) {
 	if mock.InitClientAppFunc == nil {
@@ -348,7 +801,8 @@ func (mock *ServiceMock) InitClientApp(deviceInfo *models.Device) (*models.Versi
 
 // InitClientAppCalls gets all the calls that were made to InitClientApp.
 // Check the length with:
-//     len(mockedService.InitClientAppCalls())
+//
+//	len(mockedService.InitClientAppCalls())
 func (mock *ServiceMock) InitClientAppCalls() []struct {
 	DeviceInfo *models.Device
 } {
@@ -361,6 +815,174 @@ func (mock *ServiceMock) InitClientAppCalls() []struct {
 	return calls
 }
 
+// PurgeDeletedApps calls PurgeDeletedAppsFunc.
+func (mock *ServiceMock) PurgeDeletedApps(before time.Time) (int64, error) {
+	if mock.PurgeDeletedAppsFunc == nil {
+		panic("ServiceMock.PurgeDeletedAppsFunc: method is nil but Service.PurgeDeletedApps was just called")
+	}
+	callInfo := struct {
+		Before time.Time
+	}{
+		Before: before,
+	}
+	lockServiceMockPurgeDeletedApps.Lock()
+	mock.calls.PurgeDeletedApps = append(mock.calls.PurgeDeletedApps, callInfo)
+	lockServiceMockPurgeDeletedApps.Unlock()
+	return mock.PurgeDeletedAppsFunc(before)
+}
+
+// PurgeDeletedAppsCalls gets all the calls that were made to PurgeDeletedApps.
+// Check the length with:
+//
+//	len(mockedService.PurgeDeletedAppsCalls())
+func (mock *ServiceMock) PurgeDeletedAppsCalls() []struct {
+	Before time.Time
+} {
+	var calls []struct {
+		Before time.Time
+	}
+	lockServiceMockPurgeDeletedApps.RLock()
+	calls = mock.calls.PurgeDeletedApps
+	lockServiceMockPurgeDeletedApps.RUnlock()
+	return calls
+}
+
+// ReconcileApps calls ReconcileAppsFunc.
+func (mock *ServiceMock) ReconcileApps(policy models.AppsPolicy) (*models.PolicyImport, error) {
+	if mock.ReconcileAppsFunc == nil {
+		panic("ServiceMock.ReconcileAppsFunc: method is nil but Service.ReconcileApps was just called")
+	}
+	callInfo := struct {
+		Policy models.AppsPolicy
+	}{
+		Policy: policy,
+	}
+	lockServiceMockReconcileApps.Lock()
+	mock.calls.ReconcileApps = append(mock.calls.ReconcileApps, callInfo)
+	lockServiceMockReconcileApps.Unlock()
+	return mock.ReconcileAppsFunc(policy)
+}
+
+// ReconcileAppsCalls gets all the calls that were made to ReconcileApps.
+// Check the length with:
+//
+//	len(mockedService.ReconcileAppsCalls())
+func (mock *ServiceMock) ReconcileAppsCalls() []struct {
+	Policy models.AppsPolicy
+} {
+	var calls []struct {
+		Policy models.AppsPolicy
+	}
+	lockServiceMockReconcileApps.RLock()
+	calls = mock.calls.ReconcileApps
+	lockServiceMockReconcileApps.RUnlock()
+	return calls
+}
+
+// RestoreAppByID calls RestoreAppByIDFunc.
+func (mock *ServiceMock) RestoreAppByID(id string) error {
+	if mock.RestoreAppByIDFunc == nil {
+		panic("ServiceMock.RestoreAppByIDFunc: method is nil but Service.RestoreAppByID was just called")
+	}
+	callInfo := struct {
+		ID string
+	}{
+		ID: id,
+	}
+	lockServiceMockRestoreAppByID.Lock()
+	mock.calls.RestoreAppByID = append(mock.calls.RestoreAppByID, callInfo)
+	lockServiceMockRestoreAppByID.Unlock()
+	return mock.RestoreAppByIDFunc(id)
+}
+
+// RestoreAppByIDCalls gets all the calls that were made to RestoreAppByID.
+// Check the length with:
+//
+//	len(mockedService.RestoreAppByIDCalls())
+func (mock *ServiceMock) RestoreAppByIDCalls() []struct {
+	ID string
+} {
+	var calls []struct {
+		ID string
+	}
+	lockServiceMockRestoreAppByID.RLock()
+	calls = mock.calls.RestoreAppByID
+	lockServiceMockRestoreAppByID.RUnlock()
+	return calls
+}
+
+// SetReleases calls SetReleasesFunc.
+func (mock *ServiceMock) SetReleases(id string, releases models.Releases) error {
+	if mock.SetReleasesFunc == nil {
+		panic("ServiceMock.SetReleasesFunc: method is nil but Service.SetReleases was just called")
+	}
+	callInfo := struct {
+		ID       string
+		Releases models.Releases
+	}{
+		ID:       id,
+		Releases: releases,
+	}
+	lockServiceMockSetReleases.Lock()
+	mock.calls.SetReleases = append(mock.calls.SetReleases, callInfo)
+	lockServiceMockSetReleases.Unlock()
+	return mock.SetReleasesFunc(id, releases)
+}
+
+// SetReleasesCalls gets all the calls that were made to SetReleases.
+// Check the length with:
+//
+//	len(mockedService.SetReleasesCalls())
+func (mock *ServiceMock) SetReleasesCalls() []struct {
+	ID       string
+	Releases models.Releases
+} {
+	var calls []struct {
+		ID       string
+		Releases models.Releases
+	}
+	lockServiceMockSetReleases.RLock()
+	calls = mock.calls.SetReleases
+	lockServiceMockSetReleases.RUnlock()
+	return calls
+}
+
+// SetTamperedBuilds calls SetTamperedBuildsFunc.
+func (mock *ServiceMock) SetTamperedBuilds(id string, mode string) error {
+	if mock.SetTamperedBuildsFunc == nil {
+		panic("ServiceMock.SetTamperedBuildsFunc: method is nil but Service.SetTamperedBuilds was just called")
+	}
+	callInfo := struct {
+		ID   string
+		Mode string
+	}{
+		ID:   id,
+		Mode: mode,
+	}
+	lockServiceMockSetTamperedBuilds.Lock()
+	mock.calls.SetTamperedBuilds = append(mock.calls.SetTamperedBuilds, callInfo)
+	lockServiceMockSetTamperedBuilds.Unlock()
+	return mock.SetTamperedBuildsFunc(id, mode)
+}
+
+// SetTamperedBuildsCalls gets all the calls that were made to SetTamperedBuilds.
+// Check the length with:
+//
+//	len(mockedService.SetTamperedBuildsCalls())
+func (mock *ServiceMock) SetTamperedBuildsCalls() []struct {
+	ID   string
+	Mode string
+} {
+	var calls []struct {
+		ID   string
+		Mode string
+	}
+	lockServiceMockSetTamperedBuilds.RLock()
+	calls = mock.calls.SetTamperedBuilds
+	lockServiceMockSetTamperedBuilds.RUnlock()
+	return calls
+}
+
 // UpdateAppNameByID calls UpdateAppNameByIDFunc.
 func (mock *ServiceMock) UpdateAppNameByID(id string, name string) error {
 	if mock.UpdateAppNameByIDFunc == nil {
@@ -381,7 +1003,8 @@ func (mock *ServiceMock) UpdateAppNameByID(id string, name string) error {
 
 // UpdateAppNameByIDCalls gets all the calls that were made to UpdateAppNameByID.
 // Check the length with:
-//     len(mockedService.UpdateAppNameByIDCalls())
+//
+//	len(mockedService.UpdateAppNameByIDCalls())
 func (mock *ServiceMock) UpdateAppNameByIDCalls() []struct {
 	ID   string
 	Name string
@@ -416,7 +1039,8 @@ func (mock *ServiceMock) UpdateAppVersions(id string, versions []models.Version)
 
 // UpdateAppVersionsCalls gets all the calls that were made to UpdateAppVersions.
 // Check the length with:
-//     len(mockedService.UpdateAppVersionsCalls())
+//
+//	len(mockedService.UpdateAppVersionsCalls())
 func (mock *ServiceMock) UpdateAppVersionsCalls() []struct {
 	ID       string
 	Versions []models.Version
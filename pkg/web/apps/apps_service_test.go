@@ -1,6 +1,7 @@
 package apps
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -19,9 +20,8 @@ var (
 			res := helpers.GetMockAppVersionList()
 			return &res, nil
 		},
-		GetAppsFunc: func() (*[]models.App, error) {
-			apps := helpers.GetMockAppList()
-			return &apps, nil
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+			return &models.AppsPage{Apps: helpers.GetMockAppList()}, nil
 		},
 		UpdateAppVersionsFunc: func(versions []models.Version) error {
 			return nil
@@ -35,7 +35,7 @@ var (
 		DeleteAppByIdFunc: func(id string) error {
 			return nil
 		},
-		CreateAppFunc: func(id string, appId string, name string) error {
+		CreateAppFunc: func(id string, appId string, name string, tenantID string) error {
 			return nil
 		},
 		GetAppByAppIDFunc: func(appID string) (*models.App, error) {
@@ -54,6 +54,27 @@ var (
 			}
 			return nil, models.ErrNotFound
 		},
+		GetReleasesByAppIDFunc: func(appID string) (*models.Releases, error) {
+			return &models.Releases{AppID: appID, UnknownVersions: models.UnknownVersionsFlag, Versions: []string{}}, nil
+		},
+		SetReleasesFunc: func(releases models.Releases) error {
+			return nil
+		},
+		AddReleasedVersionFunc: func(appID, version string) error {
+			return nil
+		},
+		GetSigningPolicyByAppIDFunc: func(appID string) (*models.SigningPolicy, error) {
+			return &models.SigningPolicy{AppID: appID, TamperedBuilds: models.TamperedBuildsFlag, Certificates: []models.SigningCertificate{}}, nil
+		},
+		CreateSigningCertificateFunc: func(certificate models.SigningCertificate) error {
+			return nil
+		},
+		DeleteSigningCertificateFunc: func(appID, id string) error {
+			return nil
+		},
+		SetTamperedBuildsFunc: func(appID, mode string) error {
+			return nil
+		},
 	}
 
 	mockRepositoryError = &RepositoryMock{
@@ -63,7 +84,7 @@ var (
 		GetAppVersionsByAppIDFunc: func(ID string) (*[]models.Version, error) {
 			return nil, models.ErrNotFound
 		},
-		GetAppsFunc: func() (*[]models.App, error) {
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
 			return nil, models.ErrNotFound
 		},
 		UpdateAppVersionsFunc: func(versions []models.Version) error {
@@ -78,7 +99,7 @@ var (
 		DeleteAppByIdFunc: func(id string) error {
 			return models.ErrNotFound
 		},
-		CreateAppFunc: func(id string, appId string, name string) error {
+		CreateAppFunc: func(id string, appId string, name string, tenantID string) error {
 			return models.ErrConflict
 		},
 		GetAppByAppIDFunc: func(appID string) (*models.App, error) {
@@ -97,7 +118,7 @@ var (
 )
 
 func Test_appsService_GetApps(t *testing.T) {
-	apps := helpers.GetMockAppList()
+	page := &models.AppsPage{Apps: helpers.GetMockAppList()}
 
 	type fields struct {
 		repository Repository
@@ -105,27 +126,51 @@ func Test_appsService_GetApps(t *testing.T) {
 	tests := []struct {
 		name     string
 		fields   fields
-		id       string
-		want     *[]models.App
+		query    models.AppsQuery
+		want     *models.AppsPage
 		wantErr  error
 		mockRepo RepositoryMock
 	}{
 		{
 			name:     "Get all apps should return success",
-			want:     &apps,
+			want:     page,
+			mockRepo: *mockRepositoryWithSuccessResults,
+		},
+		{
+			name:     "Get a sorted and filtered page of apps should return success",
+			query:    models.AppsQuery{Limit: 10, Sort: "-launches", Search: "aerogear"},
+			want:     page,
 			mockRepo: *mockRepositoryWithSuccessResults,
 		},
 		{
 			name:     "Get all apps should return error when apps are not found",
-			want:     &apps,
+			want:     page,
 			wantErr:  models.ErrNotFound,
 			mockRepo: *mockRepositoryError,
 		},
+		{
+			name:     "Get all apps should return error when the limit is out of range",
+			query:    models.AppsQuery{Limit: MaxAppsLimit + 1},
+			wantErr:  models.ErrBadParamInput,
+			mockRepo: *mockRepositoryWithSuccessResults,
+		},
+		{
+			name:     "Get all apps should return error when the sort field is unknown",
+			query:    models.AppsQuery{Sort: "deletedAt"},
+			wantErr:  models.ErrBadParamInput,
+			mockRepo: *mockRepositoryWithSuccessResults,
+		},
+		{
+			name:     "Get all apps should return error when the cursor was issued for another sort",
+			query:    models.AppsQuery{Sort: "installs", Cursor: encodeAppsCursor(appsCursor{Sort: "name", Key: "foobar", ID: uuid.New().String()})},
+			wantErr:  models.ErrBadParamInput,
+			mockRepo: *mockRepositoryWithSuccessResults,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := NewService(&tt.mockRepo)
-			got, err := a.GetApps()
+			a := NewService(&tt.mockRepo, nil)
+			got, err := a.GetApps(tt.query)
 			if (err != nil) && tt.wantErr == nil {
 				t.Errorf("appsService.GetApps() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -133,7 +178,7 @@ func Test_appsService_GetApps(t *testing.T) {
 			if (err == nil) && (!reflect.DeepEqual(got, tt.want)) {
 				t.Errorf("appsService.GetApps() = %v, want %v", got, tt.want)
 			}
-			if (err != nil) && (tt.wantErr != err || tt.wantErr == nil) {
+			if (err != nil) && (!errors.Is(err, tt.wantErr) || tt.wantErr == nil) {
 				t.Errorf("appsService.GetApps() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -169,7 +214,7 @@ func Test_appsService_UpdateAppNameByID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := NewService(&tt.mockRepo)
+			a := NewService(&tt.mockRepo, nil)
 			err := a.UpdateAppNameByID(tt.id, tt.appName)
 			if (err != nil) && tt.wantErr == nil {
 				t.Errorf("appsService.UpdateAppNameByID() error = %v, wantErr %v", err, tt.wantErr)
@@ -208,7 +253,7 @@ func Test_appsService_DeleteAppById(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := NewService(&tt.repo)
+			a := NewService(&tt.repo, nil)
 			err := a.DeleteAppById(tt.id)
 
 			if (err != nil) && (tt.wantErr != err || tt.wantErr == nil) {
@@ -219,6 +264,66 @@ func Test_appsService_DeleteAppById(t *testing.T) {
 	}
 }
 
+func Test_appsService_RestoreAppByID(t *testing.T) {
+	deletedApp := helpers.GetMockApp()
+	deletedApp.DeletedAt = "2019-02-15T09:38:33+00:00"
+
+	tests := []struct {
+		name         string
+		id           string
+		app          *models.App
+		getErr       error
+		wantErr      error
+		wantUndelete bool
+	}{
+		{
+			name:         "Should restore an app which is soft deleted",
+			id:           deletedApp.ID,
+			app:          deletedApp,
+			wantUndelete: true,
+		},
+		{
+			name:    "Should return a conflict when the app is not deleted",
+			id:      helpers.GetMockApp().ID,
+			app:     helpers.GetMockApp(),
+			wantErr: models.ErrConflict,
+		},
+		{
+			name:    "Should return not found when the app does not exist",
+			id:      helpers.GetMockApp().ID,
+			getErr:  models.ErrNotFound,
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &RepositoryMock{
+				GetAppByIDFunc: func(id string) (*models.App, error) {
+					return tt.app, tt.getErr
+				},
+				UnDeleteAppByAppIDFunc: func(appID string) error {
+					return nil
+				},
+				GetAppVersionsByAppIDFunc: func(ID string) (*[]models.Version, error) {
+					return nil, models.ErrNotFound
+				},
+			}
+
+			a := NewService(repo, nil)
+			err := a.RestoreAppByID(tt.id)
+
+			if err != tt.wantErr {
+				t.Errorf("appsService.RestoreAppByID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got := len(repo.UnDeleteAppByAppIDCalls()) == 1; got != tt.wantUndelete {
+				t.Errorf("appsService.RestoreAppByID() undeleted = %v, want %v", got, tt.wantUndelete)
+			}
+		})
+	}
+}
+
 func Test_appsService_GetActiveAppByID(t *testing.T) {
 	type fields struct {
 		repository Repository
@@ -247,7 +352,7 @@ func Test_appsService_GetActiveAppByID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := NewService(&tt.mockRepo)
+			a := NewService(&tt.mockRepo, nil)
 			got, err := a.GetActiveAppByID(tt.id)
 			if (err != nil) && tt.wantErr == nil {
 				t.Errorf("appsService.GetActiveAppByID() error = %v, wantErr %v", err, tt.wantErr)
@@ -292,7 +397,7 @@ func Test_appsService_GetActiveAppByAppID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := NewService(&tt.mockRepo)
+			a := NewService(&tt.mockRepo, nil)
 			got, err := a.GetActiveAppByAppID(tt.appId)
 			if (err != nil) && tt.wantErr == nil {
 				t.Errorf("appsService.GetActiveAppByAppID() error = %v, wantErr %v", err, tt.wantErr)
@@ -342,7 +447,7 @@ func Test_appsService_DisableAllAppVersionsByAppID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := NewService(mockRepositoryWithSuccessResults)
+			a := NewService(mockRepositoryWithSuccessResults, nil)
 			err := a.DisableAllAppVersionsByAppID(tt.id, tt.msg)
 			if (err != nil) && (tt.wantErr != err || tt.wantErr == nil) {
 				t.Errorf("appsService.DisableAllAppVersionsByAppID() error = %v, wantErr %v", err, tt.wantErr)
@@ -388,7 +493,7 @@ func Test_appsService_UpdateAppVersions(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := NewService(&tt.repo)
+			a := NewService(&tt.repo, nil)
 			err := a.UpdateAppVersions(tt.id, tt.versions)
 
 			if (err != nil) && tt.wantErr == nil {
@@ -413,7 +518,7 @@ func Test_appsService_CreateApp(t *testing.T) {
 		GetAppByAppIDFunc: func(appID string) (*models.App, error) {
 			return nil, models.ErrNotFound
 		},
-		CreateAppFunc: func(id string, appId string, name string) error {
+		CreateAppFunc: func(id string, appId string, name string, tenantID string) error {
 			return nil
 		},
 		GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
@@ -429,7 +534,7 @@ func Test_appsService_CreateApp(t *testing.T) {
 		GetAppByAppIDFunc: func(appID string) (*models.App, error) {
 			return nil, models.ErrInternalServerError
 		},
-		CreateAppFunc: func(id string, appId string, name string) error {
+		CreateAppFunc: func(id string, appId string, name string, tenantID string) error {
 			return nil
 		},
 	}
@@ -441,7 +546,7 @@ func Test_appsService_CreateApp(t *testing.T) {
 		UnDeleteAppByAppIDFunc: func(appID string) error {
 			return nil
 		},
-		CreateAppFunc: func(id string, appId string, name string) error {
+		CreateAppFunc: func(id string, appId string, name string, tenantID string) error {
 			return models.ErrConflict
 		},
 		UpdateAppNameByIDFunc: func(appId string, name string) error {
@@ -458,7 +563,7 @@ func Test_appsService_CreateApp(t *testing.T) {
 			deletedApp.DeletedAt = "2019-02-15T09:38:33+00:00"
 			return deletedApp, nil
 		},
-		CreateAppFunc: func(id string, appId string, name string) error {
+		CreateAppFunc: func(id string, appId string, name string, tenantID string) error {
 			return nil
 		},
 		GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
@@ -467,8 +572,14 @@ func Test_appsService_CreateApp(t *testing.T) {
 		UpdateAppNameByIDFunc: func(appId string, name string) error {
 			return nil
 		},
+		GetAppVersionsByAppIDFunc: func(ID string) (*[]models.Version, error) {
+			return nil, models.ErrNotFound
+		},
 	}
 
+	mockAppOfAnotherTenant := helpers.GetMockApp()
+	mockAppOfAnotherTenant.TenantID = "6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d"
+
 	mockAppWithoutName := &models.App{
 		AppID: "com.aerogear.mobile_app_one",
 	}
@@ -515,6 +626,12 @@ func Test_appsService_CreateApp(t *testing.T) {
 			data: helpers.GetMockApp(),
 			repo: *mockRepositoryWithDeletedAppSuccessResults,
 		},
+		{
+			name:    "Should not restore a deleted app of another tenant",
+			data:    mockAppOfAnotherTenant,
+			repo:    *mockRepositoryWithDeletedAppSuccessResults,
+			wantErr: models.ErrConflict,
+		},
 		{
 			name:    "Should return error to binding an new app by app_id and name",
 			data:    helpers.GetMockApp(),
@@ -530,13 +647,17 @@ func Test_appsService_CreateApp(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			a := NewService(&tt.repo)
+			a := NewService(&tt.repo, nil)
 			err := a.CreateApp(*tt.data)
 
 			if (err != nil) && (tt.wantErr != err || tt.wantErr == nil) {
 				t.Errorf("appsService.CreateApp() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+
+			if tt.wantErr == models.ErrConflict && len(tt.repo.UnDeleteAppByAppIDCalls()) != 0 {
+				t.Errorf("appsService.CreateApp() restored the app on a conflict")
+			}
 		})
 	}
 }
@@ -717,9 +838,15 @@ func Test_appsService_InitClientApp(t *testing.T) {
 
 					return nil
 				},
+				GetReleasesByAppIDFunc: func(appID string) (*models.Releases, error) {
+					return &models.Releases{AppID: appID, UnknownVersions: models.UnknownVersionsFlag, Versions: []string{}}, nil
+				},
+				GetSigningPolicyByAppIDFunc: func(appID string) (*models.SigningPolicy, error) {
+					return &models.SigningPolicy{AppID: appID, TamperedBuilds: models.TamperedBuildsFlag}, nil
+				},
 			}
 
-			service := NewService(mockedRepository)
+			service := NewService(mockedRepository, nil)
 
 			got, err := service.InitClientApp(tt.args.deviceInfo)
 
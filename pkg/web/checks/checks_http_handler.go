@@ -1,23 +1,23 @@
 package checks
 
 import (
-	"github.com/aerogear/mobile-security-service/pkg/models"
-	"github.com/aerogear/mobile-security-service/pkg/web/apps"
-	"github.com/labstack/echo"
 	"net/http"
+
+	"github.com/aerogear/mobile-security-service/pkg/health"
+	"github.com/labstack/echo"
 )
 
 type (
 	// HTTPHandler instance
 	HTTPHandler struct {
-		appsService apps.Service
+		health *health.Registry
 	}
 )
 
 // NewHTTPHandler returns a new instance of app.Handler
-func NewHTTPHandler(e *echo.Echo, a apps.Service) *HTTPHandler {
+func NewHTTPHandler(e *echo.Echo, h *health.Registry) *HTTPHandler {
 	return &HTTPHandler{
-		appsService: a,
+		health: h,
 	}
 }
 
@@ -28,13 +28,11 @@ func (a *HTTPHandler) Ping(c echo.Context) error {
 
 //Check if the server is able to receive requests - Readiness
 func (a *HTTPHandler) Healthz(c echo.Context) error {
-	// TODO: Create an specific service to check if it is readiness
-	_, err := a.appsService.GetApps()
-	if err != nil {
-		if err == models.ErrNotFound {
-			return c.JSON(http.StatusOK, "OK")
-		}
-		return c.JSON(http.StatusInternalServerError, err)
+	report := a.health.Run(c.Request().Context())
+
+	if report.Status != health.StatusUp {
+		return c.JSON(http.StatusServiceUnavailable, report)
 	}
-	return c.JSON(http.StatusOK, "OK")
+
+	return c.JSON(http.StatusOK, report)
 }
@@ -1,36 +1,27 @@
 package checks
 
 import (
-	"github.com/aerogear/mobile-security-service/pkg/helpers"
-	"github.com/aerogear/mobile-security-service/pkg/models"
-	"github.com/aerogear/mobile-security-service/pkg/web/apps"
-	"github.com/labstack/echo"
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/health"
+	"github.com/aerogear/mobile-security-service/pkg/lifecycle"
+	"github.com/labstack/echo"
 )
 
 var (
-	// make and configure a mocked Service which will return the success scenarios
-	mockedServiceSuccess = &apps.ServiceMock{
-		GetAppsFunc: func() (*[]models.App, error) {
-			return &[]models.App{
-				*helpers.GetMockApp(),
-			}, nil
-		},
-	}
+	checkerUp = health.CheckerFunc(func(ctx context.Context) error {
+		return nil
+	})
 
-	mockedServiceNoData = &apps.ServiceMock{
-		GetAppsFunc: func() (*[]models.App, error) {
-			return nil, models.ErrNotFound
-		},
-	}
-
-	mockedServiceError = &apps.ServiceMock{
-		GetAppsFunc: func() (*[]models.App, error) {
-			return nil, models.ErrInternalServerError
-		},
-	}
+	checkerDown = health.CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
 )
 
 func Test_HttpHandler_Ping(t *testing.T) {
@@ -53,7 +44,7 @@ func Test_HttpHandler_Ping(t *testing.T) {
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 			c.SetPath("/api/ping")
-			h := NewHTTPHandler(e, mockedServiceSuccess)
+			h := NewHTTPHandler(e, health.NewRegistry(time.Second))
 			err := h.Ping(c)
 			if err != nil {
 				t.Errorf("httpHandler.Ping() error = %v, wantErr %v", err, tt.wantErr)
@@ -66,33 +57,31 @@ func Test_HttpHandler_Ping(t *testing.T) {
 }
 
 func Test_HttpHandler_Healthz(t *testing.T) {
-	type fields struct {
-		Service apps.Service
-	}
 	tests := []struct {
-		name        string
-		fields      fields
-		wantErr     bool
-		wantCode    int
-		mockService apps.ServiceMock
+		name       string
+		checks     map[string]health.Checker
+		draining   bool
+		wantCode   int
+		wantStatus health.Status
 	}{
 		{
-			name:        "Should return success",
-			wantErr:     false,
-			mockService: *mockedServiceSuccess,
-			wantCode:    200,
+			name:       "Should return success when all checks pass",
+			checks:     map[string]health.Checker{"database": checkerUp, "schema": checkerUp},
+			wantCode:   200,
+			wantStatus: health.StatusUp,
 		},
 		{
-			name:        "Should return success",
-			wantErr:     false,
-			mockService: *mockedServiceNoData,
-			wantCode:    200,
+			name:       "Should return service unavailable when a check fails",
+			checks:     map[string]health.Checker{"database": checkerDown, "schema": checkerUp},
+			wantCode:   503,
+			wantStatus: health.StatusDown,
 		},
 		{
-			name:        "Should return error",
-			wantErr:     true,
-			mockService: *mockedServiceError,
-			wantCode:    500,
+			name:       "Should return service unavailable when the server is draining",
+			checks:     map[string]health.Checker{"database": checkerUp},
+			draining:   true,
+			wantCode:   503,
+			wantStatus: health.StatusDown,
 		},
 	}
 	for _, tt := range tests {
@@ -103,14 +92,36 @@ func Test_HttpHandler_Healthz(t *testing.T) {
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 			c.SetPath("/api/healthz")
-			h := NewHTTPHandler(e, &tt.mockService)
-			err := h.Healthz(c)
-			if err != nil {
-				t.Errorf("httpHandler.Health() error = %v, wantErr %v", err, tt.wantErr)
+
+			l := lifecycle.NewManager()
+			if tt.draining {
+				l.StartDraining()
+			}
+
+			registry := health.NewRegistry(time.Second)
+			registry.Register("server", l)
+			for name, checker := range tt.checks {
+				registry.Register(name, checker)
+			}
+
+			h := NewHTTPHandler(e, registry)
+			if err := h.Healthz(c); err != nil {
+				t.Errorf("httpHandler.Health() error = %v", err)
 			}
 			if rec.Code != tt.wantCode {
 				t.Errorf("HTTPHandler.Health() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
 			}
+
+			var report health.Report
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatalf("HTTPHandler.Health() returned an invalid report: %v", err)
+			}
+			if report.Status != tt.wantStatus {
+				t.Errorf("HTTPHandler.Health() report status = %v, wantStatus = %v", report.Status, tt.wantStatus)
+			}
+			if len(report.Checks) != len(tt.checks)+1 {
+				t.Errorf("HTTPHandler.Health() report has %v checks, want %v", len(report.Checks), len(tt.checks)+1)
+			}
 		})
 	}
 }
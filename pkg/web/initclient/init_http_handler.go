@@ -3,12 +3,15 @@ package initclient
 import (
 	"errors"
 	log "github.com/sirupsen/logrus"
+	"net"
 	"net/http"
 
-	"github.com/aerogear/mobile-security-service/pkg/helpers"
 	"github.com/aerogear/mobile-security-service/pkg/httperrors"
 	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/ratelimit"
 	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+	"github.com/aerogear/mobile-security-service/pkg/web/user"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
 	"github.com/labstack/echo"
 )
 
@@ -16,35 +19,49 @@ type (
 	// HTTPHandler instance
 	HTTPHandler struct {
 		appsService apps.Service
+		limiter     *ratelimit.Limiter
+		proxies     []*net.IPNet
 	}
 )
 
-// NewHTTPHandler returns a new instance of app.Handler
-func NewHTTPHandler(e *echo.Echo, a apps.Service) *HTTPHandler {
+// NewHTTPHandler returns a new instance of app.Handler. The requests are limited by client IP,
+// app and device with the limiter, unless it is nil. The client IP is only read from the headers
+// of the requests of the trusted proxies.
+func NewHTTPHandler(e *echo.Echo, a apps.Service, l *ratelimit.Limiter, proxies []*net.IPNet) *HTTPHandler {
 	return &HTTPHandler{
 		appsService: a,
+		limiter:     l,
+		proxies:     proxies,
 	}
 }
 
 // InitClientApp stores device information and returns if the app version is disabled
 func (h *HTTPHandler) InitClientApp(c echo.Context) error {
-	deviceInfo := new(models.Device)
-
-	if err := c.Bind(deviceInfo); err != nil {
-		log.Info(err)
-		return err
+	// The client IP is limited before the body is read, and the app and device once it is valid
+	if res := h.allow(ratelimit.ClientIP, user.ClientIP(c.Request(), h.proxies)); !res.Allowed {
+		return tooManyRequests(c, res)
 	}
 
+	req := initRequest{}
+
 	// Check the request body is valid
-	if err := validateInitBody(deviceInfo); err != nil {
+	if err := validation.Body(c, &req); err != nil {
 		log.Info(err)
-		return httperrors.BadRequest(c, err.Error())
+		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
 
-	initResponse, err := h.appsService.InitClientApp(deviceInfo)
+	if res := h.allow(ratelimit.App, req.AppID); !res.Allowed {
+		return tooManyRequests(c, res)
+	}
+
+	if res := h.allow(ratelimit.Device, req.DeviceID); !res.Allowed {
+		return tooManyRequests(c, res)
+	}
+
+	version, err := h.appsService.InitClientApp(req.toModel())
 
 	// If no app has been found in the database, return a bad request to the client
-	if err == models.ErrNotFound {
+	if errors.Is(err, models.ErrNotFound) {
 		return httperrors.BadRequest(c, "No bound app found for the sent App ID")
 	}
 
@@ -52,23 +69,23 @@ func (h *HTTPHandler) InitClientApp(c echo.Context) error {
 		return httperrors.GetHTTPResponseFromErr(c, err)
 	}
 
-	return c.JSON(http.StatusOK, initResponse)
+	return c.JSON(http.StatusOK, newInitResponse(*version))
 }
 
-// validateInitBody validates the properties of an init
-// request and returns an error if any of them are missing
-func validateInitBody(d *models.Device) error {
-	if d.Version == "" {
-		return errors.New("version property is required")
+// allow takes the request from the bucket of the value of a dimension, when the requests are limited
+func (h *HTTPHandler) allow(dimension, value string) ratelimit.Result {
+	if h.limiter == nil {
+		return ratelimit.Result{Allowed: true}
 	}
 
-	if d.AppID == "" {
-		return errors.New("appId property is required")
-	}
-
-	if !helpers.IsValidUUID(d.DeviceID) {
-		return errors.New("appId is invalid")
+	res := h.limiter.Allow(dimension, value)
+	if !res.Allowed {
+		log.WithFields(log.Fields{"dimension": dimension, "value": value}).Info("Rejected an init request over the rate limit")
 	}
+	return res
+}
 
-	return nil
+// tooManyRequests rejects a request over a rate limit with the time the client has to wait
+func tooManyRequests(c echo.Context, res ratelimit.Result) error {
+	return httperrors.TooManyRequests(c, "Too many init requests, retry later", res.RetryAfter)
 }
@@ -3,16 +3,20 @@ package initclient
 import (
 	"encoding/json"
 	"github.com/google/uuid"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/aerogear/mobile-security-service/pkg/helpers"
 
 	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/ratelimit"
 
 	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
 	"github.com/labstack/echo"
 )
 
@@ -30,6 +34,9 @@ func TestHTTPHandler_InitClientApp(t *testing.T) {
 	deviceWithoutAppID := *validDevice
 	deviceWithoutAppID.AppID = ""
 
+	deviceWithInvalidDeviceID := *validDevice
+	deviceWithInvalidDeviceID.DeviceID = "invalid"
+
 	type fields struct {
 		appsService apps.Service
 	}
@@ -41,10 +48,11 @@ func TestHTTPHandler_InitClientApp(t *testing.T) {
 		fields         fields
 		args           args
 		wantStatusCode int
+		wantFields     []models.FieldError
 		mockAppService *apps.ServiceMock
 	}{
 		{
-			name: "A 400 Bad Request should be returned when request body is missing device ID",
+			name: "A 422 Unprocessable Entity should be returned when request body is missing device ID",
 			args: args{
 				device: deviceWithoutDeviceID,
 			},
@@ -53,10 +61,10 @@ func TestHTTPHandler_InitClientApp(t *testing.T) {
 					return nil, nil
 				},
 			},
-			wantStatusCode: 400,
+			wantStatusCode: 422,
 		},
 		{
-			name: "A 400 Bad Request should be returned when request body is missing version",
+			name: "A 422 Unprocessable Entity should be returned when request body is missing version",
 			args: args{
 				device: deviceWithoutVersion,
 			},
@@ -65,14 +73,27 @@ func TestHTTPHandler_InitClientApp(t *testing.T) {
 					return nil, nil
 				},
 			},
-			wantStatusCode: 400,
+			wantStatusCode: 422,
 		},
 		{
-			name: "A 400 Bad Request should be returned when request body is missing app ID",
+			name: "A 422 Unprocessable Entity should be returned when request body is missing app ID",
 			args: args{
 				device: deviceWithoutAppID,
 			},
-			wantStatusCode: 400,
+			wantStatusCode: 422,
+			mockAppService: &apps.ServiceMock{
+				InitClientAppFunc: func(device *models.Device) (*models.Version, error) {
+					return nil, nil
+				},
+			},
+		},
+		{
+			name: "A 422 Unprocessable Entity should be returned when the device ID is not a UUID",
+			args: args{
+				device: deviceWithInvalidDeviceID,
+			},
+			wantStatusCode: 422,
+			wantFields:     []models.FieldError{{Field: "deviceId", Message: "deviceId must be a valid UUID"}},
 			mockAppService: &apps.ServiceMock{
 				InitClientAppFunc: func(device *models.Device) (*models.Version, error) {
 					return nil, nil
@@ -101,6 +122,7 @@ func TestHTTPHandler_InitClientApp(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			e := echo.New()
+			e.Validator = validation.NewValidator()
 
 			deviceJSON, _ := json.Marshal(tt.args.device)
 
@@ -111,11 +133,150 @@ func TestHTTPHandler_InitClientApp(t *testing.T) {
 			c := e.NewContext(req, rec)
 			c.SetPath("/api/init")
 
-			handler := NewHTTPHandler(e, tt.mockAppService)
+			handler := NewHTTPHandler(e, tt.mockAppService, nil, nil)
 
 			if handler.InitClientApp(c); rec.Code != tt.wantStatusCode {
 				t.Errorf("HTTPHandler.InitClientApp() statusCode = %v, wantStatusCode %v", rec.Code, tt.wantStatusCode)
 			}
+
+			if tt.wantFields != nil {
+				body := struct {
+					Errors []models.FieldError `json:"errors"`
+				}{}
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || !reflect.DeepEqual(body.Errors, tt.wantFields) {
+					t.Errorf("HTTPHandler.InitClientApp() errors = %+v, wantFields %+v", body.Errors, tt.wantFields)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_InitClientApp_rateLimit(t *testing.T) {
+	device := *helpers.GetMockDevice()
+	device.Version = "1.0.0"
+	otherDevice := device
+	otherDevice.DeviceID = uuid.New().String()
+
+	tests := []struct {
+		name          string
+		limits        map[string]ratelimit.Limit
+		devices       []models.Device
+		wantCodes     []int
+		wantInitCalls int
+	}{
+		{
+			name:          "Should reject the requests of a device over its limit",
+			limits:        map[string]ratelimit.Limit{ratelimit.Device: ratelimit.PerMinute(1)},
+			devices:       []models.Device{device, device, otherDevice},
+			wantCodes:     []int{200, 429, 200},
+			wantInitCalls: 2,
+		},
+		{
+			name:          "Should reject the requests of an app over its limit",
+			limits:        map[string]ratelimit.Limit{ratelimit.App: ratelimit.PerMinute(1)},
+			devices:       []models.Device{device, otherDevice},
+			wantCodes:     []int{200, 429},
+			wantInitCalls: 1,
+		},
+		{
+			name:          "Should reject the requests of a client IP over its limit before reading the body",
+			limits:        map[string]ratelimit.Limit{ratelimit.ClientIP: ratelimit.PerMinute(1)},
+			devices:       []models.Device{{}, otherDevice},
+			wantCodes:     []int{422, 429},
+			wantInitCalls: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			appsService := &apps.ServiceMock{
+				InitClientAppFunc: func(device *models.Device) (*models.Version, error) {
+					return &models.Version{ID: uuid.New().String(), Version: device.Version, AppID: device.AppID}, nil
+				},
+			}
+			handler := NewHTTPHandler(e, appsService, ratelimit.NewLimiter(ratelimit.NewMemoryStore(), tt.limits), nil)
+
+			for i, device := range tt.devices {
+				deviceJSON, _ := json.Marshal(device)
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(deviceJSON)))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+				c := e.NewContext(req, rec)
+				c.SetPath("/api/init")
+
+				if handler.InitClientApp(c); rec.Code != tt.wantCodes[i] {
+					t.Errorf("HTTPHandler.InitClientApp() request %v statusCode = %v, wantStatusCode %v", i, rec.Code, tt.wantCodes[i])
+				}
+
+				if rec.Code == 429 && rec.Header().Get("Retry-After") != "60" {
+					t.Errorf("HTTPHandler.InitClientApp() request %v Retry-After = %v, want 60", i, rec.Header().Get("Retry-After"))
+				}
+			}
+
+			if got := len(appsService.InitClientAppCalls()); got != tt.wantInitCalls {
+				t.Errorf("HTTPHandler.InitClientApp() initialised %v devices, want %v", got, tt.wantInitCalls)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_InitClientApp_rateLimitOfForwardedIPs(t *testing.T) {
+	device := *helpers.GetMockDevice()
+	device.Version = "1.0.0"
+	_, proxy, _ := net.ParseCIDR("10.0.0.0/8")
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		wantCodes  []int
+	}{
+		{
+			name:       "Should limit a client by its address whatever the X-Forwarded-For it sends",
+			remoteAddr: "192.0.2.1:1234",
+			forwarded:  []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"},
+			wantCodes:  []int{200, 429, 429},
+		},
+		{
+			name:       "Should limit the clients of a trusted proxy by their forwarded address",
+			remoteAddr: "10.0.0.7:4180",
+			forwarded:  []string{"198.51.100.1", "198.51.100.2", "198.51.100.1"},
+			wantCodes:  []int{200, 200, 429},
+		},
+		{
+			name:       "Should limit the clients of a trusted proxy by the address the proxy forwarded last",
+			remoteAddr: "10.0.0.7:4180",
+			forwarded:  []string{"203.0.113.1, 198.51.100.1", "203.0.113.2, 198.51.100.1"},
+			wantCodes:  []int{200, 429},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = validation.NewValidator()
+			appsService := &apps.ServiceMock{
+				InitClientAppFunc: func(device *models.Device) (*models.Version, error) {
+					return &models.Version{ID: uuid.New().String(), Version: device.Version, AppID: device.AppID}, nil
+				},
+			}
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{ratelimit.ClientIP: ratelimit.PerMinute(1)})
+			handler := NewHTTPHandler(e, appsService, limiter, []*net.IPNet{proxy})
+
+			for i, forwarded := range tt.forwarded {
+				deviceJSON, _ := json.Marshal(device)
+				req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(deviceJSON)))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				req.Header.Set(echo.HeaderXForwardedFor, forwarded)
+				req.RemoteAddr = tt.remoteAddr
+				rec := httptest.NewRecorder()
+				c := e.NewContext(req, rec)
+				c.SetPath("/api/init")
+
+				if handler.InitClientApp(c); rec.Code != tt.wantCodes[i] {
+					t.Errorf("HTTPHandler.InitClientApp() request %v statusCode = %v, wantStatusCode %v", i, rec.Code, tt.wantCodes[i])
+				}
+			}
 		})
 	}
 }
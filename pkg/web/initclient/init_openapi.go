@@ -0,0 +1,11 @@
+package initclient
+
+import "github.com/aerogear/mobile-security-service/pkg/openapi"
+
+// Schemas returns the request and response of the init endpoint by the name of their schema in the API documentation
+func Schemas() openapi.Schemas {
+	return openapi.Schemas{
+		"InitRequest":  initRequest{},
+		"InitResponse": initResponse{},
+	}
+}
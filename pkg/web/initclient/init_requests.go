@@ -0,0 +1,30 @@
+package initclient
+
+import "github.com/aerogear/mobile-security-service/pkg/models"
+
+// initRequest is the body of POST /init sent by the SDK, with the validation rules of each field
+// swagger:model InitRequest
+type initRequest struct {
+	// required: true
+	AppID string `json:"appId" validate:"required"`
+	// required: true
+	DeviceID string `json:"deviceId" validate:"required,uuid"`
+	// required: true
+	Version       string `json:"version" validate:"required"`
+	DeviceVersion string `json:"deviceVersion"`
+	DeviceType    string `json:"deviceType"`
+	// SigningCertificate is the SHA-256 fingerprint of the certificate which signed the build of the app
+	SigningCertificate string `json:"signingCertificate" validate:"omitempty,fingerprint"`
+}
+
+// toModel maps the request to the device which is initialising the app
+func (r *initRequest) toModel() *models.Device {
+	return &models.Device{
+		AppID:              r.AppID,
+		DeviceID:           r.DeviceID,
+		Version:            r.Version,
+		DeviceVersion:      r.DeviceVersion,
+		DeviceType:         r.DeviceType,
+		SigningCertificate: r.SigningCertificate,
+	}
+}
@@ -0,0 +1,24 @@
+package initclient
+
+import "github.com/aerogear/mobile-security-service/pkg/models"
+
+// initResponse is the response of POST /init with the state of the version used by the device
+// swagger:model InitResponse
+type initResponse struct {
+	ID              string `json:"id"`
+	AppID           string `json:"appId"`
+	Version         string `json:"version"`
+	Disabled        bool   `json:"disabled"`
+	DisabledMessage string `json:"disabledMessage"`
+}
+
+// newInitResponse maps the version used by the device to the response
+func newInitResponse(v models.Version) initResponse {
+	return initResponse{
+		ID:              v.ID,
+		AppID:           v.AppID,
+		Version:         v.Version,
+		Disabled:        v.Disabled,
+		DisabledMessage: v.DisabledMessage,
+	}
+}
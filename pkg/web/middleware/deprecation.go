@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// Deprecated adds the headers announcing that the routes are deprecated to their responses:
+// Deprecation, a Link to the successor version and, when the sunset is set, the date they will be removed.
+func Deprecated(successor string, sunset time.Time) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set("Deprecation", "true")
+			header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+
+			if !sunset.IsZero() {
+				header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+func TestDeprecated(t *testing.T) {
+	tests := []struct {
+		name       string
+		sunset     time.Time
+		wantHeader http.Header
+	}{
+		{
+			name: "Deprecated() should link to the successor version",
+			wantHeader: http.Header{
+				"Deprecation": []string{"true"},
+				"Link":        []string{`</api/v2>; rel="successor-version"`},
+			},
+		},
+		{
+			name:   "Deprecated() should send the sunset date when it is set",
+			sunset: time.Date(2020, time.June, 30, 0, 0, 0, 0, time.UTC),
+			wantHeader: http.Header{
+				"Deprecation": []string{"true"},
+				"Link":        []string{`</api/v2>; rel="successor-version"`},
+				"Sunset":      []string{"Tue, 30 Jun 2020 00:00:00 GMT"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/apps", nil), rec)
+
+			handler := Deprecated("/api/v2", tt.sunset)(func(c echo.Context) error {
+				return c.NoContent(http.StatusNoContent)
+			})
+
+			if err := handler(c); err != nil {
+				t.Fatalf("Deprecated() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(rec.Header(), tt.wantHeader) {
+				t.Errorf("Deprecated() header = %v, want %v", rec.Header(), tt.wantHeader)
+			}
+		})
+	}
+}
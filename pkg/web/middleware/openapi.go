@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/openapi"
+	"github.com/labstack/echo"
+	log "github.com/sirupsen/logrus"
+)
+
+// OpenAPIConfig defines the config of the OpenAPI validation middleware
+type OpenAPIConfig struct {
+	// Document holds the operations the requests are validated against
+	Document *openapi.Document
+
+	// Path returns the path of the operation of a request in the document, e.g. /v1/apps/{id}.
+	// By default it is the path of the route without the URL of the server of the document.
+	Path func(c echo.Context) string
+
+	// ErrorHandler writes the response of a request which is not valid from a models error,
+	// e.g. httperrors.GetHTTPResponseFromErr
+	ErrorHandler func(c echo.Context, err error) error
+
+	// ValidateResponses also checks the status and the JSON body of the responses against the document.
+	// A response which does not match is replaced by an internal server error with the violations.
+	// It buffers every response, so it is meant to catch the mismatches between the handlers and the document in tests.
+	ValidateResponses bool
+}
+
+// OpenAPIValidatorWithConfig validates the path and query parameters and the JSON body of the requests
+// against their operation in the document before the handlers run. The requests of the routes which
+// are not documented are not validated, nor are the bodies sent in another media type documented by their operation.
+// The invalid parameters and the malformed bodies are reported as a bad request, and the bodies
+// which do not match their schema as a validation error, as the handlers do.
+func OpenAPIValidatorWithConfig(config OpenAPIConfig) echo.MiddlewareFunc {
+	if config.Path == nil {
+		config.Path = routePath(config.Document)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			op := config.Document.Operation(c.Request().Method, config.Path(c))
+			if op == nil {
+				return next(c)
+			}
+
+			if err := validateRequest(config.Document, op, c); err != nil {
+				return config.ErrorHandler(c, err)
+			}
+
+			if !config.ValidateResponses {
+				return next(c)
+			}
+
+			return validateResponse(config, op, c, next)
+		}
+	}
+}
+
+// routePath returns the path of the route of a request without the URL of the server of the document
+func routePath(doc *openapi.Document) func(c echo.Context) string {
+	prefix := ""
+	if len(doc.Servers) > 0 {
+		prefix = doc.Servers[0].URL
+	}
+
+	return func(c echo.Context) string {
+		return openapi.PathFromRoute(strings.TrimPrefix(c.Path(), prefix))
+	}
+}
+
+func validateRequest(doc *openapi.Document, op *openapi.Operation, c echo.Context) error {
+	var fields []models.FieldError
+	for _, p := range op.Parameters {
+		var raw string
+		switch p.In {
+		case "path":
+			raw = c.Param(p.Name)
+		case "query":
+			if _, ok := c.QueryParams()[p.Name]; !ok {
+				if p.Required {
+					fields = append(fields, models.FieldError{Field: p.Name, Message: fmt.Sprintf("%v is required", p.Name)})
+				}
+				continue
+			}
+			raw = c.QueryParam(p.Name)
+		default:
+			continue
+		}
+
+		fields = append(fields, doc.ValidateParameter(p, raw)...)
+	}
+
+	if len(fields) > 0 {
+		return &models.Error{
+			Code:    models.CodeBadParamInput,
+			Message: "Invalid parameters supplied",
+			Fields:  fields,
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+
+	req := c.Request()
+
+	// the bodies of the other documented media types, e.g. YAML, are decoded and validated by the handlers
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if _, ok := op.RequestBody.Content[contentType]; ok && contentType != echo.MIMEApplicationJSON {
+		return nil
+	}
+
+	media, ok := op.RequestBody.Content[echo.MIMEApplicationJSON]
+	if !ok {
+		return nil
+	}
+
+	b, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return models.ErrBadParamInput.WithMessage("Invalid data").Wrap(err)
+	}
+	// the handlers decode the body again
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+
+	var body interface{}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return models.ErrBadParamInput.WithMessage("Invalid data").Wrap(err)
+	}
+
+	if fields := doc.Validate("body", media.Schema, body); len(fields) > 0 {
+		return models.NewValidationError(fields...)
+	}
+
+	return nil
+}
+
+// validateResponse runs the handler with a buffered response and only sends it when it matches the document
+func validateResponse(config OpenAPIConfig, op *openapi.Operation, c echo.Context, next echo.HandlerFunc) error {
+	res := c.Response()
+	writer := res.Writer
+	buffer := &bufferedResponse{header: writer.Header()}
+	res.Writer = buffer
+
+	err := next(c)
+	res.Writer = writer
+	if err != nil {
+		return err
+	}
+
+	fields := responseViolations(config.Document, op, buffer)
+	if len(fields) == 0 {
+		writer.WriteHeader(buffer.status)
+		_, err := writer.Write(buffer.body.Bytes())
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"method":     c.Request().Method,
+		"path":       c.Path(),
+		"violations": fields,
+	}).Error("The response does not match the API documentation")
+
+	// the buffered response was never sent, so the error can replace it
+	res.Committed = false
+	res.Size = 0
+	writer.Header().Del(echo.HeaderContentType)
+
+	return config.ErrorHandler(c, &models.Error{
+		Code:    models.CodeInternalServerError,
+		Message: "The response does not match the API documentation",
+		Fields:  fields,
+	})
+}
+
+func responseViolations(doc *openapi.Document, op *openapi.Operation, buffer *bufferedResponse) []models.FieldError {
+	response, ok := op.Responses[strconv.Itoa(buffer.status)]
+	if !ok {
+		return []models.FieldError{{Field: "status", Message: fmt.Sprintf("status %v is not documented", buffer.status)}}
+	}
+
+	if len(response.Content) == 0 {
+		if buffer.body.Len() > 0 {
+			return []models.FieldError{{Field: "body", Message: "body must be empty"}}
+		}
+		return nil
+	}
+
+	contentType, _, _ := mime.ParseMediaType(buffer.header.Get(echo.HeaderContentType))
+	media, ok := response.Content[contentType]
+	if !ok {
+		return []models.FieldError{{Field: "Content-Type", Message: fmt.Sprintf("Content-Type %v is not documented", contentType)}}
+	}
+
+	if !strings.HasSuffix(contentType, "json") {
+		return nil
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(buffer.body.Bytes(), &body); err != nil {
+		return []models.FieldError{{Field: "body", Message: "body must be valid JSON"}}
+	}
+
+	return doc.ValidateStrict("body", media.Schema, body)
+}
+
+// bufferedResponse holds the status and the body of a response until it is validated.
+// The headers are set on the response directly.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *bufferedResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *bufferedResponse) WriteHeader(status int) {
+	r.status = status
+}
+
+// Flush does nothing, as the response is sent once the handler returns, e.g. at the end of an event stream
+func (r *bufferedResponse) Flush() {}
+
+func (r *bufferedResponse) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
@@ -0,0 +1,256 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/openapi"
+	"github.com/labstack/echo"
+)
+
+type itemRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type itemResponse struct {
+	ID string `json:"id"`
+}
+
+const itemID = "1b9e7a5f-af7c-4055-b488-72f2b5f72266"
+
+// newItemsDocument returns a document with a single operation: POST /api/items/{id}
+func newItemsDocument() *openapi.Document {
+	max := 10.0
+	doc := openapi.NewDocument(openapi.Options{
+		ServerURL: "/api",
+		Schemas:   []openapi.Schemas{{"ItemRequest": itemRequest{}, "ItemResponse": itemResponse{}}},
+	})
+	doc.AddOperation(http.MethodPost, "/items/{id}", &openapi.Operation{
+		OperationID: "updateItem",
+		Parameters: []openapi.Parameter{
+			{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+			{Name: "limit", In: "query", Schema: &openapi.Schema{Type: "integer", Maximum: &max}},
+		},
+		RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(openapi.Ref("ItemRequest"))},
+		Responses: map[string]*openapi.Response{
+			"200": {Description: "The item", Content: openapi.JSON(openapi.Ref("ItemResponse"))},
+			"204": {Description: "No content"},
+		},
+	})
+	return doc
+}
+
+// serveItems serves a request with the middleware in front of the handler, returning the error sent to the error handler
+func serveItems(config OpenAPIConfig, target, body string, handler echo.HandlerFunc) (*httptest.ResponseRecorder, error) {
+	var got error
+	config.Document = newItemsDocument()
+	config.ErrorHandler = func(c echo.Context, err error) error {
+		got = err
+		return c.NoContent(http.StatusTeapot)
+	}
+
+	e := echo.New()
+	g := e.Group("/api", OpenAPIValidatorWithConfig(config))
+	g.POST("/items/:id", handler)
+	g.POST("/other", handler)
+
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec, got
+}
+
+func TestOpenAPIValidatorWithConfig_Requests(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		wantCalled bool
+		wantErr    error
+		wantFields []models.FieldError
+	}{
+		{
+			name:       "A valid request should be passed to the handler",
+			target:     "/api/items/" + itemID + "?limit=5",
+			body:       `{"name":"item"}`,
+			wantCalled: true,
+		},
+		{
+			name:       "An invalid path parameter should be a bad request",
+			target:     "/api/items/1",
+			body:       `{"name":"item"}`,
+			wantErr:    models.ErrBadParamInput,
+			wantFields: []models.FieldError{{Field: "id", Message: "id must be a valid UUID"}},
+		},
+		{
+			name:       "An invalid query parameter should be a bad request",
+			target:     "/api/items/" + itemID + "?limit=20",
+			body:       `{"name":"item"}`,
+			wantErr:    models.ErrBadParamInput,
+			wantFields: []models.FieldError{{Field: "limit", Message: "limit must be at most 10"}},
+		},
+		{
+			name:    "A malformed body should be a bad request",
+			target:  "/api/items/" + itemID,
+			body:    `{"name":`,
+			wantErr: models.ErrBadParamInput,
+		},
+		{
+			name:       "A body which does not match its schema should be a validation error",
+			target:     "/api/items/" + itemID,
+			body:       `{"name":""}`,
+			wantErr:    models.ErrValidationFailed,
+			wantFields: []models.FieldError{{Field: "name", Message: "name must not be empty"}},
+		},
+		{
+			name:       "A request of a route which is not documented should not be validated",
+			target:     "/api/other",
+			body:       `{"name":`,
+			wantCalled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			_, err := serveItems(OpenAPIConfig{}, tt.target, tt.body, func(c echo.Context) error {
+				called = true
+				// the body must still be readable by the handler
+				req := itemRequest{}
+				if err := c.Bind(&req); err != nil && tt.target != "/api/other" {
+					t.Errorf("the handler could not decode the body: %v", err)
+				}
+				return c.NoContent(http.StatusNoContent)
+			})
+
+			if called != tt.wantCalled {
+				t.Errorf("handler called = %v, want %v", called, tt.wantCalled)
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OpenAPIValidatorWithConfig() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantFields != nil {
+				var modelErr *models.Error
+				errors.As(err, &modelErr)
+				if !reflect.DeepEqual(modelErr.Fields, tt.wantFields) {
+					t.Errorf("OpenAPIValidatorWithConfig() fields = %v, want %v", modelErr.Fields, tt.wantFields)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAPIValidatorWithConfig_Responses(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		wantStatus int
+		wantBody   string
+		wantFields []models.FieldError
+	}{
+		{
+			name: "A response which matches the document should be sent",
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, itemResponse{ID: itemID})
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"id":"` + itemID + `"}`,
+		},
+		{
+			name: "A response with a property which is not documented should be an error",
+			handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]string{"id": itemID, "secret": "value"})
+			},
+			wantStatus: http.StatusTeapot,
+			wantFields: []models.FieldError{{Field: "secret", Message: "secret is not allowed"}},
+		},
+		{
+			name: "A response with a status which is not documented should be an error",
+			handler: func(c echo.Context) error {
+				return c.NoContent(http.StatusCreated)
+			},
+			wantStatus: http.StatusTeapot,
+			wantFields: []models.FieldError{{Field: "status", Message: "status 201 is not documented"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := serveItems(OpenAPIConfig{ValidateResponses: true}, "/api/items/"+itemID, `{"name":"item"}`, tt.handler)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("statusCode = %v, want %v", rec.Code, tt.wantStatus)
+			}
+
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("OpenAPIValidatorWithConfig() error = %v", err)
+				}
+				if got := strings.TrimSpace(rec.Body.String()); got != tt.wantBody {
+					t.Errorf("body = %v, want %v", got, tt.wantBody)
+				}
+				return
+			}
+
+			var modelErr *models.Error
+			if !errors.As(err, &modelErr) || modelErr.Code != models.CodeInternalServerError {
+				t.Fatalf("OpenAPIValidatorWithConfig() error = %v, want an internal server error", err)
+			}
+			if !reflect.DeepEqual(modelErr.Fields, tt.wantFields) {
+				t.Errorf("OpenAPIValidatorWithConfig() fields = %v, want %v", modelErr.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestOpenAPIValidatorWithConfig_OtherMediaTypes(t *testing.T) {
+	doc := newItemsDocument()
+	doc.Operation(http.MethodPost, "/items/{id}").RequestBody.Content["application/yaml"] = openapi.MediaType{Schema: openapi.Ref("ItemRequest")}
+
+	tests := []struct {
+		name        string
+		contentType string
+		wantCalled  bool
+	}{
+		{
+			name:        "A body in another documented media type should be left to the handler",
+			contentType: "application/yaml; charset=utf-8",
+			wantCalled:  true,
+		},
+		{
+			name:        "A body in a media type which is not documented should be validated as JSON",
+			contentType: "text/plain",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			e := echo.New()
+			g := e.Group("/api", OpenAPIValidatorWithConfig(OpenAPIConfig{
+				Document: doc,
+				ErrorHandler: func(c echo.Context, err error) error {
+					return c.NoContent(http.StatusTeapot)
+				},
+			}))
+			g.POST("/items/:id", func(c echo.Context) error {
+				called = true
+				return c.NoContent(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/items/"+itemID, strings.NewReader("name: item\n"))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			if called != tt.wantCalled {
+				t.Errorf("handler called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
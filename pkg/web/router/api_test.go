@@ -1,3 +1,4 @@
+//go:build integration
 // +build integration
 
 package router
@@ -5,10 +6,12 @@ package router
 import (
 	"github.com/aerogear/mobile-security-service/pkg/config"
 	"github.com/aerogear/mobile-security-service/pkg/db"
+	"github.com/aerogear/mobile-security-service/pkg/health"
 	"github.com/aerogear/mobile-security-service/pkg/helpers"
 	"github.com/aerogear/mobile-security-service/pkg/web/apps"
 	"github.com/aerogear/mobile-security-service/pkg/web/checks"
 	"github.com/aerogear/mobile-security-service/pkg/web/initclient"
+	"github.com/aerogear/mobile-security-service/pkg/web/tenants"
 	"github.com/aerogear/mobile-security-service/pkg/web/user"
 	"net/http"
 	"net/http/httptest"
@@ -30,24 +33,37 @@ func setupTestServer() *httptest.Server {
 
 	APIRoutePrefix := config.APIRoutePrefix
 	apiGroup := e.Group(APIRoutePrefix)
+	openAPI := NewOpenAPI(config)
+	validate := NewOpenAPIValidator(openAPI, config, true)
+	apiV1Groups := NewAPIV1Groups(e, config, validate)
+	apiV2Group := NewAPIV2Group(e, config, validate)
 
 	// App handler setup
 	appsPostgreSQLRepository := apps.NewPostgreSQLRepository(dbConn)
-	appsService := apps.NewService(appsPostgreSQLRepository)
+	appsService := apps.NewService(appsPostgreSQLRepository, nil)
 	appsHandler := apps.NewHTTPHandler(e, appsService)
+	scopeApp := tenants.ScopeApp(tenants.NewService(tenants.NewPostgreSQLRepository(dbConn), config.Tenants.SuperAdmins))
 
 	// User handler setup
 	userHandler := user.NewHTTPHandler(e)
 
 	// Init handler setup
-	initClientHandler := initclient.NewHTTPHandler(e, appsService)
-	checksHandler := checks.NewHTTPHandler(e, appsService)
+	initClientHandler := initclient.NewHTTPHandler(e, appsService, nil, nil)
+	healthRegistry := health.NewRegistry(config.Health.CheckTimeout)
+	healthRegistry.Register("database", health.DatabaseChecker(dbConn))
+	checksHandler := checks.NewHTTPHandler(e, healthRegistry)
 
 	// Setup routes
-	SetAppRoutes(apiGroup, appsHandler)
-	SetUserRoutes(apiGroup, userHandler)
-	SetInitRoutes(apiGroup, initClientHandler)
+	for _, g := range apiV1Groups {
+		SetAppRoutes(g, appsHandler, user.RequireAdmin(config.AdminUsers), scopeApp)
+		SetUserRoutes(g, userHandler)
+		SetInitRoutes(g, initClientHandler)
+	}
+	SetAppRoutesV2(apiV2Group, appsHandler, user.RequireAdmin(config.AdminUsers), scopeApp)
+	SetUserRoutesV2(apiV2Group, userHandler)
+	SetInitRoutesV2(apiV2Group, initClientHandler)
 	SetChecksRouter(apiGroup, checksHandler)
+	SetOpenAPIRouter(apiGroup, openAPI)
 
 	return httptest.NewServer(e)
 }
@@ -57,17 +73,29 @@ func TestHTTPHandler_GetAppsEndpoint(t *testing.T) {
 
 	tests := []struct {
 		name       string
+		path       string
 		wantStatus int
 	}{
 		{
 			name:       "GetApps() should return a 200 status code with an array of data",
+			path:       "/api/apps",
+			wantStatus: 200,
+		},
+		{
+			name:       "GetApps() should return a 200 status code with an array of data in version 1",
+			path:       "/api/v1/apps",
+			wantStatus: 200,
+		},
+		{
+			name:       "GetAppsPage() should return a 200 status code with a page of data in version 2",
+			path:       "/api/v2/apps",
 			wantStatus: 200,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			res, err := http.Get(server.URL + "/api/apps")
+			res, err := http.Get(server.URL + tt.path)
 
 			if err != nil {
 				t.Errorf("Got an unexpected error during GET request to /apps")
@@ -0,0 +1,837 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/health"
+	"github.com/aerogear/mobile-security-service/pkg/httperrors"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/openapi"
+	"github.com/aerogear/mobile-security-service/pkg/web/alerts"
+	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+	"github.com/aerogear/mobile-security-service/pkg/web/initclient"
+	"github.com/aerogear/mobile-security-service/pkg/web/middleware"
+	"github.com/aerogear/mobile-security-service/pkg/web/stream"
+	"github.com/aerogear/mobile-security-service/pkg/web/tenants"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/aerogear/mobile-security-service/pkg/web/webhooks"
+	"github.com/labstack/echo"
+)
+
+// exampleID is the id of the app in the examples of the API documentation
+const exampleID = "1b9e7a5f-af7c-4055-b488-72f2b5f72266"
+
+// NewOpenAPI returns the OpenAPI 3 document of the routes bound by this package.
+// The schemas are generated from the requests and responses of the handlers.
+func NewOpenAPI(config config.Config) *openapi.Document {
+	doc := openapi.NewDocument(openapi.Options{
+		Info: openapi.Info{
+			Title:       "Mobile Security Service",
+			Description: "Version 1 of the API is also served without the /v1 prefix.",
+			Version:     "2",
+		},
+		ServerURL: config.APIRoutePrefix,
+		Schemas: []openapi.Schemas{
+			apps.Schemas(),
+			initclient.Schemas(),
+			webhooks.Schemas(),
+			tenants.Schemas(),
+			alerts.Schemas(),
+			httperrors.Schemas(),
+			{
+				"User":         models.User{},
+				"HealthReport": health.Report{},
+				"CheckResult":  health.CheckResult{},
+			},
+		},
+		Patterns: map[string]string{
+			"appid":       validation.AppIDPattern,
+			"fingerprint": validation.FingerprintPattern,
+		},
+	})
+
+	addChecksOperations(doc)
+
+	v1 := apiVersion{doc: doc, prefix: APIVersion1, tag: "v1", deprecated: true}
+	addGetAppsOperation(v1)
+	addAPIOperations(v1)
+
+	v2 := apiVersion{doc: doc, prefix: APIVersion2, tag: "v2", suffix: "V2"}
+	addGetAppsPageOperation(v2)
+	addAPIOperations(v2)
+
+	return doc
+}
+
+// SetOpenAPIRouter binds the route returning the OpenAPI document of the API
+func SetOpenAPIRouter(r *echo.Group, doc *openapi.Document) {
+	// swagger:operation GET /openapi.json OpenAPI
+	//
+	// Get the OpenAPI 3 document of the API
+	// ---
+	// summary: Retrieve the OpenAPI 3 document of the API
+	// operationId: getOpenAPI
+	// produces:
+	// - application/json
+	// responses:
+	//   200:
+	//     description: successful operation
+	r.GET("/openapi.json", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, doc)
+	})
+}
+
+// NewOpenAPIValidator returns the middleware validating the requests of the API groups against the document.
+// When validateResponses is true, the responses are validated as well, which is meant for tests.
+func NewOpenAPIValidator(doc *openapi.Document, config config.Config, validateResponses bool) echo.MiddlewareFunc {
+	return middleware.OpenAPIValidatorWithConfig(middleware.OpenAPIConfig{
+		Document:          doc,
+		Path:              openAPIPath(config.APIRoutePrefix),
+		ErrorHandler:      httperrors.GetHTTPResponseFromErr,
+		ValidateResponses: validateResponses,
+	})
+}
+
+// openAPIPath returns the path of the operation documenting the route of a request.
+// The routes of the first version of the API served without version are documented under /v1.
+func openAPIPath(prefix string) func(c echo.Context) string {
+	return func(c echo.Context) string {
+		path := openapi.PathFromRoute(strings.TrimPrefix(c.Path(), prefix))
+		if !strings.HasPrefix(path, APIVersion1+"/") && !strings.HasPrefix(path, APIVersion2+"/") {
+			path = APIVersion1 + path
+		}
+		return path
+	}
+}
+
+// apiVersion documents the operations of a version of the API under its prefix
+type apiVersion struct {
+	doc    *openapi.Document
+	prefix string
+	tag    string
+	// suffix is added to the ids of the operations which are in more than one version
+	suffix     string
+	deprecated bool
+}
+
+func (v apiVersion) add(method, path string, op *openapi.Operation) {
+	op.OperationID += v.suffix
+	op.Tags = []string{v.tag}
+	op.Deprecated = v.deprecated
+	v.doc.AddOperation(method, v.prefix+path, op)
+}
+
+func addChecksOperations(doc *openapi.Document) {
+	doc.AddOperation(http.MethodGet, "/ping", &openapi.Operation{
+		OperationID: "ping",
+		Summary:     "Check if the server is running",
+		Tags:        []string{"checks"},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The server is running", &openapi.Schema{Type: "string"}),
+		},
+	})
+
+	doc.AddOperation(http.MethodGet, "/healthz", &openapi.Operation{
+		OperationID: "healthz",
+		Summary:     "Check if the server and its dependencies can receive requests",
+		Tags:        []string{"checks"},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("All checks passed", openapi.Ref("HealthReport")),
+			"503": jsonResponse("One or more checks failed or the server is shutting down", openapi.Ref("HealthReport")),
+		},
+	})
+
+	doc.AddOperation(http.MethodGet, "/metrics", &openapi.Operation{
+		OperationID: "metrics",
+		Summary:     "Retrieve the Prometheus metrics of the server",
+		Tags:        []string{"checks"},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The metrics in the Prometheus text format",
+				Content:     map[string]openapi.MediaType{"text/plain": {Schema: &openapi.Schema{Type: "string"}}},
+			},
+		},
+	})
+
+	doc.AddOperation(http.MethodGet, "/openapi.json", &openapi.Operation{
+		OperationID: "getOpenAPI",
+		Summary:     "Retrieve the OpenAPI 3 document of the API",
+		Tags:        []string{"checks"},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The OpenAPI document", &openapi.Schema{Type: "object"}),
+		},
+	})
+}
+
+// addGetAppsOperation documents the list of apps of the first version of the API
+func addGetAppsOperation(v apiVersion) {
+	v.add(http.MethodGet, "/apps", &openapi.Operation{
+		OperationID: "getApps",
+		Summary:     "Retrieve a list of apps",
+		Description: "The cursor of the following page is sent in the X-Next-Cursor and Link headers.",
+		Parameters:  appsQueryParameters(),
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The apps",
+				Headers: map[string]openapi.Header{
+					apps.NextCursorHeader: {Description: "The cursor of the next page, when there is one", Schema: &openapi.Schema{Type: "string"}},
+					"Link":                {Description: `The URL of the next page with rel="next", when there is one`, Schema: &openapi.Schema{Type: "string"}},
+				},
+				Content: openapi.JSON(openapi.ArrayOf(openapi.Ref("AppResponse"))),
+			},
+			"204": {Description: "No apps were found"},
+			"400": problem("Invalid query parameters supplied"),
+			"500": problem("Unexpected error"),
+		},
+	})
+}
+
+// addGetAppsPageOperation documents the list of apps of the second version of the API
+func addGetAppsPageOperation(v apiVersion) {
+	v.add(http.MethodGet, "/apps", &openapi.Operation{
+		OperationID: "getAppsPage",
+		Summary:     "Retrieve a page of apps",
+		Description: "The cursor of the following page is sent in the body. An empty page is returned when no apps are found.",
+		Parameters:  appsQueryParameters(),
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The page of apps", openapi.Ref("AppsPageResponse")),
+			"400": problem("Invalid query parameters supplied"),
+			"500": problem("Unexpected error"),
+		},
+	})
+}
+
+// addAPIOperations documents the operations which are the same in every version of the API
+func addAPIOperations(v apiVersion) {
+	v.add(http.MethodGet, "/user", &openapi.Operation{
+		OperationID: "getUser",
+		Summary:     "Retrieve the user logged in through the OAuth proxy",
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The user", openapi.Ref("User")),
+			"404": problem("No user found"),
+		},
+	})
+
+	v.add(http.MethodPost, "/apps", &openapi.Operation{
+		OperationID: "createApp",
+		Summary:     "Create an app, or restore it when an app with the same appId was deleted",
+		RequestBody: jsonBody(openapi.Ref("CreateAppRequest"), map[string]interface{}{
+			"appId":   "com.aerogear.mobile_app_one",
+			"appName": "Mobile App One",
+		}),
+		Responses: map[string]*openapi.Response{
+			"201": {Description: "The app was created"},
+			"400": problem("Invalid data supplied"),
+			"409": problem("An app with the same appId already exists or the app is managed by the policy directory"),
+			"422": problem("The app is not valid, or its tenant is not a tenant of the user"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodGet, "/apps/{id}", &openapi.Operation{
+		OperationID: "getApp",
+		Summary:     "Retrieve an app with its versions",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The app", openapi.Ref("AppResponse")),
+			"400": problem("Invalid id supplied"),
+			"404": problem("App not found"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPatch, "/apps/{id}", &openapi.Operation{
+		OperationID: "updateAppName",
+		Summary:     "Update the name of an app",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		RequestBody: jsonBody(openapi.Ref("UpdateAppNameRequest"), map[string]interface{}{
+			"appName": "Mobile App One",
+		}),
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The name was updated"},
+			"400": problem("Invalid id or data supplied"),
+			"404": problem("App not found"),
+			"409": problem("The app is managed by the policy directory"),
+			"422": problem("The name is not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodDelete, "/apps/{id}", &openapi.Operation{
+		OperationID: "deleteApp",
+		Summary:     "Soft delete an app",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The app was deleted"},
+			"400": problem("Invalid id supplied"),
+			"404": problem("App not found"),
+			"409": problem("The app is managed by the policy directory"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPost, "/apps/{id}/restore", &openapi.Operation{
+		OperationID: "restoreApp",
+		Summary:     "Restore a soft deleted app",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The app was restored"},
+			"400": problem("Invalid id supplied"),
+			"404": problem("App not found"),
+			"409": problem("The app is not deleted"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPost, "/apps/{id}/purge", &openapi.Operation{
+		OperationID: "hardDeleteApp",
+		Summary:     "Permanently delete an app with all its versions and devices, released versions, signing certificates, webhooks and alerts. Only admin users can do it.",
+		Parameters: []openapi.Parameter{
+			idParameter("The id of the app"),
+			{
+				Name:        "dryRun",
+				In:          "query",
+				Description: "Returns the number of versions, devices, released versions, signing certificates, webhooks and alerts which would be deleted without deleting anything",
+				Schema:      &openapi.Schema{Type: "boolean", Default: false},
+			},
+		},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The app was deleted, or would be on a dry run", openapi.Ref("HardDeleteAppResponse")),
+			"400": problem("Invalid id supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not an admin"),
+			"404": problem("App not found"),
+			"409": problem("The app is managed by the policy directory"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPut, "/apps/{id}/versions", &openapi.Operation{
+		OperationID: "updateAppVersions",
+		Summary:     "Update one or more versions of an app",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		RequestBody: jsonBody(openapi.ArrayOf(openapi.Ref("UpdateVersionRequest")), []interface{}{
+			map[string]interface{}{
+				"id":              "55ebd387-9c68-4137-a367-a12025cc2cdb",
+				"appId":           "com.aerogear.mobile_app_one",
+				"version":         "1.0",
+				"disabled":        true,
+				"disabledMessage": "Please update to the latest version",
+			},
+		}),
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The versions were updated"},
+			"400": problem("Invalid id or versions supplied"),
+			"404": problem("App not found"),
+			"409": problem("The app is managed by the policy directory"),
+			"422": problem("The versions are not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPost, "/apps/{id}/versions/disable", &openapi.Operation{
+		OperationID: "disableAppVersions",
+		Summary:     "Disable all the versions of an app",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		RequestBody: jsonBody(openapi.Ref("DisableAppVersionsRequest"), map[string]interface{}{
+			"disabledMessage": "Please update to the latest version",
+		}),
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The versions were disabled"},
+			"400": problem("Invalid id or data supplied"),
+			"404": problem("App not found"),
+			"409": problem("The app is managed by the policy directory"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodGet, "/apps/{id}/releases", &openapi.Operation{
+		OperationID: "getReleases",
+		Summary:     "Retrieve the released versions declared for an app and its mode for the other versions",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The released versions of the app", openapi.Ref("ReleasesResponse")),
+			"400": problem("Invalid id supplied"),
+			"404": problem("App not found"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPut, "/apps/{id}/releases", &openapi.Operation{
+		OperationID: "setReleases",
+		Summary:     "Replace the released versions declared for an app and its mode for the other versions",
+		Description: "When the app declares released versions, the other versions sent to the init endpoint are accepted (" + models.UnknownVersionsAccept +
+			"), accepted and flagged as unknown in the versions of the app (" + models.UnknownVersionsFlag + ") or disabled without being registered (" +
+			models.UnknownVersionsBlock + ").",
+		Parameters: []openapi.Parameter{idParameter("The id of the app")},
+		RequestBody: jsonBody(openapi.Ref("SetReleasesRequest"), map[string]interface{}{
+			"unknownVersions": models.UnknownVersionsBlock,
+			"versions":        []interface{}{"1.0", "1.1"},
+		}),
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The released versions were replaced"},
+			"400": problem("Invalid id or data supplied"),
+			"404": problem("App not found"),
+			"409": problem("The app is managed by the policy directory"),
+			"422": problem("The released versions are not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPost, "/apps/{id}/releases", &openapi.Operation{
+		OperationID: "addRelease",
+		Summary:     "Declare a released version of an app",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		RequestBody: jsonBody(openapi.Ref("AddReleaseRequest"), map[string]interface{}{
+			"version": "1.2",
+		}),
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The version was declared"},
+			"400": problem("Invalid id or data supplied"),
+			"404": problem("App not found"),
+			"409": problem("The app is managed by the policy directory"),
+			"422": problem("The version is not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodGet, "/apps/{id}/certificates", &openapi.Operation{
+		OperationID: "getSigningCertificates",
+		Summary:     "Retrieve the signing certificates registered for an app and its mode for the tampered builds",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The signing certificates of the app", openapi.Ref("SigningPolicyResponse")),
+			"400": problem("Invalid id supplied"),
+			"404": problem("App not found"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPost, "/apps/{id}/certificates", &openapi.Operation{
+		OperationID: "createSigningCertificate",
+		Summary:     "Register a certificate signing the Android or iOS builds of an app. Only admin users can do it.",
+		Description: "Once a platform has certificates, the builds of the platform which report another signingCertificate or none to the init endpoint are tampered.",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		RequestBody: jsonBody(openapi.Ref("CreateSigningCertificateRequest"), map[string]interface{}{
+			"platform":    models.PlatformAndroid,
+			"fingerprint": "14:6D:E9:83:C5:73:06:50:D8:EE:B9:95:2F:34:FC:64:16:A0:83:42:E6:1D:BE:A8:8A:04:96:B2:3F:CF:44:E5",
+			"description": "Play Store release key",
+		}),
+		Responses: map[string]*openapi.Response{
+			"201": jsonResponse("The certificate", openapi.Ref("SigningCertificateResponse")),
+			"400": problem("Invalid id or data supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not an admin"),
+			"404": problem("App not found"),
+			"409": problem("The certificate is already registered for the app"),
+			"422": problem("The certificate is not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPut, "/apps/{id}/certificates/mode", &openapi.Operation{
+		OperationID: "setTamperedBuilds",
+		Summary:     "Set the mode of an app for the builds which are not signed by its certificates. Only admin users can do it.",
+		Description: "The tampered builds are reported in the device.tampered events and the logs (" + models.TamperedBuildsFlag +
+			") and are also disabled without being registered (" + models.TamperedBuildsBlock + ").",
+		Parameters: []openapi.Parameter{idParameter("The id of the app")},
+		RequestBody: jsonBody(openapi.Ref("SetTamperedBuildsRequest"), map[string]interface{}{
+			"tamperedBuilds": models.TamperedBuildsBlock,
+		}),
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The mode was set"},
+			"400": problem("Invalid id or data supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not an admin"),
+			"404": problem("App not found"),
+			"422": problem("The mode is not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodDelete, "/apps/{id}/certificates/{certificateId}", &openapi.Operation{
+		OperationID: "deleteSigningCertificate",
+		Summary:     "Delete a signing certificate of an app. Only admin users can do it.",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app"), uuidParameter("certificateId", "The id of the certificate")},
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The certificate was deleted"},
+			"400": problem("Invalid ids supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not an admin"),
+			"404": problem("App or certificate not found"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodGet, "/export", &openapi.Operation{
+		OperationID: "exportApps",
+		Summary:     "Export the active apps and the state of their versions as a policy document",
+		Description: "The apps are sorted by appId and the versions by version, so the document can be kept in git.",
+		Parameters: []openapi.Parameter{
+			{
+				Name:        "format",
+				In:          "query",
+				Description: "The format of the document. Without it, YAML is returned when the Accept header asks for it",
+				Schema:      &openapi.Schema{Type: "string", Enum: []interface{}{"json", "yaml"}},
+			},
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The policy document",
+				Content: map[string]openapi.MediaType{
+					echo.MIMEApplicationJSON: {Schema: openapi.Ref("AppsPolicy")},
+					apps.MIMEApplicationYAML: {Schema: openapi.Ref("AppsPolicy")},
+				},
+			},
+			"400": problem("Invalid format supplied"),
+			"403": problem("The tenants are enabled and the user is not a super admin"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	policyExample := map[string]interface{}{
+		"apps": []interface{}{
+			map[string]interface{}{
+				"appId":   "com.aerogear.mobile_app_one",
+				"appName": "Mobile App One",
+				"versions": []interface{}{
+					map[string]interface{}{"version": "1.0", "disabled": true, "disabledMessage": "Please update to the latest version"},
+					map[string]interface{}{"version": "1.1", "disabled": false},
+				},
+			},
+		},
+	}
+	v.add(http.MethodPost, "/import", &openapi.Operation{
+		OperationID: "importApps",
+		Summary:     "Apply a policy document of the apps. Only admin users can do it.",
+		Description: "The apps are created, restored and renamed and the state of their versions updated to match the document. " +
+			"The apps and the versions which are not in the document are left as they are, and the versions never launched are skipped.",
+		Parameters: []openapi.Parameter{
+			{
+				Name:        "dryRun",
+				In:          "query",
+				Description: "Returns the changes which would be made without changing anything",
+				Schema:      &openapi.Schema{Type: "boolean", Default: false},
+			},
+		},
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]openapi.MediaType{
+				echo.MIMEApplicationJSON: {Schema: openapi.Ref("AppsPolicy"), Example: policyExample},
+				apps.MIMEApplicationYAML: {Schema: openapi.Ref("AppsPolicy")},
+			},
+		},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The changes made, or which would be made on a dry run", openapi.Ref("ImportAppsResponse")),
+			"400": problem("Invalid data supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not an admin, or not a super admin when the tenants are enabled"),
+			"409": problem("The app is managed by the policy directory"),
+			"422": problem("The document is not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	addWebhookOperations(v)
+	addTenantOperations(v)
+	addStreamOperations(v)
+	addAlertOperations(v)
+
+	v.add(http.MethodPost, "/init", &openapi.Operation{
+		OperationID: "initApp",
+		Summary:     "Record the launch of an app by a device and return if its version is disabled",
+		RequestBody: jsonBody(openapi.Ref("InitRequest"), map[string]interface{}{
+			"appId":              "com.aerogear.mobile_app_one",
+			"deviceId":           "0ebc8e9d-5e6a-4e2b-a9a5-5c2d5d2b3f11",
+			"version":            "1.0",
+			"deviceVersion":      "9.0",
+			"deviceType":         "Android",
+			"signingCertificate": "14:6D:E9:83:C5:73:06:50:D8:EE:B9:95:2F:34:FC:64:16:A0:83:42:E6:1D:BE:A8:8A:04:96:B2:3F:CF:44:E5",
+		}),
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The state of the version", openapi.Ref("InitResponse")),
+			"400": problem("Invalid data supplied or no app found for the appId"),
+			"422": problem("The device information is not valid"),
+			"429": tooManyRequests("Too many requests of the client IP, the app or the device"),
+			"500": problem("Unexpected error"),
+		},
+	})
+}
+
+// addAlertOperations documents the alerts of the apps
+func addAlertOperations(v apiVersion) {
+	v.add(http.MethodGet, "/apps/{id}/alerts", &openapi.Operation{
+		OperationID: "getAlerts",
+		Summary:     "Retrieve the alerts raised by the analysis of the init requests of an app, the last detected first",
+		Description: "An alert is raised when an app has much more new devices than usual (" + models.AlertNewDevicesSpike + "), when a version " +
+			"launched for the first time is still used by very few devices after a while (" + models.AlertUnreleasedVersion + "), " +
+			"or when a device reports many OS versions (" + models.AlertDeviceOSVersionsCycling + "). " +
+			"An alert which is detected again is updated.",
+		Parameters: []openapi.Parameter{idParameter("The id of the app")},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The alerts", openapi.ArrayOf(openapi.Ref("AlertResponse"))),
+			"400": problem("Invalid id supplied"),
+			"404": problem("App not found"),
+			"500": problem("Unexpected error"),
+		},
+	})
+}
+
+// addWebhookOperations documents the webhooks of the apps
+func addWebhookOperations(v apiVersion) {
+	v.add(http.MethodGet, "/apps/{id}/webhooks", &openapi.Operation{
+		OperationID: "getWebhooks",
+		Summary:     "Retrieve the webhooks of an app, without their secrets",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The webhooks", openapi.ArrayOf(openapi.Ref("WebhookResponse"))),
+			"400": problem("Invalid id supplied"),
+			"404": problem("App not found"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPost, "/apps/{id}/webhooks", &openapi.Operation{
+		OperationID: "createWebhook",
+		Summary:     "Subscribe a URL to some types of the events of an app. Only admin users can do it.",
+		Description: "The events are sent in POST requests signed with the secret of the webhook in the " + webhooks.SignatureHeader +
+			" header, as sha256= followed by the hex encoded HMAC-SHA256 of the body. The deliveries which are not accepted with a 2xx response are retried.",
+		Parameters: []openapi.Parameter{idParameter("The id of the app")},
+		RequestBody: jsonBody(openapi.Ref("CreateWebhookRequest"), map[string]interface{}{
+			"url":    "https://hooks.example.com/mobile-security",
+			"events": []interface{}{models.EventVersionDisabled, models.EventDeviceBlocked},
+		}),
+		Responses: map[string]*openapi.Response{
+			"201": jsonResponse("The webhook, with the secret signing the deliveries which is not returned again", openapi.Ref("CreatedWebhookResponse")),
+			"400": problem("Invalid id or data supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not an admin"),
+			"404": problem("App not found"),
+			"422": problem("The webhook is not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodDelete, "/apps/{id}/webhooks/{webhookId}", &openapi.Operation{
+		OperationID: "deleteWebhook",
+		Summary:     "Delete a webhook with its deliveries. Only admin users can do it.",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app"), uuidParameter("webhookId", "The id of the webhook")},
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The webhook was deleted"},
+			"400": problem("Invalid ids supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not an admin"),
+			"404": problem("App or webhook not found"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodGet, "/apps/{id}/webhooks/{webhookId}/deliveries", &openapi.Operation{
+		OperationID: "getWebhookDeliveries",
+		Summary:     "Retrieve the last 100 deliveries of a webhook, the most recent first",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app"), uuidParameter("webhookId", "The id of the webhook")},
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The deliveries", openapi.ArrayOf(openapi.Ref("DeliveryResponse"))),
+			"400": problem("Invalid ids supplied"),
+			"404": problem("App or webhook not found"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPost, "/apps/{id}/webhooks/{webhookId}/deliveries/{deliveryId}/replay", &openapi.Operation{
+		OperationID: "replayWebhookDelivery",
+		Summary:     "Send again the event of a delivery with a new delivery. Only admin users can do it.",
+		Parameters: []openapi.Parameter{
+			idParameter("The id of the app"),
+			uuidParameter("webhookId", "The id of the webhook"),
+			uuidParameter("deliveryId", "The id of the delivery to replay"),
+		},
+		Responses: map[string]*openapi.Response{
+			"202": jsonResponse("The new delivery, which is pending", openapi.Ref("DeliveryResponse")),
+			"400": problem("Invalid ids supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not an admin"),
+			"404": problem("App, webhook or delivery not found"),
+			"500": problem("Unexpected error"),
+		},
+	})
+}
+
+// addTenantOperations documents the tenants owning the apps
+func addTenantOperations(v apiVersion) {
+	v.add(http.MethodGet, "/tenants", &openapi.Operation{
+		OperationID: "getTenants",
+		Summary:     "Retrieve the tenants of the user, or every tenant for a super admin",
+		Responses: map[string]*openapi.Response{
+			"200": jsonResponse("The tenants sorted by name", openapi.ArrayOf(openapi.Ref("TenantResponse"))),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPost, "/tenants", &openapi.Operation{
+		OperationID: "createTenant",
+		Summary:     "Create a tenant with its members. Only super admin users can do it.",
+		RequestBody: jsonBody(openapi.Ref("CreateTenantRequest"), map[string]interface{}{
+			"name":    "payments",
+			"members": []interface{}{"alice", "bob"},
+		}),
+		Responses: map[string]*openapi.Response{
+			"201": jsonResponse("The tenant", openapi.Ref("TenantResponse")),
+			"400": problem("Invalid data supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not a super admin"),
+			"409": problem("A tenant with the same name already exists"),
+			"422": problem("The tenant is not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPut, "/tenants/{tenantId}/members", &openapi.Operation{
+		OperationID: "setTenantMembers",
+		Summary:     "Replace the members of a tenant. Only super admin users can do it.",
+		Parameters:  []openapi.Parameter{uuidParameter("tenantId", "The id of the tenant")},
+		RequestBody: jsonBody(openapi.Ref("SetTenantMembersRequest"), map[string]interface{}{
+			"members": []interface{}{"alice", "carol"},
+		}),
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The members were replaced"},
+			"400": problem("Invalid id or data supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not a super admin"),
+			"404": problem("Tenant not found"),
+			"422": problem("The members are not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodDelete, "/tenants/{tenantId}", &openapi.Operation{
+		OperationID: "deleteTenant",
+		Summary:     "Delete a tenant which owns no app. Only super admin users can do it.",
+		Parameters:  []openapi.Parameter{uuidParameter("tenantId", "The id of the tenant")},
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The tenant was deleted"},
+			"400": problem("Invalid id supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not a super admin"),
+			"404": problem("Tenant not found"),
+			"409": problem("The tenant still owns apps or is the default tenant"),
+			"500": problem("Unexpected error"),
+		},
+	})
+
+	v.add(http.MethodPut, "/apps/{id}/tenant", &openapi.Operation{
+		OperationID: "moveApp",
+		Summary:     "Move an app to another tenant. Only super admin users can do it.",
+		Parameters:  []openapi.Parameter{idParameter("The id of the app")},
+		RequestBody: jsonBody(openapi.Ref("MoveAppRequest"), map[string]interface{}{
+			"tenantId": "00000000-0000-4000-8000-000000000001",
+		}),
+		Responses: map[string]*openapi.Response{
+			"204": {Description: "The app was moved"},
+			"400": problem("Invalid id or data supplied"),
+			"401": problem("No user found"),
+			"403": problem("The user is not a super admin"),
+			"404": problem("App or tenant not found"),
+			"422": problem("The tenant is not valid"),
+			"500": problem("Unexpected error"),
+		},
+	})
+}
+
+func addStreamOperations(v apiVersion) {
+	v.add(http.MethodGet, "/apps/{id}/events", &openapi.Operation{
+		OperationID: "getAppEvents",
+		Summary:     "Stream the changes of an app as Server-Sent Events",
+		Description: "A new stream starts with a " + stream.ReadyEvent + " message. The " + stream.VersionsEvent + " messages have the state of the versions " +
+			"which were disabled, enabled or launched for the first time, and the " + stream.LaunchesEvent + " messages the launches and the new devices " +
+			"of the versions since the previous one. The stream ends with a " + stream.DeletedEvent + " message when the app is deleted. " +
+			"A stream resumed with the Last-Event-ID header starts with the messages which were missed, or with a " + stream.ResetEvent +
+			" message when they are no longer known, after which the app should be reloaded.",
+		Parameters: []openapi.Parameter{
+			idParameter("The id of the app"),
+			{
+				Name:        stream.LastEventIDHeader,
+				In:          "header",
+				Description: "The id of the last message received, to resume a stream",
+				Schema:      &openapi.Schema{Type: "string"},
+			},
+		},
+		Responses: map[string]*openapi.Response{
+			"200": {
+				Description: "The stream of the messages of the app",
+				Content:     map[string]openapi.MediaType{stream.ContentType: {Schema: &openapi.Schema{Type: "string"}}},
+			},
+			"400": problem("Invalid id supplied"),
+			"401": problem("No user found"),
+			"404": problem("App not found"),
+			"500": problem("Unexpected error"),
+		},
+	})
+}
+
+// appsQueryParameters are the parameters to paginate, sort and filter the list of apps
+func appsQueryParameters() []openapi.Parameter {
+	zero, max := 0.0, 100.0
+
+	return []openapi.Parameter{
+		{Name: "appId", In: "query", Description: "Returns only the app with this appId", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "limit", In: "query", Description: "The maximum number of apps to return. All apps are returned when not set", Schema: &openapi.Schema{Type: "integer", Minimum: &zero, Maximum: &max}},
+		{Name: "cursor", In: "query", Description: "The cursor of the page to return, as returned with the previous page", Schema: &openapi.Schema{Type: "string"}},
+		{
+			Name:        "sort",
+			In:          "query",
+			Description: `The field to order by, prefixed with "-" for descending order`,
+			Schema: &openapi.Schema{
+				Type:    "string",
+				Enum:    []interface{}{"name", "-name", "launches", "-launches", "installs", "-installs"},
+				Default: "name",
+			},
+		},
+		{Name: "q", In: "query", Description: "Returns only the apps whose name or appId contains the given text", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "deleted", In: "query", Description: "Returns the soft deleted apps instead of the active ones", Schema: &openapi.Schema{Type: "boolean", Default: false}},
+		{Name: "tenantId", In: "query", Description: "Returns only the apps of this tenant", Schema: &openapi.Schema{Type: "string", Format: "uuid"}},
+	}
+}
+
+func idParameter(description string) openapi.Parameter {
+	return uuidParameter("id", description)
+}
+
+func uuidParameter(name, description string) openapi.Parameter {
+	return openapi.Parameter{
+		Name:        name,
+		In:          "path",
+		Description: description,
+		Required:    true,
+		Schema:      &openapi.Schema{Type: "string", Format: "uuid"},
+	}
+}
+
+func jsonBody(s *openapi.Schema, example interface{}) *openapi.RequestBody {
+	return &openapi.RequestBody{
+		Required: true,
+		Content:  map[string]openapi.MediaType{echo.MIMEApplicationJSON: {Schema: s, Example: example}},
+	}
+}
+
+func jsonResponse(description string, s *openapi.Schema) *openapi.Response {
+	return &openapi.Response{Description: description, Content: openapi.JSON(s)}
+}
+
+func problem(description string) *openapi.Response {
+	return &openapi.Response{
+		Description: description,
+		Content:     map[string]openapi.MediaType{httperrors.ProblemContentType: {Schema: openapi.Ref("Problem")}},
+	}
+}
+
+// tooManyRequests is the problem of a request over a rate limit, with the time to wait before the next one
+func tooManyRequests(description string) *openapi.Response {
+	r := problem(description)
+	r.Headers = map[string]openapi.Header{
+		"Retry-After": {Description: "The seconds to wait before sending another request", Schema: &openapi.Schema{Type: "integer"}},
+	}
+	return r
+}
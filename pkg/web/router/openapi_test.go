@@ -0,0 +1,431 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/openapi"
+	"github.com/aerogear/mobile-security-service/pkg/web/alerts"
+	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+	"github.com/aerogear/mobile-security-service/pkg/web/tenants"
+	"github.com/aerogear/mobile-security-service/pkg/web/user"
+	"github.com/aerogear/mobile-security-service/pkg/web/webhooks"
+	"github.com/labstack/echo"
+)
+
+// newContractTest returns the document and a router whose apps, webhooks and tenants services succeed on every call
+func newContractTest() (config.Config, *openapi.Document, *echo.Echo) {
+	config := config.Get()
+	config.AdminUsers = []string{"admin"}
+	config.Tenants.Enabled = true
+	config.Tenants.SuperAdmins = []string{"admin"}
+
+	certificate := models.SigningCertificate{
+		ID:          "9e1f3b2a-6c4d-4f8e-a7b5-2d1c0e9f8a7b",
+		AppID:       "com.aerogear.mobile_app_one",
+		Platform:    models.PlatformAndroid,
+		Fingerprint: "146de983c5730650d8eeb9952f34fc6416a08342e61dbea88a0496b23fcf44e5",
+		CreatedAt:   time.Now().UTC(),
+	}
+	appsService := &apps.ServiceMock{
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+			return &models.AppsPage{Apps: helpers.GetMockAppList(), NextCursor: "bmV4dA"}, nil
+		},
+		GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
+			return helpers.GetMockApp(), nil
+		},
+		CreateAppFunc: func(app models.App) error {
+			return nil
+		},
+		UpdateAppNameByIDFunc: func(id string, name string) error {
+			return nil
+		},
+		DeleteAppByIdFunc: func(id string) error {
+			return nil
+		},
+		RestoreAppByIDFunc: func(id string) error {
+			return nil
+		},
+		HardDeleteAppByIDFunc: func(id string, dryRun bool) (*models.AppHardDelete, error) {
+			app := helpers.GetMockApp()
+			return &models.AppHardDelete{
+				Versions: len(*app.DeployedVersions),
+				Archive:  &models.AppArchive{ExportedAt: time.Now().UTC().Format(time.RFC3339), App: *app},
+			}, nil
+		},
+		UpdateAppVersionsFunc: func(id string, versions []models.Version) error {
+			return nil
+		},
+		DisableAllAppVersionsByAppIDFunc: func(id string, message string) error {
+			return nil
+		},
+		InitClientAppFunc: func(deviceInfo *models.Device) (*models.Version, error) {
+			return helpers.GetMockVersion(), nil
+		},
+		ExportAppsFunc: func() (*models.AppsPolicy, error) {
+			return &models.AppsPolicy{Apps: []models.AppPolicy{{
+				AppID:    "com.aerogear.mobile_app_one",
+				AppName:  "Mobile App One",
+				Versions: []models.VersionPolicy{{Version: "1.0", Disabled: true, DisabledMessage: "Please update"}},
+			}}}, nil
+		},
+		ImportAppsFunc: func(policy models.AppsPolicy, dryRun bool) (*models.PolicyImport, error) {
+			return &models.PolicyImport{DryRun: dryRun, Changes: []models.PolicyChange{
+				{Action: models.PolicyActionUpdate, AppID: "com.aerogear.mobile_app_one", Field: "appName", From: "App", To: "Mobile App One"},
+				{Action: models.PolicyActionSkip, AppID: "com.aerogear.mobile_app_one", Version: "1.1", Reason: "the version was never launched"},
+			}}, nil
+		},
+		GetReleasesFunc: func(id string) (*models.Releases, error) {
+			return &models.Releases{AppID: "com.aerogear.mobile_app_one", UnknownVersions: models.UnknownVersionsFlag, Versions: []string{"1.0", "1.1"}}, nil
+		},
+		SetReleasesFunc: func(id string, releases models.Releases) error {
+			return nil
+		},
+		AddReleaseFunc: func(id, version string) error {
+			return nil
+		},
+		GetSigningPolicyFunc: func(id string) (*models.SigningPolicy, error) {
+			return &models.SigningPolicy{AppID: "com.aerogear.mobile_app_one", TamperedBuilds: models.TamperedBuildsFlag, Certificates: []models.SigningCertificate{certificate}}, nil
+		},
+		CreateSigningCertificateFunc: func(id string, c models.SigningCertificate) (*models.SigningCertificate, error) {
+			return &certificate, nil
+		},
+		DeleteSigningCertificateFunc: func(id, certificateID string) error {
+			return nil
+		},
+		SetTamperedBuildsFunc: func(id, mode string) error {
+			return nil
+		},
+	}
+
+	webhook := models.Webhook{
+		ID:        "c3a1cbb3-3f1e-4d0c-9f53-5a8ea3b9a7f2",
+		AppID:     "com.aerogear.mobile_app_one",
+		URL:       "https://hooks.example.com/mobile-security",
+		Secret:    "5f2b0d9c1e8a4b7d",
+		Events:    []string{models.EventVersionDisabled},
+		CreatedAt: time.Now().UTC(),
+	}
+	attemptedAt := time.Now().UTC()
+	delivery := models.WebhookDelivery{
+		ID:             "7d4c8a3e-2b1f-4e6a-9c5d-8f0e1a2b3c4d",
+		WebhookID:      webhook.ID,
+		EventID:        "0b6f2e1c-9a8d-4c7b-8e5f-3d2c1b0a9f8e",
+		EventType:      models.EventVersionDisabled,
+		Payload:        []byte(`{"id":"0b6f2e1c-9a8d-4c7b-8e5f-3d2c1b0a9f8e","type":"version.disabled"}`),
+		Status:         models.DeliveryDelivered,
+		Attempts:       1,
+		LastAttemptAt:  &attemptedAt,
+		ResponseStatus: http.StatusOK,
+		CreatedAt:      attemptedAt,
+		DeliveredAt:    &attemptedAt,
+	}
+
+	webhooksService := &webhooks.ServiceMock{
+		GetWebhooksFunc: func(id string) ([]models.Webhook, error) {
+			return []models.Webhook{webhook}, nil
+		},
+		CreateWebhookFunc: func(id string, created models.Webhook) (*models.Webhook, error) {
+			return &webhook, nil
+		},
+		DeleteWebhookFunc: func(id string, webhookID string) error {
+			return nil
+		},
+		GetDeliveriesFunc: func(id string, webhookID string) ([]models.WebhookDelivery, error) {
+			return []models.WebhookDelivery{delivery}, nil
+		},
+		ReplayDeliveryFunc: func(id string, webhookID string, deliveryID string) (*models.WebhookDelivery, error) {
+			replay := delivery
+			replay.ID, replay.ReplayOf, replay.Status, replay.Attempts = "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b", delivery.ID, models.DeliveryPending, 0
+			replay.LastAttemptAt, replay.DeliveredAt, replay.ResponseStatus = nil, nil, 0
+			replay.NextAttemptAt = time.Now().UTC()
+			return &replay, nil
+		},
+	}
+
+	tenant := models.Tenant{
+		ID:        models.DefaultTenantID,
+		Name:      models.DefaultTenantName,
+		Members:   []string{"alice"},
+		NumOfApps: 3,
+		CreatedAt: time.Now().UTC(),
+	}
+	tenantsService := &tenants.ServiceMock{
+		GetScopeFunc: func(username string) (*models.TenantScope, error) {
+			return &models.TenantScope{All: true}, nil
+		},
+		GetTenantsFunc: func(scope *models.TenantScope) ([]models.Tenant, error) {
+			return []models.Tenant{tenant}, nil
+		},
+		CreateTenantFunc: func(created models.Tenant) (*models.Tenant, error) {
+			return &tenant, nil
+		},
+		SetMembersFunc: func(id string, members []string) error {
+			return nil
+		},
+		DeleteTenantFunc: func(id string) error {
+			return nil
+		},
+		MoveAppFunc: func(id, tenantID string) error {
+			return nil
+		},
+	}
+
+	alertsService := &alerts.ServiceMock{
+		GetAlertsFunc: func(id string) ([]models.Alert, error) {
+			detectedAt := time.Now().UTC()
+			return []models.Alert{{
+				ID:              "5b1e2f3a-4c5d-4e6f-8a9b-0c1d2e3f4a5b",
+				AppID:           "com.aerogear.mobile_app_one",
+				Kind:            models.AlertUnreleasedVersion,
+				Subject:         "9.9.9",
+				Value:           1,
+				Threshold:       3,
+				FirstDetectedAt: detectedAt,
+				LastDetectedAt:  detectedAt,
+			}}, nil
+		},
+	}
+
+	return config, NewOpenAPI(config), newTestRouter(config, appsService, webhooksService, tenantsService, alertsService)
+}
+
+// pathParamRegexp matches the parameters of a documented path, e.g. {id}
+var pathParamRegexp = regexp.MustCompile(`{[^}]+}`)
+
+// documentedPath returns the path of the operation documenting a route, or an empty string when there is none.
+// The routes of the first version of the API served without prefix are documented under /v1.
+func documentedPath(doc *openapi.Document, prefix string, route *echo.Route) string {
+	path := openapi.PathFromRoute(strings.TrimPrefix(route.Path, prefix))
+	if doc.Operation(route.Method, path) != nil {
+		return path
+	}
+	if doc.Operation(route.Method, APIVersion1+path) != nil {
+		return APIVersion1 + path
+	}
+	return ""
+}
+
+// isCatchAll is true for the routes echo binds to run the middlewares of a group on unknown paths
+func isCatchAll(prefix string, route *echo.Route) bool {
+	return strings.HasSuffix(route.Path, "*") || route.Path == prefix ||
+		route.Path == prefix+APIVersion1 || route.Path == prefix+APIVersion2
+}
+
+func TestOpenAPI_RoutesAreDocumented(t *testing.T) {
+	config, doc, e := newContractTest()
+
+	routed := map[openapi.Endpoint]bool{}
+	for _, route := range e.Routes() {
+		if isCatchAll(config.APIRoutePrefix, route) {
+			continue
+		}
+
+		path := documentedPath(doc, config.APIRoutePrefix, route)
+		if path == "" {
+			t.Errorf("%v %v is not documented", route.Method, route.Path)
+			continue
+		}
+		routed[openapi.Endpoint{Method: route.Method, Path: path}] = true
+	}
+
+	for _, endpoint := range doc.Endpoints() {
+		if !routed[endpoint] {
+			t.Errorf("%v %v is documented but not routed", endpoint.Method, endpoint.Path)
+		}
+	}
+}
+
+func TestOpenAPI_ResponsesMatchTheDocument(t *testing.T) {
+	config, doc, e := newContractTest()
+
+	for _, endpoint := range doc.Endpoints() {
+		endpoint := endpoint
+		op := doc.Operation(endpoint.Method, endpoint.Path)
+
+		t.Run(endpoint.Method+" "+endpoint.Path, func(t *testing.T) {
+			var body io.Reader
+			if op.RequestBody != nil {
+				media := op.RequestBody.Content[echo.MIMEApplicationJSON]
+				example := toJSONValue(t, media.Example)
+				if violations := doc.ValidateStrict("body", media.Schema, example); len(violations) > 0 {
+					t.Fatalf("the example of the request body is not valid: %v", violations)
+				}
+				b, _ := json.Marshal(example)
+				body = bytes.NewReader(b)
+			}
+
+			path := pathParamRegexp.ReplaceAllString(endpoint.Path, exampleID)
+			rec := serveAsAdmin(e, endpoint.Method, config.APIRoutePrefix+path, body)
+
+			if rec.Code >= http.StatusMultipleChoices {
+				t.Errorf("statusCode = %v, want a success, body = %v", rec.Code, rec.Body.String())
+			}
+			checkResponse(t, doc, op, rec)
+		})
+	}
+}
+
+func TestOpenAPI_InvalidIDsAreDocumented(t *testing.T) {
+	config, doc, e := newContractTest()
+
+	for _, endpoint := range doc.Endpoints() {
+		if !strings.Contains(endpoint.Path, "{id}") {
+			continue
+		}
+		endpoint := endpoint
+		op := doc.Operation(endpoint.Method, endpoint.Path)
+
+		t.Run(endpoint.Method+" "+endpoint.Path, func(t *testing.T) {
+			path := pathParamRegexp.ReplaceAllString(strings.Replace(endpoint.Path, "{id}", "not-a-uuid", 1), exampleID)
+			rec := serveAsAdmin(e, endpoint.Method, config.APIRoutePrefix+path, strings.NewReader("{}"))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("statusCode = %v, want 400", rec.Code)
+			}
+			checkResponse(t, doc, op, rec)
+		})
+	}
+}
+
+func TestOpenAPI_YAMLDocumentsAreServed(t *testing.T) {
+	config, doc, e := newContractTest()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{
+			name:   "GET /export should return the policy in YAML",
+			method: http.MethodGet,
+			path:   APIVersion2 + "/export?format=yaml",
+		},
+		{
+			name:   "POST /import should accept a policy in YAML",
+			method: http.MethodPost,
+			path:   APIVersion2 + "/import",
+			body:   "apps:\n- appId: com.aerogear.mobile_app_one\n  versions:\n  - version: \"1.0\"\n    disabled: true\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, config.APIRoutePrefix+tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, apps.MIMEApplicationYAML)
+			req.Header.Set(user.USER_NAME_HEADER, "admin")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("statusCode = %v, want 200, body = %v", rec.Code, rec.Body.String())
+			}
+			checkResponse(t, doc, doc.Operation(tt.method, strings.Split(tt.path, "?")[0]), rec)
+		})
+	}
+}
+
+func TestSetOpenAPIRouter(t *testing.T) {
+	config, _, e := newContractTest()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, config.APIRoutePrefix+"/openapi.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("statusCode = %v, want 200", rec.Code)
+	}
+
+	got := openapi.Document{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("the document is not valid JSON: %v", err)
+	}
+
+	if got.OpenAPI != openapi.Version {
+		t.Errorf("openapi = %v, want %v", got.OpenAPI, openapi.Version)
+	}
+	if len(got.Servers) != 1 || got.Servers[0].URL != config.APIRoutePrefix {
+		t.Errorf("servers = %v, want the prefix of the API", got.Servers)
+	}
+	if got.Components.Schemas["AppResponse"] == nil {
+		t.Errorf("components.schemas has no AppResponse")
+	}
+}
+
+func serveAsAdmin(e *echo.Echo, method, target string, body io.Reader) *httptest.ResponseRecorder {
+	// the request is cancelled after a while so the event streams end
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req := httptest.NewRequest(method, target, body).WithContext(ctx)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(user.USER_NAME_HEADER, "admin")
+	req.Header.Set(user.USER_EMAIL_HEADER, "admin@example.com")
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+// checkResponse fails the test when the status of the response is not documented or its body does not match the schema
+func checkResponse(t *testing.T, doc *openapi.Document, op *openapi.Operation, rec *httptest.ResponseRecorder) {
+	t.Helper()
+
+	response, ok := op.Responses[strconv.Itoa(rec.Code)]
+	if !ok {
+		t.Fatalf("statusCode %v is not documented, body = %v", rec.Code, rec.Body.String())
+	}
+
+	if len(response.Content) == 0 {
+		if rec.Body.Len() > 0 {
+			t.Errorf("body = %v, want no body", rec.Body.String())
+		}
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(rec.Header().Get(echo.HeaderContentType))
+	media, ok := response.Content[contentType]
+	if !ok {
+		t.Fatalf("Content-Type %v is not documented for the status %v", contentType, rec.Code)
+	}
+
+	if !strings.HasSuffix(contentType, "json") {
+		return
+	}
+
+	var got interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("the body is not valid JSON: %v", err)
+	}
+	if violations := doc.ValidateStrict("body", media.Schema, got); len(violations) > 0 {
+		t.Errorf("the body does not match the schema: %v, body = %v", violations, rec.Body.String())
+	}
+}
+
+// toJSONValue converts a Go value to its form decoded by encoding/json, e.g. with float64 numbers
+func toJSONValue(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var got interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	return got
+}
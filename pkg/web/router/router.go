@@ -2,24 +2,27 @@ package router
 
 import (
 	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/web/alerts"
 	"github.com/aerogear/mobile-security-service/pkg/web/apps"
 	"github.com/aerogear/mobile-security-service/pkg/web/checks"
 	"github.com/aerogear/mobile-security-service/pkg/web/initclient"
 	"github.com/aerogear/mobile-security-service/pkg/web/middleware"
+	"github.com/aerogear/mobile-security-service/pkg/web/stream"
+	"github.com/aerogear/mobile-security-service/pkg/web/tenants"
 	"github.com/aerogear/mobile-security-service/pkg/web/user"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/aerogear/mobile-security-service/pkg/web/webhooks"
 	"github.com/labstack/echo"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	validator "gopkg.in/go-playground/validator.v9"
 )
 
-type requestValidator struct {
-	validator *validator.Validate
-}
-
-// Validate validates structs and individual fields based on tags.
-func (v *requestValidator) Validate(i interface{}) error {
-	return v.validator.Struct(i)
-}
+const (
+	// APIVersion1 is the path of the first version of the API under the API route prefix.
+	// Its routes are also served directly under the API route prefix for the existing clients.
+	APIVersion1 = "/v1"
+	// APIVersion2 is the path of the second version of the API under the API route prefix
+	APIVersion2 = "/v2"
+)
 
 // NewRouter creates and returns a new instance of the Echo framework
 func NewRouter(config config.Config) *echo.Echo {
@@ -27,10 +30,28 @@ func NewRouter(config config.Config) *echo.Echo {
 
 	middleware.Init(router, config)
 
-	router.Validator = &requestValidator{validator: validator.New()}
+	router.Validator = validation.NewValidator()
 	return router
 }
 
+// NewAPIV1Groups returns the groups serving the first version of the API: the API route prefix and its /v1 alias.
+// Their responses announce that the version is deprecated in favour of the second one.
+// The given middlewares run on every route of the groups, e.g. the validation of the requests.
+func NewAPIV1Groups(e *echo.Echo, config config.Config, m ...echo.MiddlewareFunc) []*echo.Group {
+	m = append([]echo.MiddlewareFunc{middleware.Deprecated(config.APIRoutePrefix+APIVersion2, config.APIV1Sunset)}, m...)
+
+	return []*echo.Group{
+		e.Group(config.APIRoutePrefix, m...),
+		e.Group(config.APIRoutePrefix+APIVersion1, m...),
+	}
+}
+
+// NewAPIV2Group returns the group serving the second version of the API.
+// The given middlewares run on every route of the group.
+func NewAPIV2Group(e *echo.Echo, config config.Config, m ...echo.MiddlewareFunc) *echo.Group {
+	return e.Group(config.APIRoutePrefix+APIVersion2, m...)
+}
+
 func SetUserRoutes(r *echo.Group, userHandler user.HTTPHandler) {
 	// swagger:operation GET /user User
 	//
@@ -49,8 +70,14 @@ func SetUserRoutes(r *echo.Group, userHandler user.HTTPHandler) {
 	r.GET("/user", middleware.LogHTTPMetrics(userHandler.GetUser))
 }
 
-// SetAppRoutes binds the route address to their handler functions
-func SetAppRoutes(r *echo.Group, appsHandler apps.HTTPHandler) {
+// SetUserRoutesV2 binds the user routes of the second version of the API, which are the same as in the first one
+func SetUserRoutesV2(r *echo.Group, userHandler user.HTTPHandler) {
+	SetUserRoutes(r, userHandler)
+}
+
+// SetAppRoutes binds the route address to their handler functions.
+// The admin operations are wrapped with the requireAdmin middleware and the routes of a single app with the scopeApp one.
+func SetAppRoutes(r *echo.Group, appsHandler apps.HTTPHandler, requireAdmin, scopeApp echo.MiddlewareFunc) {
 	// swagger:operation GET /apps App
 	//
 	// Returns root level information for all apps
@@ -65,17 +92,128 @@ func SetAppRoutes(r *echo.Group, appsHandler apps.HTTPHandler) {
 	//   description: The app_id to filter the app by appId
 	//   required: false
 	//   type: string
+	// - name: limit
+	//   in: query
+	//   description: The maximum number of apps to return (1-100). All apps are returned when not set
+	//   required: false
+	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: The cursor of the page to return, as sent in the X-Next-Cursor header of the previous page
+	//   required: false
+	//   type: string
+	// - name: sort
+	//   in: query
+	//   description: The field to order by, prefixed with "-" for descending order
+	//   required: false
+	//   type: string
+	//   enum: [name, -name, launches, -launches, installs, -installs]
+	//   default: name
+	// - name: q
+	//   in: query
+	//   description: Returns only the apps whose name or appId contains the given text
+	//   required: false
+	//   type: string
+	// - name: deleted
+	//   in: query
+	//   description: Returns the soft deleted apps instead of the active ones
+	//   required: false
+	//   type: boolean
+	//   default: false
+	// - name: tenantId
+	//   in: query
+	//   description: Returns only the apps of this tenant
+	//   required: false
+	//   type: string
 	// responses:
 	//   200:
 	//     description: successful operation
+	//     headers:
+	//       X-Next-Cursor:
+	//         type: string
+	//         description: The cursor of the next page, when there is one
+	//       Link:
+	//         type: string
+	//         description: The URL of the next page with rel="next", when there is one
 	//     schema:
-	//       $ref: '#/definitions/App'
+	//       type: array
+	//       items:
+	//         $ref: '#/definitions/AppResponse'
 	//   204:
 	//     description: successful operation by no apps were found
+	//   400:
+	//     description: Invalid query parameters supplied
 	//   404:
 	//     description: App not found
 	r.GET("/apps", middleware.LogHTTPMetrics(appsHandler.GetApps))
 
+	setAppRoutes(r, appsHandler, requireAdmin, scopeApp)
+}
+
+// SetAppRoutesV2 binds the app routes of the second version of the API to their handler functions.
+// The list of apps is returned as a page with the cursor of the following one in the body.
+func SetAppRoutesV2(r *echo.Group, appsHandler apps.HTTPHandler, requireAdmin, scopeApp echo.MiddlewareFunc) {
+	// swagger:operation GET /v2/apps App
+	//
+	// Returns a page of apps with the cursor of the following page
+	// ---
+	// summary: Retrieve a page of apps
+	// operationId: getAppsPage
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: appId
+	//   in: query
+	//   description: The app_id to filter the app by appId
+	//   required: false
+	//   type: string
+	// - name: limit
+	//   in: query
+	//   description: The maximum number of apps to return (1-100). All apps are returned when not set
+	//   required: false
+	//   type: integer
+	// - name: cursor
+	//   in: query
+	//   description: The cursor of the page to return, as sent in the nextCursor of the previous page
+	//   required: false
+	//   type: string
+	// - name: sort
+	//   in: query
+	//   description: The field to order by, prefixed with "-" for descending order
+	//   required: false
+	//   type: string
+	//   enum: [name, -name, launches, -launches, installs, -installs]
+	//   default: name
+	// - name: q
+	//   in: query
+	//   description: Returns only the apps whose name or appId contains the given text
+	//   required: false
+	//   type: string
+	// - name: deleted
+	//   in: query
+	//   description: Returns the soft deleted apps instead of the active ones
+	//   required: false
+	//   type: boolean
+	//   default: false
+	// - name: tenantId
+	//   in: query
+	//   description: Returns only the apps of this tenant
+	//   required: false
+	//   type: string
+	// responses:
+	//   200:
+	//     description: successful operation, with an empty list of apps when none were found
+	//     schema:
+	//       $ref: '#/definitions/AppsPageResponse'
+	//   400:
+	//     description: Invalid query parameters supplied
+	r.GET("/apps", middleware.LogHTTPMetrics(appsHandler.GetAppsPage))
+
+	setAppRoutes(r, appsHandler, requireAdmin, scopeApp)
+}
+
+// setAppRoutes binds the routes of a single app, which are the same in every version of the API
+func setAppRoutes(r *echo.Group, appsHandler apps.HTTPHandler, requireAdmin, scopeApp echo.MiddlewareFunc) {
 	// swagger:operation GET /apps/{id} App
 	//
 	// Retrieve all information for a single app including all child information
@@ -94,12 +232,12 @@ func SetAppRoutes(r *echo.Group, appsHandler apps.HTTPHandler) {
 	//   200:
 	//     description: successful operation
 	//     schema:
-	//       $ref: '#/definitions/App'
+	//       $ref: '#/definitions/AppResponse'
 	//   400:
 	//     description: Invalid id supplied
 	//   404:
 	//     description: App not found
-	r.GET("/apps/:id", middleware.LogHTTPMetrics(appsHandler.GetActiveAppByID))
+	r.GET("/apps/:id", middleware.LogHTTPMetrics(scopeApp(appsHandler.GetActiveAppByID)))
 
 	// swagger:operation DELETE /apps/{id} App
 	//
@@ -122,9 +260,73 @@ func SetAppRoutes(r *echo.Group, appsHandler apps.HTTPHandler) {
 	//     description: Invalid id supplied
 	//   404:
 	//     description: App not found
-	r.DELETE("/apps/:id", middleware.LogHTTPMetrics(appsHandler.DeleteAppById))
+	//   409:
+	//     description: The app is managed by the policy directory
+	r.DELETE("/apps/:id", middleware.LogHTTPMetrics(scopeApp(appsHandler.DeleteAppById)))
+
+	// swagger:operation POST /apps/{id}/restore App
+	//
+	// Restore an app which was soft deleted
+	// ---
+	// summary: Restore a soft deleted app
+	// operationId: RestoreAppByID
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app to restore
+	//   required: true
+	//   type: string
+	// responses:
+	//   204:
+	//     description: successful operation
+	//   400:
+	//     description: Invalid id supplied
+	//   404:
+	//     description: App not found
+	//   409:
+	//     description: The app is not deleted
+	r.POST("/apps/:id/restore", middleware.LogHTTPMetrics(scopeApp(appsHandler.RestoreAppByID)))
+
+	// swagger:operation POST /apps/{id}/purge App
+	//
+	// Permanently delete an app with all its versions and devices, released versions, signing certificates, webhooks and alerts, returning them as a JSON archive. Only admin users can do it.
+	// ---
+	// summary: Hard delete an app
+	// operationId: HardDeleteAppByID
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app to delete
+	//   required: true
+	//   type: string
+	// - name: dryRun
+	//   in: query
+	//   description: Returns the number of versions, devices, released versions, signing certificates, webhooks and alerts which would be deleted without deleting anything
+	//   required: false
+	//   type: boolean
+	//   default: false
+	// responses:
+	//   200:
+	//     description: successful operation
+	//     schema:
+	//       $ref: '#/definitions/HardDeleteAppResponse'
+	//   400:
+	//     description: Invalid id supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not an admin
+	//   404:
+	//     description: App not found
+	//   409:
+	//     description: The app is managed by the policy directory
+	r.POST("/apps/:id/purge", middleware.LogHTTPMetrics(scopeApp(requireAdmin(appsHandler.HardDeleteAppByID))))
 
-	// swagger:operation PUT /apps/:id/versions Version
+	// swagger:operation PUT /apps/{id}/versions Version
 	//
 	// Update all versions informed of an app using the app id, including updating version information
 	// ---
@@ -143,17 +345,25 @@ func SetAppRoutes(r *echo.Group, appsHandler apps.HTTPHandler) {
 	//   description: Updated 1 or more versions of an app
 	//   required: true
 	//   schema:
-	//     $ref: '#/definitions/Version'
+	//     type: array
+	//     items:
+	//       $ref: '#/definitions/UpdateVersionRequest'
 	// responses:
-	//   200:
+	//   204:
 	//     description: successful update
 	//   400:
 	//     description: Invalid app and/or versions supplied
 	//   404:
 	//     description: App not found
-	r.PUT("/apps/:id/versions", middleware.LogHTTPMetrics(appsHandler.UpdateAppVersions))
+	//   409:
+	//     description: The app is managed by the policy directory
+	//   422:
+	//     description: The versions are not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.PUT("/apps/:id/versions", middleware.LogHTTPMetrics(scopeApp(appsHandler.UpdateAppVersions)))
 
-	// swagger:operation POST /apps/:id/versions/disable Version
+	// swagger:operation POST /apps/{id}/versions/disable Version
 	//
 	// Disable all versions of an app
 	// ---
@@ -172,117 +382,852 @@ func SetAppRoutes(r *echo.Group, appsHandler apps.HTTPHandler) {
 	//   description:
 	//   required: true
 	//   schema:
-	//     $ref: '#/definitions/Version'
+	//     $ref: '#/definitions/DisableAppVersionsRequest'
 	// responses:
-	//   200:
+	//   204:
 	//     description: successful update
 	//   400:
 	//     description: Invalid app supplied
 	//   404:
 	//     description: App not found
-	r.POST("/apps/:id/versions/disable", middleware.LogHTTPMetrics(appsHandler.DisableAllAppVersionsByAppID))
+	//   409:
+	//     description: The app is managed by the policy directory
+	r.POST("/apps/:id/versions/disable", middleware.LogHTTPMetrics(scopeApp(appsHandler.DisableAllAppVersionsByAppID)))
 
-	// Create an app
+	// swagger:operation GET /apps/{id}/releases Version
+	//
+	// Retrieve the released versions declared for an app and what the init endpoint does with the other versions
 	// ---
-	// summary:
-	// - Create an new app
-	// - Update the deleted_at field of an app when the appId already exist
-	// operationId: CreateApp
+	// summary: Retrieve the released versions of an app
+	// operationId: GetReleases
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// responses:
+	//   200:
+	//     description: successful operation
+	//     schema:
+	//       $ref: '#/definitions/ReleasesResponse'
+	//   400:
+	//     description: Invalid id supplied
+	//   404:
+	//     description: App not found
+	r.GET("/apps/:id/releases", middleware.LogHTTPMetrics(scopeApp(appsHandler.GetReleases)))
+
+	// swagger:operation PUT /apps/{id}/releases Version
+	//
+	// Replace the released versions declared for an app and its mode for the other versions sent to the init endpoint:
+	// accept them, accept and flag them in the versions of the app, or block them without registering them
+	// ---
+	// summary: Replace the released versions of an app
+	// operationId: SetReleases
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     $ref: '#/definitions/SetReleasesRequest'
+	// responses:
+	//   204:
+	//     description: successful update
+	//   400:
+	//     description: Invalid id supplied
+	//   404:
+	//     description: App not found
+	//   409:
+	//     description: The app is managed by the policy directory
+	//   422:
+	//     description: The released versions are not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.PUT("/apps/:id/releases", middleware.LogHTTPMetrics(scopeApp(appsHandler.SetReleases)))
+
+	// swagger:operation POST /apps/{id}/releases Version
+	//
+	// Declare a released version of an app, e.g. from the pipeline which publishes it
+	// ---
+	// summary: Declare a released version of an app
+	// operationId: AddRelease
 	// produces:
 	// - application/json
 	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
 	// - name: body
 	//   in: body
-	//   description:
 	//   required: true
 	//   schema:
-	//     $ref: '#/definitions/App'
+	//     $ref: '#/definitions/AddReleaseRequest'
 	// responses:
-	//   201:
+	//   204:
+	//     description: successful update
+	//   400:
+	//     description: Invalid id supplied
+	//   404:
+	//     description: App not found
+	//   409:
+	//     description: The app is managed by the policy directory
+	//   422:
+	//     description: The version is not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.POST("/apps/:id/releases", middleware.LogHTTPMetrics(scopeApp(appsHandler.AddRelease)))
+
+	// swagger:operation GET /apps/{id}/certificates Certificate
+	//
+	// Retrieve the signing certificates registered for an app and what the init endpoint does with the builds which are not signed by them
+	// ---
+	// summary: Retrieve the signing certificates of an app
+	// operationId: GetSigningCertificates
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// responses:
+	//   200:
 	//     description: successful operation
+	//     schema:
+	//       $ref: '#/definitions/SigningPolicyResponse'
 	//   400:
-	//     description: Invalid data supplied
-	r.POST("/apps", middleware.LogHTTPMetrics(appsHandler.CreateApp))
+	//     description: Invalid id supplied
+	//   404:
+	//     description: App not found
+	r.GET("/apps/:id/certificates", middleware.LogHTTPMetrics(scopeApp(appsHandler.GetSigningCertificates)))
 
-	// Update an app
+	// swagger:operation POST /apps/{id}/certificates Certificate
+	//
+	// Register the SHA-256 fingerprint of a certificate signing the Android or iOS builds of an app. Only admin users can do it.
+	// Once a platform has certificates, the builds reporting another one or none to the init endpoint are tampered.
 	// ---
-	// summary: Update the app name of an app
-	// operationId: UpdateAppNameByID
+	// summary: Register a signing certificate of an app
+	// operationId: CreateSigningCertificate
 	// produces:
 	// - application/json
 	// parameters:
 	// - name: id
 	//   in: path
-	//   description: The id for the app that will have all its versions updated
+	//   description: The id of the app
 	//   required: true
 	//   type: string
 	// - name: body
 	//   in: body
-	//   description:
 	//   required: true
 	//   schema:
-	//     $ref: '#/definitions/App'
+	//     $ref: '#/definitions/CreateSigningCertificateRequest'
 	// responses:
 	//   201:
 	//     description: successful operation
+	//     schema:
+	//       $ref: '#/definitions/SigningCertificateResponse'
 	//   400:
-	//     description: Invalid data supplied
-	r.PATCH("/apps/:id", middleware.LogHTTPMetrics(appsHandler.UpdateAppNameByID))
-}
+	//     description: Invalid id or data supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not an admin
+	//   404:
+	//     description: App not found
+	//   409:
+	//     description: The certificate is already registered for the app
+	//   422:
+	//     description: The certificate is not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.POST("/apps/:id/certificates", middleware.LogHTTPMetrics(scopeApp(requireAdmin(appsHandler.CreateSigningCertificate))))
 
-func SetInitRoutes(r *echo.Group, initHandler *initclient.HTTPHandler) {
-	// swagger:operation POST /init Device
+	// swagger:operation PUT /apps/{id}/certificates/mode Certificate
 	//
-	// Capture metrics from device and return if the app version they are using is disabled and has a set disabled message
+	// Set what the init endpoint does with the builds of an app which are not signed by its certificates:
+	// flag them in the events and the logs, or also disable them without registering them. Only admin users can do it.
 	// ---
-	// summary: Init call from SDK
-	// operationId: initAppFromDevice
+	// summary: Set the mode of an app for the tampered builds
+	// operationId: SetTamperedBuilds
 	// produces:
 	// - application/json
 	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
 	// - name: body
 	//   in: body
-	//   description: Updated app object
 	//   required: true
 	//   schema:
-	//     $ref: '#/definitions/Version'
+	//     $ref: '#/definitions/SetTamperedBuildsRequest'
 	// responses:
-	//   200:
-	//     description: successful operation
+	//   204:
+	//     description: successful update
 	//   400:
 	//     description: Invalid id supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not an admin
 	//   404:
-	//     description: Data not found
-	r.POST("/init", middleware.LogHTTPMetrics(initHandler.InitClientApp))
-}
+	//     description: App not found
+	//   422:
+	//     description: The mode is not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.PUT("/apps/:id/certificates/mode", middleware.LogHTTPMetrics(scopeApp(requireAdmin(appsHandler.SetTamperedBuilds))))
 
-func SetChecksRouter(r *echo.Group, handler *checks.HTTPHandler) {
-	// swagger:operation GET /ping Status
+	// swagger:operation DELETE /apps/{id}/certificates/{certificateId} Certificate
 	//
-	// Check the status of the REST SERVICE API
+	// Delete a signing certificate of an app. Only admin users can do it.
 	// ---
-	// summary: Check if the server is running
-	// operationId: status
+	// summary: Delete a signing certificate of an app
+	// operationId: DeleteSigningCertificate
 	// produces:
 	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// - name: certificateId
+	//   in: path
+	//   description: The id of the certificate
+	//   required: true
+	//   type: string
 	// responses:
-	//   200:
+	//   204:
 	//     description: successful operation
-	r.GET("/ping", handler.Ping)
+	//   400:
+	//     description: Invalid ids supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not an admin
+	//   404:
+	//     description: App or certificate not found
+	r.DELETE("/apps/:id/certificates/:certificateId", middleware.LogHTTPMetrics(scopeApp(requireAdmin(appsHandler.DeleteSigningCertificate))))
 
-	// swagger:operation GET /healthz Status
+	// swagger:operation POST /apps App
 	//
-	// Check the health of the REST SERVICE API
+	// Create an app
 	// ---
-	// summary: Check if the server can receive requests
-	// operationId: health
+	// summary: Create an app, or restore it when an app with the same appId was deleted
+	// operationId: CreateApp
 	// produces:
 	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   description:
+	//   required: true
+	//   schema:
+	//     $ref: '#/definitions/CreateAppRequest'
 	// responses:
-	//   200:
+	//   201:
 	//     description: successful operation
-	//   500:
-	//     description: Internal Server Error
+	//   400:
+	//     description: Invalid data supplied
+	//   409:
+	//     description: An app with the same appId already exists or the app is managed by the policy directory
+	//   422:
+	//     description: The app is not valid, or its tenant is not a tenant of the user
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.POST("/apps", middleware.LogHTTPMetrics(appsHandler.CreateApp))
+
+	// swagger:operation PATCH /apps/{id} App
+	//
+	// Update an app
+	// ---
+	// summary: Update the app name of an app
+	// operationId: UpdateAppNameByID
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id for the app that will have its name updated
+	//   required: true
+	//   type: string
+	// - name: body
+	//   in: body
+	//   description:
+	//   required: true
+	//   schema:
+	//     $ref: '#/definitions/UpdateAppNameRequest'
+	// responses:
+	//   204:
+	//     description: successful operation
+	//   400:
+	//     description: Invalid data supplied
+	//   404:
+	//     description: App not found
+	//   409:
+	//     description: The app is managed by the policy directory
+	//   422:
+	//     description: The name is not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.PATCH("/apps/:id", middleware.LogHTTPMetrics(scopeApp(appsHandler.UpdateAppNameByID)))
+
+	// swagger:operation GET /export Policy
+	//
+	// Export the active apps and the state of their versions as a policy document, which can be imported in another service
+	// ---
+	// summary: Export the policy document of the apps
+	// operationId: ExportApps
+	// produces:
+	// - application/json
+	// - application/yaml
+	// parameters:
+	// - name: format
+	//   in: query
+	//   description: The format of the document. Without it, YAML is returned when the Accept header asks for it
+	//   required: false
+	//   type: string
+	//   enum: [json, yaml]
+	// responses:
+	//   200:
+	//     description: successful operation
+	//     schema:
+	//       $ref: '#/definitions/AppsPolicy'
+	//   400:
+	//     description: Invalid format supplied
+	//   403:
+	//     description: The tenants are enabled and the user is not a super admin
+	r.GET("/export", middleware.LogHTTPMetrics(appsHandler.ExportApps))
+
+	// swagger:operation POST /import Policy
+	//
+	// Apply a policy document, creating, restoring and renaming the apps and updating the state of their versions. Only admin users can do it.
+	// ---
+	// summary: Import a policy document of the apps
+	// operationId: ImportApps
+	// consumes:
+	// - application/json
+	// - application/yaml
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: dryRun
+	//   in: query
+	//   description: Returns the changes which would be made without changing anything
+	//   required: false
+	//   type: boolean
+	//   default: false
+	// - name: body
+	//   in: body
+	//   description: The policy document, in JSON or YAML
+	//   required: true
+	//   schema:
+	//     $ref: '#/definitions/AppsPolicy'
+	// responses:
+	//   200:
+	//     description: successful operation
+	//     schema:
+	//       $ref: '#/definitions/ImportAppsResponse'
+	//   400:
+	//     description: Invalid data supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not an admin, or not a super admin when the tenants are enabled
+	//   409:
+	//     description: The app is managed by the policy directory
+	//   422:
+	//     description: The document is not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.POST("/import", middleware.LogHTTPMetrics(requireAdmin(appsHandler.ImportApps)))
+}
+
+// SetWebhookRoutes binds the routes of the webhooks of the apps, which are the same in every version of the API.
+// The changes of the webhooks are wrapped with the requireAdmin middleware and all the routes with the scopeApp one.
+func SetWebhookRoutes(r *echo.Group, webhooksHandler webhooks.HTTPHandler, requireAdmin, scopeApp echo.MiddlewareFunc) {
+	// swagger:operation GET /apps/{id}/webhooks Webhook
+	//
+	// Retrieve the webhooks of an app, without their secrets
+	// ---
+	// summary: Retrieve the webhooks of an app
+	// operationId: GetWebhooks
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// responses:
+	//   200:
+	//     description: successful operation
+	//     schema:
+	//       type: array
+	//       items:
+	//         $ref: '#/definitions/WebhookResponse'
+	//   400:
+	//     description: Invalid id supplied
+	//   404:
+	//     description: App not found
+	r.GET("/apps/:id/webhooks", middleware.LogHTTPMetrics(scopeApp(webhooksHandler.GetWebhooks)))
+
+	// swagger:operation POST /apps/{id}/webhooks Webhook
+	//
+	// Subscribe a URL to some types of the events of an app. Only admin users can do it.
+	// ---
+	// summary: Create a webhook
+	// operationId: CreateWebhook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// - name: body
+	//   in: body
+	//   description: The URL and the types of the events to send to it
+	//   required: true
+	//   schema:
+	//     $ref: '#/definitions/CreateWebhookRequest'
+	// responses:
+	//   201:
+	//     description: successful operation, with the secret signing the deliveries which is not returned again
+	//     schema:
+	//       $ref: '#/definitions/CreatedWebhookResponse'
+	//   400:
+	//     description: Invalid id or data supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not an admin
+	//   404:
+	//     description: App not found
+	//   422:
+	//     description: The webhook is not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.POST("/apps/:id/webhooks", middleware.LogHTTPMetrics(scopeApp(requireAdmin(webhooksHandler.CreateWebhook))))
+
+	// swagger:operation DELETE /apps/{id}/webhooks/{webhookId} Webhook
+	//
+	// Delete a webhook of an app with its deliveries. Only admin users can do it.
+	// ---
+	// summary: Delete a webhook
+	// operationId: DeleteWebhook
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// - name: webhookId
+	//   in: path
+	//   description: The id of the webhook
+	//   required: true
+	//   type: string
+	// responses:
+	//   204:
+	//     description: successful operation
+	//   400:
+	//     description: Invalid ids supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not an admin
+	//   404:
+	//     description: App or webhook not found
+	r.DELETE("/apps/:id/webhooks/:webhookId", middleware.LogHTTPMetrics(scopeApp(requireAdmin(webhooksHandler.DeleteWebhook))))
+
+	// swagger:operation GET /apps/{id}/webhooks/{webhookId}/deliveries Webhook
+	//
+	// Retrieve the last 100 deliveries of a webhook, the most recent first
+	// ---
+	// summary: Retrieve the deliveries of a webhook
+	// operationId: GetWebhookDeliveries
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// - name: webhookId
+	//   in: path
+	//   description: The id of the webhook
+	//   required: true
+	//   type: string
+	// responses:
+	//   200:
+	//     description: successful operation
+	//     schema:
+	//       type: array
+	//       items:
+	//         $ref: '#/definitions/DeliveryResponse'
+	//   400:
+	//     description: Invalid ids supplied
+	//   404:
+	//     description: App or webhook not found
+	r.GET("/apps/:id/webhooks/:webhookId/deliveries", middleware.LogHTTPMetrics(scopeApp(webhooksHandler.GetDeliveries)))
+
+	// swagger:operation POST /apps/{id}/webhooks/{webhookId}/deliveries/{deliveryId}/replay Webhook
+	//
+	// Send again the event of a delivery with a new delivery. Only admin users can do it.
+	// ---
+	// summary: Replay a delivery
+	// operationId: ReplayWebhookDelivery
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// - name: webhookId
+	//   in: path
+	//   description: The id of the webhook
+	//   required: true
+	//   type: string
+	// - name: deliveryId
+	//   in: path
+	//   description: The id of the delivery to replay
+	//   required: true
+	//   type: string
+	// responses:
+	//   202:
+	//     description: successful operation, the new delivery is pending
+	//     schema:
+	//       $ref: '#/definitions/DeliveryResponse'
+	//   400:
+	//     description: Invalid ids supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not an admin
+	//   404:
+	//     description: App, webhook or delivery not found
+	r.POST("/apps/:id/webhooks/:webhookId/deliveries/:deliveryId/replay", middleware.LogHTTPMetrics(scopeApp(requireAdmin(webhooksHandler.ReplayDelivery))))
+}
+
+// SetTenantRoutes binds the routes of the tenants owning the apps, which are the same in every version of the API.
+// The changes of the tenants are wrapped with the requireSuperAdmin middleware.
+func SetTenantRoutes(r *echo.Group, tenantsHandler tenants.HTTPHandler, requireSuperAdmin echo.MiddlewareFunc) {
+	// swagger:operation GET /tenants Tenant
+	//
+	// Retrieve the tenants of the user, or every tenant for a super admin
+	// ---
+	// summary: Retrieve the tenants
+	// operationId: GetTenants
+	// produces:
+	// - application/json
+	// responses:
+	//   200:
+	//     description: successful operation, the tenants sorted by name
+	//     schema:
+	//       type: array
+	//       items:
+	//         $ref: '#/definitions/TenantResponse'
+	r.GET("/tenants", middleware.LogHTTPMetrics(tenantsHandler.GetTenants))
+
+	// swagger:operation POST /tenants Tenant
+	//
+	// Create a tenant with its members. Only super admin users can do it.
+	// ---
+	// summary: Create a tenant
+	// operationId: CreateTenant
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   description: The name and the members of the tenant
+	//   required: true
+	//   schema:
+	//     $ref: '#/definitions/CreateTenantRequest'
+	// responses:
+	//   201:
+	//     description: successful operation
+	//     schema:
+	//       $ref: '#/definitions/TenantResponse'
+	//   400:
+	//     description: Invalid data supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not a super admin
+	//   409:
+	//     description: A tenant with the same name already exists
+	//   422:
+	//     description: The tenant is not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.POST("/tenants", middleware.LogHTTPMetrics(requireSuperAdmin(tenantsHandler.CreateTenant)))
+
+	// swagger:operation PUT /tenants/{tenantId}/members Tenant
+	//
+	// Replace the members of a tenant. Only super admin users can do it.
+	// ---
+	// summary: Replace the members of a tenant
+	// operationId: SetTenantMembers
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: tenantId
+	//   in: path
+	//   description: The id of the tenant
+	//   required: true
+	//   type: string
+	// - name: body
+	//   in: body
+	//   description: The usernames of the members
+	//   required: true
+	//   schema:
+	//     $ref: '#/definitions/SetTenantMembersRequest'
+	// responses:
+	//   204:
+	//     description: successful operation
+	//   400:
+	//     description: Invalid id or data supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not a super admin
+	//   404:
+	//     description: Tenant not found
+	//   422:
+	//     description: The members are not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.PUT("/tenants/:tenantId/members", middleware.LogHTTPMetrics(requireSuperAdmin(tenantsHandler.SetMembers)))
+
+	// swagger:operation DELETE /tenants/{tenantId} Tenant
+	//
+	// Delete a tenant which owns no app. Only super admin users can do it.
+	// ---
+	// summary: Delete a tenant
+	// operationId: DeleteTenant
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: tenantId
+	//   in: path
+	//   description: The id of the tenant
+	//   required: true
+	//   type: string
+	// responses:
+	//   204:
+	//     description: successful operation
+	//   400:
+	//     description: Invalid id supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not a super admin
+	//   404:
+	//     description: Tenant not found
+	//   409:
+	//     description: The tenant still owns apps or is the default tenant
+	r.DELETE("/tenants/:tenantId", middleware.LogHTTPMetrics(requireSuperAdmin(tenantsHandler.DeleteTenant)))
+
+	// swagger:operation PUT /apps/{id}/tenant Tenant
+	//
+	// Move an app to another tenant. Only super admin users can do it.
+	// ---
+	// summary: Move an app to another tenant
+	// operationId: MoveApp
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// - name: body
+	//   in: body
+	//   description: The id of the tenant which owns the app from now on
+	//   required: true
+	//   schema:
+	//     $ref: '#/definitions/MoveAppRequest'
+	// responses:
+	//   204:
+	//     description: successful operation
+	//   400:
+	//     description: Invalid id or data supplied
+	//   401:
+	//     description: No user found
+	//   403:
+	//     description: The user is not a super admin
+	//   404:
+	//     description: App or tenant not found
+	//   422:
+	//     description: The tenant is not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.PUT("/apps/:id/tenant", middleware.LogHTTPMetrics(requireSuperAdmin(tenantsHandler.MoveApp)))
+}
+
+// SetAlertRoutes binds the routes of the alerts raised by the analysis of the init requests, wrapped with the scopeApp middleware
+func SetAlertRoutes(r *echo.Group, alertsHandler alerts.HTTPHandler, scopeApp echo.MiddlewareFunc) {
+	// swagger:operation GET /apps/{id}/alerts Alert
+	//
+	// Retrieve the alerts raised by the analysis of the init requests of an app, the last detected first
+	// ---
+	// summary: Retrieve the alerts of an app
+	// operationId: GetAlerts
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// responses:
+	//   200:
+	//     description: successful operation
+	//     schema:
+	//       type: array
+	//       items:
+	//         $ref: '#/definitions/AlertResponse'
+	//   400:
+	//     description: Invalid id supplied
+	//   404:
+	//     description: App not found
+	r.GET("/apps/:id/alerts", middleware.LogHTTPMetrics(scopeApp(alertsHandler.GetAlerts)))
+}
+
+// SetStreamRoutes binds the routes of the event streams, which are only open to the identified users.
+// The streams of a single app are wrapped with the scopeApp middleware.
+func SetStreamRoutes(r *echo.Group, streamHandler stream.HTTPHandler, requireUser, scopeApp echo.MiddlewareFunc) {
+	// swagger:operation GET /apps/{id}/events Stream
+	//
+	// Stream the changes of the versions and the launch counters of an app as Server-Sent Events.
+	// A stream resumed with the Last-Event-ID header starts with the messages which were missed.
+	// ---
+	// summary: Stream the changes of an app
+	// operationId: GetAppEvents
+	// produces:
+	// - text/event-stream
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: The id of the app
+	//   required: true
+	//   type: string
+	// - name: Last-Event-ID
+	//   in: header
+	//   description: The id of the last message received, to resume a stream
+	//   type: string
+	// responses:
+	//   200:
+	//     description: successful operation, the stream of the messages of the app
+	//   400:
+	//     description: Invalid id supplied
+	//   401:
+	//     description: No user found
+	//   404:
+	//     description: App not found
+	r.GET("/apps/:id/events", middleware.LogHTTPMetrics(scopeApp(requireUser(streamHandler.GetAppEvents))))
+}
+
+func SetInitRoutes(r *echo.Group, initHandler *initclient.HTTPHandler) {
+	// swagger:operation POST /init Device
+	//
+	// Capture metrics from device and return if the app version they are using is disabled and has a set disabled message
+	// ---
+	// summary: Init call from SDK
+	// operationId: initAppFromDevice
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   description: The device which is initialising the app
+	//   required: true
+	//   schema:
+	//     $ref: '#/definitions/InitRequest'
+	// responses:
+	//   200:
+	//     description: successful operation
+	//     schema:
+	//       $ref: '#/definitions/InitResponse'
+	//   400:
+	//     description: Invalid id supplied
+	//   404:
+	//     description: Data not found
+	//   422:
+	//     description: The device information is not valid
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	//   429:
+	//     description: Too many requests of the client IP, the app or the device
+	//     headers:
+	//       Retry-After:
+	//         type: integer
+	//         description: The seconds to wait before sending another request
+	//     schema:
+	//       $ref: '#/definitions/Problem'
+	r.POST("/init", middleware.LogHTTPMetrics(initHandler.InitClientApp))
+}
+
+// SetInitRoutesV2 binds the init route of the second version of the API, which is the same as in the first one
+func SetInitRoutesV2(r *echo.Group, initHandler *initclient.HTTPHandler) {
+	SetInitRoutes(r, initHandler)
+}
+
+func SetChecksRouter(r *echo.Group, handler *checks.HTTPHandler) {
+	// swagger:operation GET /ping Status
+	//
+	// Check the status of the REST SERVICE API
+	// ---
+	// summary: Check if the server is running
+	// operationId: status
+	// produces:
+	// - application/json
+	// responses:
+	//   200:
+	//     description: successful operation
+	r.GET("/ping", handler.Ping)
+
+	// swagger:operation GET /healthz Status
+	//
+	// Check the health of the REST SERVICE API
+	// ---
+	// summary: Check if the server and its dependencies can receive requests
+	// operationId: health
+	// produces:
+	// - application/json
+	// responses:
+	//   200:
+	//     description: all checks passed
+	//     schema:
+	//       $ref: '#/definitions/HealthReport'
+	//   503:
+	//     description: one or more checks failed or the server is shutting down
+	//     schema:
+	//       $ref: '#/definitions/HealthReport'
 	r.GET("/healthz", handler.Healthz)
 }
 
@@ -0,0 +1,190 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/health"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/alerts"
+	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+	"github.com/aerogear/mobile-security-service/pkg/web/checks"
+	"github.com/aerogear/mobile-security-service/pkg/web/initclient"
+	"github.com/aerogear/mobile-security-service/pkg/web/stream"
+	"github.com/aerogear/mobile-security-service/pkg/web/tenants"
+	"github.com/aerogear/mobile-security-service/pkg/web/user"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/aerogear/mobile-security-service/pkg/web/webhooks"
+	"github.com/labstack/echo"
+)
+
+// newTestRouter returns a router with all the routes of the server bound to handlers using the apps, webhooks, tenants and alerts services.
+// The event streams have no messages but their first one.
+// It is built without NewRouter, which registers the Prometheus metrics.
+// The requests and the responses of the API are validated against the OpenAPI document.
+func newTestRouter(config config.Config, appsService apps.Service, webhooksService webhooks.Service, tenantsService tenants.Service, alertsService alerts.Service) *echo.Echo {
+	e := echo.New()
+	e.Validator = validation.NewValidator()
+
+	appsHandler := apps.NewHTTPHandler(e, appsService)
+	userHandler := user.NewHTTPHandler(e)
+	initHandler := initclient.NewHTTPHandler(e, appsService, nil, nil)
+	webhooksHandler := webhooks.NewHTTPHandler(e, webhooksService)
+	tenantsHandler := tenants.NewHTTPHandler(e, tenantsService)
+	alertsHandler := alerts.NewHTTPHandler(e, alertsService)
+	streamHandler := stream.NewHTTPHandler(e, appsService, stream.NewBroker(config.Stream.LaunchesInterval), config.Stream)
+	requireAdmin := user.RequireAdmin(config.AdminUsers)
+	requireUser := user.RequireUser()
+	requireSuperAdmin := user.RequireAdmin(config.Tenants.SuperAdmins)
+	scopeApp := tenants.ScopeApp(tenantsService)
+
+	authenticate, err := user.Authenticate(config.Auth)
+	if err != nil {
+		panic(err)
+	}
+	openAPI := NewOpenAPI(config)
+	middlewares := []echo.MiddlewareFunc{authenticate, NewOpenAPIValidator(openAPI, config, true)}
+	if config.Tenants.Enabled {
+		middlewares = append(middlewares, tenants.Scope(tenantsService))
+	}
+
+	for _, g := range NewAPIV1Groups(e, config, middlewares...) {
+		SetUserRoutes(g, userHandler)
+		SetAppRoutes(g, appsHandler, requireAdmin, scopeApp)
+		SetWebhookRoutes(g, webhooksHandler, requireAdmin, scopeApp)
+		SetTenantRoutes(g, tenantsHandler, requireSuperAdmin)
+		SetStreamRoutes(g, streamHandler, requireUser, scopeApp)
+		SetAlertRoutes(g, alertsHandler, scopeApp)
+		SetInitRoutes(g, initHandler)
+	}
+	v2 := NewAPIV2Group(e, config, middlewares...)
+	SetUserRoutesV2(v2, userHandler)
+	SetAppRoutesV2(v2, appsHandler, requireAdmin, scopeApp)
+	SetWebhookRoutes(v2, webhooksHandler, requireAdmin, scopeApp)
+	SetTenantRoutes(v2, tenantsHandler, requireSuperAdmin)
+	SetStreamRoutes(v2, streamHandler, requireUser, scopeApp)
+	SetAlertRoutes(v2, alertsHandler, scopeApp)
+	SetInitRoutesV2(v2, initHandler)
+
+	apiGroup := e.Group(config.APIRoutePrefix)
+	SetChecksRouter(apiGroup, checks.NewHTTPHandler(e, health.NewRegistry(time.Second)))
+	SetMetricsRouter(apiGroup)
+	SetOpenAPIRouter(apiGroup, openAPI)
+
+	return e
+}
+
+func TestAPIVersions(t *testing.T) {
+	config := config.Get()
+	config.APIV1Sunset = time.Date(2020, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	e := newTestRouter(config, &apps.ServiceMock{
+		GetAppsFunc: func(query models.AppsQuery) (*models.AppsPage, error) {
+			return &models.AppsPage{Apps: helpers.GetMockAppList()}, nil
+		},
+	}, &webhooks.ServiceMock{}, &tenants.ServiceMock{}, &alerts.ServiceMock{})
+
+	tests := []struct {
+		name           string
+		path           string
+		wantBody       string
+		wantDeprecated bool
+	}{
+		{
+			name:           "The apps should be served without version for the existing clients",
+			path:           "/api/apps",
+			wantBody:       "[",
+			wantDeprecated: true,
+		},
+		{
+			name:           "The apps should be served in version 1",
+			path:           "/api/v1/apps",
+			wantBody:       "[",
+			wantDeprecated: true,
+		},
+		{
+			name:     "The apps should be served as a page in version 2",
+			path:     "/api/v2/apps",
+			wantBody: `{"apps":[`,
+		},
+		{
+			name:     "The probes should not be versioned",
+			path:     "/api/ping",
+			wantBody: `"OK"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GET %v statusCode = %v, want 200", tt.path, rec.Code)
+			}
+
+			if !strings.HasPrefix(rec.Body.String(), tt.wantBody) {
+				t.Errorf("GET %v body = %v, want prefix %v", tt.path, rec.Body.String(), tt.wantBody)
+			}
+
+			if deprecated := rec.Header().Get("Deprecation") == "true"; deprecated != tt.wantDeprecated {
+				t.Errorf("GET %v deprecated = %v, wantDeprecated %v", tt.path, deprecated, tt.wantDeprecated)
+			}
+
+			if !tt.wantDeprecated {
+				return
+			}
+
+			if link := rec.Header().Get("Link"); link != `</api/v2>; rel="successor-version"` {
+				t.Errorf("GET %v Link = %v, want the successor version", tt.path, link)
+			}
+
+			if sunset := rec.Header().Get("Sunset"); sunset != "Tue, 30 Jun 2020 00:00:00 GMT" {
+				t.Errorf("GET %v Sunset = %v, want the sunset of version 1", tt.path, sunset)
+			}
+		})
+	}
+}
+
+func TestAppRoutesAreScoped(t *testing.T) {
+	config := config.Get()
+	config.Tenants.Enabled = true
+
+	// the apps, webhooks and alerts services are not called for the app of another tenant
+	e := newTestRouter(config, &apps.ServiceMock{}, &webhooks.ServiceMock{}, &tenants.ServiceMock{
+		GetScopeFunc: func(username string) (*models.TenantScope, error) {
+			return &models.TenantScope{TenantIDs: []string{"6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d"}}, nil
+		},
+		GetTenantIDByAppIDFunc: func(id string) (string, error) {
+			return "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d", nil
+		},
+	}, &alerts.ServiceMock{})
+
+	ids := strings.NewReplacer(":id", "0890506c-3dd1-43ad-8a09-21a4111a65a6", ":webhookId", "53d4e6c6-6b7f-4a50-8d2c-0fe5b4c1a2b9", ":certificateId", "53d4e6c6-6b7f-4a50-8d2c-0fe5b4c1a2b9")
+	scoped := 0
+	for _, route := range e.Routes() {
+		// the requests with a body are rejected by the validation first, and only the super admins move the apps
+		if !strings.Contains(route.Path, "/apps/:id") || strings.HasSuffix(route.Path, "/tenant") ||
+			(route.Method != http.MethodGet && route.Method != http.MethodDelete) {
+			continue
+		}
+
+		scoped++
+		path := ids.Replace(route.Path)
+		req := httptest.NewRequest(route.Method, path, nil)
+		req.Header.Set(user.USER_NAME_HEADER, "alice")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%v %v statusCode = %v, want 404", route.Method, path, rec.Code)
+		}
+	}
+	if scoped == 0 {
+		t.Error("no route of a single app was requested")
+	}
+}
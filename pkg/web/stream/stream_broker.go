@@ -0,0 +1,363 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/events"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	log "github.com/sirupsen/logrus"
+)
+
+// The events of the messages sent in the streams
+const (
+	// ReadyEvent is the first message of a new stream, whose id is where the stream resumes from
+	ReadyEvent = "ready"
+	// ResetEvent replaces the missed messages when a stream can not be resumed, so the client reloads the app
+	ResetEvent = "reset"
+	// VersionsEvent has the state of versions which were disabled, enabled or launched for the first time
+	VersionsEvent = "versions"
+	// LaunchesEvent has the launches and the new devices of the versions since the last one
+	LaunchesEvent = "launches"
+	// DeletedEvent is the last message of the streams of an app which was deleted
+	DeletedEvent = "deleted"
+)
+
+const (
+	// historySize is the number of messages of each app kept to resume its streams
+	historySize = 100
+	// subscriberBuffer is the number of messages waiting to be sent to a stream before it is closed as too slow
+	subscriberBuffer = 64
+)
+
+// message is a message of the streams of an app
+type message struct {
+	ID    string
+	Event string
+	Data  []byte
+	seq   uint64
+}
+
+// subscription is a stream of the messages of an app. Its channel is closed when the stream has to end.
+type subscription struct {
+	appID string
+	c     chan message
+}
+
+// appStreams holds the messages and the subscriptions of an app
+type appStreams struct {
+	history []message
+	// dropped is the sequence of the last message removed from the history
+	dropped       uint64
+	subscriptions map[*subscription]bool
+}
+
+// launchCounter aggregates the launches of a version until they are sent
+type launchCounter struct {
+	version    string
+	launches   int64
+	newDevices int64
+}
+
+// Broker turns the events of the apps into the messages of their streams. It is an asynchronous subscriber
+// of the events bus and a background worker sending the launch counters every interval.
+// Only the apps which were streamed since the server started have their messages kept.
+type Broker struct {
+	mu       sync.Mutex
+	epoch    string
+	seq      uint64
+	apps     map[string]*appStreams
+	launches map[string]map[string]*launchCounter
+	closed   bool
+	interval time.Duration
+}
+
+// NewBroker returns a new Broker sending the launch counters every interval
+func NewBroker(interval time.Duration) *Broker {
+	return &Broker{
+		// the ids of the messages of another process, e.g. before a restart, can not be resumed
+		epoch:    strconv.FormatInt(time.Now().UnixNano(), 36),
+		apps:     map[string]*appStreams{},
+		launches: map[string]map[string]*launchCounter{},
+		interval: interval,
+	}
+}
+
+// Handle implements the events.Handler contract
+func (b *Broker) Handle(event events.Event) error {
+	switch e := event.(type) {
+	case events.VersionsUpdated:
+		return b.publish(e.AppID, VersionsEvent, newVersionsData(e.Updated()))
+	case events.AllVersionsDisabled:
+		return b.publish(e.AppID, VersionsEvent, newVersionsData(e.Updated()))
+	case events.VersionFirstSeen:
+		return b.publish(e.Version.AppID, VersionsEvent, newVersionsData([]models.Version{e.Version}))
+	case events.AppLaunched:
+		b.count(e.Device.AppID, e.Version, false)
+	case events.DeviceRegistered:
+		b.count(e.Device.AppID, models.Version{ID: e.Device.VersionID, Version: e.Device.Version}, true)
+	case events.AppDeleted:
+		if err := b.publish(e.App.AppID, DeletedEvent, appData{ID: e.App.ID, AppID: e.App.AppID}); err != nil {
+			return err
+		}
+		b.remove(e.App.AppID)
+	}
+	return nil
+}
+
+// Run implements the lifecycle.Worker contract. It sends the launch counters every interval.
+func (b *Broker) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.flushLaunches()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Close ends all the streams, e.g. when the server shuts down so it does not wait for them.
+// The clients resume their streams from another instance.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, app := range b.apps {
+		for s := range app.subscriptions {
+			delete(app.subscriptions, s)
+			close(s.c)
+		}
+	}
+}
+
+// subscribe starts a stream of the messages of an app. The messages to send first are returned with it:
+// the messages after lastEventID when the stream is resumed, or a ready or reset message otherwise.
+func (b *Broker) subscribe(appID, lastEventID string) (*subscription, []message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	appID = strings.ToLower(appID)
+	s := &subscription{appID: appID, c: make(chan message, subscriberBuffer)}
+	if b.closed {
+		close(s.c)
+		return s, nil
+	}
+
+	app := b.app(appID)
+	app.subscriptions[s] = true
+
+	if lastEventID == "" {
+		return s, []message{b.current(ReadyEvent)}
+	}
+
+	last, ok := b.parseID(lastEventID)
+	if !ok || last < app.dropped || last > b.seq {
+		return s, []message{b.current(ResetEvent)}
+	}
+
+	var missed []message
+	for _, m := range app.history {
+		if m.seq > last {
+			missed = append(missed, m)
+		}
+	}
+	return s, missed
+}
+
+// unsubscribe ends a stream
+func (b *Broker) unsubscribe(s *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if app, ok := b.apps[s.appID]; ok && app.subscriptions[s] {
+		delete(app.subscriptions, s)
+		close(s.c)
+	}
+}
+
+// publish adds a message to the history of an app and sends it to its streams.
+// The streams which are too slow to take it are closed, and resumed by their clients.
+func (b *Broker) publish(appID, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	app, ok := b.apps[strings.ToLower(appID)]
+	if !ok {
+		return nil
+	}
+
+	b.seq++
+	m := message{ID: b.id(b.seq), Event: event, Data: payload, seq: b.seq}
+
+	app.history = append(app.history, m)
+	if len(app.history) > historySize {
+		app.dropped = app.history[0].seq
+		app.history = app.history[1:]
+	}
+
+	for s := range app.subscriptions {
+		select {
+		case s.c <- m:
+		default:
+			log.WithField("appId", appID).Warn("The stream of the app is too slow, it is closed")
+			delete(app.subscriptions, s)
+			close(s.c)
+		}
+	}
+
+	return nil
+}
+
+// count adds a launch of a version to the counters of its app
+func (b *Broker) count(appID string, version models.Version, newDevice bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	appID = strings.ToLower(appID)
+	if _, ok := b.apps[appID]; !ok {
+		return
+	}
+
+	versions, ok := b.launches[appID]
+	if !ok {
+		versions = map[string]*launchCounter{}
+		b.launches[appID] = versions
+	}
+
+	counter, ok := versions[version.ID]
+	if !ok {
+		counter = &launchCounter{version: version.Version}
+		versions[version.ID] = counter
+	}
+
+	if newDevice {
+		counter.newDevices++
+	} else {
+		counter.launches++
+	}
+}
+
+// flushLaunches sends the launch counters of the apps and resets them
+func (b *Broker) flushLaunches() {
+	b.mu.Lock()
+	launches := b.launches
+	b.launches = map[string]map[string]*launchCounter{}
+	b.mu.Unlock()
+
+	for appID, versions := range launches {
+		if err := b.publish(appID, LaunchesEvent, newLaunchesData(versions)); err != nil {
+			log.WithField("appId", appID).Errorf("Failed to send the launches of the app: %v", err)
+		}
+	}
+}
+
+// remove ends the streams of an app and forgets its messages
+func (b *Broker) remove(appID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	appID = strings.ToLower(appID)
+	if app, ok := b.apps[appID]; ok {
+		for s := range app.subscriptions {
+			close(s.c)
+		}
+	}
+	delete(b.apps, appID)
+	delete(b.launches, appID)
+}
+
+// app returns the streams of an app, created on its first subscription.
+// The messages published before are not kept, so they are considered dropped.
+func (b *Broker) app(appID string) *appStreams {
+	app, ok := b.apps[appID]
+	if !ok {
+		app = &appStreams{dropped: b.seq, subscriptions: map[*subscription]bool{}}
+		b.apps[appID] = app
+	}
+	return app
+}
+
+// current returns a message which is not kept, with the id of the last message so the stream resumes from it
+func (b *Broker) current(event string) message {
+	return message{ID: b.id(b.seq), Event: event, Data: []byte("{}"), seq: b.seq}
+}
+
+func (b *Broker) id(seq uint64) string {
+	return fmt.Sprintf("%v-%v", b.epoch, seq)
+}
+
+// parseID returns the sequence of the id of a message of this process
+func (b *Broker) parseID(id string) (uint64, bool) {
+	parts := strings.Split(id, "-")
+	if len(parts) != 2 || parts[0] != b.epoch {
+		return 0, false
+	}
+
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	return seq, err == nil
+}
+
+// versionData is the state of a version in a versions message
+type versionData struct {
+	ID              string `json:"id"`
+	Version         string `json:"version"`
+	Disabled        bool   `json:"disabled"`
+	DisabledMessage string `json:"disabledMessage"`
+}
+
+// versionsData is the data of a versions message
+type versionsData struct {
+	Versions []versionData `json:"versions"`
+}
+
+// launchData is the launches of a version in a launches message
+type launchData struct {
+	ID         string `json:"id"`
+	Version    string `json:"version"`
+	Launches   int64  `json:"launches"`
+	NewDevices int64  `json:"newDevices"`
+}
+
+// launchesData is the data of a launches message
+type launchesData struct {
+	Versions []launchData `json:"versions"`
+}
+
+// appData is the data of a deleted message
+type appData struct {
+	ID    string `json:"id"`
+	AppID string `json:"appId"`
+}
+
+func newVersionsData(versions []models.Version) versionsData {
+	data := versionsData{Versions: make([]versionData, 0, len(versions))}
+	for _, v := range versions {
+		data.Versions = append(data.Versions, versionData{ID: v.ID, Version: v.Version, Disabled: v.Disabled, DisabledMessage: v.DisabledMessage})
+	}
+	return data
+}
+
+func newLaunchesData(versions map[string]*launchCounter) launchesData {
+	data := launchesData{Versions: make([]launchData, 0, len(versions))}
+	for id, counter := range versions {
+		data.Versions = append(data.Versions, launchData{ID: id, Version: counter.version, Launches: counter.launches, NewDevices: counter.newDevices})
+	}
+	sort.Slice(data.Versions, func(i, j int) bool { return data.Versions[i].Version < data.Versions[j].Version })
+	return data
+}
@@ -0,0 +1,212 @@
+package stream
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/events"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// receive returns the messages waiting in a subscription, and whether it is still open
+func receive(s *subscription) ([]message, bool) {
+	var got []message
+	for {
+		select {
+		case m, ok := <-s.c:
+			if !ok {
+				return got, false
+			}
+			got = append(got, m)
+		default:
+			return got, true
+		}
+	}
+}
+
+// eventsOf returns the events of messages
+func eventsOf(messages []message) []string {
+	var got []string
+	for _, m := range messages {
+		got = append(got, m.Event)
+	}
+	return got
+}
+
+func disableVersion(b *Broker, appID string) {
+	version := helpers.GetMockAppVersionList()[0]
+	version.AppID = appID
+	_ = b.Handle(events.VersionsUpdated{
+		AppID:    appID,
+		Previous: []models.Version{version},
+		Versions: []models.Version{{ID: version.ID, AppID: appID, Disabled: true, DisabledMessage: "Please update"}},
+	})
+}
+
+func TestBroker_subscribe(t *testing.T) {
+	app := helpers.GetMockApp()
+	b := NewBroker(time.Second)
+
+	s, first := b.subscribe(app.AppID, "")
+	if got := eventsOf(first); !reflect.DeepEqual(got, []string{ReadyEvent}) {
+		t.Fatalf("Broker.subscribe() = %v, want a ready message", got)
+	}
+
+	disableVersion(b, app.AppID)
+
+	got, open := receive(s)
+	if !open || len(got) != 1 || got[0].Event != VersionsEvent {
+		t.Fatalf("Broker.Handle() sent %v, want a versions message", eventsOf(got))
+	}
+
+	var data versionsData
+	if err := json.Unmarshal(got[0].Data, &data); err != nil {
+		t.Fatalf("the data of the message is not valid: %v", err)
+	}
+	want := versionsData{Versions: []versionData{{ID: "55ebd387-9c68-4137-a367-a12025cc2cdb", Version: "1.0", Disabled: true, DisabledMessage: "Please update"}}}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Broker.Handle() sent %+v, want %+v", data, want)
+	}
+
+	b.unsubscribe(s)
+	if _, open := receive(s); open {
+		t.Error("Broker.unsubscribe() did not end the stream")
+	}
+
+	// the stream is resumed with the messages after the ready one
+	disableVersion(b, app.AppID)
+	s, missed := b.subscribe(app.AppID, first[0].ID)
+	defer b.unsubscribe(s)
+	if gotEvents := eventsOf(missed); !reflect.DeepEqual(gotEvents, []string{VersionsEvent, VersionsEvent}) {
+		t.Errorf("Broker.subscribe() resumed with %v, want the 2 versions messages", gotEvents)
+	}
+}
+
+func TestBroker_subscribe_reset(t *testing.T) {
+	app := helpers.GetMockApp()
+	b := NewBroker(time.Second)
+
+	s, first := b.subscribe(app.AppID, "")
+	b.unsubscribe(s)
+
+	for i := 0; i < historySize+1; i++ {
+		disableVersion(b, app.AppID)
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		want        string
+	}{
+		{name: "Should reset a stream whose messages are no longer kept", lastEventID: first[0].ID, want: ResetEvent},
+		{name: "Should reset a stream of another process", lastEventID: "other-1", want: ResetEvent},
+		{name: "Should reset a stream with an invalid id", lastEventID: "not-an-id", want: ResetEvent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, got := b.subscribe(app.AppID, tt.lastEventID)
+			defer b.unsubscribe(s)
+
+			if events := eventsOf(got); !reflect.DeepEqual(events, []string{tt.want}) {
+				t.Errorf("Broker.subscribe() = %v, want %v", events, tt.want)
+			}
+		})
+	}
+}
+
+func TestBroker_launches(t *testing.T) {
+	app := helpers.GetMockApp()
+	other := "com.aerogear.other_app"
+	b := NewBroker(time.Second)
+
+	s, _ := b.subscribe(app.AppID, "")
+	defer b.unsubscribe(s)
+
+	version := models.Version{ID: "55ebd387-9c68-4137-a367-a12025cc2cdb", Version: "1.0", AppID: app.AppID}
+	device := models.Device{VersionID: version.ID, Version: version.Version, AppID: app.AppID, DeviceID: "a742f8b7-5e2f-43f4-a5b8-2e8bb3e5a3ad"}
+
+	_ = b.Handle(events.DeviceRegistered{Device: device})
+	for i := 0; i < 3; i++ {
+		_ = b.Handle(events.AppLaunched{Device: device, Version: version})
+	}
+	// the apps which are not streamed are ignored
+	_ = b.Handle(events.AppLaunched{Device: models.Device{AppID: other}, Version: models.Version{AppID: other}})
+
+	if got, _ := receive(s); len(got) > 0 {
+		t.Fatalf("Broker.Handle() sent %v before the launches interval", eventsOf(got))
+	}
+
+	b.flushLaunches()
+	got, _ := receive(s)
+	if len(got) != 1 || got[0].Event != LaunchesEvent {
+		t.Fatalf("Broker.flushLaunches() sent %v, want a launches message", eventsOf(got))
+	}
+
+	var data launchesData
+	if err := json.Unmarshal(got[0].Data, &data); err != nil {
+		t.Fatalf("the data of the message is not valid: %v", err)
+	}
+	want := launchesData{Versions: []launchData{{ID: version.ID, Version: "1.0", Launches: 3, NewDevices: 1}}}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Broker.flushLaunches() sent %+v, want %+v", data, want)
+	}
+
+	// the counters are reset once sent
+	b.flushLaunches()
+	if got, _ := receive(s); len(got) > 0 {
+		t.Errorf("Broker.flushLaunches() sent %v without launches", eventsOf(got))
+	}
+	if _, ok := b.apps[other]; ok {
+		t.Errorf("Broker.Handle() kept the messages of %v", other)
+	}
+}
+
+func TestBroker_end(t *testing.T) {
+	app := helpers.GetMockApp()
+
+	t.Run("Should end the streams of a deleted app", func(t *testing.T) {
+		b := NewBroker(time.Second)
+		s, _ := b.subscribe(app.AppID, "")
+
+		_ = b.Handle(events.AppDeleted{App: *app})
+
+		got, open := receive(s)
+		if open || !reflect.DeepEqual(eventsOf(got), []string{DeletedEvent}) {
+			t.Errorf("Broker.Handle() sent %v and open = %v, want a deleted message ending the stream", eventsOf(got), open)
+		}
+		b.unsubscribe(s)
+	})
+
+	t.Run("Should end the streams which are too slow", func(t *testing.T) {
+		b := NewBroker(time.Second)
+		s, _ := b.subscribe(app.AppID, "")
+
+		for i := 0; i < subscriberBuffer+1; i++ {
+			disableVersion(b, app.AppID)
+		}
+
+		if got, open := receive(s); open || len(got) != subscriberBuffer {
+			t.Errorf("Broker.Handle() sent %v messages and open = %v, want %v messages ending the stream", len(got), open, subscriberBuffer)
+		}
+		b.unsubscribe(s)
+	})
+
+	t.Run("Should end all the streams when the broker is closed", func(t *testing.T) {
+		b := NewBroker(time.Second)
+		s, _ := b.subscribe(app.AppID, "")
+
+		b.Close()
+
+		if _, open := receive(s); open {
+			t.Error("Broker.Close() did not end the stream")
+		}
+		if s, _ := b.subscribe(app.AppID, ""); s != nil {
+			if _, open := receive(s); open {
+				t.Error("Broker.subscribe() started a stream once the broker is closed")
+			}
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package stream
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/httperrors"
+	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/labstack/echo"
+)
+
+const (
+	// ContentType is the content type of the event streams
+	ContentType = "text/event-stream"
+	// LastEventIDHeader is sent by the clients resuming a stream with the id of the last message they received
+	LastEventIDHeader = "Last-Event-ID"
+	// retryDelay is how long the clients wait before they resume a stream, in milliseconds
+	retryDelay = 3000
+)
+
+type (
+	HTTPHandler interface {
+		GetAppEvents(c echo.Context) error
+	}
+
+	// httpHandler instance
+	httpHandler struct {
+		Service   apps.Service
+		Broker    *Broker
+		heartbeat time.Duration
+	}
+)
+
+// appIDParams holds the id path parameter of the stream of an app
+type appIDParams struct {
+	ID string `param:"id" validate:"uuid"`
+}
+
+// NewHTTPHandler returns a new instance of stream.Handler
+func NewHTTPHandler(e *echo.Echo, s apps.Service, b *Broker, c config.StreamConfig) HTTPHandler {
+	return &httpHandler{
+		Service:   s,
+		Broker:    b,
+		heartbeat: c.HeartbeatInterval,
+	}
+}
+
+// GetAppEvents streams the changes of the versions and the launch counters of an app as Server-Sent Events.
+// A stream resumed with the Last-Event-ID header starts with the messages the client missed.
+func (h *httpHandler) GetAppEvents(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	app, err := h.Service.GetActiveAppByID(params.ID)
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	s, messages := h.Broker.subscribe(app.AppID, c.Request().Header.Get(LastEventIDHeader))
+	defer h.Broker.unsubscribe(s)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, ContentType)
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	// disable the buffering of the responses by the nginx proxies
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(res, "retry: %d\n\n", retryDelay); err != nil {
+		return nil
+	}
+	for _, m := range messages {
+		if err := writeMessage(res, m); err != nil {
+			return nil
+		}
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case m, ok := <-s.c:
+			if !ok {
+				return nil
+			}
+			if err := writeMessage(res, m); err != nil || m.Event == DeletedEvent {
+				return nil
+			}
+		case <-heartbeat.C:
+			// a comment, ignored by the clients
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		case <-c.Request().Context().Done():
+			return nil
+		}
+		res.Flush()
+	}
+}
+
+// writeMessage writes a message in the format of the event streams
+func writeMessage(res *echo.Response, m message) error {
+	_, err := fmt.Fprintf(res, "id: %s\nevent: %s\ndata: %s\n\n", m.ID, m.Event, m.Data)
+	return err
+}
@@ -0,0 +1,148 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/labstack/echo"
+)
+
+// newTestServer returns a server streaming the events of the mock app with the broker
+func newTestServer(b *Broker) *httptest.Server {
+	e := echo.New()
+	e.Validator = validation.NewValidator()
+
+	s := &apps.ServiceMock{
+		GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
+			if app := helpers.GetMockApp(); app.ID == ID {
+				return app, nil
+			}
+			return nil, models.ErrNotFound
+		},
+	}
+
+	h := NewHTTPHandler(e, s, b, config.StreamConfig{HeartbeatInterval: time.Minute})
+	e.GET("/apps/:id/events", h.GetAppEvents)
+
+	return httptest.NewServer(e)
+}
+
+// readMessage returns the fields of the next message of a stream, skipping the comments
+func readMessage(t *testing.T, r *bufio.Reader) map[string]string {
+	t.Helper()
+
+	fields := map[string]string{}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read the stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && len(fields) > 0:
+			return fields
+		case line == "" || strings.HasPrefix(line, ":"):
+			continue
+		}
+
+		parts := strings.SplitN(line, ": ", 2)
+		fields[parts[0]] = parts[1]
+	}
+}
+
+func openStream(t *testing.T, ctx context.Context, url, lastEventID string) *http.Response {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req = req.WithContext(ctx)
+	if lastEventID != "" {
+		req.Header.Set(LastEventIDHeader, lastEventID)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open the stream: %v", err)
+	}
+	return res
+}
+
+func Test_httpHandler_GetAppEvents(t *testing.T) {
+	app := helpers.GetMockApp()
+	b := NewBroker(time.Second)
+	server := newTestServer(b)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	url := server.URL + "/apps/" + app.ID + "/events"
+	res := openStream(t, ctx, url, "")
+	r := bufio.NewReader(res.Body)
+
+	if res.StatusCode != http.StatusOK || res.Header.Get(echo.HeaderContentType) != ContentType {
+		t.Fatalf("statusCode = %v, Content-Type = %v, want a stream", res.StatusCode, res.Header.Get(echo.HeaderContentType))
+	}
+
+	if got := readMessage(t, r); got["retry"] == "" {
+		t.Errorf("the stream starts with %v, want the retry delay", got)
+	}
+	ready := readMessage(t, r)
+	if ready["event"] != ReadyEvent || ready["id"] == "" {
+		t.Fatalf("the stream starts with %v, want a ready message", ready)
+	}
+
+	disableVersion(b, app.AppID)
+
+	got := readMessage(t, r)
+	if got["event"] != VersionsEvent || !strings.Contains(got["data"], `"disabled":true`) {
+		t.Errorf("the stream sent %v, want the disabled version", got)
+	}
+	res.Body.Close()
+
+	// the messages sent while the client was disconnected are sent when it resumes the stream
+	disableVersion(b, app.AppID)
+
+	res = openStream(t, ctx, url, got["id"])
+	defer res.Body.Close()
+	r = bufio.NewReader(res.Body)
+
+	readMessage(t, r)
+	if missed := readMessage(t, r); missed["event"] != VersionsEvent || missed["id"] == got["id"] {
+		t.Errorf("the resumed stream sent %v, want the missed versions message", missed)
+	}
+}
+
+func Test_httpHandler_GetAppEvents_errors(t *testing.T) {
+	server := newTestServer(NewBroker(time.Second))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		id       string
+		wantCode int
+	}{
+		{name: "Should return bad request for an invalid id", id: "not-a-uuid", wantCode: http.StatusBadRequest},
+		{name: "Should return not found for an unknown app", id: "53d4e6c6-6b7f-4a50-8d2c-0fe5b4c1a2b9", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := openStream(t, context.Background(), server.URL+"/apps/"+tt.id+"/events", "")
+			defer res.Body.Close()
+
+			if res.StatusCode != tt.wantCode {
+				t.Errorf("statusCode = %v, want %v", res.StatusCode, tt.wantCode)
+			}
+		})
+	}
+}
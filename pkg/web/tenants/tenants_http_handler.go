@@ -0,0 +1,108 @@
+package tenants
+
+import (
+	"net/http"
+
+	"github.com/aerogear/mobile-security-service/pkg/httperrors"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/labstack/echo"
+)
+
+type (
+	HTTPHandler interface {
+		GetTenants(c echo.Context) error
+		CreateTenant(c echo.Context) error
+		DeleteTenant(c echo.Context) error
+		SetMembers(c echo.Context) error
+		MoveApp(c echo.Context) error
+	}
+
+	// httpHandler instance
+	httpHandler struct {
+		Service Service
+	}
+)
+
+// NewHTTPHandler returns a new instance of tenants.Handler
+func NewHTTPHandler(e *echo.Echo, s Service) HTTPHandler {
+	return &httpHandler{
+		Service: s,
+	}
+}
+
+// GetTenants returns the tenants of the user, or every tenant for the super admins
+func (h *httpHandler) GetTenants(c echo.Context) error {
+	tenants, err := h.Service.GetTenants(ScopeFromContext(c))
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newTenantsResponse(tenants))
+}
+
+// CreateTenant creates a tenant with its members and returns it
+func (h *httpHandler) CreateTenant(c echo.Context) error {
+	req := createTenantRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	tenant, err := h.Service.CreateTenant(req.toModel())
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newTenantResponse(*tenant))
+}
+
+// DeleteTenant deletes a tenant which owns no app
+func (h *httpHandler) DeleteTenant(c echo.Context) error {
+	params := tenantParams{TenantID: c.Param("tenantId")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	if err := h.Service.DeleteTenant(params.TenantID); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SetMembers replaces the members of a tenant
+func (h *httpHandler) SetMembers(c echo.Context) error {
+	params := tenantParams{TenantID: c.Param("tenantId")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	req := setMembersRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	if err := h.Service.SetMembers(params.TenantID, req.Members); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// MoveApp moves an app to another tenant
+func (h *httpHandler) MoveApp(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	req := moveAppRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	if err := h.Service.MoveApp(params.ID, req.TenantID); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
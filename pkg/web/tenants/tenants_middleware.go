@@ -0,0 +1,62 @@
+package tenants
+
+import (
+	"errors"
+
+	"github.com/aerogear/mobile-security-service/pkg/httperrors"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/user"
+	"github.com/labstack/echo"
+)
+
+// scopeKey is the key of the tenant scope of the user in the echo context
+const scopeKey = "tenantScope"
+
+// Scope returns a middleware which sets the tenants of the user in the context of the requests, see ScopeFromContext.
+func Scope(s Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scope := &models.TenantScope{TenantIDs: []string{}}
+			if u := user.FromContext(c); u != nil {
+				var err error
+				if scope, err = s.GetScope(u.Username); err != nil {
+					return httperrors.GetHTTPResponseFromErr(c, err)
+				}
+			}
+			c.Set(scopeKey, scope)
+
+			return next(c)
+		}
+	}
+}
+
+// ScopeApp returns a middleware for the endpoints of a single app, by the id of the path, which answer not found
+// when the app belongs to a tenant the user cannot see. It lets every request through when the Scope middleware
+// did not set the tenants of the user, i.e. when the tenants are disabled.
+func ScopeApp(s Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scope := ScopeFromContext(c)
+			if scope == nil || scope.All {
+				return next(c)
+			}
+
+			tenantID, err := s.GetTenantIDByAppID(c.Param("id"))
+			// the handler answers for the apps which are not found
+			if err != nil && !errors.Is(err, models.ErrNotFound) {
+				return httperrors.GetHTTPResponseFromErr(c, err)
+			}
+			if err == nil && !scope.Includes(tenantID) {
+				return httperrors.GetHTTPResponseFromErr(c, models.ErrNotFound)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// ScopeFromContext returns the tenants of the user set by the Scope middleware, or nil when the tenants are disabled
+func ScopeFromContext(c echo.Context) *models.TenantScope {
+	scope, _ := c.Get(scopeKey).(*models.TenantScope)
+	return scope
+}
@@ -0,0 +1,149 @@
+package tenants
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/user"
+	"github.com/labstack/echo"
+)
+
+const (
+	ownTenant   = "6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d"
+	otherTenant = "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d"
+	ownApp      = "1b9e7a5f-af7c-4055-b488-72f2b5f72266"
+	otherApp    = "0890506c-3dd1-43ad-8a09-21a4111a65a6"
+)
+
+// newScopeService returns a service where alice is a member of ownTenant, which owns ownApp, and root is a super admin
+func newScopeService() Service {
+	return NewService(&RepositoryMock{
+		GetTenantIDsByMemberFunc: func(username string) ([]string, error) {
+			return []string{ownTenant}, nil
+		},
+		GetTenantIDByAppIDFunc: func(id string) (string, error) {
+			switch id {
+			case ownApp:
+				return ownTenant, nil
+			case otherApp:
+				return otherTenant, nil
+			}
+			return "", models.ErrNotFound
+		},
+	}, []string{"root"})
+}
+
+func Test_Scope(t *testing.T) {
+	tests := []struct {
+		name      string
+		username  string
+		wantScope *models.TenantScope
+	}{
+		{
+			name:      "Should set the tenants of the user",
+			username:  "alice",
+			wantScope: &models.TenantScope{TenantIDs: []string{ownTenant}},
+		},
+		{
+			name:      "Should set no tenant without a user",
+			wantScope: &models.TenantScope{TenantIDs: []string{}},
+		},
+		{
+			name:      "Should set all the tenants for a super admin",
+			username:  "root",
+			wantScope: &models.TenantScope{All: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(user.USER_NAME_HEADER, tt.username)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var got *models.TenantScope
+			err := Scope(newScopeService())(func(c echo.Context) error {
+				got = ScopeFromContext(c)
+				return c.NoContent(http.StatusOK)
+			})(c)
+			if err != nil {
+				t.Fatalf("Scope() error = %v", err)
+			}
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("Scope() statusCode = %v, want %v", rec.Code, http.StatusOK)
+			}
+			if !reflect.DeepEqual(got, tt.wantScope) {
+				t.Errorf("ScopeFromContext() = %v, want %v", got, tt.wantScope)
+			}
+		})
+	}
+}
+
+func Test_ScopeApp(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    *models.TenantScope
+		id       string
+		wantCode int
+	}{
+		{
+			name:     "Should let through an app of a tenant of the user",
+			scope:    &models.TenantScope{TenantIDs: []string{ownTenant}},
+			id:       ownApp,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Should return not found for an app of another tenant",
+			scope:    &models.TenantScope{TenantIDs: []string{ownTenant}},
+			id:       otherApp,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Should let a super admin through to the app of any tenant",
+			scope:    &models.TenantScope{All: true},
+			id:       otherApp,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Should leave the apps which are not found to the handler",
+			scope:    &models.TenantScope{TenantIDs: []string{ownTenant}},
+			id:       "53d4e6c6-6b7f-4a50-8d2c-0fe5b4c1a2b9",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Should let every request through when the tenants are disabled",
+			id:       otherApp,
+			wantCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/apps/:id")
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			if tt.scope != nil {
+				c.Set(scopeKey, tt.scope)
+			}
+
+			err := ScopeApp(newScopeService())(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+			if err != nil {
+				t.Fatalf("ScopeApp() error = %v", err)
+			}
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("ScopeApp() statusCode = %v, want %v", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package tenants
+
+import "github.com/aerogear/mobile-security-service/pkg/openapi"
+
+// Schemas returns the requests and responses of the tenants endpoints by the name of their schema in the API documentation
+func Schemas() openapi.Schemas {
+	return openapi.Schemas{
+		"CreateTenantRequest":     createTenantRequest{},
+		"SetTenantMembersRequest": setMembersRequest{},
+		"MoveAppRequest":          moveAppRequest{},
+		"TenantResponse":          tenantResponse{},
+	}
+}
@@ -0,0 +1,250 @@
+package tenants
+
+import (
+	"database/sql"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+)
+
+type (
+	tenantsPostgreSQLRepository struct {
+		db *sql.DB
+	}
+)
+
+// NewPostgreSQLRepository creates a new instance of tenantsPostgreSQLRepository
+func NewPostgreSQLRepository(db *sql.DB) Repository {
+	return &tenantsPostgreSQLRepository{db}
+}
+
+// GetTenants returns the tenants in the scope sorted by name, with their members and the number of their active apps.
+// A nil scope returns every tenant.
+func (t *tenantsPostgreSQLRepository) GetTenants(scope *models.TenantScope) ([]models.Tenant, error) {
+	var ids interface{}
+	if scope != nil && !scope.All {
+		ids = pq.Array(scope.TenantIDs)
+	}
+
+	rows, err := t.db.Query(`
+	SELECT t.id,t.name,t.created_at,
+	(SELECT COUNT(*) FROM app as a WHERE a.tenant_id = t.id AND a.deleted_at IS NULL) as num_of_apps,
+	COALESCE(ARRAY_AGG(m.username ORDER BY m.username) FILTER (WHERE m.username IS NOT NULL), '{}') as members
+	FROM tenant as t LEFT JOIN tenant_member as m on t.id = m.tenant_id
+	WHERE $1::uuid[] IS NULL OR t.id = ANY($1::uuid[])
+	GROUP BY t.id
+	ORDER BY t.name;`, ids)
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	tenants := []models.Tenant{}
+	for rows.Next() {
+		var tenant models.Tenant
+		if err := rows.Scan(&tenant.ID, &tenant.Name, &tenant.CreatedAt, &tenant.NumOfApps, pq.Array(&tenant.Members)); err != nil {
+			log.Error(err)
+			return nil, models.ErrDatabaseError
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	return tenants, nil
+}
+
+// GetTenantIDsByMember returns the ids of the tenants of a user, with an empty list when the user is in none
+func (t *tenantsPostgreSQLRepository) GetTenantIDsByMember(username string) ([]string, error) {
+	rows, err := t.db.Query(`SELECT tenant_id FROM tenant_member WHERE username=$1 ORDER BY tenant_id;`, username)
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			log.Error(err)
+			return nil, models.ErrDatabaseError
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	return ids, nil
+}
+
+// CreateTenant creates a tenant with its members in a single transaction.
+// It returns ErrConflict when a tenant already has the same name.
+func (t *tenantsPostgreSQLRepository) CreateTenant(tenant models.Tenant) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO tenant (id,name,created_at)
+		VALUES ($1,$2,$3)
+		ON CONFLICT (name) DO NOTHING;`, tenant.ID, tenant.Name, tenant.CreatedAt)
+	if err != nil {
+		return rollback(tx, err, models.ErrDatabaseError)
+	}
+
+	if created, err := result.RowsAffected(); err != nil || created == 0 {
+		return rollback(tx, err, models.ErrConflict)
+	}
+
+	if err := insertMembers(tx, tenant.ID, tenant.Members); err != nil {
+		return rollback(tx, err, models.ErrDatabaseError)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// DeleteTenant deletes a tenant with its members. It returns ErrNotFound when the tenant is not found
+// and ErrConflict when it still owns apps, even soft deleted ones.
+func (t *tenantsPostgreSQLRepository) DeleteTenant(id string) error {
+	result, err := t.db.Exec(`
+		DELETE FROM tenant
+		WHERE id=$1 AND NOT EXISTS (SELECT 1 FROM app WHERE tenant_id=$1);`, id)
+
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	if deleted, err := result.RowsAffected(); err == nil && deleted > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := t.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM tenant WHERE id=$1);`, id).Scan(&exists); err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	if exists {
+		return models.ErrConflict
+	}
+	return models.ErrNotFound
+}
+
+// SetMembers replaces the members of a tenant in a single transaction. It returns ErrNotFound when the tenant is not found.
+func (t *tenantsPostgreSQLRepository) SetMembers(id string, members []string) error {
+	tx, err := t.db.Begin()
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	var found string
+	err = tx.QueryRow(`SELECT id FROM tenant WHERE id=$1 FOR UPDATE;`, id).Scan(&found)
+	if err == sql.ErrNoRows {
+		return rollback(tx, err, models.ErrNotFound)
+	}
+	if err != nil {
+		return rollback(tx, err, models.ErrDatabaseError)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM tenant_member WHERE tenant_id=$1;`, id); err != nil {
+		return rollback(tx, err, models.ErrDatabaseError)
+	}
+
+	if err := insertMembers(tx, id, members); err != nil {
+		return rollback(tx, err, models.ErrDatabaseError)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// GetTenantIDByAppID returns the id of the tenant of an app by its id, even when the app is soft deleted
+func (t *tenantsPostgreSQLRepository) GetTenantIDByAppID(id string) (string, error) {
+	var tenantID string
+
+	err := t.db.QueryRow(`SELECT tenant_id FROM app WHERE id=$1;`, id).Scan(&tenantID)
+
+	if err != nil {
+		log.Error(err)
+		if err == sql.ErrNoRows {
+			return "", models.ErrNotFound
+		}
+		return "", models.ErrDatabaseError
+	}
+
+	return tenantID, nil
+}
+
+// SetAppTenant moves an app by its id to a tenant. It returns ErrNotFound when the app or the tenant is not found.
+func (t *tenantsPostgreSQLRepository) SetAppTenant(id, tenantID string) error {
+	result, err := t.db.Exec(`
+		UPDATE app SET tenant_id=tenant.id
+		FROM tenant
+		WHERE app.id=$1 AND tenant.id=$2;`, id, tenantID)
+
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	if updated, err := result.RowsAffected(); err == nil && updated == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// insertMembers adds the members of a tenant
+func insertMembers(tx *sql.Tx, id string, members []string) error {
+	for _, username := range members {
+		if _, err := tx.Exec(`INSERT INTO tenant_member (tenant_id, username) VALUES ($1,$2);`, id, username); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollback rolls a transaction back after a failed statement and returns the error of the operation
+func rollback(tx *sql.Tx, err error, result error) error {
+	if err != nil {
+		log.Error(err)
+	}
+	if rbErr := tx.Rollback(); rbErr != nil {
+		log.Error(rbErr)
+	}
+	return result
+}
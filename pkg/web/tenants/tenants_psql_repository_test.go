@@ -0,0 +1,219 @@
+package tenants
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/lib/pq"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var (
+	getTenantsQueryString = `SELECT t.id,t.name,t.created_at,
+	\(SELECT COUNT\(\*\) FROM app as a WHERE a.tenant_id = t.id AND a.deleted_at IS NULL\) as num_of_apps,`
+	createTenantQueryString = `INSERT INTO tenant \(id,name,created_at\)`
+	insertMemberQueryString = `INSERT INTO tenant_member \(tenant_id, username\) VALUES \(\$1,\$2\);`
+	deleteTenantQueryString = `DELETE FROM tenant
+		WHERE id=\$1 AND NOT EXISTS \(SELECT 1 FROM app WHERE tenant_id=\$1\);`
+	tenantExistsQueryString = `SELECT EXISTS \(SELECT 1 FROM tenant WHERE id=\$1\);`
+
+	tenantRowColumns = []string{"id", "name", "created_at", "num_of_apps", "members"}
+)
+
+func getMockTenant() models.Tenant {
+	return models.Tenant{
+		ID:        "6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d",
+		Name:      "payments",
+		Members:   []string{"alice", "bob"},
+		NumOfApps: 2,
+		CreatedAt: time.Date(2019, time.June, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func Test_tenantsPostgreSQLRepository_GetTenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	tenant := getMockTenant()
+
+	tests := []struct {
+		name    string
+		scope   *models.TenantScope
+		expect  func()
+		want    []models.Tenant
+		wantErr bool
+	}{
+		{
+			name: "Should return every tenant with its members without a scope",
+			expect: func() {
+				rows := sqlmock.NewRows(tenantRowColumns).
+					AddRow(tenant.ID, tenant.Name, tenant.CreatedAt, tenant.NumOfApps, []byte("{alice,bob}"))
+				mock.ExpectQuery(getTenantsQueryString).WithArgs(nil).WillReturnRows(rows)
+			},
+			want: []models.Tenant{tenant},
+		},
+		{
+			name:  "Should only return the tenants of a restricted scope",
+			scope: &models.TenantScope{TenantIDs: []string{tenant.ID}},
+			expect: func() {
+				rows := sqlmock.NewRows(tenantRowColumns).
+					AddRow(tenant.ID, tenant.Name, tenant.CreatedAt, tenant.NumOfApps, []byte("{alice,bob}"))
+				mock.ExpectQuery(getTenantsQueryString).WithArgs(pq.Array([]string{tenant.ID})).WillReturnRows(rows)
+			},
+			want: []models.Tenant{tenant},
+		},
+		{
+			name:  "Should return every tenant for the scope of a super admin",
+			scope: &models.TenantScope{All: true},
+			expect: func() {
+				mock.ExpectQuery(getTenantsQueryString).WithArgs(nil).WillReturnRows(sqlmock.NewRows(tenantRowColumns))
+			},
+			want: []models.Tenant{},
+		},
+		{
+			name: "Should return a database error when the query fails",
+			expect: func() {
+				mock.ExpectQuery(getTenantsQueryString).WillReturnError(models.ErrDatabaseError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			got, err := NewPostgreSQLRepository(db).GetTenants(tt.scope)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("tenantsPostgreSQLRepository.GetTenants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tenantsPostgreSQLRepository.GetTenants() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_tenantsPostgreSQLRepository_CreateTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	tenant := getMockTenant()
+
+	tests := []struct {
+		name    string
+		expect  func()
+		wantErr error
+	}{
+		{
+			name: "Should create the tenant with its members",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(createTenantQueryString).WithArgs(tenant.ID, tenant.Name, tenant.CreatedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertMemberQueryString).WithArgs(tenant.ID, "alice").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertMemberQueryString).WithArgs(tenant.ID, "bob").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Should return a conflict when the name is taken",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(createTenantQueryString).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			wantErr: models.ErrConflict,
+		},
+		{
+			name: "Should return a database error when a member cannot be added",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(createTenantQueryString).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(insertMemberQueryString).WillReturnError(models.ErrInternalServerError)
+				mock.ExpectRollback()
+			},
+			wantErr: models.ErrDatabaseError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			if err := NewPostgreSQLRepository(db).CreateTenant(tenant); err != tt.wantErr {
+				t.Errorf("tenantsPostgreSQLRepository.CreateTenant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_tenantsPostgreSQLRepository_DeleteTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	id := getMockTenant().ID
+
+	tests := []struct {
+		name    string
+		expect  func()
+		wantErr error
+	}{
+		{
+			name: "Should delete the tenant",
+			expect: func() {
+				mock.ExpectExec(deleteTenantQueryString).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Should return a conflict when the tenant still owns apps",
+			expect: func() {
+				mock.ExpectExec(deleteTenantQueryString).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(tenantExistsQueryString).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			wantErr: models.ErrConflict,
+		},
+		{
+			name: "Should return not found when there is no tenant",
+			expect: func() {
+				mock.ExpectExec(deleteTenantQueryString).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(tenantExistsQueryString).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			if err := NewPostgreSQLRepository(db).DeleteTenant(id); err != tt.wantErr {
+				t.Errorf("tenantsPostgreSQLRepository.DeleteTenant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package tenants
+
+import "github.com/aerogear/mobile-security-service/pkg/models"
+
+// Repository represent the tenant's repository contract
+type Repository interface {
+	GetTenants(scope *models.TenantScope) ([]models.Tenant, error)
+	GetTenantIDsByMember(username string) ([]string, error)
+	CreateTenant(tenant models.Tenant) error
+	DeleteTenant(id string) error
+	SetMembers(id string, members []string) error
+	GetTenantIDByAppID(id string) (string, error)
+	SetAppTenant(id, tenantID string) error
+}
@@ -0,0 +1,50 @@
+package tenants
+
+import "github.com/aerogear/mobile-security-service/pkg/models"
+
+// The requests of the tenants endpoints, with the validation rules of each field.
+// They are validated with the validator registered in echo before being mapped to the models.
+
+// tenantParams holds the path parameter of the endpoints of a single tenant
+type tenantParams struct {
+	TenantID string `param:"tenantId" validate:"uuid"`
+}
+
+// appIDParams holds the id path parameter of an app
+type appIDParams struct {
+	ID string `param:"id" validate:"uuid"`
+}
+
+// createTenantRequest is the body of POST /tenants
+// swagger:model CreateTenantRequest
+type createTenantRequest struct {
+	// Name is the unique name of the tenant
+	// required: true
+	Name string `json:"name" validate:"required,max=100"`
+	// Members are the usernames of the users who see the apps of the tenant
+	Members []string `json:"members" validate:"dive,required"`
+}
+
+// toModel maps the request to the tenant to create
+func (r *createTenantRequest) toModel() models.Tenant {
+	return models.Tenant{
+		Name:    r.Name,
+		Members: r.Members,
+	}
+}
+
+// setMembersRequest is the body of PUT /tenants/{tenantId}/members
+// swagger:model SetTenantMembersRequest
+type setMembersRequest struct {
+	// Members replace the usernames of the users who see the apps of the tenant
+	// required: true
+	Members []string `json:"members" validate:"required,dive,required"`
+}
+
+// moveAppRequest is the body of PUT /apps/{id}/tenant
+// swagger:model MoveAppRequest
+type moveAppRequest struct {
+	// TenantID is the id of the tenant which owns the app from now on
+	// required: true
+	TenantID string `json:"tenantId" validate:"required,uuid"`
+}
@@ -0,0 +1,45 @@
+package tenants
+
+import (
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// The responses of the tenants endpoints.
+
+// tenantResponse is a tenant returned by GET /tenants
+// swagger:model TenantResponse
+type tenantResponse struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+	// NumOfApps is the number of the apps of the tenant which are not deleted
+	NumOfApps int       `json:"numOfApps"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// newTenantResponse maps a tenant to its response
+func newTenantResponse(tenant models.Tenant) tenantResponse {
+	members := tenant.Members
+	if members == nil {
+		members = []string{}
+	}
+
+	return tenantResponse{
+		ID:        tenant.ID,
+		Name:      tenant.Name,
+		Members:   members,
+		NumOfApps: tenant.NumOfApps,
+		CreatedAt: tenant.CreatedAt,
+	}
+}
+
+// newTenantsResponse maps a list of tenants to their responses
+func newTenantsResponse(tenants []models.Tenant) []tenantResponse {
+	r := make([]tenantResponse, 0, len(tenants))
+	for _, tenant := range tenants {
+		r = append(r, newTenantResponse(tenant))
+	}
+	return r
+}
@@ -0,0 +1,117 @@
+package tenants
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+type (
+	// Service defines the interface methods to be used
+	Service interface {
+		GetTenants(scope *models.TenantScope) ([]models.Tenant, error)
+		CreateTenant(tenant models.Tenant) (*models.Tenant, error)
+		DeleteTenant(id string) error
+		SetMembers(id string, members []string) error
+		MoveApp(id, tenantID string) error
+		GetScope(username string) (*models.TenantScope, error)
+		GetTenantIDByAppID(id string) (string, error)
+	}
+
+	tenantsService struct {
+		repository  Repository
+		superAdmins map[string]bool
+	}
+)
+
+// NewService instantiates this service. The super admins see and manage every tenant.
+func NewService(repository Repository, superAdmins []string) Service {
+	admins := map[string]bool{}
+	for _, username := range superAdmins {
+		admins[username] = true
+	}
+
+	return &tenantsService{
+		repository:  repository,
+		superAdmins: admins,
+	}
+}
+
+// GetTenants returns the tenants in the scope of a user
+func (t *tenantsService) GetTenants(scope *models.TenantScope) ([]models.Tenant, error) {
+	return t.repository.GetTenants(scope)
+}
+
+// CreateTenant creates a tenant with its members and returns it. It returns ErrConflict when the name is taken.
+func (t *tenantsService) CreateTenant(tenant models.Tenant) (*models.Tenant, error) {
+	tenant.ID = helpers.GetUUID()
+	tenant.Members = uniqueMembers(tenant.Members)
+	tenant.NumOfApps = 0
+	tenant.CreatedAt = time.Now().UTC()
+
+	if err := t.repository.CreateTenant(tenant); err != nil {
+		return nil, err
+	}
+
+	return &tenant, nil
+}
+
+// DeleteTenant deletes a tenant which owns no app. The default tenant cannot be deleted.
+func (t *tenantsService) DeleteTenant(id string) error {
+	if id == models.DefaultTenantID {
+		return models.ErrConflict.WithMessage("The default tenant cannot be deleted")
+	}
+
+	err := t.repository.DeleteTenant(id)
+	if errors.Is(err, models.ErrConflict) {
+		return models.ErrConflict.WithMessage("The tenant still owns apps")
+	}
+	return err
+}
+
+// SetMembers replaces the members of a tenant
+func (t *tenantsService) SetMembers(id string, members []string) error {
+	return t.repository.SetMembers(id, uniqueMembers(members))
+}
+
+// MoveApp moves an app by its id to another tenant
+func (t *tenantsService) MoveApp(id, tenantID string) error {
+	return t.repository.SetAppTenant(id, tenantID)
+}
+
+// GetScope returns the tenants whose apps a user can see: every tenant for a super admin, else the tenants
+// the user is a member of
+func (t *tenantsService) GetScope(username string) (*models.TenantScope, error) {
+	if t.superAdmins[username] {
+		return &models.TenantScope{All: true}, nil
+	}
+
+	ids, err := t.repository.GetTenantIDsByMember(username)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TenantScope{TenantIDs: ids}, nil
+}
+
+// GetTenantIDByAppID returns the id of the tenant of an app by its id
+func (t *tenantsService) GetTenantIDByAppID(id string) (string, error) {
+	return t.repository.GetTenantIDByAppID(id)
+}
+
+// uniqueMembers returns the usernames sorted and without duplicates or blanks
+func uniqueMembers(members []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, m := range members {
+		if m != "" && !seen[m] {
+			seen[m] = true
+			unique = append(unique, m)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -0,0 +1,122 @@
+package tenants
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+func Test_tenantsService_CreateTenant(t *testing.T) {
+	repo := &RepositoryMock{
+		CreateTenantFunc: func(tenant models.Tenant) error {
+			return nil
+		},
+	}
+
+	got, err := NewService(repo, nil).CreateTenant(models.Tenant{Name: "payments", Members: []string{"bob", "alice", "", "bob"}})
+	if err != nil {
+		t.Fatalf("tenantsService.CreateTenant() error = %v", err)
+	}
+
+	if got.ID == "" || got.CreatedAt.IsZero() {
+		t.Errorf("tenantsService.CreateTenant() = %v, want an id and a creation time", got)
+	}
+	if want := []string{"alice", "bob"}; !reflect.DeepEqual(got.Members, want) {
+		t.Errorf("tenantsService.CreateTenant() members = %v, want %v", got.Members, want)
+	}
+	if calls := repo.CreateTenantCalls(); len(calls) != 1 || !reflect.DeepEqual(calls[0].Tenant, *got) {
+		t.Errorf("tenantsService.CreateTenant() stored %v, want %v", calls, *got)
+	}
+}
+
+func Test_tenantsService_DeleteTenant(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		repoErr error
+		wantErr error
+	}{
+		{
+			name: "Should delete a tenant",
+			id:   "6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d",
+		},
+		{
+			name:    "Should not delete the default tenant",
+			id:      models.DefaultTenantID,
+			wantErr: models.ErrConflict,
+		},
+		{
+			name:    "Should not delete a tenant which owns apps",
+			id:      "6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d",
+			repoErr: models.ErrConflict,
+			wantErr: models.ErrConflict,
+		},
+		{
+			name:    "Should return ErrNotFound when the tenant is not found",
+			id:      "6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d",
+			repoErr: models.ErrNotFound,
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &RepositoryMock{
+				DeleteTenantFunc: func(id string) error {
+					return tt.repoErr
+				},
+			}
+
+			err := NewService(repo, nil).DeleteTenant(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("tenantsService.DeleteTenant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_tenantsService_GetScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		want     *models.TenantScope
+	}{
+		{
+			name:     "Should return every tenant for a super admin",
+			username: "root",
+			want:     &models.TenantScope{All: true},
+		},
+		{
+			name:     "Should return the tenants of a member",
+			username: "alice",
+			want:     &models.TenantScope{TenantIDs: []string{"6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d"}},
+		},
+		{
+			name:     "Should return no tenant for a user who is in none",
+			username: "mallory",
+			want:     &models.TenantScope{TenantIDs: []string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &RepositoryMock{
+				GetTenantIDsByMemberFunc: func(username string) ([]string, error) {
+					if username == "alice" {
+						return []string{"6f1d3c2b-8a4e-4b7f-9c0d-1e2f3a4b5c6d"}, nil
+					}
+					return []string{}, nil
+				},
+			}
+
+			got, err := NewService(repo, []string{"root"}).GetScope(tt.username)
+			if err != nil {
+				t.Fatalf("tenantsService.GetScope() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tenantsService.GetScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package user
+
+import (
+	"github.com/aerogear/mobile-security-service/pkg/httperrors"
+	"github.com/labstack/echo"
+)
+
+// RequireAdmin returns a middleware which only lets through the requests of the given admin users,
+// identified by their username, see FromContext. No one is allowed when the list is empty.
+func RequireAdmin(admins []string) echo.MiddlewareFunc {
+	allowed := map[string]bool{}
+	for _, admin := range admins {
+		if admin != "" {
+			allowed[admin] = true
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user := FromContext(c)
+			if user == nil {
+				return httperrors.Unauthorized(c, "No User Found")
+			}
+
+			if !allowed[user.Username] {
+				return httperrors.Forbidden(c, "Only admin users can perform this operation")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequireUser returns a middleware which only lets through the requests of an identified user, see FromContext
+func RequireUser() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if FromContext(c) == nil {
+				return httperrors.Unauthorized(c, "No User Found")
+			}
+
+			return next(c)
+		}
+	}
+}
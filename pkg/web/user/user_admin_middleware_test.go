@@ -0,0 +1,102 @@
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+)
+
+func Test_RequireAdmin(t *testing.T) {
+	tests := []struct {
+		name     string
+		admins   []string
+		username string
+		wantCode int
+	}{
+		{
+			name:     "Should let an admin user through",
+			admins:   []string{"admin", "other-admin"},
+			username: "admin",
+			wantCode: 200,
+		},
+		{
+			name:     "Should return forbidden when the user is not an admin",
+			admins:   []string{"admin"},
+			username: "TestUser",
+			wantCode: 403,
+		},
+		{
+			name:     "Should return forbidden when there are no admin users",
+			admins:   []string{},
+			username: "TestUser",
+			wantCode: 403,
+		},
+		{
+			name:     "Should return unauthorized when no user is provided",
+			admins:   []string{"admin"},
+			wantCode: 401,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//Setup
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.username != "" {
+				req.Header.Add(USER_NAME_HEADER, tt.username)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			next := func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}
+			if err := RequireAdmin(tt.admins)(next)(c); err != nil {
+				t.Errorf("RequireAdmin() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("RequireAdmin() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_RequireUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		wantCode int
+	}{
+		{
+			name:     "Should let a user through",
+			username: "TestUser",
+			wantCode: 200,
+		},
+		{
+			name:     "Should return unauthorized when no user is provided",
+			wantCode: 401,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//Setup
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.username != "" {
+				req.Header.Add(USER_NAME_HEADER, tt.username)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			next := func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}
+			if err := RequireUser()(next)(c); err != nil {
+				t.Errorf("RequireUser() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("RequireUser() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/httperrors"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/labstack/echo"
+	log "github.com/sirupsen/logrus"
+)
+
+// userKey is the key of the user of the request in the echo context
+const userKey = "user"
+
+// Authenticate returns a middleware which identifies the user of the requests, see FromContext.
+// The user is read from the headers set by the oauth-proxy when the request comes from a trusted proxy,
+// or else from a bearer JWT of the OpenID Connect issuer, which is rejected with unauthorized when it is not valid.
+// The headers of every client are trusted when there are neither trusted proxies nor an issuer.
+func Authenticate(c config.AuthConfig) (echo.MiddlewareFunc, error) {
+	proxies, err := ParseTrustedProxies(c.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifier *tokenVerifier
+	if c.OIDC.Issuer != "" {
+		verifier = newTokenVerifier(c.OIDC)
+	}
+	trustAll := len(proxies) == 0 && verifier == nil
+	if trustAll {
+		log.Warn("Neither TRUSTED_PROXY_CIDRS nor OIDC_ISSUER_URL is set: the user headers of every client are trusted, so any client can act as any user")
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+
+			var user *models.User
+			if username := req.Header.Get(USER_NAME_HEADER); username != "" && (trustAll || isTrusted(proxies, req.RemoteAddr)) {
+				user = &models.User{Username: username, Email: req.Header.Get(USER_EMAIL_HEADER)}
+			} else if token := bearerToken(req.Header.Get(echo.HeaderAuthorization)); token != "" && verifier != nil {
+				var err error
+				if user, err = verifier.Verify(token); err != nil {
+					if errors.Is(err, errInvalidToken) {
+						log.WithField("remoteAddr", req.RemoteAddr).Warnf("Rejected a bearer token: %v", err)
+					} else {
+						log.Error(err)
+					}
+					return httperrors.Unauthorized(ctx, "Invalid bearer token")
+				}
+			}
+
+			ctx.Set(userKey, user)
+			return next(ctx)
+		}
+	}, nil
+}
+
+// FromContext returns the user identified by the Authenticate middleware, or nil when there is none.
+// Without the middleware, the user is read from the headers set by the oauth-proxy.
+func FromContext(c echo.Context) *models.User {
+	if v := c.Get(userKey); v != nil {
+		user, _ := v.(*models.User)
+		return user
+	}
+
+	username := c.Request().Header.Get(USER_NAME_HEADER)
+	if username == "" {
+		return nil
+	}
+	return &models.User{Username: username, Email: c.Request().Header.Get(USER_EMAIL_HEADER)}
+}
+
+// ParseTrustedProxies returns the networks of the CIDRs of the trusted proxies
+func ParseTrustedProxies(cidrs []string) ([]*net.IPNet, error) {
+	proxies := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy CIDR %q: %v", cidr, err)
+		}
+		proxies = append(proxies, ipNet)
+	}
+	return proxies, nil
+}
+
+// ClientIP returns the IP address of the client of a request. The X-Forwarded-For and X-Real-IP headers are only
+// read when the request comes from a trusted proxy, since the clients can set them to any value. The address
+// forwarded last by a proxy which is not trusted is the client, as the ones before it can be forged.
+func ClientIP(req *http.Request, proxies []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	if !isTrusted(proxies, req.RemoteAddr) {
+		return host
+	}
+
+	if forwarded := req.Header.Get(echo.HeaderXForwardedFor); forwarded != "" {
+		ips := strings.Split(forwarded, ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(ips[i])
+			if i == 0 || !isTrusted(proxies, ip) {
+				return ip
+			}
+		}
+	}
+	if realIP := strings.TrimSpace(req.Header.Get(echo.HeaderXRealIP)); realIP != "" {
+		return realIP
+	}
+	return host
+}
+
+// isTrusted is true when the address of the client is in the CIDRs of the trusted proxies.
+// The X-Forwarded-For header is not used since it is set by the clients.
+func isTrusted(proxies []*net.IPNet, remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, proxy := range proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// bearerToken returns the token of a bearer Authorization header
+func bearerToken(authorization string) string {
+	const prefix = "bearer "
+	if len(authorization) > len(prefix) && strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return strings.TrimSpace(authorization[len(prefix):])
+	}
+	return ""
+}
@@ -0,0 +1,242 @@
+package user
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/labstack/echo"
+	log "github.com/sirupsen/logrus"
+)
+
+func Test_Authenticate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	oidc := writeTestJWKS(t, dir)
+
+	proxy := "10.0.0.7:4180"
+	client := "192.0.2.1:1234"
+
+	tests := []struct {
+		name          string
+		config        config.AuthConfig
+		remoteAddr    string
+		username      string
+		authorization string
+		want          *models.User
+		wantCode      int
+	}{
+		{
+			name:       "Should trust the headers of every client when there are no trusted proxies and no issuer",
+			remoteAddr: client,
+			username:   "alice",
+			want:       &models.User{Username: "alice", Email: "alice@example.com"},
+			wantCode:   200,
+		},
+		{
+			name:       "Should use the headers set by a trusted proxy",
+			config:     config.AuthConfig{TrustedProxies: []string{"10.0.0.0/8"}},
+			remoteAddr: proxy,
+			username:   "alice",
+			want:       &models.User{Username: "alice", Email: "alice@example.com"},
+			wantCode:   200,
+		},
+		{
+			name:       "Should ignore the headers of a client which is not a trusted proxy",
+			config:     config.AuthConfig{TrustedProxies: []string{"10.0.0.0/8"}},
+			remoteAddr: client,
+			username:   "admin",
+			wantCode:   200,
+		},
+		{
+			name:       "Should ignore the headers of every client when there is an issuer but no trusted proxies",
+			config:     config.AuthConfig{OIDC: oidc},
+			remoteAddr: client,
+			username:   "admin",
+			wantCode:   200,
+		},
+		{
+			name:          "Should use the user of a valid bearer token",
+			config:        config.AuthConfig{TrustedProxies: []string{"10.0.0.0/8"}, OIDC: oidc},
+			remoteAddr:    client,
+			authorization: "Bearer " + signToken("RS256", "rsa", validClaims()),
+			want:          &models.User{Username: "alice", Email: "alice@example.com"},
+			wantCode:      200,
+		},
+		{
+			name:          "Should prefer the headers of a trusted proxy to a bearer token",
+			config:        config.AuthConfig{TrustedProxies: []string{"10.0.0.0/8"}, OIDC: oidc},
+			remoteAddr:    proxy,
+			username:      "bob",
+			authorization: "Bearer sha256~opaque",
+			want:          &models.User{Username: "bob", Email: "alice@example.com"},
+			wantCode:      200,
+		},
+		{
+			name:          "Should return unauthorized for an invalid bearer token",
+			config:        config.AuthConfig{OIDC: oidc},
+			remoteAddr:    client,
+			authorization: "Bearer sha256~opaque",
+			wantCode:      401,
+		},
+		{
+			name:          "Should ignore a bearer token when there is no issuer",
+			config:        config.AuthConfig{TrustedProxies: []string{"10.0.0.0/8"}},
+			remoteAddr:    client,
+			authorization: "Bearer " + signToken("RS256", "rsa", validClaims()),
+			wantCode:      200,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticate, err := Authenticate(tt.config)
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.username != "" {
+				req.Header.Add(USER_NAME_HEADER, tt.username)
+				req.Header.Add(USER_EMAIL_HEADER, "alice@example.com")
+			}
+			if tt.authorization != "" {
+				req.Header.Add(echo.HeaderAuthorization, tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var got *models.User
+			next := func(c echo.Context) error {
+				got = FromContext(c)
+				return c.NoContent(http.StatusOK)
+			}
+			if err := authenticate(next)(c); err != nil {
+				t.Errorf("Authenticate() error = %v", err)
+			}
+			if rec.Code != tt.wantCode {
+				t.Errorf("Authenticate() statusCode = %v, wantCode = %v", rec.Code, tt.wantCode)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_Authenticate_RejectsInvalidCIDRs(t *testing.T) {
+	if _, err := Authenticate(config.AuthConfig{TrustedProxies: []string{"10.0.0.0/33"}}); err == nil {
+		t.Errorf("Authenticate() error = nil, want an error")
+	}
+}
+
+func Test_Authenticate_WarnsWhenEveryClientIsTrusted(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	for _, tt := range []struct {
+		config   config.AuthConfig
+		wantWarn bool
+	}{
+		{config: config.AuthConfig{}, wantWarn: true},
+		{config: config.AuthConfig{TrustedProxies: []string{"10.0.0.0/8"}}, wantWarn: false},
+		{config: config.AuthConfig{OIDC: config.OIDCConfig{Issuer: testIssuer}}, wantWarn: false},
+	} {
+		buf.Reset()
+		if _, err := Authenticate(tt.config); err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+		if warned := strings.Contains(buf.String(), "level=warning"); warned != tt.wantWarn {
+			t.Errorf("Authenticate(%+v) warned = %v, want %v", tt.config, warned, tt.wantWarn)
+		}
+	}
+}
+
+func Test_ClientIP(t *testing.T) {
+	proxies, _ := ParseTrustedProxies([]string{"10.0.0.0/8"})
+
+	tests := []struct {
+		name       string
+		proxies    []*net.IPNet
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		want       string
+	}{
+		{
+			name:       "Should return the address of a client which is not a trusted proxy",
+			proxies:    proxies,
+			remoteAddr: "192.0.2.1:1234",
+			forwarded:  "198.51.100.1",
+			realIP:     "198.51.100.2",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "Should ignore the headers when there are no trusted proxies",
+			remoteAddr: "10.0.0.7:4180",
+			forwarded:  "198.51.100.1",
+			want:       "10.0.0.7",
+		},
+		{
+			name:       "Should return the address forwarded last by a trusted proxy",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.7:4180",
+			forwarded:  "203.0.113.1, 198.51.100.1, 10.0.0.8",
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "Should return the X-Real-IP of a trusted proxy without X-Forwarded-For",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.7:4180",
+			realIP:     "198.51.100.2",
+			want:       "198.51.100.2",
+		},
+		{
+			name:       "Should return the address of a trusted proxy without forwarded address",
+			proxies:    proxies,
+			remoteAddr: "10.0.0.7:4180",
+			want:       "10.0.0.7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set(echo.HeaderXForwardedFor, tt.forwarded)
+			}
+			if tt.realIP != "" {
+				req.Header.Set(echo.HeaderXRealIP, tt.realIP)
+			}
+
+			if got := ClientIP(req, tt.proxies); got != tt.want {
+				t.Errorf("ClientIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_FromContext_WithoutAuthenticate(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Add(USER_NAME_HEADER, "alice")
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	want := &models.User{Username: "alice"}
+	if got := FromContext(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("FromContext() = %v, want %v", got, want)
+	}
+}
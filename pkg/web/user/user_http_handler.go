@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/aerogear/mobile-security-service/pkg/httperrors"
-	"github.com/aerogear/mobile-security-service/pkg/models"
 	"github.com/labstack/echo"
 )
 
@@ -27,20 +26,13 @@ func NewHTTPHandler(e *echo.Echo) HTTPHandler {
 	return &httpHandler{}
 }
 
-// GetUser returns the user if Oauth added headers are present
+// GetUser returns the user identified by the oauth-proxy headers or by a bearer token
 func (a *httpHandler) GetUser(c echo.Context) error {
 
-	var user models.User
-
-	//these headers values will be set by the openshift oauth-proxy
-	if userNameHeader := c.Request().Header[USER_NAME_HEADER]; userNameHeader != nil && userNameHeader[0] != "" {
-		user.Username = userNameHeader[0]
-	} else {
+	user := FromContext(c)
+	if user == nil {
 		return httperrors.NotFound(c, "No User Found")
 	}
-	if userEmailHeader := c.Request().Header[USER_EMAIL_HEADER]; userEmailHeader != nil && userEmailHeader[0] != "" {
-		user.Email = userEmailHeader[0]
-	}
 
 	return c.JSON(http.StatusOK, user)
 
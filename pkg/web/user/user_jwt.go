@@ -0,0 +1,402 @@
+package user
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers SHA-256 for RS256 and ES256
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for RS384, RS512, ES384 and ES512
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+const (
+	// clockSkew is the difference allowed between the clocks of the issuer and of the service
+	clockSkew = time.Minute
+	// keysRefreshInterval is how long a key set is used before it is read again for a key it does not have
+	keysRefreshInterval = time.Minute
+	// keysTimeout is how long the issuer is given to return its discovery document and its keys
+	keysTimeout = 10 * time.Second
+)
+
+// errInvalidToken is returned for the bearer tokens which are not valid
+var errInvalidToken = errors.New("invalid token")
+
+// signingAlgorithms are the hash functions of the accepted asymmetric signing algorithms.
+// The unsigned tokens and the tokens signed with a shared secret are rejected.
+var signingAlgorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// tokenVerifier validates the bearer JWTs of an issuer and maps their claims to a user
+type tokenVerifier struct {
+	config config.OIDCConfig
+	keys   *keySet
+	now    func() time.Time
+}
+
+// newTokenVerifier returns a verifier of the tokens of the issuer, whose keys are read from the file or the URL
+// of the configuration, or else from the jwks_uri of the discovery document of the issuer
+func newTokenVerifier(c config.OIDCConfig) *tokenVerifier {
+	client := &http.Client{Timeout: keysTimeout}
+
+	var read func() ([]byte, error)
+	switch {
+	case c.JWKSFile != "":
+		read = func() ([]byte, error) { return ioutil.ReadFile(c.JWKSFile) }
+	case c.JWKSURL != "":
+		read = func() ([]byte, error) { return get(client, c.JWKSURL) }
+	default:
+		read = func() ([]byte, error) {
+			url, err := discoverJWKSURL(client, c.Issuer)
+			if err != nil {
+				return nil, err
+			}
+			return get(client, url)
+		}
+	}
+
+	return &tokenVerifier{config: c, keys: &keySet{read: read}, now: time.Now}
+}
+
+// Verify checks the signature and the claims of a token and returns its user
+func (v *tokenVerifier) Verify(token string) (*models.User, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errInvalidToken
+	}
+
+	hash, ok := signingAlgorithms[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", errInvalidToken, header.Alg)
+	}
+
+	key, err := v.keys.key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errInvalidToken
+	}
+
+	if err := verifySignature(key, header.Alg, hash, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	claims := map[string]interface{}{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, errInvalidToken
+	}
+
+	if err := v.verifyClaims(claims); err != nil {
+		return nil, err
+	}
+
+	username, _ := claims[v.config.UsernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("%w: no %v claim", errInvalidToken, v.config.UsernameClaim)
+	}
+	email, _ := claims[v.config.EmailClaim].(string)
+
+	return &models.User{Username: username, Email: email}, nil
+}
+
+// verifyClaims checks the issuer, the audience and the validity period of a token
+func (v *tokenVerifier) verifyClaims(claims map[string]interface{}) error {
+	if iss, _ := claims["iss"].(string); iss != v.config.Issuer {
+		return fmt.Errorf("%w: issued by %q", errInvalidToken, iss)
+	}
+
+	if v.config.Audience != "" && !hasAudience(claims["aud"], v.config.Audience) {
+		return fmt.Errorf("%w: not issued for %q", errInvalidToken, v.config.Audience)
+	}
+
+	now := v.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok || now.Add(-clockSkew).After(time.Unix(int64(exp), 0)) {
+		return fmt.Errorf("%w: expired", errInvalidToken)
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("%w: not valid yet", errInvalidToken)
+	}
+
+	return nil
+}
+
+// hasAudience is true when the aud claim, a string or a list of strings, has the audience
+func hasAudience(aud interface{}, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifySignature checks the signature of the header and the payload of a token with the key of the issuer
+func verifySignature(key crypto.PublicKey, alg string, hash crypto.Hash, signed string, signature []byte) error {
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if strings.HasPrefix(alg, "RS") && rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		// the signature is r and s, each padded to the size of the curve
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if strings.HasPrefix(alg, "ES") && len(signature) == 2*size {
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			if ecdsa.Verify(key, digest, r, s) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: bad signature", errInvalidToken)
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a token
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// keySet holds the public keys of an issuer by their ids. It is read again when a token is signed by an unknown key,
+// at most once every keysRefreshInterval, so the keys rotated by the issuer are picked up.
+type keySet struct {
+	read func() ([]byte, error)
+
+	// readMu lets a single caller read the set at a time. The set is read without holding mu,
+	// so the tokens of the known keys are still verified while the issuer is slow to answer.
+	readMu sync.Mutex
+
+	mu     sync.Mutex
+	keys   map[string]crypto.PublicKey
+	readAt time.Time
+}
+
+// key returns the key with the id. A token without a key id can only be signed by the single key of the set.
+func (k *keySet) key(kid string) (crypto.PublicKey, error) {
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+
+	k.readMu.Lock()
+	defer k.readMu.Unlock()
+
+	// the set may have been read by another caller while this one was waiting
+	k.mu.Lock()
+	key, ok := k.find(kid)
+	// the set is not read again for every token when the issuer is down or the tokens are forged
+	recent := !k.readAt.IsZero() && time.Since(k.readAt) < keysRefreshInterval
+	if !ok && !recent {
+		k.readAt = time.Now()
+	}
+	k.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+	if recent {
+		return nil, fmt.Errorf("%w: unknown key %q", errInvalidToken, kid)
+	}
+
+	b, err := k.read()
+	if err != nil {
+		return nil, fmt.Errorf("reading the signing keys: %v", err)
+	}
+
+	keys, err := parseJWKS(b)
+	if err != nil {
+		return nil, fmt.Errorf("reading the signing keys: %v", err)
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	key, ok = k.find(kid)
+	k.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key %q", errInvalidToken, kid)
+}
+
+// lookup returns the key with the id when it is in the set
+func (k *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.find(kid)
+}
+
+// find returns the key with the id when it is in the set. It is called with mu held.
+func (k *keySet) find(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// jsonWebKey is a public key of a JSON Web Key Set, RFC 7517
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS returns the RSA and EC signing keys of a JSON Web Key Set by their ids. The other keys are skipped.
+func parseJWKS(b []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %v", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+// publicKey returns the RSA or EC key, or nil for the other types of keys
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("the point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// discoverJWKSURL returns the jwks_uri of the OpenID Connect discovery document of the issuer
+func discoverJWKSURL(client *http.Client, issuer string) (string, error) {
+	b, err := get(client, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration")
+	if err != nil {
+		return "", err
+	}
+
+	var discovery struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.Unmarshal(b, &discovery); err != nil {
+		return "", err
+	}
+	if discovery.JWKSURI == "" {
+		return "", errors.New("no jwks_uri in the discovery document of the issuer")
+	}
+
+	return discovery.JWKSURI, nil
+}
+
+func get(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %v: %v", url, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,347 @@
+package user
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+const testIssuer = "https://sso.example.com/auth/realms/mobile"
+
+var (
+	testRSAKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+	testECKey, _  = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+)
+
+// testJWKS returns the JSON Web Key Set of the test keys
+func testJWKS() []byte {
+	encode := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+
+	b, _ := json.Marshal(map[string]interface{}{
+		"keys": []interface{}{
+			map[string]string{"kid": "rsa", "kty": "RSA", "use": "sig", "n": encode(testRSAKey.N), "e": encode(big.NewInt(int64(testRSAKey.E)))},
+			map[string]string{"kid": "ec", "kty": "EC", "crv": "P-256", "x": encode(testECKey.X), "y": encode(testECKey.Y)},
+			map[string]string{"kid": "enc", "kty": "RSA", "use": "enc", "n": encode(testRSAKey.N), "e": "AQAB"},
+		},
+	})
+	return b
+}
+
+// writeTestJWKS writes the test keys to a file of the directory and returns the configuration
+// of an issuer reading them from it
+func writeTestJWKS(t *testing.T, dir string) config.OIDCConfig {
+	file := filepath.Join(dir, "jwks.json")
+	if err := ioutil.WriteFile(file, testJWKS(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return config.OIDCConfig{
+		Issuer:        testIssuer,
+		Audience:      "mobile-security-service",
+		JWKSFile:      file,
+		UsernameClaim: "preferred_username",
+		EmailClaim:    "email",
+	}
+}
+
+// signToken returns a JWT of the claims signed with the test key of the algorithm
+func signToken(alg, kid string, claims map[string]interface{}) string {
+	encode := func(v interface{}) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	signed := encode(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + encode(claims)
+	h := signingAlgorithms[alg].New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	var signature []byte
+	switch {
+	case strings.HasPrefix(alg, "RS"):
+		signature, _ = rsa.SignPKCS1v15(rand.Reader, testRSAKey, signingAlgorithms[alg], digest)
+	case strings.HasPrefix(alg, "ES"):
+		r, s, _ := ecdsa.Sign(rand.Reader, testECKey, digest)
+		// r and s are padded to the 32 bytes of the curve
+		signature = make([]byte, 64)
+		rb, sb := r.Bytes(), s.Bytes()
+		copy(signature[32-len(rb):32], rb)
+		copy(signature[64-len(sb):], sb)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// withSignatureOf returns the token with the signature of another one
+func withSignatureOf(token, other string) string {
+	return token[:strings.LastIndex(token, ".")] + other[strings.LastIndex(other, "."):]
+}
+
+// unsigned returns a token of the claims with the none algorithm
+func unsigned(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "none", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+}
+
+// validClaims returns the claims of a token of alice which is valid for an hour
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":                testIssuer,
+		"aud":                []string{"account", "mobile-security-service"},
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": "alice",
+		"email":              "alice@example.com",
+	}
+}
+
+func Test_tokenVerifier_Verify(t *testing.T) {
+	alice := &models.User{Username: "alice", Email: "alice@example.com"}
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    *models.User
+		wantErr bool
+	}{
+		{
+			name:  "Should return the user of a token signed with an RSA key",
+			token: signToken("RS256", "rsa", validClaims()),
+			want:  alice,
+		},
+		{
+			name:  "Should return the user of a token signed with an EC key",
+			token: signToken("ES256", "ec", validClaims()),
+			want:  alice,
+		},
+		{
+			name:  "Should accept a single audience",
+			token: signToken("RS384", "rsa", with("aud", "mobile-security-service")),
+			want:  alice,
+		},
+		{
+			name:  "Should return a user without email when the token has none",
+			token: signToken("RS256", "rsa", with("email", nil)),
+			want:  &models.User{Username: "alice"},
+		},
+		{
+			name:    "Should reject a token of another issuer",
+			token:   signToken("RS256", "rsa", with("iss", "https://evil.example.com")),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject a token for another audience",
+			token:   signToken("RS256", "rsa", with("aud", "account")),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject an expired token",
+			token:   signToken("RS256", "rsa", with("exp", time.Now().Add(-time.Hour).Unix())),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject a token without expiration",
+			token:   signToken("RS256", "rsa", with("exp", nil)),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject a token which is not valid yet",
+			token:   signToken("RS256", "rsa", with("nbf", time.Now().Add(time.Hour).Unix())),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject a token without username",
+			token:   signToken("RS256", "rsa", with("preferred_username", nil)),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject a token whose claims were changed",
+			token:   withSignatureOf(signToken("RS256", "rsa", with("preferred_username", "admin")), signToken("RS256", "rsa", validClaims())),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject a token signed with the key of another algorithm",
+			token:   signToken("RS256", "ec", validClaims()),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject a token signed by an unknown key",
+			token:   signToken("RS256", "other", validClaims()),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject a token signed by an encryption key",
+			token:   signToken("RS256", "enc", validClaims()),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject an unsigned token",
+			token:   unsigned(validClaims()),
+			wantErr: true,
+		},
+		{
+			name:    "Should reject a token which is not a JWT",
+			token:   "sha256~opaque",
+			wantErr: true,
+		},
+	}
+	dir, err := ioutil.TempDir("", "jwks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	c := writeTestJWKS(t, dir)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTokenVerifier(c).Verify(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tokenVerifier.Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, errInvalidToken) {
+				t.Errorf("tokenVerifier.Verify() error = %v, want an invalid token", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenVerifier.Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_tokenVerifier_DiscoversTheKeysOfTheIssuer(t *testing.T) {
+	var requests int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{"issuer": server.URL, "jwks_uri": server.URL + "/certs"})
+		case "/certs":
+			w.Write(testJWKS())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	v := newTokenVerifier(config.OIDCConfig{Issuer: server.URL, UsernameClaim: "preferred_username", EmailClaim: "email"})
+	claims := validClaims()
+	claims["iss"] = server.URL
+
+	for i := 0; i < 2; i++ {
+		if _, err := v.Verify(signToken("ES256", "ec", claims)); err != nil {
+			t.Fatalf("tokenVerifier.Verify() error = %v", err)
+		}
+	}
+
+	// the keys are read once, and not again for a forged key id
+	if _, err := v.Verify(signToken("ES256", "forged", claims)); err == nil {
+		t.Errorf("tokenVerifier.Verify() accepted a token of an unknown key")
+	}
+	if requests != 2 {
+		t.Errorf("the issuer received %v requests, want 2", requests)
+	}
+}
+
+func Test_keySet_ServesTheKnownKeysWhileReading(t *testing.T) {
+	keys, err := parseJWKS(testJWKS())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reading := make(chan struct{})
+	release := make(chan struct{})
+	var reads int32
+	k := &keySet{keys: keys, read: func() ([]byte, error) {
+		if atomic.AddInt32(&reads, 1) == 1 {
+			close(reading)
+		}
+		<-release
+		return testJWKS(), nil
+	}}
+
+	// a token of a rotated key reads the set again, which the issuer is slow to answer
+	rotated := make(chan error)
+	go func() {
+		_, err := k.key("rotated")
+		rotated <- err
+	}()
+	<-reading
+
+	known := make(chan error)
+	go func() {
+		_, err := k.key("rsa")
+		known <- err
+	}()
+	select {
+	case err := <-known:
+		if err != nil {
+			t.Errorf("keySet.key() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("keySet.key() of a known key waited for the set to be read")
+	}
+
+	// the callers waiting for the read do not read the set again
+	waiting := make(chan error)
+	go func() {
+		_, err := k.key("forged")
+		waiting <- err
+	}()
+	close(release)
+
+	for _, ch := range []chan error{rotated, waiting} {
+		if err := <-ch; !errors.Is(err, errInvalidToken) {
+			t.Errorf("keySet.key() error = %v, want an invalid token", err)
+		}
+	}
+	if reads != 1 {
+		t.Errorf("the set was read %v times, want 1", reads)
+	}
+}
+
+func Test_parseJWKS_RejectsInvalidKeys(t *testing.T) {
+	for _, jwks := range []string{
+		`not json`,
+		`{"keys":[{"kid":"rsa","kty":"RSA","n":"","e":"AQAB"}]}`,
+		`{"keys":[{"kid":"ec","kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`,
+		`{"keys":[{"kid":"ec","kty":"EC","crv":"secp256k1","x":"AQ","y":"AQ"}]}`,
+	} {
+		if _, err := parseJWKS([]byte(jwks)); err == nil {
+			t.Errorf("parseJWKS(%v) error = nil, want an error", jwks)
+		}
+	}
+
+	// the keys of the other types are skipped
+	keys, err := parseJWKS([]byte(`{"keys":[{"kid":"shared","kty":"oct","k":"c2VjcmV0"}]}`))
+	if err != nil || len(keys) != 0 {
+		t.Errorf("parseJWKS() = %v, %v, want no keys", keys, err)
+	}
+}
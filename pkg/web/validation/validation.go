@@ -0,0 +1,149 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/labstack/echo"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+// AppIDPattern matches the reverse-DNS app identifiers used by Android and iOS, e.g. com.example.app
+const AppIDPattern = `^[A-Za-z][A-Za-z0-9_-]*(\.[A-Za-z0-9_-]+)+$`
+
+var appIDRegexp = regexp.MustCompile(AppIDPattern)
+
+// FingerprintPattern matches a SHA-256 certificate fingerprint in hex, with or without colons between the bytes
+const FingerprintPattern = `^[0-9A-Fa-f]{2}(:?[0-9A-Fa-f]{2}){31}$`
+
+var fingerprintRegexp = regexp.MustCompile(FingerprintPattern)
+
+// tagNames are the struct tags used to name a field in a violation, in order of preference
+var tagNames = []string{"json", "query", "param"}
+
+// Validator implements the echo.Validator contract with the validation tags of the request structs.
+// The violations are returned as a models validation error with the details of each invalid field.
+type Validator struct {
+	validate *validator.Validate
+}
+
+// NewValidator returns a new Validator with the custom validations of the service registered
+func NewValidator() *Validator {
+	v := validator.New()
+
+	// name the fields as the clients send them
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range tagNames {
+			if name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]; name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+
+	// appid validates the reverse-DNS format of an app identifier
+	if err := v.RegisterValidation("appid", func(fl validator.FieldLevel) bool {
+		return appIDRegexp.MatchString(fl.Field().String())
+	}); err != nil {
+		panic(err)
+	}
+
+	// fingerprint validates the format of a SHA-256 signing certificate fingerprint
+	if err := v.RegisterValidation("fingerprint", func(fl validator.FieldLevel) bool {
+		return fingerprintRegexp.MatchString(fl.Field().String())
+	}); err != nil {
+		panic(err)
+	}
+
+	return &Validator{validate: v}
+}
+
+// Validate validates the struct based on its validation tags
+func (v *Validator) Validate(i interface{}) error {
+	err := v.validate.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	violations, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return models.ErrInternalServerError.Wrap(err)
+	}
+
+	fields := make([]models.FieldError, 0, len(violations))
+	for _, violation := range violations {
+		fields = append(fields, models.FieldError{
+			Field:   fieldPath(violation),
+			Message: message(violation),
+		})
+	}
+
+	return models.NewValidationError(fields...)
+}
+
+// Params validates the path and query parameters of a request with the validator registered in echo.
+// The violations are reported as a bad request.
+func Params(c echo.Context, i interface{}) error {
+	err := c.Validate(i)
+
+	var validationErr *models.Error
+	if errors.As(err, &validationErr) && validationErr.Code == models.CodeValidationFailed {
+		return &models.Error{
+			Code:    models.CodeBadParamInput,
+			Message: "Invalid parameters supplied",
+			Fields:  validationErr.Fields,
+		}
+	}
+
+	return err
+}
+
+// Body decodes the JSON body of a request into i and validates it with the validator registered in echo.
+// A body which is not valid JSON is reported as a bad request and the violations as an unprocessable entity.
+func Body(c echo.Context, i interface{}) error {
+	if err := json.NewDecoder(c.Request().Body).Decode(i); err != nil {
+		return models.ErrBadParamInput.WithMessage("Invalid data").Wrap(err)
+	}
+
+	return c.Validate(i)
+}
+
+// fieldPath returns the path of the field in the request without the name of the request struct, e.g. versions[0].id
+func fieldPath(violation validator.FieldError) string {
+	parts := strings.SplitN(violation.Namespace(), ".", 2)
+	if len(parts) == 2 {
+		return parts[1]
+	}
+	return violation.Field()
+}
+
+// message returns a human readable description of the violation
+func message(violation validator.FieldError) string {
+	field := violation.Field()
+
+	switch violation.Tag() {
+	case "required":
+		return fmt.Sprintf("%v is required", field)
+	case "uuid":
+		return fmt.Sprintf("%v must be a valid UUID", field)
+	case "appid":
+		return fmt.Sprintf("%v must be in reverse-DNS format, e.g. com.example.app", field)
+	case "fingerprint":
+		return fmt.Sprintf("%v must be a SHA-256 fingerprint in hex, e.g. 14:6D:E9:83:...", field)
+	case "min":
+		return fmt.Sprintf("%v must be at least %v", field, violation.Param())
+	case "max":
+		return fmt.Sprintf("%v must be at most %v", field, violation.Param())
+	case "oneof":
+		return fmt.Sprintf("%v must be one of [%v]", field, violation.Param())
+	case "url":
+		return fmt.Sprintf("%v must be a URL", field)
+	default:
+		return fmt.Sprintf("%v is not valid", field)
+	}
+}
@@ -0,0 +1,153 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/labstack/echo"
+)
+
+type testRequest struct {
+	AppID    string     `json:"appId" validate:"required,appid"`
+	DeviceID string     `json:"deviceId" validate:"omitempty,uuid"`
+	Cert     string     `json:"cert" validate:"omitempty,fingerprint"`
+	Items    []testItem `json:"items" validate:"dive"`
+}
+
+type testItem struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type testParams struct {
+	ID    string `param:"id" validate:"uuid"`
+	Limit int    `query:"limit" validate:"max=10"`
+}
+
+func TestValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    interface{}
+		wantFields []models.FieldError
+	}{
+		{
+			name:    "Validate() should accept a valid request",
+			request: &testRequest{AppID: "com.aerogear.mobile_app-one", DeviceID: "7f89ce49-a736-459e-9110-e52d049fc027"},
+		},
+		{
+			name:    "Validate() should list every violation with the field name sent by the client",
+			request: &testRequest{DeviceID: "device", Items: []testItem{{ID: "1"}, {}}},
+			wantFields: []models.FieldError{
+				{Field: "appId", Message: "appId is required"},
+				{Field: "deviceId", Message: "deviceId must be a valid UUID"},
+				{Field: "items[1].id", Message: "id is required"},
+			},
+		},
+		{
+			name:    "Validate() should reject an appId which is not in reverse-DNS format",
+			request: &testRequest{AppID: "mobile app"},
+			wantFields: []models.FieldError{
+				{Field: "appId", Message: "appId must be in reverse-DNS format, e.g. com.example.app"},
+			},
+		},
+		{
+			name:    "Validate() should accept a SHA-256 fingerprint with or without colons",
+			request: &testRequest{AppID: "com.aerogear.app", Cert: "146de983c5730650e10c53e5e7d3e2b1a03b2d7a1a92c56ae3ea0ab7fb64c5d9"},
+		},
+		{
+			name:    "Validate() should reject a fingerprint which is not a SHA-256 one",
+			request: &testRequest{AppID: "com.aerogear.app", Cert: "14:6D:E9:83:C5:73:06:50"},
+			wantFields: []models.FieldError{
+				{Field: "cert", Message: "cert must be a SHA-256 fingerprint in hex, e.g. 14:6D:E9:83:..."},
+			},
+		},
+		{
+			name:    "Validate() should name the parameters with their param and query tags",
+			request: &testParams{ID: "invalid", Limit: 20},
+			wantFields: []models.FieldError{
+				{Field: "id", Message: "id must be a valid UUID"},
+				{Field: "limit", Message: "limit must be at most 10"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewValidator().Validate(tt.request)
+
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Errorf("Validator.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *models.Error
+			if !errors.As(err, &validationErr) || validationErr.Code != models.CodeValidationFailed {
+				t.Fatalf("Validator.Validate() error = %v, want a validation error", err)
+			}
+
+			if !reflect.DeepEqual(validationErr.Fields, tt.wantFields) {
+				t.Errorf("Validator.Validate() fields = %+v, want %+v", validationErr.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode models.ErrorCode
+	}{
+		{
+			name: "Body() should decode and validate the request",
+			body: `{"appId": "com.aerogear.mobile_app_one"}`,
+		},
+		{
+			name:     "Body() should return a bad request when the body is not valid JSON",
+			body:     `{"appId": `,
+			wantCode: models.CodeBadParamInput,
+		},
+		{
+			name:     "Body() should return a validation error when a field is not valid",
+			body:     `{"appId": ""}`,
+			wantCode: models.CodeValidationFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Validator = NewValidator()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			err := Body(c, &testRequest{})
+
+			var modelErr *models.Error
+			if errors.As(err, &modelErr) != (tt.wantCode != "") {
+				t.Fatalf("Body() error = %v, wantCode %v", err, tt.wantCode)
+			}
+
+			if modelErr != nil && modelErr.Code != tt.wantCode {
+				t.Errorf("Body() error code = %v, wantCode %v", modelErr.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestParams(t *testing.T) {
+	e := echo.New()
+	e.Validator = NewValidator()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	err := Params(c, &testParams{ID: "invalid"})
+
+	var modelErr *models.Error
+	if !errors.As(err, &modelErr) || modelErr.Code != models.CodeBadParamInput || len(modelErr.Fields) != 1 {
+		t.Errorf("Params() error = %+v, want a bad request with the invalid id", err)
+	}
+}
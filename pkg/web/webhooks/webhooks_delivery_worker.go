@@ -0,0 +1,55 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/lifecycle"
+	log "github.com/sirupsen/logrus"
+)
+
+type deliveryWorker struct {
+	service  Service
+	interval time.Duration
+}
+
+// NewDeliveryWorker returns a background worker which sends, every interval,
+// the deliveries of the outbox whose next attempt is due
+func NewDeliveryWorker(s Service, interval time.Duration) lifecycle.Worker {
+	return &deliveryWorker{
+		service:  s,
+		interval: interval,
+	}
+}
+
+// Run sends the due deliveries until the context is cancelled
+func (w *deliveryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.deliver(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver sends the due deliveries, a batch after the other, until none is left
+func (w *deliveryWorker) deliver(ctx context.Context) {
+	for ctx.Err() == nil {
+		sent, err := w.service.DeliverDue()
+		if err != nil {
+			log.Errorf("Failed to deliver the events to the webhooks: %v", err)
+			return
+		}
+
+		if sent == 0 {
+			return
+		}
+		log.WithField("deliveries", sent).Debug("Delivered events to the webhooks")
+	}
+}
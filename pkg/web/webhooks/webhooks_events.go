@@ -0,0 +1,83 @@
+package webhooks
+
+import (
+	"github.com/aerogear/mobile-security-service/pkg/events"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// NewEventHandler returns the subscriber of the events bus which publishes the security relevant events
+// of the apps to their webhooks. It should be synchronous, so the deliveries are added to the outbox
+// before the response of the change is sent.
+func NewEventHandler(s Service) events.Handler {
+	return events.HandlerFunc(func(event events.Event) error {
+		for _, e := range webhookEvents(event) {
+			if err := s.Publish(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// webhookEvents returns the events sent to the webhooks for an event of the bus, if any
+func webhookEvents(event events.Event) []models.Event {
+	switch e := event.(type) {
+	case events.AppDeleted:
+		return []models.Event{models.NewEvent(models.EventAppDeleted, e.App.AppID, models.AppEventData{ID: e.App.ID, AppName: e.App.AppName})}
+	case events.AppRestored:
+		return []models.Event{models.NewEvent(models.EventAppRestored, e.App.AppID, models.AppEventData{ID: e.App.ID, AppName: e.App.AppName})}
+	case events.VersionsUpdated:
+		return versionEvents(e.AppID, e.Previous, e.Updated())
+	case events.AllVersionsDisabled:
+		return versionEvents(e.AppID, e.Previous, e.Updated())
+	case events.DeviceBlocked:
+		return []models.Event{models.NewEvent(models.EventDeviceBlocked, e.Device.AppID, models.DeviceEventData{
+			DeviceID:        e.Device.DeviceID,
+			DeviceType:      e.Device.DeviceType,
+			DeviceVersion:   e.Device.DeviceVersion,
+			Version:         e.Version.Version,
+			DisabledMessage: e.Version.DisabledMessage,
+		})}
+	case events.TamperedBuild:
+		return []models.Event{models.NewEvent(models.EventDeviceTampered, e.Device.AppID, models.DeviceEventData{
+			DeviceID:           e.Device.DeviceID,
+			DeviceType:         e.Device.DeviceType,
+			DeviceVersion:      e.Device.DeviceVersion,
+			Version:            e.Device.Version,
+			SigningCertificate: e.Device.SigningCertificate,
+			Blocked:            e.Blocked,
+		})}
+	}
+	return nil
+}
+
+// versionEvents returns the events of the versions which were disabled and the ones which were enabled
+// by the update of the previous versions of an app
+func versionEvents(appID string, previous, updated []models.Version) []models.Event {
+	byID := map[string]models.Version{}
+	for _, v := range previous {
+		byID[v.ID] = v
+	}
+
+	var disabled, enabled []models.EventVersion
+	for _, v := range updated {
+		was, ok := byID[v.ID]
+		switch {
+		case !ok || was.Disabled == v.Disabled:
+			continue
+		case v.Disabled:
+			disabled = append(disabled, models.EventVersion{ID: v.ID, Version: v.Version, DisabledMessage: v.DisabledMessage})
+		default:
+			enabled = append(enabled, models.EventVersion{ID: v.ID, Version: v.Version})
+		}
+	}
+
+	var result []models.Event
+	if len(disabled) > 0 {
+		result = append(result, models.NewEvent(models.EventVersionDisabled, appID, models.VersionsEventData{Versions: disabled}))
+	}
+	if len(enabled) > 0 {
+		result = append(result, models.NewEvent(models.EventVersionEnabled, appID, models.VersionsEventData{Versions: enabled}))
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package webhooks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aerogear/mobile-security-service/pkg/events"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+func TestNewEventHandler(t *testing.T) {
+	app := *helpers.GetMockApp()
+	versions := helpers.GetMockAppVersionList()
+	device := models.Device{AppID: app.AppID, DeviceID: "a742f8b7-5e2f-43f4-a5b8-2e8bb3e5a3ad", DeviceType: "Android", DeviceVersion: "9", Version: "1.0"}
+
+	tests := []struct {
+		name  string
+		event events.Event
+		want  []models.Event
+	}{
+		{
+			name: "Should publish the versions disabled and enabled by an update",
+			event: events.VersionsUpdated{
+				AppID:    app.AppID,
+				Previous: []models.Version{versions[0], {ID: "59ebd387-9c68-4137-a367-a12025cc1cdb", Version: "1.1", Disabled: true}},
+				Versions: []models.Version{
+					{ID: versions[0].ID, Disabled: true, DisabledMessage: "Please update"},
+					{ID: "59ebd387-9c68-4137-a367-a12025cc1cdb"},
+				},
+			},
+			want: []models.Event{
+				{Type: models.EventVersionDisabled, AppID: app.AppID, Data: models.VersionsEventData{Versions: []models.EventVersion{
+					{ID: versions[0].ID, Version: versions[0].Version, DisabledMessage: "Please update"},
+				}}},
+				{Type: models.EventVersionEnabled, AppID: app.AppID, Data: models.VersionsEventData{Versions: []models.EventVersion{
+					{ID: "59ebd387-9c68-4137-a367-a12025cc1cdb", Version: "1.1"},
+				}}},
+			},
+		},
+		{
+			name: "Should not publish an update which does not disable or enable a version",
+			event: events.VersionsUpdated{
+				AppID:    app.AppID,
+				Previous: versions,
+				Versions: []models.Version{{ID: versions[0].ID, DisabledMessage: "Changed"}},
+			},
+		},
+		{
+			name: "Should publish the versions which were not disabled yet when all the versions are disabled",
+			event: events.AllVersionsDisabled{
+				AppID:           app.AppID,
+				DisabledMessage: "Blocked",
+				Previous:        []models.Version{versions[0], {ID: "59ebd387-9c68-4137-a367-a12025cc1cdb", Version: "1.1", Disabled: true}},
+			},
+			want: []models.Event{
+				{Type: models.EventVersionDisabled, AppID: app.AppID, Data: models.VersionsEventData{Versions: []models.EventVersion{
+					{ID: versions[0].ID, Version: versions[0].Version, DisabledMessage: "Blocked"},
+				}}},
+			},
+		},
+		{
+			name:  "Should publish the delete of an app",
+			event: events.AppDeleted{App: app},
+			want:  []models.Event{{Type: models.EventAppDeleted, AppID: app.AppID, Data: models.AppEventData{ID: app.ID, AppName: app.AppName}}},
+		},
+		{
+			name:  "Should publish the restore of an app",
+			event: events.AppRestored{App: app},
+			want:  []models.Event{{Type: models.EventAppRestored, AppID: app.AppID, Data: models.AppEventData{ID: app.ID, AppName: app.AppName}}},
+		},
+		{
+			name:  "Should publish the launch of a disabled version",
+			event: events.DeviceBlocked{Device: device, Version: models.Version{Version: "1.0", Disabled: true, DisabledMessage: "Please update"}},
+			want: []models.Event{{Type: models.EventDeviceBlocked, AppID: app.AppID, Data: models.DeviceEventData{
+				DeviceID:        device.DeviceID,
+				DeviceType:      "Android",
+				DeviceVersion:   "9",
+				Version:         "1.0",
+				DisabledMessage: "Please update",
+			}}},
+		},
+		{
+			name:  "Should publish the launch of a tampered build",
+			event: events.TamperedBuild{Device: device, Blocked: true},
+			want: []models.Event{{Type: models.EventDeviceTampered, AppID: app.AppID, Data: models.DeviceEventData{
+				DeviceID:           device.DeviceID,
+				DeviceType:         "Android",
+				DeviceVersion:      "9",
+				Version:            device.Version,
+				SigningCertificate: device.SigningCertificate,
+				Blocked:            true,
+			}}},
+		},
+		{
+			name:  "Should not publish the events which are not sent to the webhooks",
+			event: events.AppCreated{App: app},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ServiceMock{
+				PublishFunc: func(event models.Event) error {
+					return nil
+				},
+			}
+
+			if err := NewEventHandler(s).Handle(tt.event); err != nil {
+				t.Fatalf("NewEventHandler().Handle() error = %v", err)
+			}
+
+			var got []models.Event
+			for _, call := range s.PublishCalls() {
+				if call.Event.ID == "" || call.Event.OccurredAt.IsZero() {
+					t.Errorf("NewEventHandler().Handle() published %v without an id and time", call.Event)
+				}
+				got = append(got, models.Event{Type: call.Event.Type, AppID: call.Event.AppID, Data: call.Event.Data})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewEventHandler().Handle() published %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
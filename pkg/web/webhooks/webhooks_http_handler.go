@@ -0,0 +1,110 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/aerogear/mobile-security-service/pkg/httperrors"
+	"github.com/aerogear/mobile-security-service/pkg/web/validation"
+	"github.com/labstack/echo"
+)
+
+type (
+	HTTPHandler interface {
+		GetWebhooks(c echo.Context) error
+		CreateWebhook(c echo.Context) error
+		DeleteWebhook(c echo.Context) error
+		GetDeliveries(c echo.Context) error
+		ReplayDelivery(c echo.Context) error
+	}
+
+	// httpHandler instance
+	httpHandler struct {
+		Service Service
+	}
+)
+
+// NewHTTPHandler returns a new instance of webhooks.Handler
+func NewHTTPHandler(e *echo.Echo, s Service) HTTPHandler {
+	return &httpHandler{
+		Service: s,
+	}
+}
+
+// GetWebhooks returns the webhooks of an app, without their secrets
+func (h *httpHandler) GetWebhooks(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	webhooks, err := h.Service.GetWebhooks(params.ID)
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newWebhooksResponse(webhooks))
+}
+
+// CreateWebhook subscribes a URL to events of an app and returns the webhook with its secret
+func (h *httpHandler) CreateWebhook(c echo.Context) error {
+	params := appIDParams{ID: c.Param("id")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	req := createWebhookRequest{}
+	if err := validation.Body(c, &req); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	webhook, err := h.Service.CreateWebhook(params.ID, req.toModel())
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, newCreatedWebhookResponse(*webhook))
+}
+
+// DeleteWebhook deletes a webhook of an app with its deliveries
+func (h *httpHandler) DeleteWebhook(c echo.Context) error {
+	params := webhookParams{ID: c.Param("id"), WebhookID: c.Param("webhookId")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	if err := h.Service.DeleteWebhook(params.ID, params.WebhookID); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetDeliveries returns the last deliveries of a webhook, the most recent first
+func (h *httpHandler) GetDeliveries(c echo.Context) error {
+	params := webhookParams{ID: c.Param("id"), WebhookID: c.Param("webhookId")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	deliveries, err := h.Service.GetDeliveries(params.ID, params.WebhookID)
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, newDeliveriesResponse(deliveries))
+}
+
+// ReplayDelivery sends again the event of a delivery and returns the new delivery, which is pending
+func (h *httpHandler) ReplayDelivery(c echo.Context) error {
+	params := deliveryParams{ID: c.Param("id"), WebhookID: c.Param("webhookId"), DeliveryID: c.Param("deliveryId")}
+	if err := validation.Params(c, &params); err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	delivery, err := h.Service.ReplayDelivery(params.ID, params.WebhookID, params.DeliveryID)
+	if err != nil {
+		return httperrors.GetHTTPResponseFromErr(c, err)
+	}
+
+	return c.JSON(http.StatusAccepted, newDeliveryResponse(*delivery))
+}
@@ -0,0 +1,13 @@
+package webhooks
+
+import "github.com/aerogear/mobile-security-service/pkg/openapi"
+
+// Schemas returns the requests and responses of the webhooks endpoints by the name of their schema in the API documentation
+func Schemas() openapi.Schemas {
+	return openapi.Schemas{
+		"CreateWebhookRequest":   createWebhookRequest{},
+		"WebhookResponse":        webhookResponse{},
+		"CreatedWebhookResponse": createdWebhookResponse{},
+		"DeliveryResponse":       deliveryResponse{},
+	}
+}
@@ -0,0 +1,315 @@
+package webhooks
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+)
+
+// deliveryColumns are the columns of a delivery, in the order they are scanned by scanDelivery
+const deliveryColumns = `id,webhook_id,event_id,event_type,payload,status,attempts,next_attempt_at,last_attempt_at,
+	response_status,last_error,replay_of,created_at,delivered_at`
+
+type (
+	webhooksPostgreSQLRepository struct {
+		db *sql.DB
+	}
+)
+
+// NewPostgreSQLRepository creates a new instance of webhooksPostgreSQLRepository
+func NewPostgreSQLRepository(db *sql.DB) Repository {
+	return &webhooksPostgreSQLRepository{db}
+}
+
+// GetWebhooksByAppID returns the webhooks of an app by its app ID, with an empty list when it has none
+func (w *webhooksPostgreSQLRepository) GetWebhooksByAppID(appID string) ([]models.Webhook, error) {
+	return w.queryWebhooks(`
+	SELECT id,app_id,url,secret,events,created_at
+	FROM webhook
+	WHERE LOWER(app_id)=$1
+	ORDER BY created_at, id;`, strings.ToLower(appID))
+}
+
+// GetWebhooksByEvent returns the webhooks of an app subscribed to a type of event
+func (w *webhooksPostgreSQLRepository) GetWebhooksByEvent(appID, eventType string) ([]models.Webhook, error) {
+	return w.queryWebhooks(`
+	SELECT id,app_id,url,secret,events,created_at
+	FROM webhook
+	WHERE LOWER(app_id)=$1 AND $2 = ANY(events)
+	ORDER BY created_at, id;`, strings.ToLower(appID), eventType)
+}
+
+func (w *webhooksPostgreSQLRepository) queryWebhooks(query string, args ...interface{}) ([]models.Webhook, error) {
+	rows, err := w.db.Query(query, args...)
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		var webhook models.Webhook
+		if err := rows.Scan(&webhook.ID, &webhook.AppID, &webhook.URL, &webhook.Secret, pq.Array(&webhook.Events), &webhook.CreatedAt); err != nil {
+			log.Error(err)
+			return nil, models.ErrDatabaseError
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	return webhooks, nil
+}
+
+// ExportAppWebhooks adds the webhooks of an app to the archive of its hard delete. The secrets are not exported,
+// as the API does not return them after the creation of a webhook.
+func ExportAppWebhooks(tx *sql.Tx, appID string, archive *models.AppArchive) error {
+	rows, err := tx.Query(`
+	SELECT id,app_id,url,events,created_at
+	FROM webhook
+	WHERE app_id=$1
+	ORDER BY created_at, id;`, appID)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		var webhook models.Webhook
+		if err := rows.Scan(&webhook.ID, &webhook.AppID, &webhook.URL, pq.Array(&webhook.Events), &webhook.CreatedAt); err != nil {
+			return err
+		}
+		archive.Webhooks = append(archive.Webhooks, webhook)
+	}
+
+	return rows.Err()
+}
+
+// GetWebhookByID retrieves a webhook by id from the database
+func (w *webhooksPostgreSQLRepository) GetWebhookByID(id string) (*models.Webhook, error) {
+	var webhook models.Webhook
+
+	err := w.db.QueryRow(`SELECT id,app_id,url,secret,events,created_at FROM webhook WHERE id=$1;`, id).
+		Scan(&webhook.ID, &webhook.AppID, &webhook.URL, &webhook.Secret, pq.Array(&webhook.Events), &webhook.CreatedAt)
+
+	if err != nil {
+		log.Error(err)
+		if err == sql.ErrNoRows {
+			return nil, models.ErrNotFound
+		}
+		return nil, models.ErrDatabaseError
+	}
+
+	return &webhook, nil
+}
+
+// CreateWebhook creates a new webhook row in the webhook table
+func (w *webhooksPostgreSQLRepository) CreateWebhook(webhook models.Webhook) error {
+	_, err := w.db.Exec(`
+	INSERT INTO webhook(id,app_id,url,secret,events,created_at)
+	VALUES($1, $2, $3, $4, $5, $6);`, webhook.ID, webhook.AppID, webhook.URL, webhook.Secret, pq.Array(webhook.Events), webhook.CreatedAt)
+
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// DeleteWebhookByID deletes a webhook with its deliveries
+func (w *webhooksPostgreSQLRepository) DeleteWebhookByID(id string) error {
+	result, err := w.db.Exec(`DELETE FROM webhook WHERE id=$1;`, id)
+
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	if deleted, err := result.RowsAffected(); err == nil && deleted == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateDeliveries adds the deliveries to the outbox, in a single transaction
+func (w *webhooksPostgreSQLRepository) CreateDeliveries(deliveries []models.WebhookDelivery) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	for _, d := range deliveries {
+		_, err := tx.Exec(`
+		INSERT INTO webhook_delivery(id,webhook_id,event_id,event_type,payload,status,attempts,next_attempt_at,replay_of,created_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`,
+			d.ID, d.WebhookID, d.EventID, d.EventType, d.Payload, d.Status, d.Attempts, d.NextAttemptAt, nullString(d.ReplayOf), d.CreatedAt)
+
+		if err != nil {
+			log.Error(err)
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Error(rbErr)
+			}
+			return models.ErrDatabaseError
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// ClaimDueDeliveries returns up to limit pending deliveries whose next attempt is due and postpones it by the lease,
+// so they are not claimed again, e.g. by another instance of the service, while they are being sent.
+// The deliveries locked by another claim are skipped.
+func (w *webhooksPostgreSQLRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	return w.queryDeliveries(`
+	UPDATE webhook_delivery
+	SET next_attempt_at=$2
+	WHERE id IN (
+		SELECT id FROM webhook_delivery
+		WHERE status='pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING `+deliveryColumns+`;`, now, now.Add(lease), limit)
+}
+
+// UpdateDelivery records the outcome of an attempt of a delivery
+func (w *webhooksPostgreSQLRepository) UpdateDelivery(d models.WebhookDelivery) error {
+	_, err := w.db.Exec(`
+	UPDATE webhook_delivery
+	SET status=$1,attempts=$2,next_attempt_at=$3,last_attempt_at=$4,response_status=$5,last_error=$6,delivered_at=$7
+	WHERE id=$8;`, d.Status, d.Attempts, d.NextAttemptAt, nullTime(d.LastAttemptAt), d.ResponseStatus, d.LastError, nullTime(d.DeliveredAt), d.ID)
+
+	if err != nil {
+		log.Error(err)
+		return models.ErrDatabaseError
+	}
+
+	return nil
+}
+
+// GetDeliveriesByWebhookID returns the last deliveries of a webhook, the most recent first
+func (w *webhooksPostgreSQLRepository) GetDeliveriesByWebhookID(webhookID string, limit int) ([]models.WebhookDelivery, error) {
+	return w.queryDeliveries(`
+	SELECT `+deliveryColumns+`
+	FROM webhook_delivery
+	WHERE webhook_id=$1
+	ORDER BY created_at DESC, id
+	LIMIT $2;`, webhookID, limit)
+}
+
+// GetDeliveryByID retrieves a delivery by id from the database
+func (w *webhooksPostgreSQLRepository) GetDeliveryByID(id string) (*models.WebhookDelivery, error) {
+	d, err := scanDelivery(w.db.QueryRow(`SELECT `+deliveryColumns+` FROM webhook_delivery WHERE id=$1;`, id))
+
+	if err != nil {
+		log.Error(err)
+		if err == sql.ErrNoRows {
+			return nil, models.ErrNotFound
+		}
+		return nil, models.ErrDatabaseError
+	}
+
+	return d, nil
+}
+
+func (w *webhooksPostgreSQLRepository) queryDeliveries(query string, args ...interface{}) ([]models.WebhookDelivery, error) {
+	rows, err := w.db.Query(query, args...)
+
+	if err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			log.Error(err)
+			return nil, models.ErrDatabaseError
+		}
+		deliveries = append(deliveries, *d)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(err)
+		return nil, models.ErrDatabaseError
+	}
+
+	return deliveries, nil
+}
+
+// scanner is a *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanDelivery scans the deliveryColumns of a row
+func scanDelivery(row scanner) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var lastAttemptAt, deliveredAt pq.NullTime
+	var replayOf sql.NullString
+
+	err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&lastAttemptAt, &d.ResponseStatus, &d.LastError, &replayOf, &d.CreatedAt, &deliveredAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastAttemptAt.Valid {
+		d.LastAttemptAt = &lastAttemptAt.Time
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	d.ReplayOf = replayOf.String
+
+	return &d, nil
+}
+
+// nullString stores an empty string as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullTime stores a nil time as NULL
+func nullTime(t *time.Time) pq.NullTime {
+	if t == nil {
+		return pq.NullTime{}
+	}
+	return pq.NullTime{Time: *t, Valid: true}
+}
@@ -0,0 +1,397 @@
+package webhooks
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	_ "github.com/lib/pq"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+var (
+	getWebhooksByEventQueryString = `SELECT id,app_id,url,secret,events,created_at
+	FROM webhook
+	WHERE LOWER\(app_id\)=\$1 AND \$2 = ANY\(events\)`
+	createWebhookQueryString      = `INSERT INTO webhook\(id,app_id,url,secret,events,created_at\)`
+	deleteWebhookQueryString      = `DELETE FROM webhook WHERE id=\$1;`
+	createDeliveryQueryString     = `INSERT INTO webhook_delivery`
+	claimDueDeliveriesQueryString = `UPDATE webhook_delivery
+	SET next_attempt_at=\$2
+	WHERE id IN \(
+		SELECT id FROM webhook_delivery
+		WHERE status='pending' AND next_attempt_at <= \$1
+		ORDER BY next_attempt_at
+		LIMIT \$3
+		FOR UPDATE SKIP LOCKED
+	\)`
+	updateDeliveryQueryString = `UPDATE webhook_delivery
+	SET status=\$1,attempts=\$2,next_attempt_at=\$3,last_attempt_at=\$4,response_status=\$5,last_error=\$6,delivered_at=\$7
+	WHERE id=\$8;`
+
+	webhookRowColumns  = []string{"id", "app_id", "url", "secret", "events", "created_at"}
+	deliveryRowColumns = []string{"id", "webhook_id", "event_id", "event_type", "payload", "status", "attempts", "next_attempt_at",
+		"last_attempt_at", "response_status", "last_error", "replay_of", "created_at", "delivered_at"}
+)
+
+func getMockWebhook() models.Webhook {
+	return models.Webhook{
+		ID:        "c3a1cbb3-3f1e-4d0c-9f53-5a8ea3b9a7f2",
+		AppID:     "com.aerogear.mobile_app_one",
+		URL:       "https://hooks.example.com/mobile-security",
+		Secret:    "5f2b0d9c1e8a4b7d",
+		Events:    []string{models.EventVersionDisabled, models.EventDeviceBlocked},
+		CreatedAt: time.Date(2019, time.June, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func Test_webhooksPostgreSQLRepository_GetWebhooksByEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	webhook := getMockWebhook()
+
+	tests := []struct {
+		name    string
+		expect  func()
+		want    []models.Webhook
+		wantErr bool
+	}{
+		{
+			name: "Should return the webhooks subscribed to the event",
+			expect: func() {
+				rows := sqlmock.NewRows(webhookRowColumns).
+					AddRow(webhook.ID, webhook.AppID, webhook.URL, webhook.Secret, []byte("{version.disabled,device.blocked}"), webhook.CreatedAt)
+				mock.ExpectQuery(getWebhooksByEventQueryString).WithArgs("com.aerogear.mobile_app_one", models.EventVersionDisabled).WillReturnRows(rows)
+			},
+			want: []models.Webhook{webhook},
+		},
+		{
+			name: "Should return an empty list when no webhook is subscribed",
+			expect: func() {
+				mock.ExpectQuery(getWebhooksByEventQueryString).WillReturnRows(sqlmock.NewRows(webhookRowColumns))
+			},
+			want: []models.Webhook{},
+		},
+		{
+			name: "Should return a database error when the query fails",
+			expect: func() {
+				mock.ExpectQuery(getWebhooksByEventQueryString).WillReturnError(models.ErrDatabaseError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			got, err := NewPostgreSQLRepository(db).GetWebhooksByEvent("COM.aerogear.mobile_app_one", models.EventVersionDisabled)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("webhooksPostgreSQLRepository.GetWebhooksByEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("webhooksPostgreSQLRepository.GetWebhooksByEvent() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestExportAppWebhooks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	webhook := getMockWebhook()
+	exported := webhook
+	exported.Secret = ""
+
+	tests := []struct {
+		name    string
+		expect  func()
+		want    []models.Webhook
+		wantErr bool
+	}{
+		{
+			name: "Should add the webhooks of the app to the archive without their secrets",
+			expect: func() {
+				rows := sqlmock.NewRows([]string{"id", "app_id", "url", "events", "created_at"}).
+					AddRow(webhook.ID, webhook.AppID, webhook.URL, []byte("{version.disabled,device.blocked}"), webhook.CreatedAt)
+				mock.ExpectQuery(`SELECT id,app_id,url,events,created_at FROM webhook WHERE app_id=\$1`).WithArgs(webhook.AppID).WillReturnRows(rows)
+			},
+			want: []models.Webhook{exported},
+		},
+		{
+			name: "Should return the error when the query fails",
+			expect: func() {
+				mock.ExpectQuery(`FROM webhook WHERE app_id=\$1`).WillReturnError(models.ErrDatabaseError)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			tt.expect()
+			mock.ExpectRollback()
+
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatalf("Unexpected error beginning a stub transaction: %v", err)
+			}
+
+			archive := models.AppArchive{}
+			err = ExportAppWebhooks(tx, webhook.AppID, &archive)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExportAppWebhooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(archive.Webhooks, tt.want) {
+				t.Errorf("ExportAppWebhooks() archived = %v, want %v", archive.Webhooks, tt.want)
+			}
+
+			if err := tx.Rollback(); err != nil {
+				t.Errorf("Unexpected error rolling back the stub transaction: %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_webhooksPostgreSQLRepository_CreateWebhook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	webhook := getMockWebhook()
+
+	mock.ExpectExec(createWebhookQueryString).
+		WithArgs(webhook.ID, webhook.AppID, webhook.URL, webhook.Secret, "{\"version.disabled\",\"device.blocked\"}", webhook.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	if err := NewPostgreSQLRepository(db).CreateWebhook(webhook); err != nil {
+		t.Errorf("webhooksPostgreSQLRepository.CreateWebhook() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_webhooksPostgreSQLRepository_DeleteWebhookByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	tests := []struct {
+		name    string
+		expect  func()
+		wantErr error
+	}{
+		{
+			name: "Should delete the webhook",
+			expect: func() {
+				mock.ExpectExec(deleteWebhookQueryString).WithArgs("c3a1cbb3-3f1e-4d0c-9f53-5a8ea3b9a7f2").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Should return not found when there is no webhook",
+			expect: func() {
+				mock.ExpectExec(deleteWebhookQueryString).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: models.ErrNotFound,
+		},
+		{
+			name: "Should return a database error when the delete fails",
+			expect: func() {
+				mock.ExpectExec(deleteWebhookQueryString).WillReturnError(models.ErrInternalServerError)
+			},
+			wantErr: models.ErrDatabaseError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			if err := NewPostgreSQLRepository(db).DeleteWebhookByID("c3a1cbb3-3f1e-4d0c-9f53-5a8ea3b9a7f2"); err != tt.wantErr {
+				t.Errorf("webhooksPostgreSQLRepository.DeleteWebhookByID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_webhooksPostgreSQLRepository_CreateDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	deliveries := []models.WebhookDelivery{
+		newDelivery("c3a1cbb3-3f1e-4d0c-9f53-5a8ea3b9a7f2", "0b6f2e1c-9a8d-4c7b-8e5f-3d2c1b0a9f8e", models.EventAppDeleted, []byte("{}")),
+		newDelivery("5a4f4e3d-8c2b-4a0f-9e8d-7c6b5a4f4e3d", "0b6f2e1c-9a8d-4c7b-8e5f-3d2c1b0a9f8e", models.EventAppDeleted, []byte("{}")),
+	}
+
+	tests := []struct {
+		name    string
+		expect  func()
+		wantErr bool
+	}{
+		{
+			name: "Should add the deliveries in a transaction",
+			expect: func() {
+				mock.ExpectBegin()
+				for _, d := range deliveries {
+					mock.ExpectExec(createDeliveryQueryString).
+						WithArgs(d.ID, d.WebhookID, d.EventID, d.EventType, d.Payload, models.DeliveryPending, 0, d.NextAttemptAt, nil, d.CreatedAt).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Should add none of the deliveries when one fails",
+			expect: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(createDeliveryQueryString).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(createDeliveryQueryString).WillReturnError(models.ErrInternalServerError)
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expect()
+
+			if err := NewPostgreSQLRepository(db).CreateDeliveries(deliveries); (err != nil) != tt.wantErr {
+				t.Errorf("webhooksPostgreSQLRepository.CreateDeliveries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func Test_webhooksPostgreSQLRepository_ClaimDueDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	now := time.Date(2019, time.June, 1, 10, 0, 0, 0, time.UTC)
+	attemptedAt := now.Add(-time.Minute)
+
+	rows := sqlmock.NewRows(deliveryRowColumns).
+		AddRow("7d4c8a3e-2b1f-4e6a-9c5d-8f0e1a2b3c4d", "c3a1cbb3-3f1e-4d0c-9f53-5a8ea3b9a7f2", "0b6f2e1c-9a8d-4c7b-8e5f-3d2c1b0a9f8e",
+			models.EventAppDeleted, []byte("{}"), models.DeliveryPending, 0, now, nil, 0, "", nil, now, nil).
+		AddRow("9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b", "c3a1cbb3-3f1e-4d0c-9f53-5a8ea3b9a7f2", "0b6f2e1c-9a8d-4c7b-8e5f-3d2c1b0a9f8e",
+			models.EventAppDeleted, []byte("{}"), models.DeliveryPending, 1, now.Add(time.Minute), attemptedAt, 500,
+			"unexpected response status 500", "7d4c8a3e-2b1f-4e6a-9c5d-8f0e1a2b3c4d", now, nil)
+
+	mock.ExpectQuery(claimDueDeliveriesQueryString).WithArgs(now, now.Add(time.Minute), 20).WillReturnRows(rows)
+
+	got, err := NewPostgreSQLRepository(db).ClaimDueDeliveries(now, time.Minute, 20)
+	if err != nil {
+		t.Fatalf("webhooksPostgreSQLRepository.ClaimDueDeliveries() error = %v", err)
+	}
+
+	want := []models.WebhookDelivery{
+		{
+			ID:            "7d4c8a3e-2b1f-4e6a-9c5d-8f0e1a2b3c4d",
+			WebhookID:     "c3a1cbb3-3f1e-4d0c-9f53-5a8ea3b9a7f2",
+			EventID:       "0b6f2e1c-9a8d-4c7b-8e5f-3d2c1b0a9f8e",
+			EventType:     models.EventAppDeleted,
+			Payload:       []byte("{}"),
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		},
+		{
+			ID:             "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b",
+			WebhookID:      "c3a1cbb3-3f1e-4d0c-9f53-5a8ea3b9a7f2",
+			EventID:        "0b6f2e1c-9a8d-4c7b-8e5f-3d2c1b0a9f8e",
+			EventType:      models.EventAppDeleted,
+			Payload:        []byte("{}"),
+			Status:         models.DeliveryPending,
+			Attempts:       1,
+			NextAttemptAt:  now.Add(time.Minute),
+			LastAttemptAt:  &attemptedAt,
+			ResponseStatus: 500,
+			LastError:      "unexpected response status 500",
+			ReplayOf:       "7d4c8a3e-2b1f-4e6a-9c5d-8f0e1a2b3c4d",
+			CreatedAt:      now,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("webhooksPostgreSQLRepository.ClaimDueDeliveries() = %v, want %v", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_webhooksPostgreSQLRepository_UpdateDelivery(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Unexpected error opening a stub database connection: %v", err)
+	}
+
+	defer db.Close()
+
+	now := time.Date(2019, time.June, 1, 10, 0, 0, 0, time.UTC)
+	d := models.WebhookDelivery{
+		ID:             "7d4c8a3e-2b1f-4e6a-9c5d-8f0e1a2b3c4d",
+		Status:         models.DeliveryDelivered,
+		Attempts:       2,
+		NextAttemptAt:  now,
+		LastAttemptAt:  &now,
+		ResponseStatus: 204,
+		DeliveredAt:    &now,
+	}
+
+	mock.ExpectExec(updateDeliveryQueryString).
+		WithArgs(models.DeliveryDelivered, 2, now, now, 204, "", now, d.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := NewPostgreSQLRepository(db).UpdateDelivery(d); err != nil {
+		t.Errorf("webhooksPostgreSQLRepository.UpdateDelivery() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
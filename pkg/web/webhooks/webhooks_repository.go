@@ -0,0 +1,21 @@
+package webhooks
+
+import (
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// Repository represent the webhook's repository contract
+type Repository interface {
+	GetWebhooksByAppID(appID string) ([]models.Webhook, error)
+	GetWebhookByID(id string) (*models.Webhook, error)
+	GetWebhooksByEvent(appID, eventType string) ([]models.Webhook, error)
+	CreateWebhook(webhook models.Webhook) error
+	DeleteWebhookByID(id string) error
+	CreateDeliveries(deliveries []models.WebhookDelivery) error
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(delivery models.WebhookDelivery) error
+	GetDeliveriesByWebhookID(webhookID string, limit int) ([]models.WebhookDelivery, error)
+	GetDeliveryByID(id string) (*models.WebhookDelivery, error)
+}
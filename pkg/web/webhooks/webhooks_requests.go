@@ -0,0 +1,46 @@
+package webhooks
+
+import "github.com/aerogear/mobile-security-service/pkg/models"
+
+// The requests of the webhooks endpoints, with the validation rules of each field.
+// They are validated with the validator registered in echo before being mapped to the models.
+
+// appIDParams holds the id path parameter of the webhooks of an app
+type appIDParams struct {
+	ID string `param:"id" validate:"uuid"`
+}
+
+// webhookParams holds the path parameters of the endpoints of a single webhook
+type webhookParams struct {
+	ID        string `param:"id" validate:"uuid"`
+	WebhookID string `param:"webhookId" validate:"uuid"`
+}
+
+// deliveryParams holds the path parameters of the endpoints of a single delivery
+type deliveryParams struct {
+	ID         string `param:"id" validate:"uuid"`
+	WebhookID  string `param:"webhookId" validate:"uuid"`
+	DeliveryID string `param:"deliveryId" validate:"uuid"`
+}
+
+// createWebhookRequest is the body of POST /apps/{id}/webhooks
+// swagger:model CreateWebhookRequest
+type createWebhookRequest struct {
+	// URL receives the events in POST requests
+	// required: true
+	URL string `json:"url" validate:"required,url"`
+	// Events are the types of the events sent to the webhook
+	// required: true
+	Events []string `json:"events" validate:"required,min=1,unique,dive,oneof=version.disabled version.enabled app.deleted app.restored device.blocked device.tampered"`
+	// Secret signs the deliveries. A random one is generated when it is not set.
+	Secret string `json:"secret,omitempty" validate:"omitempty,min=16"`
+}
+
+// toModel maps the request to the webhook to create
+func (r *createWebhookRequest) toModel() models.Webhook {
+	return models.Webhook{
+		URL:    r.URL,
+		Events: r.Events,
+		Secret: r.Secret,
+	}
+}
@@ -0,0 +1,115 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// The responses of the webhooks endpoints. The secret of a webhook is only returned when it is created.
+
+// webhookResponse is a webhook returned by GET /apps/{id}/webhooks
+// swagger:model WebhookResponse
+type webhookResponse struct {
+	ID        string    `json:"id"`
+	AppID     string    `json:"appId"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// createdWebhookResponse is the response of POST /apps/{id}/webhooks
+// swagger:model CreatedWebhookResponse
+type createdWebhookResponse struct {
+	webhookResponse
+	// Secret signs the deliveries. It is not returned again.
+	Secret string `json:"secret"`
+}
+
+// deliveryResponse is a delivery of an event to a webhook
+// swagger:model DeliveryResponse
+type deliveryResponse struct {
+	ID        string `json:"id"`
+	WebhookID string `json:"webhookId"`
+	EventID   string `json:"eventId"`
+	EventType string `json:"eventType"`
+	// Status is pending, delivered or failed
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	// NextAttemptAt is set while the delivery is pending
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt *time.Time `json:"lastAttemptAt,omitempty"`
+	// ResponseStatus is the status of the response to the last attempt, when there was one
+	ResponseStatus int    `json:"responseStatus,omitempty"`
+	LastError      string `json:"lastError,omitempty"`
+	// ReplayOf is the id of the delivery replayed by this one
+	ReplayOf    string     `json:"replayOf,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+	// Payload is the event sent as the body of the requests
+	Payload map[string]interface{} `json:"payload"`
+}
+
+// newWebhookResponse maps a webhook to its response
+func newWebhookResponse(webhook models.Webhook) webhookResponse {
+	return webhookResponse{
+		ID:        webhook.ID,
+		AppID:     webhook.AppID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+// newWebhooksResponse maps a list of webhooks to their responses
+func newWebhooksResponse(webhooks []models.Webhook) []webhookResponse {
+	r := make([]webhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		r = append(r, newWebhookResponse(webhook))
+	}
+	return r
+}
+
+// newCreatedWebhookResponse maps a created webhook to its response, with its secret
+func newCreatedWebhookResponse(webhook models.Webhook) createdWebhookResponse {
+	return createdWebhookResponse{webhookResponse: newWebhookResponse(webhook), Secret: webhook.Secret}
+}
+
+// newDeliveryResponse maps a delivery to its response
+func newDeliveryResponse(d models.WebhookDelivery) deliveryResponse {
+	r := deliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		ReplayOf:       d.ReplayOf,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+		Payload:        map[string]interface{}{},
+	}
+
+	if d.Status == models.DeliveryPending {
+		nextAttemptAt := d.NextAttemptAt
+		r.NextAttemptAt = &nextAttemptAt
+	}
+
+	// the payload is the JSON of an event, stored by the service
+	_ = json.Unmarshal(d.Payload, &r.Payload)
+
+	return r
+}
+
+// newDeliveriesResponse maps a list of deliveries to their responses
+func newDeliveriesResponse(deliveries []models.WebhookDelivery) []deliveryResponse {
+	r := make([]deliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		r = append(r, newDeliveryResponse(d))
+	}
+	return r
+}
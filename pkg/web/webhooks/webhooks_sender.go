@@ -0,0 +1,136 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+)
+
+// The headers of the requests delivering an event to a webhook
+const (
+	// EventHeader is the type of the event
+	EventHeader = "X-Mss-Event"
+	// DeliveryHeader is the id of the delivery, which is different for each replay of an event
+	DeliveryHeader = "X-Mss-Delivery"
+	// SignatureHeader is the HMAC-SHA256 signature of the body with the secret of the webhook, as returned by Sign
+	SignatureHeader = "X-Mss-Signature"
+)
+
+const (
+	// claimLimit is the number of deliveries sent by each call of DeliverDue
+	claimLimit = 20
+	// maxBackoff is the longest delay between two attempts of a delivery
+	maxBackoff = time.Hour
+	// maxResponseSize is the size of the response of a webhook which is read, the rest is ignored
+	maxResponseSize = 64 << 10
+)
+
+// Sign returns the signature of a payload with the secret of a webhook, sent in the SignatureHeader.
+// The webhooks should compute it from the body they receive and compare both in constant time.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// sender makes the attempts of the deliveries
+type sender struct {
+	client       *http.Client
+	timeout      time.Duration
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+func newSender(c config.WebhooksConfig) *sender {
+	return &sender{
+		client:       &http.Client{Timeout: c.Timeout},
+		timeout:      c.Timeout,
+		maxAttempts:  c.MaxAttempts,
+		retryBackoff: c.RetryBackoff,
+	}
+}
+
+// lease is how long the claimed deliveries are not claimed again, long enough to send all of them
+func (s *sender) lease() time.Duration {
+	return time.Duration(claimLimit+1) * s.timeout
+}
+
+// send makes an attempt of a delivery and returns it updated with the outcome. A delivery which is not
+// accepted is retried with an exponential backoff until it fails after the maximum number of attempts.
+func (s *sender) send(webhook models.Webhook, d models.WebhookDelivery) models.WebhookDelivery {
+	now := time.Now().UTC()
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.ResponseStatus = 0
+	d.LastError = ""
+
+	status, err := s.post(webhook, d)
+	d.ResponseStatus = status
+
+	switch {
+	case err == nil && status >= 200 && status < 300:
+		d.Status = models.DeliveryDelivered
+		d.DeliveredAt = &now
+		return d
+	case err != nil:
+		d.LastError = err.Error()
+	default:
+		d.LastError = fmt.Sprintf("unexpected response status %v", status)
+	}
+
+	if d.Attempts >= s.maxAttempts {
+		d.Status = models.DeliveryFailed
+		return d
+	}
+
+	d.NextAttemptAt = now.Add(s.backoff(d.Attempts))
+	return d
+}
+
+// post sends the payload of a delivery to a webhook and returns the status of the response
+func (s *sender) post(webhook models.Webhook, d models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mobile-security-service")
+	req.Header.Set(EventHeader, d.EventType)
+	req.Header.Set(DeliveryHeader, d.ID)
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// read the response so the connection can be reused
+	if _, err := io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseSize)); err != nil {
+		return resp.StatusCode, err
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the next attempt of a delivery, doubled after each attempt
+func (s *sender) backoff(attempts int) time.Duration {
+	delay := s.retryBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
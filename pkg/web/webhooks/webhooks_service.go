@@ -0,0 +1,232 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+)
+
+const (
+	// deliveriesLimit is the number of deliveries returned in the log of a webhook
+	deliveriesLimit = 100
+	// secretLength is the number of random bytes of a generated secret
+	secretLength = 32
+)
+
+type (
+	// Service defines the interface methods to be used
+	Service interface {
+		GetWebhooks(id string) ([]models.Webhook, error)
+		CreateWebhook(id string, webhook models.Webhook) (*models.Webhook, error)
+		DeleteWebhook(id, webhookID string) error
+		GetDeliveries(id, webhookID string) ([]models.WebhookDelivery, error)
+		ReplayDelivery(id, webhookID, deliveryID string) (*models.WebhookDelivery, error)
+		Publish(event models.Event) error
+		DeliverDue() (int, error)
+	}
+
+	webhooksService struct {
+		repository     Repository
+		appsRepository apps.Repository
+		sender         *sender
+	}
+)
+
+// NewService instantiates this service. The apps of the webhooks are read from the apps repository.
+// The events of the apps are given to Publish by the subscriber returned by NewEventHandler.
+func NewService(repository Repository, appsRepository apps.Repository, c config.WebhooksConfig) Service {
+	return &webhooksService{
+		repository:     repository,
+		appsRepository: appsRepository,
+		sender:         newSender(c),
+	}
+}
+
+// GetWebhooks returns the webhooks of the app with the given id
+func (w *webhooksService) GetWebhooks(id string) ([]models.Webhook, error) {
+	app, err := w.appsRepository.GetActiveAppByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.repository.GetWebhooksByAppID(app.AppID)
+}
+
+// CreateWebhook subscribes a URL to some types of the events of the app with the given id.
+// A secret is generated when the webhook has none. The webhook is returned with its secret.
+func (w *webhooksService) CreateWebhook(id string, webhook models.Webhook) (*models.Webhook, error) {
+	app, err := w.appsRepository.GetActiveAppByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the admin users are trusted with the target of the deliveries, so the internal hosts are not rejected
+	if u, err := url.Parse(webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, models.NewValidationError(models.FieldError{Field: "url", Message: "url must be an http or https URL"})
+	}
+
+	if webhook.Secret == "" {
+		if webhook.Secret, err = newSecret(); err != nil {
+			return nil, models.ErrInternalServerError.Wrap(err)
+		}
+	}
+
+	webhook.ID = helpers.GetUUID()
+	webhook.AppID = app.AppID
+	webhook.CreatedAt = time.Now().UTC()
+
+	if err := w.repository.CreateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// DeleteWebhook deletes a webhook of the app with the given id, with its deliveries
+func (w *webhooksService) DeleteWebhook(id, webhookID string) error {
+	if _, err := w.getWebhook(id, webhookID); err != nil {
+		return err
+	}
+
+	return w.repository.DeleteWebhookByID(webhookID)
+}
+
+// GetDeliveries returns the last deliveries of a webhook of the app with the given id, the most recent first
+func (w *webhooksService) GetDeliveries(id, webhookID string) ([]models.WebhookDelivery, error) {
+	if _, err := w.getWebhook(id, webhookID); err != nil {
+		return nil, err
+	}
+
+	return w.repository.GetDeliveriesByWebhookID(webhookID, deliveriesLimit)
+}
+
+// ReplayDelivery sends again the event of a delivery, whatever its status, with a new delivery
+func (w *webhooksService) ReplayDelivery(id, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	if _, err := w.getWebhook(id, webhookID); err != nil {
+		return nil, err
+	}
+
+	original, err := w.repository.GetDeliveryByID(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.WebhookID != webhookID {
+		return nil, models.ErrNotFound
+	}
+
+	replay := newDelivery(webhookID, original.EventID, original.EventType, original.Payload)
+	replay.ReplayOf = original.ID
+
+	if err := w.repository.CreateDeliveries([]models.WebhookDelivery{replay}); err != nil {
+		return nil, err
+	}
+
+	return &replay, nil
+}
+
+// Publish adds a delivery of the event to the outbox for each webhook of its app subscribed to its type.
+// The deliveries are sent by DeliverDue.
+func (w *webhooksService) Publish(event models.Event) error {
+	webhooks, err := w.repository.GetWebhooksByEvent(event.AppID, event.Type)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return models.ErrInternalServerError.Wrap(err)
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, newDelivery(webhook.ID, event.ID, event.Type, payload))
+	}
+
+	return w.repository.CreateDeliveries(deliveries)
+}
+
+// DeliverDue sends the pending deliveries whose next attempt is due and records the outcome of each attempt.
+// It returns the number of deliveries sent, successfully or not.
+func (w *webhooksService) DeliverDue() (int, error) {
+	now := time.Now().UTC()
+
+	deliveries, err := w.repository.ClaimDueDeliveries(now, w.sender.lease(), claimLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks := map[string]*models.Webhook{}
+	for _, d := range deliveries {
+		webhook, ok := webhooks[d.WebhookID]
+		if !ok {
+			// the webhook may have been deleted with its deliveries since they were claimed
+			if webhook, err = w.repository.GetWebhookByID(d.WebhookID); err != nil && !errors.Is(err, models.ErrNotFound) {
+				return 0, err
+			}
+			webhooks[d.WebhookID] = webhook
+		}
+		if webhook == nil {
+			continue
+		}
+
+		d = w.sender.send(*webhook, d)
+		if err := w.repository.UpdateDelivery(d); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// getWebhook returns a webhook of the app with the given id. The webhooks of other apps are not found.
+func (w *webhooksService) getWebhook(id, webhookID string) (*models.Webhook, error) {
+	app, err := w.appsRepository.GetActiveAppByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := w.repository.GetWebhookByID(webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(webhook.AppID, app.AppID) {
+		return nil, models.ErrNotFound
+	}
+
+	return webhook, nil
+}
+
+// newDelivery returns a pending delivery of an event to a webhook, due now
+func newDelivery(webhookID, eventID, eventType string, payload []byte) models.WebhookDelivery {
+	now := time.Now().UTC()
+
+	return models.WebhookDelivery{
+		ID:            helpers.GetUUID(),
+		WebhookID:     webhookID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// newSecret returns a random secret to sign the deliveries of a webhook
+func newSecret() (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,368 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aerogear/mobile-security-service/pkg/config"
+	"github.com/aerogear/mobile-security-service/pkg/helpers"
+	"github.com/aerogear/mobile-security-service/pkg/models"
+	"github.com/aerogear/mobile-security-service/pkg/web/apps"
+)
+
+var testConfig = config.WebhooksConfig{
+	DeliveryInterval: time.Second,
+	Timeout:          time.Second,
+	MaxAttempts:      3,
+	RetryBackoff:     time.Minute,
+}
+
+// newAppsRepository returns an apps repository with the mock app and no other
+func newAppsRepository() *apps.RepositoryMock {
+	return &apps.RepositoryMock{
+		GetActiveAppByIDFunc: func(ID string) (*models.App, error) {
+			if app := helpers.GetMockApp(); app.ID == ID {
+				return app, nil
+			}
+			return nil, models.ErrNotFound
+		},
+	}
+}
+
+func Test_webhooksService_CreateWebhook(t *testing.T) {
+	app := helpers.GetMockApp()
+
+	tests := []struct {
+		name    string
+		id      string
+		webhook models.Webhook
+		wantErr error
+	}{
+		{
+			name:    "Should create a webhook of the app with a generated secret",
+			id:      app.ID,
+			webhook: models.Webhook{URL: "https://hooks.example.com/mss", Events: []string{models.EventAppDeleted}},
+		},
+		{
+			name:    "Should create a webhook with the given secret",
+			id:      app.ID,
+			webhook: models.Webhook{URL: "http://hooks.example.com/mss", Events: []string{models.EventAppDeleted}, Secret: "0123456789abcdef"},
+		},
+		{
+			name:    "Should reject a URL which is not http or https",
+			id:      app.ID,
+			webhook: models.Webhook{URL: "ftp://hooks.example.com/mss", Events: []string{models.EventAppDeleted}},
+			wantErr: models.ErrValidationFailed,
+		},
+		{
+			name:    "Should return not found when the app does not exist",
+			id:      "53d4e6c6-6b7f-4a50-8d2c-0fe5b4c1a2b9",
+			webhook: models.Webhook{URL: "https://hooks.example.com/mss", Events: []string{models.EventAppDeleted}},
+			wantErr: models.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &RepositoryMock{
+				CreateWebhookFunc: func(webhook models.Webhook) error {
+					return nil
+				},
+			}
+
+			got, err := NewService(repo, newAppsRepository(), testConfig).CreateWebhook(tt.id, tt.webhook)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("webhooksService.CreateWebhook() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if len(repo.CreateWebhookCalls()) > 0 {
+					t.Error("webhooksService.CreateWebhook() created the webhook")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("webhooksService.CreateWebhook() error = %v", err)
+			}
+
+			if got.AppID != app.AppID || got.ID == "" || got.CreatedAt.IsZero() {
+				t.Errorf("webhooksService.CreateWebhook() = %v, want a webhook of %v", got, app.AppID)
+			}
+			if tt.webhook.Secret != "" && got.Secret != tt.webhook.Secret {
+				t.Errorf("webhooksService.CreateWebhook() secret = %v, want %v", got.Secret, tt.webhook.Secret)
+			}
+			if tt.webhook.Secret == "" && len(got.Secret) != 2*secretLength {
+				t.Errorf("webhooksService.CreateWebhook() secret = %v, want a generated secret", got.Secret)
+			}
+			if calls := repo.CreateWebhookCalls(); len(calls) != 1 || !reflect.DeepEqual(calls[0].Webhook, *got) {
+				t.Errorf("webhooksService.CreateWebhook() created %v, want %v", calls, *got)
+			}
+		})
+	}
+}
+
+func Test_webhooksService_getWebhook(t *testing.T) {
+	app := helpers.GetMockApp()
+	webhook := getMockWebhook()
+
+	repo := &RepositoryMock{
+		GetWebhookByIDFunc: func(id string) (*models.Webhook, error) {
+			if id == webhook.ID {
+				return &webhook, nil
+			}
+			other := getMockWebhook()
+			other.AppID = "com.aerogear.other_app"
+			return &other, nil
+		},
+		DeleteWebhookByIDFunc: func(id string) error {
+			return nil
+		},
+	}
+	s := NewService(repo, newAppsRepository(), testConfig)
+
+	if err := s.DeleteWebhook(app.ID, webhook.ID); err != nil {
+		t.Errorf("webhooksService.DeleteWebhook() error = %v", err)
+	}
+
+	// the webhooks of the other apps are not found
+	if err := s.DeleteWebhook(app.ID, "5a4f4e3d-8c2b-4a0f-9e8d-7c6b5a4f4e3d"); err != models.ErrNotFound {
+		t.Errorf("webhooksService.DeleteWebhook() error = %v, want %v", err, models.ErrNotFound)
+	}
+
+	if calls := repo.DeleteWebhookByIDCalls(); len(calls) != 1 || calls[0].ID != webhook.ID {
+		t.Errorf("webhooksService.DeleteWebhook() deleted %v, want only %v", calls, webhook.ID)
+	}
+}
+
+func Test_webhooksService_Publish(t *testing.T) {
+	webhook := getMockWebhook()
+	event := models.NewEvent(models.EventVersionDisabled, webhook.AppID, models.VersionsEventData{
+		Versions: []models.EventVersion{{ID: "55ebd387-9c68-4137-a367-a12025cc2cdb", Version: "1.0"}},
+	})
+
+	t.Run("Should add a delivery of the event for each webhook subscribed to it", func(t *testing.T) {
+		repo := &RepositoryMock{
+			GetWebhooksByEventFunc: func(appID string, eventType string) ([]models.Webhook, error) {
+				return []models.Webhook{webhook}, nil
+			},
+			CreateDeliveriesFunc: func(deliveries []models.WebhookDelivery) error {
+				return nil
+			},
+		}
+
+		if err := NewService(repo, newAppsRepository(), testConfig).Publish(event); err != nil {
+			t.Fatalf("webhooksService.Publish() error = %v", err)
+		}
+
+		calls := repo.CreateDeliveriesCalls()
+		if len(calls) != 1 || len(calls[0].Deliveries) != 1 {
+			t.Fatalf("webhooksService.Publish() created %v, want a delivery", calls)
+		}
+
+		d := calls[0].Deliveries[0]
+		if d.WebhookID != webhook.ID || d.EventID != event.ID || d.EventType != event.Type || d.Status != models.DeliveryPending {
+			t.Errorf("webhooksService.Publish() created %v, want a pending delivery of the event to %v", d, webhook.ID)
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal(d.Payload, &payload); err != nil || payload["id"] != event.ID || payload["appId"] != event.AppID {
+			t.Errorf("webhooksService.Publish() payload = %s, want the event", d.Payload)
+		}
+	})
+
+	t.Run("Should not add a delivery when no webhook is subscribed", func(t *testing.T) {
+		repo := &RepositoryMock{
+			GetWebhooksByEventFunc: func(appID string, eventType string) ([]models.Webhook, error) {
+				return []models.Webhook{}, nil
+			},
+		}
+
+		if err := NewService(repo, newAppsRepository(), testConfig).Publish(event); err != nil {
+			t.Errorf("webhooksService.Publish() error = %v", err)
+		}
+	})
+}
+
+func Test_webhooksService_ReplayDelivery(t *testing.T) {
+	app := helpers.GetMockApp()
+	webhook := getMockWebhook()
+	original := models.WebhookDelivery{
+		ID:        "7d4c8a3e-2b1f-4e6a-9c5d-8f0e1a2b3c4d",
+		WebhookID: webhook.ID,
+		EventID:   "0b6f2e1c-9a8d-4c7b-8e5f-3d2c1b0a9f8e",
+		EventType: models.EventAppDeleted,
+		Payload:   []byte(`{"type":"app.deleted"}`),
+		Status:    models.DeliveryFailed,
+		Attempts:  3,
+	}
+
+	repo := &RepositoryMock{
+		GetWebhookByIDFunc: func(id string) (*models.Webhook, error) {
+			return &webhook, nil
+		},
+		GetDeliveryByIDFunc: func(id string) (*models.WebhookDelivery, error) {
+			if id == original.ID {
+				return &original, nil
+			}
+			other := original
+			other.WebhookID = "5a4f4e3d-8c2b-4a0f-9e8d-7c6b5a4f4e3d"
+			return &other, nil
+		},
+		CreateDeliveriesFunc: func(deliveries []models.WebhookDelivery) error {
+			return nil
+		},
+	}
+	s := NewService(repo, newAppsRepository(), testConfig)
+
+	got, err := s.ReplayDelivery(app.ID, webhook.ID, original.ID)
+	if err != nil {
+		t.Fatalf("webhooksService.ReplayDelivery() error = %v", err)
+	}
+
+	if got.ID == original.ID || got.ReplayOf != original.ID || got.Status != models.DeliveryPending || got.Attempts != 0 ||
+		got.EventID != original.EventID || string(got.Payload) != string(original.Payload) {
+		t.Errorf("webhooksService.ReplayDelivery() = %v, want a new pending delivery of the event of %v", got, original.ID)
+	}
+
+	// the deliveries of the other webhooks are not found
+	if _, err := s.ReplayDelivery(app.ID, webhook.ID, "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b"); err != models.ErrNotFound {
+		t.Errorf("webhooksService.ReplayDelivery() error = %v, want %v", err, models.ErrNotFound)
+	}
+}
+
+func Test_webhooksService_DeliverDue(t *testing.T) {
+	payload := []byte(`{"type":"app.deleted"}`)
+
+	tests := []struct {
+		name         string
+		status       int
+		attempts     int
+		wantStatus   string
+		wantBackoff  time.Duration
+		wantResponse int
+	}{
+		{
+			name:         "Should mark the delivery accepted by the webhook as delivered",
+			status:       http.StatusNoContent,
+			wantStatus:   models.DeliveryDelivered,
+			wantResponse: http.StatusNoContent,
+		},
+		{
+			name:         "Should retry the delivery rejected by the webhook",
+			status:       http.StatusInternalServerError,
+			wantStatus:   models.DeliveryPending,
+			wantBackoff:  time.Minute,
+			wantResponse: http.StatusInternalServerError,
+		},
+		{
+			name:         "Should double the delay before each retry",
+			status:       http.StatusBadGateway,
+			attempts:     1,
+			wantStatus:   models.DeliveryPending,
+			wantBackoff:  2 * time.Minute,
+			wantResponse: http.StatusBadGateway,
+		},
+		{
+			name:         "Should mark the delivery as failed after the last attempt",
+			status:       http.StatusInternalServerError,
+			attempts:     2,
+			wantStatus:   models.DeliveryFailed,
+			wantResponse: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				body, _ = ioutil.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			webhook := getMockWebhook()
+			webhook.URL = server.URL
+
+			delivery := newDelivery(webhook.ID, "0b6f2e1c-9a8d-4c7b-8e5f-3d2c1b0a9f8e", models.EventAppDeleted, payload)
+			delivery.Attempts = tt.attempts
+
+			repo := &RepositoryMock{
+				ClaimDueDeliveriesFunc: func(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+					return []models.WebhookDelivery{delivery}, nil
+				},
+				GetWebhookByIDFunc: func(id string) (*models.Webhook, error) {
+					return &webhook, nil
+				},
+				UpdateDeliveryFunc: func(delivery models.WebhookDelivery) error {
+					return nil
+				},
+			}
+
+			sent, err := NewService(repo, newAppsRepository(), testConfig).DeliverDue()
+			if err != nil || sent != 1 {
+				t.Fatalf("webhooksService.DeliverDue() = %v, %v, want 1 delivery", sent, err)
+			}
+
+			if received == nil {
+				t.Fatal("webhooksService.DeliverDue() did not send the delivery")
+			}
+			if string(body) != string(payload) {
+				t.Errorf("webhooksService.DeliverDue() sent %s, want %s", body, payload)
+			}
+			if got := received.Header.Get(SignatureHeader); got != Sign(webhook.Secret, payload) {
+				t.Errorf("webhooksService.DeliverDue() signature = %v, want %v", got, Sign(webhook.Secret, payload))
+			}
+			if received.Header.Get(EventHeader) != models.EventAppDeleted || received.Header.Get(DeliveryHeader) != delivery.ID {
+				t.Errorf("webhooksService.DeliverDue() headers = %v", received.Header)
+			}
+
+			calls := repo.UpdateDeliveryCalls()
+			if len(calls) != 1 {
+				t.Fatalf("webhooksService.DeliverDue() updated %v deliveries, want 1", len(calls))
+			}
+			got := calls[0].Delivery
+			if got.Status != tt.wantStatus || got.Attempts != tt.attempts+1 || got.ResponseStatus != tt.wantResponse || got.LastAttemptAt == nil {
+				t.Errorf("webhooksService.DeliverDue() updated %v, want status %v after %v attempts", got, tt.wantStatus, tt.attempts+1)
+			}
+			if tt.wantBackoff > 0 && got.NextAttemptAt.Sub(*got.LastAttemptAt) != tt.wantBackoff {
+				t.Errorf("webhooksService.DeliverDue() next attempt in %v, want %v", got.NextAttemptAt.Sub(*got.LastAttemptAt), tt.wantBackoff)
+			}
+			if (got.DeliveredAt != nil) != (tt.wantStatus == models.DeliveryDelivered) {
+				t.Errorf("webhooksService.DeliverDue() deliveredAt = %v", got.DeliveredAt)
+			}
+		})
+	}
+}
+
+func Test_sender_backoff(t *testing.T) {
+	s := newSender(testConfig)
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Minute},
+		{attempts: 2, want: 2 * time.Minute},
+		{attempts: 4, want: 8 * time.Minute},
+		{attempts: 7, want: time.Hour},
+		{attempts: 100, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := s.backoff(tt.attempts); got != tt.want {
+			t.Errorf("sender.backoff(%v) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"type":"app.deleted"}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=2ea5be663cfc9e2d950a9524bd9d48cb2d172ae4f8568f63ebaef8c21423521a"
+	if got := Sign("secret", []byte(`{"type":"app.deleted"}`)); got != want {
+		t.Errorf("Sign() = %v, want %v", got, want)
+	}
+}